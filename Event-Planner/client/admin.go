@@ -0,0 +1,81 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// System roles
+const (
+	SystemRoleAdmin   = "admin"
+	SystemRoleSupport = "support"
+	SystemRoleMember  = "member"
+)
+
+// User is an account as seen by administrators
+type User struct {
+	ID         int        `json:"id"`
+	Email      string     `json:"email"`
+	Role       string     `json:"role"`
+	DisabledAt *time.Time `json:"disabled_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// UsersFilter narrows ListUsers; every field is optional
+type UsersFilter struct {
+	Query    string // email substring
+	Role     string // SystemRoleAdmin, SystemRoleSupport or SystemRoleMember
+	Disabled *bool  // only disabled / only active accounts
+}
+
+// ListUsers returns a page of the accounts matching the filter (admin and support only);
+// the server returns 50 users when the page is nil
+func (c *Client) ListUsers(ctx context.Context, filter UsersFilter, page *Page) ([]User, error) {
+	q := url.Values{}
+	if filter.Query != "" {
+		q.Set("q", filter.Query)
+	}
+	if filter.Role != "" {
+		q.Set("role", filter.Role)
+	}
+	if filter.Disabled != nil {
+		q.Set("disabled", strconv.FormatBool(*filter.Disabled))
+	}
+
+	return getData[[]User](ctx, c, http.MethodGet, "/admin/users", page.query(q), nil)
+}
+
+// Users iterates over the accounts matching the filter, fetching pageSize
+// users at a time (DefaultPageSize when zero)
+func (c *Client) Users(ctx context.Context, filter UsersFilter, pageSize int) iter.Seq2[User, error] {
+	return paginate(ctx, pageSize, func(ctx context.Context, page *Page) ([]User, error) {
+		return c.ListUsers(ctx, filter, page)
+	})
+}
+
+// GetUser returns an account by ID (admin and support only)
+func (c *Client) GetUser(ctx context.Context, id int) (*User, error) {
+	return getData[*User](ctx, c, http.MethodGet, fmt.Sprintf("/admin/users/%d", id), nil, nil)
+}
+
+// DisableUser prevents an account from signing in (admin only)
+func (c *Client) DisableUser(ctx context.Context, id int, reason string) (*User, error) {
+	body := map[string]string{"reason": reason}
+	return getData[*User](ctx, c, http.MethodPut, fmt.Sprintf("/admin/users/%d/disable", id), nil, body)
+}
+
+// EnableUser re-enables a disabled account (admin only)
+func (c *Client) EnableUser(ctx context.Context, id int) (*User, error) {
+	return getData[*User](ctx, c, http.MethodPut, fmt.Sprintf("/admin/users/%d/enable", id), nil, nil)
+}
+
+// SetUserRole changes the system role of an account (admin only)
+func (c *Client) SetUserRole(ctx context.Context, id int, role string) (*User, error) {
+	body := map[string]string{"role": role}
+	return getData[*User](ctx, c, http.MethodPut, fmt.Sprintf("/admin/users/%d/role", id), nil, body)
+}
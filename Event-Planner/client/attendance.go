@@ -0,0 +1,30 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// JoinEvent adds the current user to an event as an attendee
+func (c *Client) JoinEvent(ctx context.Context, eventID int) error {
+	return c.do(ctx, http.MethodPost, fmt.Sprintf("/events/%d/join", eventID), nil, nil, nil)
+}
+
+// SetAttendance records whether the current user is going to an event
+// (StatusGoing, StatusMaybe or StatusNotGoing)
+func (c *Client) SetAttendance(ctx context.Context, eventID int, status string) error {
+	body := map[string]string{"status": status}
+	return c.do(ctx, http.MethodPut, fmt.Sprintf("/events/%d/attendance", eventID), nil, body, nil)
+}
+
+// Attendees returns the attendees of an event
+func (c *Client) Attendees(ctx context.Context, eventID int) ([]Attendee, error) {
+	return getData[[]Attendee](ctx, c, http.MethodGet, fmt.Sprintf("/events/%d/attendees", eventID), nil, nil)
+}
+
+// AddAttendee adds a user to an event the current user organizes with the given role
+func (c *Client) AddAttendee(ctx context.Context, eventID, userID int, role string) error {
+	body := map[string]any{"user_id": userID, "role": role}
+	return c.do(ctx, http.MethodPost, fmt.Sprintf("/events/%d/invite", eventID), nil, body, nil)
+}
@@ -0,0 +1,66 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type authResponse struct {
+	Token string `json:"token"`
+}
+
+// Register creates an account and authenticates the client as it
+func (c *Client) Register(ctx context.Context, email, password string) error {
+	return c.login(ctx, "/auth/register", Credentials{Email: email, Password: password})
+}
+
+// Login authenticates the client. The credentials are kept in memory to log
+// in again when the token expires.
+func (c *Client) Login(ctx context.Context, email, password string) error {
+	return c.login(ctx, "/auth/login", Credentials{Email: email, Password: password})
+}
+
+func (c *Client) login(ctx context.Context, path string, creds Credentials) error {
+	var resp authResponse
+	if err := c.authenticate(ctx, path, creds, &resp); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.token = resp.Token
+	c.credentials = &creds
+	c.mu.Unlock()
+	return nil
+}
+
+// authenticate posts credentials without a token; it doesn't touch the client
+// state so refresh can call it while holding the lock
+func (c *Client) authenticate(ctx context.Context, path string, creds Credentials, out *authResponse) error {
+	payload, err := json.Marshal(creds)
+	if err != nil {
+		return fmt.Errorf("failed to encode credentials: %w", err)
+	}
+
+	resp, err := c.send(ctx, http.MethodPost, path, nil, nil, payload, "")
+	if err != nil {
+		return err
+	}
+	return decodeResponse(resp, out)
+}
+
+// Me returns the user the client is authenticated as
+func (c *Client) Me(ctx context.Context) (*Me, error) {
+	var me Me
+	if err := c.do(ctx, http.MethodGet, "/api/profile", nil, nil, &me); err != nil {
+		return nil, err
+	}
+	return &me, nil
+}
+
+// Me identifies the authenticated user
+type Me struct {
+	UserID int    `json:"user_id"`
+	Role   string `json:"role"` // 'admin', 'support' or 'member'
+}
@@ -0,0 +1,261 @@
+// Package client is a typed Go client for the Event Planner API.
+//
+//	c, err := client.New("http://localhost:8080")
+//	if err != nil { ... }
+//	if err := c.Login(ctx, "me@example.com", "secret"); err != nil { ... }
+//
+//	for e, err := range c.Events(ctx, 0) {
+//		if err != nil { ... }
+//		fmt.Println(e.Title)
+//	}
+//
+// API errors are returned as *Error and match the Err* kinds with errors.Is.
+// After Login the client logs in again when its token is about to expire or
+// is rejected, so long-running services don't have to manage tokens.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// refreshMargin is how long before its expiry a token is replaced
+const refreshMargin = time.Minute
+
+// headerOrganizationID selects the organization a request operates in
+const headerOrganizationID = "X-Organization-ID"
+
+// Client calls the Event Planner API; it is safe for concurrent use
+type Client struct {
+	baseURL        *url.URL
+	httpClient     *http.Client
+	organizationID *int
+
+	mu          sync.Mutex
+	token       string
+	credentials *Credentials // set by Login to refresh the token
+}
+
+// Credentials identify an account
+type Credentials struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient sends requests with hc instead of http.DefaultClient
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = hc
+	}
+}
+
+// WithToken authenticates requests with an existing token; it is not refreshed
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// WithOrganization sends every request in the scope of an organization (0 = personal)
+func WithOrganization(id int) Option {
+	return func(c *Client) {
+		c.organizationID = &id
+	}
+}
+
+// New creates a client for the API served at baseURL, e.g. "https://events.example.com"
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid base URL %q: scheme must be http or https", baseURL)
+	}
+
+	c := &Client{baseURL: u, httpClient: http.DefaultClient}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// Token returns the current bearer token, empty when not logged in
+func (c *Client) Token() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.token
+}
+
+// dataEnvelope is the {"data": ...} wrapper of successful responses
+type dataEnvelope[T any] struct {
+	Data T `json:"data"`
+}
+
+// getData sends a request and returns the data of the response
+func getData[T any](ctx context.Context, c *Client, method, path string, query url.Values, body any) (T, error) {
+	var resp dataEnvelope[T]
+	err := c.do(ctx, method, path, query, body, &resp)
+	return resp.Data, err
+}
+
+// do sends a request with the current token and decodes the response into out
+// (if not nil). A request rejected because of its token is retried once with a new one.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out any) error {
+	return c.doWithHeader(ctx, method, path, query, nil, body, out)
+}
+
+// doWithHeader is do with extra request headers, e.g. If-Match
+func (c *Client) doWithHeader(ctx context.Context, method, path string, query url.Values, header http.Header, body, out any) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+	}
+
+	token, err := c.validToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.send(ctx, method, path, query, header, payload, token)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized && token != "" && c.canRefresh() {
+		resp.Body.Close()
+		if token, err = c.refresh(ctx, token); err != nil {
+			return err
+		}
+		if resp, err = c.send(ctx, method, path, query, header, payload, token); err != nil {
+			return err
+		}
+	}
+	return decodeResponse(resp, out)
+}
+
+// decodeResponse decodes a successful response into out (if not nil) and closes it
+func decodeResponse(resp *http.Response, out any) error {
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return decodeError(resp)
+	}
+
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+	}
+	return nil
+}
+
+func (c *Client) send(ctx context.Context, method, path string, query url.Values, header http.Header, payload []byte, token string) (*http.Response, error) {
+	u := c.baseURL.JoinPath(path)
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	if c.organizationID != nil {
+		req.Header.Set(headerOrganizationID, strconv.Itoa(*c.organizationID))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		// Report cancellation as the context error so errors.Is(err, context.Canceled) holds
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, fmt.Errorf("%s %s: %w", method, path, err)
+	}
+	return resp, nil
+}
+
+// validToken returns the current token, replacing it first when it is about to expire
+func (c *Client) validToken(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	token := c.token
+	c.mu.Unlock()
+
+	if token == "" || !c.canRefresh() {
+		return token, nil
+	}
+
+	if exp, ok := tokenExpiry(token); ok && time.Until(exp) < refreshMargin {
+		return c.refresh(ctx, token)
+	}
+	return token, nil
+}
+
+func (c *Client) canRefresh() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.credentials != nil
+}
+
+// refresh logs in again unless another request already replaced the stale token
+func (c *Client) refresh(ctx context.Context, stale string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token != stale {
+		return c.token, nil
+	}
+
+	var resp authResponse
+	if err := c.authenticate(ctx, "/auth/login", *c.credentials, &resp); err != nil {
+		return "", fmt.Errorf("failed to refresh token: %w", err)
+	}
+
+	c.token = resp.Token
+	return c.token, nil
+}
+
+// tokenExpiry reads the exp claim of a JWT; the signature is the server's business
+func tokenExpiry(token string) (time.Time, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(data, &claims); err != nil || claims.Exp == 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(claims.Exp, 0), true
+}
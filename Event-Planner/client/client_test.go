@@ -0,0 +1,274 @@
+package client_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"event-planner/client"
+	"event-planner/internal/app"
+	"event-planner/internal/auth"
+)
+
+// newRouterClient serves the real router without a database; only requests
+// rejected before reaching the storage layer can be sent
+func newRouterClient(t *testing.T) *client.Client {
+	t.Helper()
+
+	application, err := app.New(nil, app.Options{
+		Auth:      auth.Config{JWTSecret: "test-secret"},
+		AvatarDir: t.TempDir(),
+	})
+	if err != nil {
+		t.Fatalf("app.New: %v", err)
+	}
+
+	srv := httptest.NewServer(application.Handler())
+	t.Cleanup(srv.Close)
+
+	return newClient(t, srv.URL)
+}
+
+func newClient(t *testing.T, baseURL string, opts ...client.Option) *client.Client {
+	t.Helper()
+
+	c, err := client.New(baseURL, opts...)
+	if err != nil {
+		t.Fatalf("client.New: %v", err)
+	}
+	return c
+}
+
+// writeJSON writes a response like the API handlers do
+func writeJSON(w http.ResponseWriter, status int, payload any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(payload)
+}
+
+// fakeJWT returns an unsigned token that expires at exp
+func fakeJWT(exp time.Time) string {
+	enc := base64.RawURLEncoding
+	claims := fmt.Sprintf(`{"user_id":1,"exp":%d}`, exp.Unix())
+	return enc.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`)) + "." + enc.EncodeToString([]byte(claims)) + ".sig"
+}
+
+func TestErrorsMapServerResponses(t *testing.T) {
+	c := newRouterClient(t)
+	ctx := context.Background()
+
+	_, err := c.CreateEvent(ctx, client.CreateEventRequest{Title: "Launch"})
+	var apiErr *client.Error
+	if !errors.As(err, &apiErr) || !errors.Is(err, client.ErrUnauthorized) {
+		t.Fatalf("CreateEvent without a token: got %v, want an unauthorized *client.Error", err)
+	}
+	if apiErr.StatusCode != http.StatusUnauthorized || apiErr.Code != "missing_token" {
+		t.Errorf("got status %d code %q, want 401 missing_token", apiErr.StatusCode, apiErr.Code)
+	}
+
+	err = c.Register(ctx, "ada@example.com", "")
+	if !errors.As(err, &apiErr) || !errors.Is(err, client.ErrValidation) {
+		t.Fatalf("Register without a password: got %v, want a validation error", err)
+	}
+	if apiErr.Code != "validation_failed" || len(apiErr.Fields) != 1 || apiErr.Fields[0].Field != "password" {
+		t.Errorf("got %+v, want a validation_failed error on password", apiErr)
+	}
+
+	_, err = c.GetEvent(ctx, 0)
+	if !errors.Is(err, client.ErrValidation) {
+		t.Errorf("GetEvent(0): got %v, want a validation error", err)
+	}
+	if errors.Is(err, client.ErrNotFound) {
+		t.Errorf("GetEvent(0): validation error also matches ErrNotFound")
+	}
+}
+
+func TestErrorWithoutEnvelope(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "upstream unavailable", http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	_, err := newClient(t, srv.URL).ListEvents(context.Background(), nil)
+
+	var apiErr *client.Error
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("got %v, want *client.Error", err)
+	}
+	if apiErr.StatusCode != http.StatusBadGateway || apiErr.Kind != nil || apiErr.Message != "Bad Gateway" {
+		t.Errorf("got %+v, want a 502 without kind", apiErr)
+	}
+}
+
+func TestRejectedTokenIsRefreshed(t *testing.T) {
+	var logins, attempts atomic.Int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /auth/login", func(w http.ResponseWriter, r *http.Request) {
+		n := logins.Add(1)
+		writeJSON(w, http.StatusOK, map[string]string{"token": fmt.Sprintf("token-%d", n)})
+	})
+	mux.HandleFunc("GET /events/my/organized", func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		if r.Header.Get("Authorization") != "Bearer token-2" {
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid token", "code": "invalid_token"})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"data": []client.Event{{ID: 7}}})
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c := newClient(t, srv.URL)
+	ctx := context.Background()
+	if err := c.Login(ctx, "ada@example.com", "secret"); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+
+	events, err := c.MyOrganizedEvents(ctx)
+	if err != nil {
+		t.Fatalf("MyOrganizedEvents: %v", err)
+	}
+	if len(events) != 1 || events[0].ID != 7 {
+		t.Errorf("got %+v, want event 7", events)
+	}
+	if logins.Load() != 2 || attempts.Load() != 2 {
+		t.Errorf("got %d logins and %d attempts, want 2 and 2", logins.Load(), attempts.Load())
+	}
+	if c.Token() != "token-2" {
+		t.Errorf("got token %q, want token-2", c.Token())
+	}
+}
+
+func TestExpiringTokenIsRefreshedBeforeUse(t *testing.T) {
+	expiring := fakeJWT(time.Now().Add(10 * time.Second))
+	fresh := fakeJWT(time.Now().Add(time.Hour))
+
+	var logins atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /auth/login", func(w http.ResponseWriter, r *http.Request) {
+		token := fresh
+		if logins.Add(1) == 1 {
+			token = expiring
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"token": token})
+	})
+	mux.HandleFunc("GET /events/my/organized", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+fresh {
+			t.Errorf("request sent with an expiring token")
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"data": []client.Event{}})
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c := newClient(t, srv.URL)
+	ctx := context.Background()
+	if err := c.Login(ctx, "ada@example.com", "secret"); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if _, err := c.MyOrganizedEvents(ctx); err != nil {
+		t.Fatalf("MyOrganizedEvents: %v", err)
+	}
+	if logins.Load() != 2 {
+		t.Errorf("got %d logins, want 2", logins.Load())
+	}
+}
+
+func TestStaticTokenIsNotRefreshed(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/auth/login" {
+			t.Errorf("client logged in without credentials")
+		}
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid token", "code": "invalid_token"})
+	}))
+	defer srv.Close()
+
+	_, err := newClient(t, srv.URL, client.WithToken("revoked")).MyOrganizedEvents(context.Background())
+	if !errors.Is(err, client.ErrUnauthorized) {
+		t.Errorf("got %v, want an unauthorized error", err)
+	}
+}
+
+func TestRequestsFollowContextCancellation(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := newClient(t, srv.URL).ListEvents(ctx, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestEventsIteratesPages(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+
+		// Five events in total
+		events := []client.Event{}
+		for id := offset + 1; id <= min(offset+limit, 5); id++ {
+			events = append(events, client.Event{ID: id})
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"data": events})
+	}))
+	defer srv.Close()
+
+	c := newClient(t, srv.URL)
+	ctx := context.Background()
+
+	var ids []int
+	for e, err := range c.Events(ctx, 2) {
+		if err != nil {
+			t.Fatalf("Events: %v", err)
+		}
+		ids = append(ids, e.ID)
+	}
+	if fmt.Sprint(ids) != "[1 2 3 4 5]" || requests.Load() != 3 {
+		t.Errorf("got %v in %d requests, want [1 2 3 4 5] in 3", ids, requests.Load())
+	}
+
+	// Breaking out of the loop stops fetching
+	requests.Store(0)
+	for e := range c.Events(ctx, 2) {
+		if e.ID == 2 {
+			break
+		}
+	}
+	if requests.Load() != 1 {
+		t.Errorf("got %d requests after break, want 1", requests.Load())
+	}
+}
+
+func TestEventsIteratorStopsOnError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusForbidden, map[string]string{"error": "not a member", "code": "not_organization_member"})
+	}))
+	defer srv.Close()
+
+	var errs int
+	for _, err := range newClient(t, srv.URL, client.WithOrganization(3)).Events(context.Background(), 0) {
+		if !errors.Is(err, client.ErrForbidden) {
+			t.Errorf("got %v, want a forbidden error", err)
+		}
+		errs++
+	}
+	if errs != 1 {
+		t.Errorf("got %d errors, want 1", errs)
+	}
+}
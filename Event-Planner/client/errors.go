@@ -0,0 +1,84 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Error kinds, mirroring the status codes of the API; every *Error wraps one
+// of them (or none for 5xx) so callers can branch with errors.Is
+var (
+	ErrValidation    = errors.New("validation failed")
+	ErrUnauthorized  = errors.New("unauthorized")
+	ErrForbidden     = errors.New("forbidden")
+	ErrNotFound      = errors.New("not found")
+	ErrConflict      = errors.New("conflict")
+	ErrUnprocessable = errors.New("unprocessable")
+
+	ErrPreconditionFailed   = errors.New("precondition failed")   // the resource changed since it was read
+	ErrPreconditionRequired = errors.New("precondition required") // the request must name the version it changes
+)
+
+var statusKinds = map[int]error{
+	http.StatusBadRequest:           ErrValidation,
+	http.StatusUnauthorized:         ErrUnauthorized,
+	http.StatusForbidden:            ErrForbidden,
+	http.StatusNotFound:             ErrNotFound,
+	http.StatusConflict:             ErrConflict,
+	http.StatusUnprocessableEntity:  ErrUnprocessable,
+	http.StatusPreconditionFailed:   ErrPreconditionFailed,
+	http.StatusPreconditionRequired: ErrPreconditionRequired,
+}
+
+// FieldError describes why a single request field was rejected
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Error is an error response of the API
+type Error struct {
+	StatusCode int
+	Kind       error        // one of the Err* kinds above, nil for server errors
+	Code       string       // stable identifier, e.g. "event_not_found"
+	Message    string       // human-readable description
+	Fields     []FieldError // validation details, if any
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("event planner: %s (%d %s)", e.Message, e.StatusCode, e.Code)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
+
+// errorBody is the JSON envelope of every error response
+type errorBody struct {
+	Error   string       `json:"error"`
+	Code    string       `json:"code"`
+	Details []FieldError `json:"details"`
+}
+
+// decodeError reads an error response; bodies that aren't an error envelope
+// (e.g. from a proxy) are reported with the status text
+func decodeError(resp *http.Response) error {
+	apiErr := &Error{
+		StatusCode: resp.StatusCode,
+		Kind:       statusKinds[resp.StatusCode],
+		Message:    http.StatusText(resp.StatusCode),
+	}
+
+	var body errorBody
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err := json.Unmarshal(data, &body); err == nil && body.Error != "" {
+		apiErr.Code = body.Code
+		apiErr.Message = body.Error
+		apiErr.Fields = body.Details
+	}
+
+	return apiErr
+}
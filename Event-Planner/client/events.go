@@ -0,0 +1,114 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ListEvents returns a page of the events visible in the client's scope,
+// newest first; a nil page returns every event
+func (c *Client) ListEvents(ctx context.Context, page *Page) ([]Event, error) {
+	return getData[[]Event](ctx, c, http.MethodGet, "/events", page.query(nil), nil)
+}
+
+// Events iterates over the events visible in the client's scope, fetching
+// pageSize events at a time (DefaultPageSize when zero)
+func (c *Client) Events(ctx context.Context, pageSize int) iter.Seq2[Event, error] {
+	return paginate(ctx, pageSize, c.ListEvents)
+}
+
+// GetEvent returns an event by ID
+func (c *Client) GetEvent(ctx context.Context, id int) (*Event, error) {
+	return getData[*Event](ctx, c, http.MethodGet, fmt.Sprintf("/events/%d", id), nil, nil)
+}
+
+// EventsByOrganizer returns the events organized by a user
+func (c *Client) EventsByOrganizer(ctx context.Context, organizerID int) ([]Event, error) {
+	return getData[[]Event](ctx, c, http.MethodGet, fmt.Sprintf("/events/organizer/%d", organizerID), nil, nil)
+}
+
+// CreateEvent creates an event organized by the current user
+func (c *Client) CreateEvent(ctx context.Context, req CreateEventRequest) (*Event, error) {
+	return getData[*Event](ctx, c, http.MethodPost, "/events", nil, req)
+}
+
+// UpdateEvent changes an event the current user organizes. version is the
+// Version of the event the changes were made to; the update fails with
+// ErrPreconditionFailed when the event changed since. AnyVersion overwrites
+// whatever the current version is.
+func (c *Client) UpdateEvent(ctx context.Context, id, version int, req UpdateEventRequest) (*Event, error) {
+	var resp dataEnvelope[*Event]
+	err := c.doWithHeader(ctx, http.MethodPut, fmt.Sprintf("/events/%d", id), nil, ifMatch(version), req, &resp)
+	return resp.Data, err
+}
+
+// DeleteEvent moves an event the current user organizes to their trash,
+// from where RestoreEvent brings it back; version is as for UpdateEvent
+func (c *Client) DeleteEvent(ctx context.Context, id, version int) error {
+	return c.doWithHeader(ctx, http.MethodDelete, fmt.Sprintf("/events/%d", id), nil, ifMatch(version), nil, nil)
+}
+
+// CancelEvent calls off an event the current user organizes, notifying its
+// attendees and invitees of the reason; version is as for UpdateEvent
+func (c *Client) CancelEvent(ctx context.Context, id, version int, reason string) (*Event, error) {
+	return c.changeStatus(ctx, fmt.Sprintf("/events/%d/cancel", id), version, reason)
+}
+
+// PostponeEvent puts off an event the current user organizes until they
+// give it a new date or time; version is as for UpdateEvent
+func (c *Client) PostponeEvent(ctx context.Context, id, version int, reason string) (*Event, error) {
+	return c.changeStatus(ctx, fmt.Sprintf("/events/%d/postpone", id), version, reason)
+}
+
+// changeStatus posts a status change with its reason to path
+func (c *Client) changeStatus(ctx context.Context, path string, version int, reason string) (*Event, error) {
+	var resp dataEnvelope[*Event]
+	body := map[string]string{"reason": reason}
+	err := c.doWithHeader(ctx, http.MethodPost, path, nil, ifMatch(version), body, &resp)
+	return resp.Data, err
+}
+
+// PublishEvent publishes a draft the current user organizes, or has it
+// published at publishAt when that is not nil; version is as for UpdateEvent
+func (c *Client) PublishEvent(ctx context.Context, id, version int, publishAt *time.Time) (*Event, error) {
+	var resp dataEnvelope[*Event]
+	body := map[string]*time.Time{"publish_at": publishAt}
+	err := c.doWithHeader(ctx, http.MethodPost, fmt.Sprintf("/events/%d/publish", id), nil, ifMatch(version), body, &resp)
+	return resp.Data, err
+}
+
+// AnyVersion makes UpdateEvent, DeleteEvent, CancelEvent, PostponeEvent and
+// PublishEvent unconditional
+const AnyVersion = 0
+
+// ifMatch returns the If-Match header naming a version of an event
+func ifMatch(version int) http.Header {
+	if version == AnyVersion {
+		return http.Header{"If-Match": {"*"}}
+	}
+	return http.Header{"If-Match": {`"` + strconv.Itoa(version) + `"`}}
+}
+
+// MyAttendingEvents returns the events the current user attends
+func (c *Client) MyAttendingEvents(ctx context.Context) ([]AttendingEvent, error) {
+	return getData[[]AttendingEvent](ctx, c, http.MethodGet, "/events/my/attending", nil, nil)
+}
+
+// MyOrganizedEvents returns the events the current user organizes
+func (c *Client) MyOrganizedEvents(ctx context.Context) ([]Event, error) {
+	return getData[[]Event](ctx, c, http.MethodGet, "/events/my/organized", nil, nil)
+}
+
+// Trash returns the events the current user deleted, most recently deleted first
+func (c *Client) Trash(ctx context.Context) ([]TrashedEvent, error) {
+	return getData[[]TrashedEvent](ctx, c, http.MethodGet, "/events/my/trash", nil, nil)
+}
+
+// RestoreEvent takes an event out of the current user's trash
+func (c *Client) RestoreEvent(ctx context.Context, id int) (*Event, error) {
+	return getData[*Event](ctx, c, http.MethodPost, fmt.Sprintf("/events/my/trash/%d/restore", id), nil, nil)
+}
@@ -0,0 +1,195 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"event-planner/client"
+	"event-planner/internal/app"
+	"event-planner/internal/auth"
+	"event-planner/internal/storetest"
+)
+
+// newServer serves the real router on a fresh schema of the database at
+// TEST_DATABASE_URL; the test is skipped when it is not set
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	pool := storetest.PostgresPool(t)
+
+	application, err := app.New(pool, app.Options{
+		Auth:      auth.Config{JWTSecret: "test-secret"},
+		AvatarDir: t.TempDir(),
+	})
+	if err != nil {
+		t.Fatalf("app.New: %v", err)
+	}
+
+	srv := httptest.NewServer(application.Handler())
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// registered returns a client logged in as a new account
+func registered(t *testing.T, srv *httptest.Server, email string) *client.Client {
+	t.Helper()
+
+	c := newClient(t, srv.URL)
+	if err := c.Register(context.Background(), email, "secret-password"); err != nil {
+		t.Fatalf("Register %s: %v", email, err)
+	}
+	return c
+}
+
+func TestClientAgainstRouter(t *testing.T) {
+	srv := newServer(t)
+	ctx := context.Background()
+
+	ada := registered(t, srv, "ada@example.com")
+	bob := registered(t, srv, "bob@example.com")
+
+	// Registering twice is a conflict
+	err := newClient(t, srv.URL).Register(ctx, "ada@example.com", "another-password")
+	if !errors.Is(err, client.ErrConflict) {
+		t.Errorf("duplicate Register: got %v, want a conflict", err)
+	}
+
+	// Logging in replaces the token
+	if err := bob.Login(ctx, "bob@example.com", "secret-password"); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+
+	date := time.Now().AddDate(0, 1, 0).Format("2006-01-02")
+	var events []*client.Event
+	for _, title := range []string{"Kickoff", "Retro", "Offsite"} {
+		e, err := ada.CreateEvent(ctx, client.CreateEventRequest{
+			Title:    title,
+			Date:     date,
+			Time:     "10:00:00",
+			Location: "Berlin",
+		})
+		if err != nil {
+			t.Fatalf("CreateEvent %s: %v", title, err)
+		}
+		events = append(events, e)
+	}
+	kickoff, retro, offsite := events[0], events[1], events[2]
+
+	if starts, err := kickoff.StartsAt(); err != nil || starts.Format("2006-01-02 15:04") != date+" 10:00" {
+		t.Errorf("StartsAt: got %v, %v", starts, err)
+	}
+
+	// The iterator walks every page
+	var listed int
+	for _, err := range bob.Events(ctx, 2) {
+		if err != nil {
+			t.Fatalf("Events: %v", err)
+		}
+		listed++
+	}
+	if listed != 3 {
+		t.Errorf("Events: got %d events, want 3", listed)
+	}
+
+	// Only the organizer may change an event
+	_, err = bob.UpdateEvent(ctx, kickoff.ID, kickoff.Version, client.UpdateEventRequest{Title: "Hijacked"})
+	if !errors.Is(err, client.ErrForbidden) {
+		t.Errorf("UpdateEvent by another user: got %v, want forbidden", err)
+	}
+	updated, err := ada.UpdateEvent(ctx, kickoff.ID, kickoff.Version, client.UpdateEventRequest{Location: "Hamburg"})
+	if err != nil || updated.Location != "Hamburg" || updated.Title != "Kickoff" || updated.Version != kickoff.Version+1 {
+		t.Errorf("UpdateEvent: got %+v, %v", updated, err)
+	}
+
+	// Updates made from an outdated copy don't overwrite newer changes
+	_, err = ada.UpdateEvent(ctx, kickoff.ID, kickoff.Version, client.UpdateEventRequest{Location: "Berlin"})
+	if !errors.Is(err, client.ErrPreconditionFailed) {
+		t.Errorf("UpdateEvent of a stale version: got %v, want precondition failed", err)
+	}
+
+	// Attendance
+	if err := bob.JoinEvent(ctx, kickoff.ID); err != nil {
+		t.Fatalf("JoinEvent: %v", err)
+	}
+	var apiErr *client.Error
+	if err := bob.JoinEvent(ctx, kickoff.ID); !errors.As(err, &apiErr) || apiErr.Code != "already_attending" {
+		t.Errorf("second JoinEvent: got %v, want already_attending", err)
+	}
+	if err := bob.SetAttendance(ctx, kickoff.ID, client.StatusMaybe); err != nil {
+		t.Fatalf("SetAttendance: %v", err)
+	}
+
+	attendees, err := ada.Attendees(ctx, kickoff.ID)
+	if err != nil {
+		t.Fatalf("Attendees: %v", err)
+	}
+	statuses := map[string]string{}
+	for _, a := range attendees {
+		statuses[a.Role] = a.Status
+	}
+	if len(attendees) != 2 || statuses[client.RoleAttendee] != client.StatusMaybe {
+		t.Errorf("Attendees: got %+v, want the organizer and bob (maybe)", attendees)
+	}
+
+	// Invitations
+	inv, err := ada.SendInvitation(ctx, client.SendInvitationRequest{
+		EventID:      retro.ID,
+		InviteeEmail: "bob@example.com",
+		Role:         client.RoleAttendee,
+	})
+	if err != nil {
+		t.Fatalf("SendInvitation: %v", err)
+	}
+
+	mine, err := bob.MyInvitations(ctx)
+	if err != nil || len(mine) != 1 || mine[0].ID != inv.ID || mine[0].EventTitle != "Retro" {
+		t.Fatalf("MyInvitations: got %+v, %v", mine, err)
+	}
+	if err := bob.RespondToInvitation(ctx, inv.ID, client.InvitationAccepted); err != nil {
+		t.Fatalf("RespondToInvitation: %v", err)
+	}
+	if err := bob.RespondToInvitation(ctx, inv.ID, client.InvitationDeclined); !errors.Is(err, client.ErrConflict) {
+		t.Errorf("second RespondToInvitation: got %v, want a conflict", err)
+	}
+
+	sent, err := ada.EventInvitations(ctx, retro.ID)
+	if err != nil || len(sent) != 1 || sent[0].Status != client.InvitationAccepted {
+		t.Errorf("EventInvitations: got %+v, %v", sent, err)
+	}
+
+	// Search sees both events bob attends, a page at a time
+	var found []string
+	for e, err := range bob.Search(ctx, client.SearchFilter{Role: client.RoleAttendee}, 1) {
+		if err != nil {
+			t.Fatalf("Search: %v", err)
+		}
+		found = append(found, e.Title)
+	}
+	if len(found) != 2 {
+		t.Errorf("Search: got %v, want Kickoff and Retro", found)
+	}
+
+	// Deleted events are gone until their organizer restores them from the trash
+	if err := ada.DeleteEvent(ctx, offsite.ID, offsite.Version); err != nil {
+		t.Fatalf("DeleteEvent: %v", err)
+	}
+	if _, err := bob.GetEvent(ctx, offsite.ID); !errors.Is(err, client.ErrNotFound) {
+		t.Errorf("GetEvent after delete: got %v, want not found", err)
+	}
+	trash, err := ada.Trash(ctx)
+	if err != nil || len(trash) != 1 || trash[0].ID != offsite.ID || !trash[0].PurgeAt.After(trash[0].DeletedAt) {
+		t.Fatalf("Trash = %+v, %v", trash, err)
+	}
+	if _, err := bob.RestoreEvent(ctx, offsite.ID); !errors.Is(err, client.ErrNotFound) {
+		t.Errorf("RestoreEvent by another user: got %v, want not found", err)
+	}
+	if restored, err := ada.RestoreEvent(ctx, offsite.ID); err != nil || restored.Title != offsite.Title {
+		t.Fatalf("RestoreEvent = %+v, %v", restored, err)
+	}
+	if _, err := bob.GetEvent(ctx, offsite.ID); err != nil {
+		t.Errorf("GetEvent after restoring: %v", err)
+	}
+}
@@ -0,0 +1,33 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// SendInvitation invites someone to an event by email
+func (c *Client) SendInvitation(ctx context.Context, req SendInvitationRequest) (*Invitation, error) {
+	return getData[*Invitation](ctx, c, http.MethodPost, "/invitations", nil, req)
+}
+
+// SendGroupInvitation invites every member of a group to an event
+func (c *Client) SendGroupInvitation(ctx context.Context, req GroupInvitationRequest) (*GroupInvitationResult, error) {
+	return getData[*GroupInvitationResult](ctx, c, http.MethodPost, "/invitations", nil, req)
+}
+
+// MyInvitations returns the invitations sent to the current user
+func (c *Client) MyInvitations(ctx context.Context) ([]InvitationWithDetails, error) {
+	return getData[[]InvitationWithDetails](ctx, c, http.MethodGet, "/invitations/my", nil, nil)
+}
+
+// EventInvitations returns the invitations sent for an event
+func (c *Client) EventInvitations(ctx context.Context, eventID int) ([]InvitationWithDetails, error) {
+	return getData[[]InvitationWithDetails](ctx, c, http.MethodGet, fmt.Sprintf("/events/%d/invitations", eventID), nil, nil)
+}
+
+// RespondToInvitation accepts or declines an invitation (InvitationAccepted or InvitationDeclined)
+func (c *Client) RespondToInvitation(ctx context.Context, id int, status string) error {
+	body := map[string]string{"status": status}
+	return c.do(ctx, http.MethodPut, fmt.Sprintf("/invitations/%d/respond", id), nil, body, nil)
+}
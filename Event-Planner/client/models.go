@@ -0,0 +1,200 @@
+package client
+
+import (
+	"fmt"
+	"time"
+)
+
+// Event visibilities
+const (
+	VisibilityPublic       = "public"       // visible to everyone
+	VisibilityOrganization = "organization" // visible to members of the event's organization
+)
+
+// Attendee roles
+const (
+	RoleOrganizer    = "organizer"
+	RoleAttendee     = "attendee"
+	RoleCollaborator = "collaborator"
+)
+
+// Attendance statuses
+const (
+	StatusGoing    = "going"
+	StatusMaybe    = "maybe"
+	StatusNotGoing = "not_going"
+)
+
+// Event statuses
+const (
+	EventDraft     = "draft" // visible to its organizer and collaborators until published
+	EventScheduled = "scheduled"
+	EventPostponed = "postponed" // until its organizer gives it a new date or time
+	EventCancelled = "cancelled"
+	EventCompleted = "completed" // once it has started
+)
+
+// Invitation statuses
+const (
+	InvitationPending  = "pending"
+	InvitationAccepted = "accepted"
+	InvitationDeclined = "declined"
+)
+
+type Event struct {
+	ID             int        `json:"id"`
+	Title          string     `json:"title"`
+	Description    string     `json:"description"`
+	Date           string     `json:"date"` // YYYY-MM-DD
+	Time           string     `json:"time"` // HH:MM:SS
+	Location       string     `json:"location"`
+	OrganizerID    int        `json:"organizer_id"`
+	OrganizationID *int       `json:"organization_id,omitempty"` // nil for personal events
+	Visibility     string     `json:"visibility"`
+	Timezone       string     `json:"timezone"` // IANA name the date and time are local to
+	Status         string     `json:"status"`   // one of the Event statuses
+	StatusReason   string     `json:"status_reason,omitempty"`
+	PublishAt      *time.Time `json:"publish_at,omitempty"` // when a draft is published
+	CreatedAt      time.Time  `json:"created_at"`
+	ArchivedAt     *time.Time `json:"archived_at,omitempty"`
+	Version        int        `json:"version"` // changes with every edit, see UpdateEvent
+}
+
+// StartsAt returns the start of the event in its timezone
+func (e Event) StartsAt() (time.Time, error) {
+	loc, err := time.LoadLocation(e.Timezone)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid event timezone: %w", err)
+	}
+	return time.ParseInLocation("2006-01-02 15:04:05", e.Date+" "+e.Time, loc)
+}
+
+// TrashedEvent is a deleted event in the current user's trash
+type TrashedEvent struct {
+	Event
+	DeletedAt time.Time `json:"deleted_at"`
+	PurgeAt   time.Time `json:"purge_at"` // when it is erased unless restored
+}
+
+// AttendingEvent is an event with the current user's role and attendance
+// status; the status of the event is EventStatus
+type AttendingEvent struct {
+	Event
+	Role        string `json:"role"`
+	Status      string `json:"status"`
+	EventStatus string `json:"event_status"`
+}
+
+type CreateEventRequest struct {
+	Title       string     `json:"title"`
+	Description string     `json:"description,omitempty"`
+	Date        string     `json:"date"` // YYYY-MM-DD
+	Time        string     `json:"time"` // HH:MM:SS
+	Location    string     `json:"location"`
+	Visibility  string     `json:"visibility,omitempty"` // defaults to the organization setting
+	Timezone    string     `json:"timezone,omitempty"`   // defaults to the organization setting
+	Draft       bool       `json:"draft,omitempty"`      // see PublishEvent
+	PublishAt   *time.Time `json:"publish_at,omitempty"` // publishes the draft at this time
+}
+
+// UpdateEventRequest changes the fields that are set
+type UpdateEventRequest struct {
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Date        string `json:"date,omitempty"`
+	Time        string `json:"time,omitempty"`
+	Location    string `json:"location,omitempty"`
+	Visibility  string `json:"visibility,omitempty"`
+}
+
+// PublicProfile is the publicly visible view of a user
+type PublicProfile struct {
+	UserID      int    `json:"user_id"`
+	DisplayName string `json:"display_name"`
+	AvatarURL   string `json:"avatar_url,omitempty"`
+}
+
+type Attendee struct {
+	ID        int           `json:"id"`
+	UserID    int           `json:"user_id"`
+	EventID   int           `json:"event_id"`
+	Role      string        `json:"role"`
+	Status    string        `json:"status"`
+	CreatedAt time.Time     `json:"created_at"`
+	User      PublicProfile `json:"user"`
+}
+
+type Invitation struct {
+	ID           int        `json:"id"`
+	EventID      int        `json:"event_id"`
+	InviterID    int        `json:"inviter_id"`
+	InviteeEmail string     `json:"invitee_email"`
+	InviteeID    *int       `json:"invitee_id,omitempty"`
+	GroupID      *int       `json:"group_id,omitempty"` // set when sent to a group
+	Role         string     `json:"role"`
+	Status       string     `json:"status"`
+	Message      string     `json:"message,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	RespondedAt  *time.Time `json:"responded_at,omitempty"`
+}
+
+// InvitationWithDetails includes event and inviter details
+type InvitationWithDetails struct {
+	Invitation
+	EventTitle    string `json:"event_title"`
+	EventDate     string `json:"event_date"`
+	EventTime     string `json:"event_time"`
+	EventLocation string `json:"event_location"`
+	EventStatus   string `json:"event_status"`
+	InviterEmail  string `json:"inviter_email"`
+
+	Inviter PublicProfile  `json:"inviter"`
+	Invitee *PublicProfile `json:"invitee,omitempty"` // nil until the invitee has an account
+}
+
+// SendInvitationRequest invites someone to an event by email
+type SendInvitationRequest struct {
+	EventID      int    `json:"event_id"`
+	InviteeEmail string `json:"invitee_email"`
+	Role         string `json:"role"`
+	Message      string `json:"message,omitempty"`
+}
+
+// GroupInvitationRequest invites every member of a group
+type GroupInvitationRequest struct {
+	EventID int    `json:"event_id"`
+	GroupID int    `json:"group_id"`
+	Role    string `json:"role"`
+	Message string `json:"message,omitempty"`
+
+	// InviteNewMembers also invites people who join the group later
+	InviteNewMembers bool `json:"invite_new_members,omitempty"`
+}
+
+// GroupInvitationResult is the outcome of inviting a group
+type GroupInvitationResult struct {
+	Invitations []Invitation `json:"invitations"`
+	Skipped     []string     `json:"skipped"` // emails that already had an invitation to the event
+}
+
+// SearchFilter narrows SearchEvents; every field is optional
+type SearchFilter struct {
+	Query    string // keyword matched against title and description
+	DateFrom string // YYYY-MM-DD
+	DateTo   string // YYYY-MM-DD
+	Role     string // RoleOrganizer, RoleAttendee or RoleCollaborator
+	Status   string // StatusGoing, StatusMaybe or StatusNotGoing
+}
+
+// Notification tells the current user that an event they attend or were
+// invited to was cancelled or postponed
+type Notification struct {
+	ID          int        `json:"id"`
+	EventID     int        `json:"event_id"`
+	EventStatus string     `json:"event_status"` // EventCancelled or EventPostponed
+	EventTitle  string     `json:"event_title"`
+	Reason      string     `json:"reason"`
+	Message     string     `json:"message"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ReadAt      *time.Time `json:"read_at,omitempty"` // nil until MarkNotificationRead
+}
@@ -0,0 +1,23 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Notifications returns the notifications of the current user, most recent
+// first; unreadOnly leaves out the ones already read
+func (c *Client) Notifications(ctx context.Context, unreadOnly bool) ([]Notification, error) {
+	var query url.Values
+	if unreadOnly {
+		query = url.Values{"unread": {"true"}}
+	}
+	return getData[[]Notification](ctx, c, http.MethodGet, "/notifications", query, nil)
+}
+
+// MarkNotificationRead marks a notification of the current user as read
+func (c *Client) MarkNotificationRead(ctx context.Context, id int) error {
+	return c.do(ctx, http.MethodPost, fmt.Sprintf("/notifications/%d/read", id), nil, nil, nil)
+}
@@ -0,0 +1,68 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"net/url"
+	"strconv"
+)
+
+const (
+	// DefaultPageSize is the page size of the iterators when none is given
+	DefaultPageSize = 50
+
+	// MaxPageSize is the largest page the server returns
+	MaxPageSize = 200
+)
+
+// Page selects a slice of a list; a zero Limit returns every item from Offset on
+type Page struct {
+	Limit  int
+	Offset int
+}
+
+func (p *Page) query(q url.Values) url.Values {
+	if q == nil {
+		q = url.Values{}
+	}
+	if p == nil {
+		return q
+	}
+	if p.Limit > 0 {
+		q.Set("limit", strconv.Itoa(p.Limit))
+	}
+	if p.Offset > 0 {
+		q.Set("offset", strconv.Itoa(p.Offset))
+	}
+	return q
+}
+
+// paginate iterates over a list fetched a page at a time, stopping after the
+// first short page or error. Breaking out of the loop stops fetching.
+func paginate[T any](ctx context.Context, pageSize int, fetch func(context.Context, *Page) ([]T, error)) iter.Seq2[T, error] {
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	pageSize = min(pageSize, MaxPageSize)
+
+	return func(yield func(T, error) bool) {
+		for offset := 0; ; offset += pageSize {
+			items, err := fetch(ctx, &Page{Limit: pageSize, Offset: offset})
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+
+			if len(items) < pageSize {
+				return
+			}
+		}
+	}
+}
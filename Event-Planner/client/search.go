@@ -0,0 +1,35 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"net/url"
+)
+
+// SearchEvents returns a page of the events the current user organizes or
+// attends that match the filter; a nil page returns every match
+func (c *Client) SearchEvents(ctx context.Context, filter SearchFilter, page *Page) ([]AttendingEvent, error) {
+	q := url.Values{}
+	for key, value := range map[string]string{
+		"q":         filter.Query,
+		"date_from": filter.DateFrom,
+		"date_to":   filter.DateTo,
+		"role":      filter.Role,
+		"status":    filter.Status,
+	} {
+		if value != "" {
+			q.Set(key, value)
+		}
+	}
+
+	return getData[[]AttendingEvent](ctx, c, http.MethodGet, "/events/search", page.query(q), nil)
+}
+
+// Search iterates over the events matching the filter, fetching pageSize
+// events at a time (DefaultPageSize when zero)
+func (c *Client) Search(ctx context.Context, filter SearchFilter, pageSize int) iter.Seq2[AttendingEvent, error] {
+	return paginate(ctx, pageSize, func(ctx context.Context, page *Page) ([]AttendingEvent, error) {
+		return c.SearchEvents(ctx, filter, page)
+	})
+}
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"event-planner/internal/apperror"
+	"event-planner/internal/auth"
+	"event-planner/internal/config"
+	"event-planner/internal/db"
+	"event-planner/internal/event"
+	"event-planner/internal/invitation"
+	"event-planner/internal/migrate"
+	"event-planner/internal/user"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// connectDB connects to the database configured like the server's, from
+// the environment and CONFIG_FILE
+func connectDB(ctx context.Context) (*pgxpool.Pool, *config.Config, error) {
+	cfg, err := config.Load(nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pool, err := db.Connect(ctx, cfg.DB, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pool, cfg, nil
+}
+
+// withMigrator connects to the database and runs fn with a migrator for it
+func withMigrator(ctx context.Context, fn func(m *migrate.Migrator) error) error {
+	pool, _, err := connectDB(ctx)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	m, err := migrate.New(pool)
+	if err != nil {
+		return err
+	}
+	return fn(m)
+}
+
+func runMigrateUp(ctx context.Context, e *env, args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("usage: %s", e.command)
+	}
+
+	return withMigrator(ctx, func(m *migrate.Migrator) error {
+		applied, err := m.Up(ctx)
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			return e.printMessage("schema is up to date")
+		}
+		return e.printMessage("applied %s", migrationNames(applied))
+	})
+}
+
+func runMigrateDown(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("[-steps n]")
+	steps := fs.Int("steps", 1, "number of migrations to revert")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *steps < 1 {
+		return errors.New("-steps must be at least 1")
+	}
+
+	return withMigrator(ctx, func(m *migrate.Migrator) error {
+		reverted, err := m.Down(ctx, *steps)
+		if err != nil {
+			return err
+		}
+		if len(reverted) == 0 {
+			return e.printMessage("no migrations to revert")
+		}
+		return e.printMessage("reverted %s", migrationNames(reverted))
+	})
+}
+
+func runMigrateStatus(ctx context.Context, e *env, args []string) error {
+	return withMigrator(ctx, func(m *migrate.Migrator) error {
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		rows := make([][]string, len(statuses))
+		for i, s := range statuses {
+			applied := "pending"
+			if s.AppliedAt != nil {
+				applied = formatTime(s.AppliedAt)
+			}
+			rows[i] = []string{itoa(s.Version), s.Name, applied}
+		}
+		return e.render(statuses, []string{"VERSION", "NAME", "APPLIED"}, rows)
+	})
+}
+
+// runMigrateCheck fails when the database has drifted from the migrations,
+// listing the differences
+func runMigrateCheck(ctx context.Context, e *env, args []string) error {
+	return withMigrator(ctx, func(m *migrate.Migrator) error {
+		err := m.Check(ctx)
+
+		var drift *migrate.DriftError
+		if !errors.As(err, &drift) {
+			if err != nil {
+				return err
+			}
+			return e.printMessage("schema matches the migrations")
+		}
+
+		for _, problem := range drift.Problems {
+			fmt.Fprintln(e.stdout, problem)
+		}
+		return fmt.Errorf("schema has drifted (%d differences)", len(drift.Problems))
+	})
+}
+
+func migrationNames(migrations []migrate.Migration) string {
+	names := make([]string, len(migrations))
+	for i, mig := range migrations {
+		names[i] = mig.String()
+	}
+	return strings.Join(names, ", ")
+}
+
+// demoUsers are the accounts created by seed; the first one is an admin
+var demoUsers = []string{"admin@example.com", "alice@example.com", "bob@example.com", "carol@example.com"}
+
+// runSeed fills the database with demo accounts, events, attendance and
+// invitations through the services, so the data passes the usual validation.
+// Running it again reuses the accounts and skips the events.
+func runSeed(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("[-password p]")
+	password := fs.String("password", "password123", "password of the demo accounts")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	pool, cfg, err := connectDB(ctx)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	authService := auth.NewService(auth.NewRepository(pool), auth.Config{JWTSecret: cfg.Auth.JWTSecret, AdminEmails: cfg.Auth.AdminEmails}, nil, nil)
+	eventRepo := event.NewRepository(pool)
+	invService := invitation.NewService(invitation.NewRepository(pool), eventRepo, nil, nil)
+	eventService := event.NewService(eventRepo, invService, nil, nil, 0)
+
+	ids := map[string]int{}
+	for _, email := range demoUsers {
+		id, err := seedUser(ctx, authService, email, *password)
+		if err != nil {
+			return fmt.Errorf("failed to seed %s: %w", email, err)
+		}
+		ids[email] = id
+	}
+
+	query := `UPDATE users SET role = 'admin' WHERE email = $1`
+	if _, err := pool.Exec(ctx, query, demoUsers[0]); err != nil {
+		return fmt.Errorf("failed to promote %s: %w", demoUsers[0], err)
+	}
+
+	alice, bob, carol := ids["alice@example.com"], ids["bob@example.com"], ids["carol@example.com"]
+	existing, err := eventService.GetMyOrganizedEvents(ctx, alice)
+	if err != nil {
+		return err
+	}
+	if len(existing) > 0 {
+		return e.printMessage("demo accounts ready (password %q); events were already seeded", *password)
+	}
+
+	if err := seedEvents(ctx, eventService, invService, alice, bob, carol); err != nil {
+		return err
+	}
+	return e.printMessage("seeded %d accounts (password %q), 3 events, attendance and an invitation", len(demoUsers), *password)
+}
+
+// seedUser registers a demo account, or logs in when it exists, and returns its ID
+func seedUser(ctx context.Context, authService *auth.Service, email, password string) (int, error) {
+	resp, err := authService.Register(ctx, user.RegisterRequest{Email: email, Password: password})
+	if errors.Is(err, apperror.ErrConflict) {
+		resp, err = authService.Login(ctx, user.LoginRequest{Email: email, Password: password})
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	claims, err := authService.ParseToken(resp.Token)
+	if err != nil {
+		return 0, err
+	}
+	return claims.UserID, nil
+}
+
+func seedEvents(ctx context.Context, events *event.Service, invitations *invitation.Service, alice, bob, carol int) error {
+	day := func(days int) string {
+		return time.Now().AddDate(0, 0, days).Format("2006-01-02")
+	}
+
+	offsite, err := events.CreateEvent(ctx, &event.CreateEventRequest{
+		Title:       "Team Offsite",
+		Description: "Two days of planning, hiking and a barbecue",
+		Date:        day(30),
+		Time:        "09:00:00",
+		Location:    "Lakeside Lodge",
+	}, alice)
+	if err != nil {
+		return fmt.Errorf("failed to seed events: %w", err)
+	}
+
+	launch, err := events.CreateEvent(ctx, &event.CreateEventRequest{
+		Title:       "Product Launch",
+		Description: "Launch party for the new release",
+		Date:        day(45),
+		Time:        "18:30:00",
+		Location:    "Main Office, Rooftop",
+		Timezone:    "Europe/Berlin",
+	}, alice)
+	if err != nil {
+		return fmt.Errorf("failed to seed events: %w", err)
+	}
+
+	if _, err := events.CreateEvent(ctx, &event.CreateEventRequest{
+		Title:    "Book Club",
+		Date:     day(14),
+		Time:     "19:00:00",
+		Location: "City Library",
+	}, bob); err != nil {
+		return fmt.Errorf("failed to seed events: %w", err)
+	}
+
+	// Bob and Carol attend the offsite
+	for userID, status := range map[int]string{bob: "going", carol: "maybe"} {
+		if err := events.JoinEvent(ctx, userID, offsite.ID); err != nil {
+			return fmt.Errorf("failed to seed attendance: %w", err)
+		}
+		if err := events.UpdateAttendanceStatus(ctx, userID, offsite.ID, status); err != nil {
+			return fmt.Errorf("failed to seed attendance: %w", err)
+		}
+	}
+
+	// Carol is invited to the launch as a collaborator
+	if _, err := invitations.SendInvitation(ctx, &invitation.SendInvitationRequest{
+		EventID:      launch.ID,
+		InviteeEmail: "carol@example.com",
+		Role:         "collaborator",
+		Message:      "Could you help with the launch?",
+	}, alice); err != nil {
+		return fmt.Errorf("failed to seed invitations: %w", err)
+	}
+
+	return nil
+}
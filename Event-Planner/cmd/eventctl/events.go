@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"event-planner/client"
+)
+
+var eventHeader = []string{"ID", "TITLE", "DATE", "TIME", "TIMEZONE", "LOCATION", "ORGANIZER", "ORGANIZATION", "VISIBILITY", "STATUS"}
+
+func eventRow(ev client.Event) []string {
+	return []string{
+		itoa(ev.ID), ev.Title, ev.Date, ev.Time, ev.Timezone, ev.Location,
+		itoa(ev.OrganizerID), optional(ev.OrganizationID), ev.Visibility, ev.Status,
+	}
+}
+
+// listEvents collects the events visible to the user, or organized by them
+// with mine, stopping after limit events when positive
+func listEvents(ctx context.Context, c *client.Client, mine bool, limit int) ([]client.Event, error) {
+	if mine {
+		events, err := c.MyOrganizedEvents(ctx)
+		if limit > 0 && len(events) > limit {
+			events = events[:limit]
+		}
+		return events, err
+	}
+
+	events := []client.Event{}
+	for ev, err := range c.Events(ctx, 0) {
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, ev)
+		if limit > 0 && len(events) == limit {
+			break
+		}
+	}
+	return events, nil
+}
+
+func runEventsList(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("[-limit n] [-mine]")
+	limit := fs.Int("limit", 0, "show at most n events (0 = all)")
+	mine := fs.Bool("mine", false, "only the events you organize")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	c, err := e.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	events, err := listEvents(ctx, c, *mine, *limit)
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]string, len(events))
+	for i, ev := range events {
+		rows[i] = eventRow(ev)
+	}
+	return e.render(events, eventHeader, rows)
+}
+
+func runEventsGet(ctx context.Context, e *env, args []string) error {
+	id, err := parseID(e, args)
+	if err != nil {
+		return err
+	}
+
+	c, err := e.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	ev, err := c.GetEvent(ctx, id)
+	if err != nil {
+		return err
+	}
+	return e.render(ev, eventHeader, [][]string{eventRow(*ev)})
+}
+
+func runEventsCreate(ctx context.Context, e *env, args []string) error {
+	var req client.CreateEventRequest
+	fs := e.flags("-title t -date YYYY-MM-DD -time HH:MM:SS -location l [flags]")
+	fs.StringVar(&req.Title, "title", "", "title (required)")
+	fs.StringVar(&req.Description, "description", "", "description")
+	fs.StringVar(&req.Date, "date", "", "date as YYYY-MM-DD (required)")
+	fs.StringVar(&req.Time, "time", "", "start time as HH:MM:SS (required)")
+	fs.StringVar(&req.Location, "location", "", "location (required)")
+	fs.StringVar(&req.Visibility, "visibility", "", "public or organization (default: organization setting)")
+	fs.StringVar(&req.Timezone, "timezone", "", "IANA timezone (default: organization setting)")
+	fs.BoolVar(&req.Draft, "draft", false, "create a draft, visible to collaborators only until published")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	c, err := e.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	ev, err := c.CreateEvent(ctx, req)
+	if err != nil {
+		return err
+	}
+	return e.render(ev, eventHeader, [][]string{eventRow(*ev)})
+}
+
+func runEventsDelete(ctx context.Context, e *env, args []string) error {
+	id, err := parseID(e, args)
+	if err != nil {
+		return err
+	}
+
+	c, err := e.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	// The user names the event to delete, whatever changed since they looked at it
+	if err := c.DeleteEvent(ctx, id, client.AnyVersion); err != nil {
+		return err
+	}
+	return e.printMessage("event %d deleted", id)
+}
+
+func runEventsAttendees(ctx context.Context, e *env, args []string) error {
+	id, err := parseID(e, args)
+	if err != nil {
+		return err
+	}
+
+	c, err := e.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	attendees, err := c.Attendees(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]string, len(attendees))
+	for i, a := range attendees {
+		rows[i] = []string{itoa(a.UserID), a.User.DisplayName, a.Role, a.Status}
+	}
+	return e.render(attendees, []string{"USER", "NAME", "ROLE", "STATUS"}, rows)
+}
+
+// parseID reads the single ID argument of a command
+func parseID(e *env, args []string) (int, error) {
+	if len(args) != 1 {
+		return 0, fmt.Errorf("usage: %s <id>", e.command)
+	}
+
+	id, err := strconv.Atoi(args[0])
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid id %q", args[0])
+	}
+	return id, nil
+}
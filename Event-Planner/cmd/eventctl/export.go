@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"event-planner/client"
+)
+
+func runExport(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("[-format csv|json|ics] [-o file] [-mine]")
+	format := fs.String("format", "csv", "csv, json or ics")
+	out := fs.String("o", "-", "output file, - for standard output")
+	mine := fs.Bool("mine", false, "only the events you organize")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	write, ok := exporters[*format]
+	if !ok {
+		return fmt.Errorf("invalid -format %q: must be csv, json or ics", *format)
+	}
+
+	c, err := e.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	events, err := listEvents(ctx, c, *mine, 0)
+	if err != nil {
+		return err
+	}
+
+	if *out == "-" {
+		return write(e.stdout, events)
+	}
+
+	f, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	if err := write(f, events); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "exported %d events to %s\n", len(events), *out)
+	return nil
+}
+
+var exporters = map[string]func(io.Writer, []client.Event) error{
+	"csv":  writeCSV,
+	"json": writeJSON,
+	"ics":  writeICS,
+}
+
+func writeCSV(w io.Writer, events []client.Event) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"id", "title", "description", "date", "time", "timezone", "location", "organizer_id", "organization_id", "visibility", "status", "created_at"})
+	for _, ev := range events {
+		organizationID := ""
+		if ev.OrganizationID != nil {
+			organizationID = itoa(*ev.OrganizationID)
+		}
+		cw.Write([]string{
+			itoa(ev.ID), ev.Title, ev.Description, ev.Date, ev.Time, ev.Timezone, ev.Location,
+			itoa(ev.OrganizerID), organizationID, ev.Visibility, ev.Status, ev.CreatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func writeJSON(w io.Writer, events []client.Event) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(events)
+}
+
+// writeICS writes the events as an iCalendar (RFC 5545) calendar. Events have
+// no end time, so each one is a point in time in its own timezone.
+func writeICS(w io.Writer, events []client.Event) error {
+	bw := bufio.NewWriter(w)
+	line := func(s string) {
+		bw.WriteString(foldICS(s))
+		bw.WriteString("\r\n")
+	}
+
+	stamp := time.Now().UTC().Format("20060102T150405Z")
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//Event Planner//eventctl//EN")
+	line("CALSCALE:GREGORIAN")
+	for _, ev := range events {
+		start, err := ev.StartsAt()
+		if err != nil {
+			return fmt.Errorf("event %d: %w", ev.ID, err)
+		}
+
+		line("BEGIN:VEVENT")
+		line(fmt.Sprintf("UID:event-%d@event-planner", ev.ID))
+		line("DTSTAMP:" + stamp)
+		line("DTSTART:" + start.UTC().Format("20060102T150405Z"))
+		line("SUMMARY:" + escapeICS(ev.Title))
+		if ev.Description != "" {
+			line("DESCRIPTION:" + escapeICS(ev.Description))
+		}
+		line("LOCATION:" + escapeICS(ev.Location))
+		if ev.Visibility != client.VisibilityPublic {
+			line("CLASS:PRIVATE")
+		}
+		line("STATUS:" + icsStatus(ev.Status))
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+
+	return bw.Flush()
+}
+
+// icsStatus maps the status of an event to the iCalendar event statuses;
+// drafts are tentative until published and postponed events until they are
+// given a new date
+func icsStatus(status string) string {
+	switch status {
+	case client.EventCancelled:
+		return "CANCELLED"
+	case client.EventDraft, client.EventPostponed:
+		return "TENTATIVE"
+	}
+	return "CONFIRMED"
+}
+
+var icsEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+func escapeICS(s string) string {
+	return icsEscaper.Replace(s)
+}
+
+// foldICS splits content lines longer than 75 octets, without splitting UTF-8 characters
+func foldICS(s string) string {
+	var b strings.Builder
+	width := 0
+	for _, r := range s {
+		size := len(string(r))
+		if width+size > 75 {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	return b.String()
+}
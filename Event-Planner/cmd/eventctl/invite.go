@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"event-planner/client"
+)
+
+func runInvite(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("-event id (-email e | -group id [-new-members]) [-role r] [-message m]")
+	eventID := fs.Int("event", 0, "event to invite to (required)")
+	email := fs.String("email", "", "invite this person")
+	groupID := fs.Int("group", 0, "invite every member of this group")
+	role := fs.String("role", client.RoleAttendee, "attendee, collaborator or organizer")
+	message := fs.String("message", "", "message shown with the invitation")
+	newMembers := fs.Bool("new-members", false, "with -group, also invite people who join the group later")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *eventID <= 0 {
+		return errors.New("-event is required")
+	}
+	if (*email == "") == (*groupID == 0) {
+		return errors.New("exactly one of -email and -group is required")
+	}
+
+	c, err := e.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	header := []string{"ID", "EVENT", "INVITEE", "ROLE", "STATUS"}
+	row := func(inv client.Invitation) []string {
+		return []string{itoa(inv.ID), itoa(inv.EventID), inv.InviteeEmail, inv.Role, inv.Status}
+	}
+
+	if *groupID > 0 {
+		result, err := c.SendGroupInvitation(ctx, client.GroupInvitationRequest{
+			EventID:          *eventID,
+			GroupID:          *groupID,
+			Role:             *role,
+			Message:          *message,
+			InviteNewMembers: *newMembers,
+		})
+		if err != nil {
+			return err
+		}
+
+		rows := make([][]string, 0, len(result.Invitations)+len(result.Skipped))
+		for _, inv := range result.Invitations {
+			rows = append(rows, row(inv))
+		}
+		for _, skipped := range result.Skipped {
+			rows = append(rows, []string{"-", itoa(*eventID), skipped, *role, "already invited"})
+		}
+		return e.render(result, header, rows)
+	}
+
+	inv, err := c.SendInvitation(ctx, client.SendInvitationRequest{
+		EventID:      *eventID,
+		InviteeEmail: *email,
+		Role:         *role,
+		Message:      *message,
+	})
+	if err != nil {
+		return err
+	}
+	return e.render(inv, header, [][]string{row(*inv)})
+}
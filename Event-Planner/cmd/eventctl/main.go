@@ -0,0 +1,244 @@
+// Command eventctl administers an Event Planner deployment.
+//
+// Most commands call the API of a running server (-server, with -token or
+// -email and -password); migrate and seed connect directly to the database
+// configured with the DB_* variables or CONFIG_FILE, like the server does.
+//
+//	eventctl -email admin@example.com -password secret users list -disabled=true
+//	eventctl -token $TOKEN -output json events list
+//	eventctl -token $TOKEN export -format ics -o events.ics
+//	eventctl migrate up && eventctl seed
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+
+	"event-planner/client"
+
+	"github.com/joho/godotenv"
+)
+
+// command is a subcommand of eventctl; groups such as "events" have subcommands instead of run
+type command struct {
+	summary     string
+	usage       string // arguments after the command name
+	run         func(ctx context.Context, e *env, args []string) error
+	subcommands map[string]*command
+}
+
+var commands = map[string]*command{
+	"login": {
+		summary: "log in and print the token for -token / EVENTCTL_TOKEN",
+		run:     runLogin,
+	},
+	"events": {
+		summary: "list, show, create and delete events",
+		subcommands: map[string]*command{
+			"list":      {summary: "list the events visible to you", usage: "[-limit n] [-mine]", run: runEventsList},
+			"get":       {summary: "show an event", usage: "<id>", run: runEventsGet},
+			"create":    {summary: "create an event", usage: "-title t -date YYYY-MM-DD -time HH:MM:SS -location l [-description d] [-visibility v] [-timezone tz]", run: runEventsCreate},
+			"delete":    {summary: "delete an event you organize", usage: "<id>", run: runEventsDelete},
+			"attendees": {summary: "list the attendees of an event", usage: "<id>", run: runEventsAttendees},
+		},
+	},
+	"users": {
+		summary: "list and manage accounts (admin)",
+		subcommands: map[string]*command{
+			"list":    {summary: "list accounts", usage: "[-q text] [-role r] [-disabled true|false] [-limit n]", run: runUsersList},
+			"disable": {summary: "disable an account", usage: "[-reason text] <id>", run: runUsersDisable},
+			"enable":  {summary: "enable an account", usage: "<id>", run: runUsersEnable},
+			"role":    {summary: "change the system role of an account", usage: "<id> admin|support|member", run: runUsersRole},
+		},
+	},
+	"invite": {
+		summary: "invite a person or a group to an event",
+		usage:   "-event id (-email e | -group id [-new-members]) [-role r] [-message m]",
+		run:     runInvite,
+	},
+	"export": {
+		summary: "export the events visible to you as CSV, JSON or iCalendar",
+		usage:   "[-format csv|json|ics] [-o file] [-mine]",
+		run:     runExport,
+	},
+	"migrate": {
+		summary: "apply, revert and verify schema migrations (database)",
+		subcommands: map[string]*command{
+			"up":     {summary: "apply the pending migrations (database)", run: runMigrateUp},
+			"down":   {summary: "revert the latest migrations (database)", usage: "[-steps n]", run: runMigrateDown},
+			"status": {summary: "list the migrations and when they were applied (database)", run: runMigrateStatus},
+			"check":  {summary: "compare the database with the migrations (database)", run: runMigrateCheck},
+		},
+	},
+	"seed": {
+		summary: "create demo accounts, events and invitations (database)",
+		usage:   "[-password p]",
+		run:     runSeed,
+	},
+}
+
+func main() {
+	_ = godotenv.Load()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := run(ctx, os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(os.Stderr, "eventctl:", err)
+		}
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	e := &env{stdout: stdout}
+
+	fs := flag.NewFlagSet("eventctl", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&e.server, "server", envOr("EVENTCTL_SERVER", "http://localhost:8080"), "API base URL (EVENTCTL_SERVER)")
+	fs.StringVar(&e.token, "token", os.Getenv("EVENTCTL_TOKEN"), "bearer token (EVENTCTL_TOKEN)")
+	fs.StringVar(&e.email, "email", os.Getenv("EVENTCTL_EMAIL"), "log in with this email (EVENTCTL_EMAIL)")
+	fs.StringVar(&e.password, "password", os.Getenv("EVENTCTL_PASSWORD"), "password for -email (EVENTCTL_PASSWORD)")
+	fs.IntVar(&e.organization, "org", -1, "organization to operate in (0 = personal)")
+	fs.StringVar(&e.output, "output", "table", "output format: table or json")
+	fs.Usage = func() { usage(fs) }
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if e.output != "table" && e.output != "json" {
+		return fmt.Errorf("invalid -output %q: must be table or json", e.output)
+	}
+
+	args = fs.Args()
+	name := "eventctl"
+	cmd := &command{subcommands: commands}
+	for cmd.subcommands != nil {
+		if len(args) == 0 {
+			usage(fs)
+			return flag.ErrHelp
+		}
+
+		next, ok := cmd.subcommands[args[0]]
+		if !ok {
+			return fmt.Errorf("unknown command %q, see eventctl -help", strings.TrimPrefix(name+" "+args[0], "eventctl "))
+		}
+		name += " " + args[0]
+		cmd, args = next, args[1:]
+	}
+
+	e.command = name
+	return cmd.run(ctx, e, args)
+}
+
+func usage(fs *flag.FlagSet) {
+	w := fs.Output()
+	fmt.Fprintln(w, "Usage: eventctl [flags] <command> [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, name := range sortedKeys(commands) {
+		cmd := commands[name]
+		if cmd.subcommands == nil {
+			fmt.Fprintf(w, "  %-18s %s\n", name, cmd.summary)
+			continue
+		}
+		for _, sub := range sortedKeys(cmd.subcommands) {
+			subcmd := cmd.subcommands[sub]
+			fmt.Fprintf(w, "  %-18s %s\n", name+" "+sub, subcmd.summary)
+		}
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run eventctl <command> -h for the arguments of a command.")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Flags:")
+	fs.PrintDefaults()
+}
+
+func sortedKeys(m map[string]*command) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// env holds the global flags shared by the commands
+type env struct {
+	command      string // full command name, e.g. "eventctl users list"
+	server       string
+	token        string
+	email        string
+	password     string
+	organization int // -1 when not set
+	output       string
+	stdout       io.Writer
+}
+
+// flags returns the flag set of the running command
+func (e *env) flags(usage string) *flag.FlagSet {
+	fs := flag.NewFlagSet(e.command, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s %s\n", e.command, usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// client returns an API client, logged in with -email and -password when given
+func (e *env) client(ctx context.Context) (*client.Client, error) {
+	var opts []client.Option
+	if e.token != "" {
+		opts = append(opts, client.WithToken(e.token))
+	}
+	if e.organization >= 0 {
+		opts = append(opts, client.WithOrganization(e.organization))
+	}
+
+	c, err := client.New(e.server, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if e.email != "" {
+		if err := c.Login(ctx, e.email, e.password); err != nil {
+			return nil, fmt.Errorf("login failed: %w", err)
+		}
+	} else if e.token == "" {
+		return nil, errors.New("not logged in: use -token or -email and -password")
+	}
+
+	return c, nil
+}
+
+func runLogin(ctx context.Context, e *env, args []string) error {
+	if e.email == "" {
+		return errors.New("login requires -email and -password")
+	}
+
+	c, err := e.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	if e.output == "json" {
+		return e.printJSON(map[string]string{"token": c.Token()})
+	}
+	_, err = fmt.Fprintln(e.stdout, c.Token())
+	return err
+}
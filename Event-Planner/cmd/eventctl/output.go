@@ -0,0 +1,62 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// render prints v as JSON with -output json, and as a table of rows otherwise
+func (e *env) render(v any, header []string, rows [][]string) error {
+	if e.output == "json" {
+		return e.printJSON(v)
+	}
+	return e.printTable(header, rows)
+}
+
+func (e *env) printJSON(v any) error {
+	enc := json.NewEncoder(e.stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func (e *env) printTable(header []string, rows [][]string) error {
+	w := tabwriter.NewWriter(e.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	return w.Flush()
+}
+
+// printMessage reports the outcome of a command without data
+func (e *env) printMessage(format string, args ...any) error {
+	message := fmt.Sprintf(format, args...)
+	if e.output == "json" {
+		return e.printJSON(map[string]string{"message": message})
+	}
+	_, err := fmt.Fprintln(e.stdout, message)
+	return err
+}
+
+func itoa(n int) string {
+	return strconv.Itoa(n)
+}
+
+// optional formats a nullable ID, "-" when unset
+func optional(n *int) string {
+	if n == nil {
+		return "-"
+	}
+	return strconv.Itoa(*n)
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
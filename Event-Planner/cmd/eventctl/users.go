@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"event-planner/client"
+)
+
+var userHeader = []string{"ID", "EMAIL", "ROLE", "DISABLED", "CREATED"}
+
+func userRow(u client.User) []string {
+	return []string{itoa(u.ID), u.Email, u.Role, formatTime(u.DisabledAt), formatTime(&u.CreatedAt)}
+}
+
+func runUsersList(ctx context.Context, e *env, args []string) error {
+	var filter client.UsersFilter
+	fs := e.flags("[-q text] [-role r] [-disabled true|false] [-limit n]")
+	fs.StringVar(&filter.Query, "q", "", "email substring")
+	fs.StringVar(&filter.Role, "role", "", "admin, support or member")
+	disabled := fs.String("disabled", "", "true for disabled accounts only, false for active ones")
+	limit := fs.Int("limit", 0, "show at most n accounts (0 = all)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *disabled != "" {
+		v, err := strconv.ParseBool(*disabled)
+		if err != nil {
+			return fmt.Errorf("invalid -disabled %q", *disabled)
+		}
+		filter.Disabled = &v
+	}
+
+	c, err := e.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	users := []client.User{}
+	for u, err := range c.Users(ctx, filter, 0) {
+		if err != nil {
+			return err
+		}
+		users = append(users, u)
+		if *limit > 0 && len(users) == *limit {
+			break
+		}
+	}
+
+	rows := make([][]string, len(users))
+	for i, u := range users {
+		rows[i] = userRow(u)
+	}
+	return e.render(users, userHeader, rows)
+}
+
+func runUsersDisable(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("[-reason text] <id>")
+	reason := fs.String("reason", "", "reason recorded in the admin log")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	id, err := parseID(e, fs.Args())
+	if err != nil {
+		return err
+	}
+
+	c, err := e.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	u, err := c.DisableUser(ctx, id, *reason)
+	if err != nil {
+		return err
+	}
+	return e.render(u, userHeader, [][]string{userRow(*u)})
+}
+
+func runUsersEnable(ctx context.Context, e *env, args []string) error {
+	id, err := parseID(e, args)
+	if err != nil {
+		return err
+	}
+
+	c, err := e.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	u, err := c.EnableUser(ctx, id)
+	if err != nil {
+		return err
+	}
+	return e.render(u, userHeader, [][]string{userRow(*u)})
+}
+
+func runUsersRole(ctx context.Context, e *env, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: %s <id> admin|support|member", e.command)
+	}
+
+	id, err := parseID(e, args[:1])
+	if err != nil {
+		return err
+	}
+
+	c, err := e.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	u, err := c.SetUserRole(ctx, id, args[1])
+	if err != nil {
+		return err
+	}
+	return e.render(u, userHeader, [][]string{userRow(*u)})
+}
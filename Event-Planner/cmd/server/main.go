@@ -1,168 +1,188 @@
 package main
 
 import (
-	"encoding/json"
-	"log"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
 	"net/http"
-
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+	_ "time/tzdata" // profile timezones must validate without system tzdata
+
+	"event-planner/internal/app"
 	"event-planner/internal/auth"
+	"event-planner/internal/config"
 	"event-planner/internal/db"
-	"event-planner/internal/event"
-	"event-planner/internal/invitation"
-	"event-planner/internal/search"
+	"event-planner/internal/logging"
+	"event-planner/internal/metrics"
+	"event-planner/internal/migrate"
+	"event-planner/internal/tracing"
 
-	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/chi/v5/middleware"
-	"github.com/go-chi/cors"
+	"github.com/jackc/pgx/v5/multitracer"
 	"github.com/joho/godotenv"
 )
 
 func main() {
 	_ = godotenv.Load()
 
-	// Connect to PostgreSQL
-	pool, err := db.ConnectDB()
+	cfg, err := config.Load(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
 	if err != nil {
-		log.Fatal(err)
+		fmt.Fprintf(os.Stderr, "Invalid configuration:\n%v\n", err)
+		os.Exit(1)
 	}
-	defer pool.Close()
-
-	//User Management
-	authService := auth.NewService(pool)
-	authHandler := auth.NewHandler(authService)
-
-	//Event Management
-	eventRepo := event.NewRepository(pool)
-	eventService := event.NewService(eventRepo)
-	eventHandler := event.NewHandler(eventService)
-
-	//Response Management / Invitations
-	invRepo := invitation.NewRepository(pool)
-	invService := invitation.NewService(invRepo, eventRepo)
-	invHandler := invitation.NewHandler(invService)
-
-	// search & Filtering
-	searchRepo := search.NewRepository(pool)
-	searchService := search.NewService(searchRepo)
-	searchHandler := search.NewHandler(searchService)
-
-	// Setup router
-	r := chi.NewRouter()
-
-	// Global middleware
-	r.Use(middleware.Logger)
-	r.Use(middleware.Recoverer)
-	r.Use(middleware.RequestID)
-
-	r.Use(cors.Handler(cors.Options{
-		AllowedOrigins: []string{
-			"http://localhost:4200",
-			"http://127.0.0.1:4200"},
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:   []string{"Link"},
-		AllowCredentials: true,
-		MaxAge:           300,
-	}))
-
-	// Health check
-	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
-		_, _ = w.Write([]byte("Server is running"))
-	})
-
-	// Auth routes
-	r.Route("/auth", func(r chi.Router) {
-		r.Post("/register", authHandler.Register)
-		r.Post("/login", authHandler.Login)
-	})
-
-	// Events routes
-	r.Route("/events", func(r chi.Router) {
-		// Public endpoints (no auth required)
-
-		// GET all events
-		r.Get("/", eventHandler.GetAllEvents)
-
-		// Advanced search 
-		r.With(authHandler.AuthMiddleware).Get("/search", searchHandler.SearchEvents)
 
-		// GET events by organizer
-		r.Get("/organizer/{id}", eventHandler.GetEventsByOrganizer)
-
-		// GET single event by ID (public)
-		r.Get("/{id}", eventHandler.GetEventByID)
-
-		// GET event attendees
-		r.Get("/{id}/attendees", eventHandler.GetEventAttendees)
-
-		// GET invitations for an event
-		r.With(authHandler.AuthMiddleware).Get("/{id}/invitations", invHandler.GetEventInvitations)
-
-		// POST create new event
-		r.With(authHandler.AuthMiddleware).Post("/", eventHandler.CreateEvent)
+	// Structured logs; the standard logger of libraries writes through them too
+	logger, err := logging.New(os.Stderr, cfg.Log)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	slog.SetDefault(logger)
 
-		// PUT update event
-		r.With(authHandler.AuthMiddleware).Put("/{id}", eventHandler.UpdateEvent)
+	var settings []any
+	for _, line := range cfg.Redacted() {
+		key, value, _ := strings.Cut(line, "=")
+		settings = append(settings, slog.String(key, value))
+	}
+	slog.Info("configuration", "env", cfg.Env, slog.Group("settings", settings...))
+	for _, warning := range cfg.Warnings {
+		slog.Warn(warning)
+	}
 
-		// DELETE event
-		r.With(authHandler.AuthMiddleware).Delete("/{id}", eventHandler.DeleteEvent)
+	// Traces of the requests, services and SQL statements
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
+	if err != nil {
+		fatal("failed to set up tracing", err)
+	}
 
-		// POST join event
-		r.With(authHandler.AuthMiddleware).Post("/{id}/join", eventHandler.JoinEvent)
+	// Metrics of the requests, the database and business activity, served on /metrics
+	m := metrics.New()
 
-		// POST invite user to event
-		r.With(authHandler.AuthMiddleware).Post("/{id}/invite", eventHandler.InviteUserToEvent)
+	// Connect to PostgreSQL
+	pool, err := db.Connect(context.Background(), cfg.DB, multitracer.New(tracing.QueryTracer(), m.QueryTracer()))
+	if err != nil {
+		fatal("failed to connect to the database", err)
+	}
+	defer pool.Close()
 
-		// PUT update attendance status
-		r.With(authHandler.AuthMiddleware).Put("/{id}/attendance", eventHandler.UpdateAttendanceStatus)
+	// Bring the schema up to date, or with DB_AUTO_MIGRATE=false only verify
+	// it; either way refuse to start on a schema that has drifted
+	migrator, err := migrate.New(pool)
+	if err != nil {
+		fatal("failed to load migrations", err)
+	}
+	if cfg.DB.AutoMigrate {
+		applied, err := migrator.Up(context.Background())
+		if err != nil {
+			fatal("failed to migrate the database", err)
+		}
+		for _, mig := range applied {
+			slog.Info("applied migration", "migration", mig.String())
+		}
+	}
+	if err := migrator.Check(context.Background()); err != nil {
+		fatal("database schema check failed", err)
+	}
 
-		r.Route("/my", func(r chi.Router) {
-			r.Use(authHandler.AuthMiddleware)
+	application, err := app.New(pool, app.Options{
+		Auth:                auth.Config{JWTSecret: cfg.Auth.JWTSecret, AdminEmails: cfg.Auth.AdminEmails},
+		CORSOrigins:         cfg.HTTP.CORSOrigins,
+		AvatarDir:           cfg.AvatarDir,
+		DeletionGracePeriod: time.Duration(cfg.DeletionGraceDays) * 24 * time.Hour,
+		TrashRetention:      time.Duration(cfg.TrashRetentionDays) * 24 * time.Hour,
+		Metrics:             m,
+	})
+	if err != nil {
+		fatal("failed to set up the API", err)
+	}
 
-			// GET events I'm attending
-			r.Get("/attending", eventHandler.GetMyAttendingEvents)
+	// Accounts listed in ADMIN_EMAILS are granted the admin role
+	if err := application.PromoteBootstrapAdmins(context.Background()); err != nil {
+		fatal("failed to promote bootstrap admins", err)
+	}
 
-			// GET events I'm organizing
-			r.Get("/organized", eventHandler.GetMyOrganizedEvents)
-		})
-	})
+	// SIGTERM (Kubernetes) or Ctrl-C starts the shutdown; a second one
+	// stops the process immediately
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	workersDone := make(chan struct{})
+	go func() {
+		application.RunWorkers(workersCtx)
+		close(workersDone)
+	}()
+
+	srv := &http.Server{
+		Addr:              cfg.HTTP.Addr,
+		Handler:           application.Handler(),
+		ReadHeaderTimeout: cfg.HTTP.ReadHeaderTimeout,
+		ReadTimeout:       cfg.HTTP.ReadTimeout,
+		WriteTimeout:      cfg.HTTP.WriteTimeout,
+		IdleTimeout:       cfg.HTTP.IdleTimeout,
+		ErrorLog:          slog.NewLogLogger(logger.Handler(), slog.LevelWarn),
+	}
 
-	// Invitation routes
-	r.Route("/invitations", func(r chi.Router) {
-		r.Use(authHandler.AuthMiddleware)
+	serveErr := make(chan error, 1)
+	go func() {
+		if cfg.HTTP.TLSCert != "" {
+			slog.Info("server started", "addr", cfg.HTTP.Addr, "tls", true)
+			serveErr <- srv.ListenAndServeTLS(cfg.HTTP.TLSCert, cfg.HTTP.TLSKey)
+			return
+		}
+		slog.Info("server started", "addr", cfg.HTTP.Addr, "tls", false)
+		serveErr <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		fatal("HTTP server failed", err)
+	case <-ctx.Done():
+	}
+	stop()
 
-		// Send invitation
-		r.Post("/", invHandler.SendInvitation)
+	// Fail /readyz and keep serving for a moment, so the load balancer stops
+	// routing new requests here before the listener closes
+	slog.Info("shutting down", "delay", cfg.HTTP.ShutdownDelay)
+	application.Drain()
+	time.Sleep(cfg.HTTP.ShutdownDelay)
 
-		// Get my invitations
-		r.Get("/my", invHandler.GetMyInvitations)
+	// Wait for in-flight requests and the background workers
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.HTTP.ShutdownTimeout)
+	defer cancel()
 
-		// Respond to invitation
-		r.Put("/{id}/respond", invHandler.RespondToInvitation)
-	})
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		slog.Error("HTTP server shutdown failed", logging.Err(err))
+	}
+	if err := <-serveErr; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		slog.Error("HTTP server failed", logging.Err(err))
+	}
 
-	r.Route("/api", func(r chi.Router) {
-		r.Use(authHandler.AuthMiddleware)
-
-		r.Get("/profile", func(w http.ResponseWriter, r *http.Request) {
-			userID, ok := auth.GetUserID(r.Context())
-			if !ok {
-				http.Error(w, "User not found", http.StatusUnauthorized)
-				return
-			}
-
-			resp := map[string]interface{}{
-				"message": "This is a protected route",
-				"user_id": userID,
-			}
-			w.Header().Set("Content-Type", "application/json")
-			_ = json.NewEncoder(w).Encode(resp)
-		})
-	})
+	stopWorkers()
+	select {
+	case <-workersDone:
+	case <-shutdownCtx.Done():
+		slog.Warn("background workers did not stop in time")
+	}
 
-	log.Println("Server started on :8080")
-	if err := http.ListenAndServe(":8080", r); err != nil {
-		log.Fatal(err)
+	// Export the spans still buffered
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		slog.Error("tracing shutdown failed", logging.Err(err))
 	}
+
+	slog.Info("server stopped")
 }
 
+// fatal logs the error that prevents the server from running and exits
+func fatal(msg string, err error) {
+	slog.Error(msg, logging.Err(err))
+	os.Exit(1)
+}
@@ -0,0 +1,49 @@
+package account
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// WriteArchive writes an export as a zip archive with one JSON document per section
+func WriteArchive(w io.Writer, export *Export) error {
+	files := []struct {
+		name string
+		data interface{}
+	}{
+		{"account.json", map[string]interface{}{
+			"generated_at": export.GeneratedAt,
+			"account":      export.Account,
+			"deletion":     export.Deletion,
+		}},
+		{"profile.json", export.Profile},
+		{"organized_events.json", export.OrganizedEvents},
+		{"rsvps.json", export.RSVPs},
+		{"invitations_received.json", export.InvitationsReceived},
+		{"invitations_sent.json", export.InvitationsSent},
+		{"groups.json", export.Groups},
+		{"organizations.json", export.Organizations},
+	}
+
+	archive := zip.NewWriter(w)
+	for _, file := range files {
+		f, err := archive.CreateHeader(&zip.FileHeader{
+			Name:     file.name,
+			Method:   zip.Deflate,
+			Modified: export.GeneratedAt,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add %s: %w", file.name, err)
+		}
+
+		enc := json.NewEncoder(f)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(file.data); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file.name, err)
+		}
+	}
+
+	return archive.Close()
+}
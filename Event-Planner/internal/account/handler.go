@@ -0,0 +1,131 @@
+package account
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"event-planner/internal/apperror"
+	"event-planner/internal/auth"
+	"event-planner/internal/logging"
+	"event-planner/internal/response"
+)
+
+// Handler handles HTTP requests for account deletion and data export
+type Handler struct {
+	service *Service
+}
+
+// NewHandler creates a new account handler
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// RequestDeletion handles POST /users/me/deletion
+func (h *Handler) RequestDeletion(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, apperror.ErrUnauthorized)
+		return
+	}
+
+	var req DeleteAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, apperror.Validation("body", "invalid request body"))
+		return
+	}
+
+	status, err := h.service.RequestDeletion(r.Context(), userID, &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "account deletion scheduled",
+		"data":    status,
+	})
+}
+
+// GetDeletionStatus handles GET /users/me/deletion
+func (h *Handler) GetDeletionStatus(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, apperror.ErrUnauthorized)
+		return
+	}
+
+	status, err := h.service.GetDeletionStatus(r.Context(), userID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data": status,
+	})
+}
+
+// CancelDeletion handles DELETE /users/me/deletion
+func (h *Handler) CancelDeletion(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, apperror.ErrUnauthorized)
+		return
+	}
+
+	if err := h.service.CancelDeletion(r.Context(), userID); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "account deletion cancelled",
+	})
+}
+
+// ExportData handles GET /users/me/export (?format=zip|json, zip by default)
+func (h *Handler) ExportData(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, apperror.ErrUnauthorized)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "zip" && format != "json" {
+		response.Error(w, apperror.Validation("format", "invalid format: must be 'zip' or 'json'"))
+		return
+	}
+
+	export, err := h.service.ExportData(r.Context(), userID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	filename := fmt.Sprintf("event-planner-export-%d-%s", userID, export.GeneratedAt.Format("20060102"))
+
+	if format == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.json"`, filename))
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(export)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.zip"`, filename))
+	w.WriteHeader(http.StatusOK)
+	if err := WriteArchive(w, export); err != nil {
+		// Headers are already sent, the client sees a truncated archive
+		slog.ErrorContext(r.Context(), "failed to write export", logging.Err(err))
+	}
+}
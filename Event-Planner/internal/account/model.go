@@ -0,0 +1,62 @@
+package account
+
+import (
+	"fmt"
+	"time"
+
+	"event-planner/internal/event"
+	"event-planner/internal/invitation"
+	"event-planner/internal/organization"
+	"event-planner/internal/profile"
+	"event-planner/internal/user"
+)
+
+// DefaultGracePeriod is how long a requested deletion can still be cancelled
+const DefaultGracePeriod = 30 * 24 * time.Hour
+
+// DeletionStatus describes a pending account deletion
+type DeletionStatus struct {
+	Pending      bool       `json:"pending"`
+	RequestedAt  *time.Time `json:"requested_at,omitempty"`
+	ScheduledFor *time.Time `json:"scheduled_for,omitempty"` // end of the grace period
+}
+
+// DeleteAccountRequest is the request payload for requesting an account deletion
+type DeleteAccountRequest struct {
+	Password string `json:"password" binding:"required"` // re-authentication
+}
+
+// ErasureResult summarizes what happened to the data of an erased account
+type ErasureResult struct {
+	UserID            int   `json:"user_id"`
+	TransferredEvents []int `json:"transferred_events"` // handed over to a co-organizer or collaborator
+	ArchivedEvents    []int `json:"archived_events"`    // kept read-only without an organizer
+}
+
+// Export is the machine-readable copy of everything stored about a user
+type Export struct {
+	GeneratedAt         time.Time                     `json:"generated_at"`
+	Account             user.User                     `json:"account"`
+	Deletion            DeletionStatus                `json:"deletion"`
+	Profile             *profile.Profile              `json:"profile"`
+	OrganizedEvents     []event.Event                 `json:"organized_events"`
+	RSVPs               []event.EventWithAttendeeInfo `json:"rsvps"`
+	InvitationsReceived []invitation.Invitation       `json:"invitations_received"`
+	InvitationsSent     []invitation.Invitation       `json:"invitations_sent"`
+	Groups              []ExportGroup                 `json:"groups"`
+	Organizations       []organization.Membership     `json:"organizations"`
+}
+
+// ExportGroup is a group owned by the user with its member emails
+type ExportGroup struct {
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Members     []string  `json:"members"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// pseudonym replaces the email of an erased account everywhere it is stored
+func pseudonym(userID int) string {
+	return fmt.Sprintf("deleted-user-%d@deleted.invalid", userID)
+}
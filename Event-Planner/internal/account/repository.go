@@ -0,0 +1,405 @@
+package account
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"event-planner/internal/apperror"
+	"event-planner/internal/db"
+	"event-planner/internal/event"
+	"event-planner/internal/invitation"
+	"event-planner/internal/user"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ErrUserNotFound is returned for missing and already erased accounts
+var ErrUserNotFound = apperror.NotFound("user_not_found", "user not found")
+
+// Repository handles all database operations for account deletion and data export
+type Repository struct {
+	db *pgxpool.Pool
+}
+
+// NewRepository creates a new account repository
+func NewRepository(db *pgxpool.Pool) *Repository {
+	return &Repository{db: db}
+}
+
+// GetUser retrieves an account that has not been erased
+func (r *Repository) GetUser(ctx context.Context, userID int) (*user.User, error) {
+	query := `
+		SELECT id, email, role, disabled_at, created_at
+		FROM users
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	u := &user.User{}
+	err := r.db.QueryRow(ctx, query, userID).Scan(&u.ID, &u.Email, &u.Role, &u.DisabledAt, &u.CreatedAt)
+	if err != nil {
+		return nil, db.TranslateError(fmt.Errorf("failed to get user: %w", err), ErrUserNotFound)
+	}
+
+	return u, nil
+}
+
+// GetDeletionStatus retrieves the pending deletion of an account, if any
+func (r *Repository) GetDeletionStatus(ctx context.Context, userID int) (*DeletionStatus, error) {
+	query := `SELECT deletion_requested_at, deletion_scheduled_for FROM users WHERE id = $1 AND deleted_at IS NULL`
+
+	status := &DeletionStatus{}
+	if err := r.db.QueryRow(ctx, query, userID).Scan(&status.RequestedAt, &status.ScheduledFor); err != nil {
+		return nil, db.TranslateError(fmt.Errorf("failed to get deletion status: %w", err), ErrUserNotFound)
+	}
+	status.Pending = status.ScheduledFor != nil
+
+	return status, nil
+}
+
+// ScheduleDeletion marks an account for erasure at scheduledFor
+func (r *Repository) ScheduleDeletion(ctx context.Context, userID int, scheduledFor time.Time) error {
+	query := `
+		UPDATE users
+		SET deletion_requested_at = NOW(), deletion_scheduled_for = $2
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	result, err := r.db.Exec(ctx, query, userID, scheduledFor)
+	if err != nil {
+		return db.TranslateError(fmt.Errorf("failed to schedule deletion: %w", err), nil)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
+// CancelDeletion clears a pending deletion
+func (r *Repository) CancelDeletion(ctx context.Context, userID int) (bool, error) {
+	query := `
+		UPDATE users
+		SET deletion_requested_at = NULL, deletion_scheduled_for = NULL
+		WHERE id = $1 AND deleted_at IS NULL AND deletion_scheduled_for IS NOT NULL
+	`
+
+	result, err := r.db.Exec(ctx, query, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to cancel deletion: %w", err)
+	}
+
+	return result.RowsAffected() > 0, nil
+}
+
+// GetDueDeletions returns the accounts whose grace period ended before now
+func (r *Repository) GetDueDeletions(ctx context.Context, now time.Time) ([]int, error) {
+	query := `
+		SELECT id FROM users
+		WHERE deleted_at IS NULL AND deletion_scheduled_for <= $1
+		ORDER BY deletion_scheduled_for
+	`
+
+	rows, err := r.db.Query(ctx, query, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due deletions: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan user ID: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating due deletions: %w", err)
+	}
+
+	return ids, nil
+}
+
+// EraseUser removes the personal data of an account in a single transaction.
+// The users row is kept as a pseudonymous tombstone so that events, invitations
+// and audit records referencing it survive; nothing is cascaded.
+func (r *Repository) EraseUser(ctx context.Context, userID int) (*ErasureResult, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var email string
+	err = tx.QueryRow(ctx, `SELECT email FROM users WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, userID).Scan(&email)
+	if err != nil {
+		return nil, db.TranslateError(fmt.Errorf("failed to lock user: %w", err), ErrUserNotFound)
+	}
+
+	result := &ErasureResult{UserID: userID}
+
+	// Hand organized events over to the longest-standing co-organizer, or else
+	// collaborator; events in the trash stay with the account until they are purged
+	transferQuery := `
+		UPDATE events e
+		SET organizer_id = s.user_id, version = e.version + 1
+		FROM (
+			SELECT DISTINCT ON (a.event_id) a.event_id, a.user_id
+			FROM event_attendees a
+			JOIN users u ON u.id = a.user_id AND u.deleted_at IS NULL
+			WHERE a.user_id <> $1 AND a.role IN ('organizer', 'collaborator')
+			ORDER BY a.event_id, a.role = 'organizer' DESC, a.created_at, a.id
+		) s
+		WHERE e.id = s.event_id AND e.organizer_id = $1 AND e.deleted_at IS NULL
+		RETURNING e.id
+	`
+	if result.TransferredEvents, err = collectIDs(tx.Query(ctx, transferQuery, userID)); err != nil {
+		return nil, fmt.Errorf("failed to transfer events: %w", err)
+	}
+
+	promoteQuery := `
+		UPDATE event_attendees a
+		SET role = 'organizer'
+		FROM events e
+		WHERE e.id = ANY($1) AND a.event_id = e.id AND a.user_id = e.organizer_id
+	`
+	if _, err := tx.Exec(ctx, promoteQuery, result.TransferredEvents); err != nil {
+		return nil, fmt.Errorf("failed to promote new organizers: %w", err)
+	}
+
+	// Events nobody can take over stay available read-only
+	archiveQuery := `
+		UPDATE events SET archived_at = NOW(), version = version + 1
+		WHERE organizer_id = $1 AND archived_at IS NULL
+		RETURNING id
+	`
+	if result.ArchivedEvents, err = collectIDs(tx.Query(ctx, archiveQuery, userID)); err != nil {
+		return nil, fmt.Errorf("failed to archive events: %w", err)
+	}
+
+	// Organizations the user solely owns get a new owner, preferring admins
+	ownerQuery := `
+		UPDATE organization_members m
+		SET role = 'owner'
+		FROM (
+			SELECT DISTINCT ON (o.organization_id) o.organization_id, o.user_id
+			FROM organization_members o
+			WHERE o.user_id <> $1
+				AND o.organization_id IN (
+					SELECT organization_id FROM organization_members WHERE user_id = $1 AND role = 'owner'
+				)
+				AND NOT EXISTS (
+					SELECT 1 FROM organization_members x
+					WHERE x.organization_id = o.organization_id AND x.role = 'owner' AND x.user_id <> $1
+				)
+			ORDER BY o.organization_id, o.role = 'admin' DESC, o.created_at
+		) s
+		WHERE m.organization_id = s.organization_id AND m.user_id = s.user_id
+	`
+
+	pseudo := pseudonym(userID)
+	statements := []struct {
+		query string
+		args  []interface{}
+		what  string
+	}{
+		{ownerQuery, []interface{}{userID}, "transfer organization ownership"},
+		{`DELETE FROM organization_members WHERE user_id = $1`, []interface{}{userID}, "remove memberships"},
+		{`DELETE FROM event_attendees WHERE user_id = $1`, []interface{}{userID}, "remove attendance"},
+		{`UPDATE invitations SET invitee_email = $2 WHERE invitee_id = $1 OR lower(invitee_email) = lower($3)`,
+			[]interface{}{userID, pseudo, email}, "pseudonymize invitations"},
+		{`DELETE FROM group_members WHERE lower(email) = lower($1)`, []interface{}{email}, "remove group memberships"},
+		{`DELETE FROM notifications WHERE lower(recipient_email) = lower($1)`, []interface{}{email}, "remove notifications"},
+		{`DELETE FROM user_groups WHERE owner_id = $1`, []interface{}{userID}, "remove groups"},
+		{`DELETE FROM user_profiles WHERE user_id = $1`, []interface{}{userID}, "remove profile"},
+		{`
+			UPDATE users
+			SET email = $2, password_hash = '', disabled_at = COALESCE(disabled_at, NOW()), deleted_at = NOW(),
+				deletion_requested_at = NULL, deletion_scheduled_for = NULL
+			WHERE id = $1
+		`, []interface{}{userID, pseudo}, "pseudonymize user"},
+	}
+
+	for _, stmt := range statements {
+		if _, err := tx.Exec(ctx, stmt.query, stmt.args...); err != nil {
+			return nil, fmt.Errorf("failed to %s: %w", stmt.what, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit erasure: %w", err)
+	}
+
+	return result, nil
+}
+
+// GetOrganizedEvents retrieves every event organized by a user, regardless of organization
+func (r *Repository) GetOrganizedEvents(ctx context.Context, userID int) ([]event.Event, error) {
+	query := `
+		SELECT ` + event.EventColumns + `
+		FROM events e
+		WHERE e.organizer_id = $1
+		ORDER BY e.date, e.time
+	`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get organized events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []event.Event{}
+	for rows.Next() {
+		var e event.Event
+		if err := event.ScanEvent(rows, &e); err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", err)
+		}
+		events = append(events, e)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating events: %w", err)
+	}
+
+	return events, nil
+}
+
+// GetRSVPs retrieves every event a user is attending, with role and status
+func (r *Repository) GetRSVPs(ctx context.Context, userID int) ([]event.EventWithAttendeeInfo, error) {
+	query := `
+		SELECT ` + event.EventColumns + `, a.role, a.status
+		FROM events e
+		JOIN event_attendees a ON a.event_id = e.id
+		WHERE a.user_id = $1
+		ORDER BY e.date, e.time
+	`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get RSVPs: %w", err)
+	}
+	defer rows.Close()
+
+	rsvps := []event.EventWithAttendeeInfo{}
+	for rows.Next() {
+		var e event.EventWithAttendeeInfo
+		if err := event.ScanEvent(rows, &e.Event, &e.Role, &e.Status); err != nil {
+			return nil, fmt.Errorf("failed to scan RSVP: %w", err)
+		}
+		rsvps = append(rsvps, e)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating RSVPs: %w", err)
+	}
+
+	return rsvps, nil
+}
+
+// GetInvitations retrieves the invitations a user received (by ID or email) and sent
+func (r *Repository) GetInvitations(ctx context.Context, userID int, email string) ([]invitation.Invitation, []invitation.Invitation, error) {
+	query := `
+		SELECT id, event_id, inviter_id, invitee_email, invitee_id, group_id, role, status,
+			COALESCE(message, ''), created_at, responded_at
+		FROM invitations
+		WHERE invitee_id = $1 OR lower(invitee_email) = lower($2) OR inviter_id = $1
+		ORDER BY created_at
+	`
+
+	rows, err := r.db.Query(ctx, query, userID, email)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get invitations: %w", err)
+	}
+	defer rows.Close()
+
+	received := []invitation.Invitation{}
+	sent := []invitation.Invitation{}
+	for rows.Next() {
+		var inv invitation.Invitation
+		err := rows.Scan(
+			&inv.ID,
+			&inv.EventID,
+			&inv.InviterID,
+			&inv.InviteeEmail,
+			&inv.InviteeID,
+			&inv.GroupID,
+			&inv.Role,
+			&inv.Status,
+			&inv.Message,
+			&inv.CreatedAt,
+			&inv.RespondedAt,
+		)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to scan invitation: %w", err)
+		}
+
+		if inv.InviterID == userID {
+			sent = append(sent, inv)
+		} else {
+			received = append(received, inv)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("error iterating invitations: %w", err)
+	}
+
+	return received, sent, nil
+}
+
+// GetGroups retrieves the groups owned by a user with their member emails
+func (r *Repository) GetGroups(ctx context.Context, userID int) ([]ExportGroup, error) {
+	query := `
+		SELECT g.id, g.name, COALESCE(g.description, ''), g.created_at,
+			COALESCE(array_agg(m.email ORDER BY m.created_at, m.id) FILTER (WHERE m.id IS NOT NULL), '{}')
+		FROM user_groups g
+		LEFT JOIN group_members m ON m.group_id = g.id
+		WHERE g.owner_id = $1
+		GROUP BY g.id
+		ORDER BY g.created_at
+	`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get groups: %w", err)
+	}
+	defer rows.Close()
+
+	groups := []ExportGroup{}
+	for rows.Next() {
+		var g ExportGroup
+		if err := rows.Scan(&g.ID, &g.Name, &g.Description, &g.CreatedAt, &g.Members); err != nil {
+			return nil, fmt.Errorf("failed to scan group: %w", err)
+		}
+		groups = append(groups, g)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating groups: %w", err)
+	}
+
+	return groups, nil
+}
+
+// collectIDs reads a single integer column from rows returned by a query
+func collectIDs(rows pgx.Rows, err error) ([]int, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
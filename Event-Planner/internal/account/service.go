@@ -0,0 +1,258 @@
+package account
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"event-planner/internal/apperror"
+	"event-planner/internal/event"
+	"event-planner/internal/invitation"
+	"event-planner/internal/logging"
+	"event-planner/internal/organization"
+	"event-planner/internal/profile"
+	"event-planner/internal/tracing"
+	"event-planner/internal/user"
+)
+
+// PasswordVerifier re-authenticates a user before destructive actions
+type PasswordVerifier interface {
+	VerifyPassword(ctx context.Context, userID int, password string) error
+}
+
+// ProfileManager is the subset of the profile service used for export and erasure
+type ProfileManager interface {
+	GetMyProfile(ctx context.Context, userID int) (*profile.Profile, error)
+	DeleteAvatar(ctx context.Context, userID int) error
+}
+
+// MembershipLister lists the organizations a user belongs to
+type MembershipLister interface {
+	GetMyOrganizations(ctx context.Context, userID int) ([]organization.Membership, error)
+}
+
+// Store is the account deletion and export storage used by Service;
+// Repository implements it on PostgreSQL
+type Store interface {
+	GetUser(ctx context.Context, userID int) (*user.User, error)
+	GetDeletionStatus(ctx context.Context, userID int) (*DeletionStatus, error)
+	ScheduleDeletion(ctx context.Context, userID int, scheduledFor time.Time) error
+	CancelDeletion(ctx context.Context, userID int) (bool, error)
+	GetDueDeletions(ctx context.Context, now time.Time) ([]int, error)
+	EraseUser(ctx context.Context, userID int) (*ErasureResult, error)
+	GetOrganizedEvents(ctx context.Context, userID int) ([]event.Event, error)
+	GetRSVPs(ctx context.Context, userID int) ([]event.EventWithAttendeeInfo, error)
+	GetInvitations(ctx context.Context, userID int, email string) ([]invitation.Invitation, []invitation.Invitation, error)
+	GetGroups(ctx context.Context, userID int) ([]ExportGroup, error)
+}
+
+// Service handles business logic for account deletion and data export
+type Service struct {
+	repo          Store
+	passwords     PasswordVerifier
+	profiles      ProfileManager
+	organizations MembershipLister
+	gracePeriod   time.Duration
+}
+
+// NewService creates a new account service; deletions are carried out gracePeriod after being requested
+func NewService(repo Store, passwords PasswordVerifier, profiles ProfileManager, organizations MembershipLister, gracePeriod time.Duration) *Service {
+	if gracePeriod <= 0 {
+		gracePeriod = DefaultGracePeriod
+	}
+
+	return &Service{
+		repo:          repo,
+		passwords:     passwords,
+		profiles:      profiles,
+		organizations: organizations,
+		gracePeriod:   gracePeriod,
+	}
+}
+
+// RequestDeletion schedules the account for erasure at the end of the grace period
+func (s *Service) RequestDeletion(ctx context.Context, userID int, req *DeleteAccountRequest) (*DeletionStatus, error) {
+	ctx, span := tracing.Start(ctx, "account.Service.RequestDeletion")
+	defer span.End()
+
+	if userID <= 0 {
+		return nil, apperror.Validation("user_id", "invalid user ID")
+	}
+
+	if req.Password == "" {
+		return nil, apperror.Validation("password", "password is required")
+	}
+
+	if err := s.passwords.VerifyPassword(ctx, userID, req.Password); err != nil {
+		return nil, err
+	}
+
+	status, err := s.repo.GetDeletionStatus(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Repeated requests keep the original schedule
+	if status.Pending {
+		return status, nil
+	}
+
+	if err := s.repo.ScheduleDeletion(ctx, userID, time.Now().Add(s.gracePeriod)); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetDeletionStatus(ctx, userID)
+}
+
+// GetDeletionStatus retrieves the pending deletion of the current user
+func (s *Service) GetDeletionStatus(ctx context.Context, userID int) (*DeletionStatus, error) {
+	ctx, span := tracing.Start(ctx, "account.Service.GetDeletionStatus")
+	defer span.End()
+
+	if userID <= 0 {
+		return nil, apperror.Validation("user_id", "invalid user ID")
+	}
+
+	return s.repo.GetDeletionStatus(ctx, userID)
+}
+
+// CancelDeletion cancels a pending deletion during the grace period
+func (s *Service) CancelDeletion(ctx context.Context, userID int) error {
+	ctx, span := tracing.Start(ctx, "account.Service.CancelDeletion")
+	defer span.End()
+
+	if userID <= 0 {
+		return apperror.Validation("user_id", "invalid user ID")
+	}
+
+	cancelled, err := s.repo.CancelDeletion(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if !cancelled {
+		return apperror.Conflict("no_pending_deletion", "no deletion is pending")
+	}
+
+	return nil
+}
+
+// ExportData collects everything stored about a user
+func (s *Service) ExportData(ctx context.Context, userID int) (*Export, error) {
+	ctx, span := tracing.Start(ctx, "account.Service.ExportData")
+	defer span.End()
+
+	if userID <= 0 {
+		return nil, apperror.Validation("user_id", "invalid user ID")
+	}
+
+	account, err := s.repo.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &Export{
+		GeneratedAt: time.Now().UTC(),
+		Account:     *account,
+	}
+
+	deletion, err := s.repo.GetDeletionStatus(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	export.Deletion = *deletion
+
+	if export.Profile, err = s.profiles.GetMyProfile(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	if export.OrganizedEvents, err = s.repo.GetOrganizedEvents(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	if export.RSVPs, err = s.repo.GetRSVPs(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	if export.InvitationsReceived, export.InvitationsSent, err = s.repo.GetInvitations(ctx, userID, account.Email); err != nil {
+		return nil, err
+	}
+
+	if export.Groups, err = s.repo.GetGroups(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	if export.Organizations, err = s.organizations.GetMyOrganizations(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return export, nil
+}
+
+// EraseAccount erases an account immediately, transferring or archiving its events
+func (s *Service) EraseAccount(ctx context.Context, userID int) (*ErasureResult, error) {
+	ctx, span := tracing.Start(ctx, "account.Service.EraseAccount")
+	defer span.End()
+
+	if userID <= 0 {
+		return nil, apperror.Validation("user_id", "invalid user ID")
+	}
+
+	// Stored files are outside the transaction, remove them first
+	p, err := s.profiles.GetMyProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if p.AvatarURL != "" {
+		if err := s.profiles.DeleteAvatar(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.repo.EraseUser(ctx, userID)
+}
+
+// EraseDueAccounts erases every account whose grace period has ended
+func (s *Service) EraseDueAccounts(ctx context.Context) (int, error) {
+	ctx, span := tracing.Start(ctx, "account.Service.EraseDueAccounts")
+	defer span.End()
+
+	ids, err := s.repo.GetDueDeletions(ctx, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	erased := 0
+	for _, id := range ids {
+		result, err := s.EraseAccount(ctx, id)
+		if err != nil {
+			// Keep going, the account is retried on the next run
+			slog.ErrorContext(ctx, "failed to erase account", "user_id", id, logging.Err(err))
+			continue
+		}
+		slog.InfoContext(ctx, "erased account", "user_id", id,
+			"events_transferred", len(result.TransferredEvents), "events_archived", len(result.ArchivedEvents))
+		erased++
+	}
+
+	return erased, nil
+}
+
+// RunDeletionWorker erases due accounts every interval until ctx is cancelled
+func (s *Service) RunDeletionWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		// A started pass is finished even when ctx is cancelled meanwhile
+		if _, err := s.EraseDueAccounts(context.WithoutCancel(ctx)); err != nil {
+			slog.ErrorContext(ctx, "account deletion worker failed", logging.Err(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"event-planner/internal/apperror"
+	"event-planner/internal/auth"
+	"event-planner/internal/response"
+)
+
+// Handler handles HTTP requests for administration
+type Handler struct {
+	service *Service
+}
+
+// NewHandler creates a new admin handler
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// ListUsers handles GET /admin/users
+func (h *Handler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	filter := &UsersFilter{
+		Query:  q.Get("q"),
+		Role:   q.Get("role"),
+		Limit:  queryInt(q, "limit"),
+		Offset: queryInt(q, "offset"),
+	}
+
+	if v := q.Get("disabled"); v != "" {
+		disabled, err := strconv.ParseBool(v)
+		if err != nil {
+			response.Error(w, apperror.Validation("disabled", "invalid disabled parameter"))
+			return
+		}
+		filter.Disabled = &disabled
+	}
+
+	users, err := h.service.ListUsers(r.Context(), filter)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data": users,
+	})
+}
+
+// GetUser handles GET /admin/users/{id}
+func (h *Handler) GetUser(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		response.Error(w, apperror.Validation("id", "invalid user ID"))
+		return
+	}
+
+	u, err := h.service.GetUser(r.Context(), userID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data": u,
+	})
+}
+
+// DisableUser handles PUT /admin/users/{id}/disable
+func (h *Handler) DisableUser(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := auth.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, apperror.ErrUnauthorized)
+		return
+	}
+
+	userID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		response.Error(w, apperror.Validation("id", "invalid user ID"))
+		return
+	}
+
+	var req DisableUserRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.Error(w, apperror.Validation("body", "invalid request body"))
+			return
+		}
+	}
+
+	u, err := h.service.DisableUser(r.Context(), actorID, userID, req.Reason)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "user disabled successfully",
+		"data":    u,
+	})
+}
+
+// EnableUser handles PUT /admin/users/{id}/enable
+func (h *Handler) EnableUser(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := auth.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, apperror.ErrUnauthorized)
+		return
+	}
+
+	userID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		response.Error(w, apperror.Validation("id", "invalid user ID"))
+		return
+	}
+
+	u, err := h.service.EnableUser(r.Context(), actorID, userID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "user enabled successfully",
+		"data":    u,
+	})
+}
+
+// SetUserRole handles PUT /admin/users/{id}/role
+func (h *Handler) SetUserRole(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := auth.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, apperror.ErrUnauthorized)
+		return
+	}
+
+	userID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		response.Error(w, apperror.Validation("id", "invalid user ID"))
+		return
+	}
+
+	var req SetRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, apperror.Validation("body", "invalid request body"))
+		return
+	}
+
+	u, err := h.service.SetUserRole(r.Context(), actorID, userID, req.Role)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "user role updated successfully",
+		"data":    u,
+	})
+}
+
+// UpdateEvent handles PUT /admin/events/{id}
+func (h *Handler) UpdateEvent(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := auth.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, apperror.ErrUnauthorized)
+		return
+	}
+
+	eventID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		response.Error(w, apperror.Validation("id", "invalid event ID"))
+		return
+	}
+
+	var req ModerateEventRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, apperror.Validation("body", "invalid request body"))
+		return
+	}
+
+	event, err := h.service.UpdateEvent(r.Context(), actorID, eventID, &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "event updated successfully",
+		"data":    event,
+	})
+}
+
+// DeleteEvent handles DELETE /admin/events/{id}?reason=...
+func (h *Handler) DeleteEvent(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := auth.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, apperror.ErrUnauthorized)
+		return
+	}
+
+	eventID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		response.Error(w, apperror.Validation("id", "invalid event ID"))
+		return
+	}
+
+	if err := h.service.DeleteEvent(r.Context(), actorID, eventID, r.URL.Query().Get("reason")); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "event deleted successfully",
+	})
+}
+
+// ListActions handles GET /admin/actions
+func (h *Handler) ListActions(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	filter := &ActionsFilter{
+		ActorID:    queryInt(q, "actor_id"),
+		TargetType: q.Get("target_type"),
+		TargetID:   queryInt(q, "target_id"),
+		Limit:      queryInt(q, "limit"),
+		Offset:     queryInt(q, "offset"),
+	}
+
+	actions, err := h.service.ListActions(r.Context(), filter)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data": actions,
+	})
+}
+
+// queryInt parses an optional integer query parameter (0 when missing or invalid)
+func queryInt(q url.Values, key string) int {
+	v, _ := strconv.Atoi(q.Get(key))
+	return v
+}
@@ -0,0 +1,53 @@
+package admin
+
+import (
+	"encoding/json"
+	"time"
+
+	"event-planner/internal/event"
+)
+
+// Action is a recorded administrative action
+type Action struct {
+	ID         int             `json:"id"`
+	ActorID    int             `json:"actor_id"`
+	Action     string          `json:"action"`      // e.g. 'user.disable', 'event.delete'
+	TargetType string          `json:"target_type"` // 'user' or 'event'
+	TargetID   int             `json:"target_id"`
+	Details    json.RawMessage `json:"details,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// UsersFilter holds filters for listing users
+type UsersFilter struct {
+	Query    string // email substring (optional)
+	Role     string // 'admin', 'support', 'member' (optional)
+	Disabled *bool  // only disabled / only active accounts (optional)
+	Limit    int
+	Offset   int
+}
+
+// ActionsFilter holds filters for listing admin actions
+type ActionsFilter struct {
+	ActorID    int    // optional
+	TargetType string // optional
+	TargetID   int    // optional
+	Limit      int
+	Offset     int
+}
+
+// DisableUserRequest is the request payload for disabling an account
+type DisableUserRequest struct {
+	Reason string `json:"reason"`
+}
+
+// SetRoleRequest is the request payload for changing a user's system role
+type SetRoleRequest struct {
+	Role string `json:"role" binding:"required"` // 'admin', 'support' or 'member'
+}
+
+// ModerateEventRequest is the request payload for force-editing an event
+type ModerateEventRequest struct {
+	event.UpdateEventRequest
+	Reason string `json:"reason"`
+}
@@ -0,0 +1,217 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"event-planner/internal/apperror"
+	"event-planner/internal/db"
+	"event-planner/internal/user"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ErrUserNotFound is returned when the target user does not exist
+var ErrUserNotFound = apperror.NotFound("user_not_found", "user not found")
+
+// Repository handles all database operations for administration
+type Repository struct {
+	db *pgxpool.Pool
+}
+
+// NewRepository creates a new admin repository
+func NewRepository(db *pgxpool.Pool) *Repository {
+	return &Repository{db: db}
+}
+
+// ListUsers retrieves users matching the filter, newest first
+func (r *Repository) ListUsers(ctx context.Context, f *UsersFilter) ([]user.User, error) {
+	query := `
+		SELECT id, email, role, disabled_at, created_at
+		FROM users
+		WHERE 1 = 1
+	`
+
+	args := []interface{}{}
+	argIdx := 1
+
+	if f.Query != "" {
+		query += fmt.Sprintf(" AND email ILIKE $%d", argIdx)
+		args = append(args, "%"+f.Query+"%")
+		argIdx++
+	}
+
+	if f.Role != "" {
+		query += fmt.Sprintf(" AND role = $%d", argIdx)
+		args = append(args, f.Role)
+		argIdx++
+	}
+
+	if f.Disabled != nil {
+		if *f.Disabled {
+			query += " AND disabled_at IS NOT NULL"
+		} else {
+			query += " AND disabled_at IS NULL"
+		}
+	}
+
+	query += fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d", argIdx, argIdx+1)
+	args = append(args, f.Limit, f.Offset)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []user.User
+	for rows.Next() {
+		u := user.User{}
+		if err := rows.Scan(&u.ID, &u.Email, &u.Role, &u.DisabledAt, &u.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, u)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating users: %w", err)
+	}
+
+	return users, nil
+}
+
+// GetUserByID retrieves a single user by ID
+func (r *Repository) GetUserByID(ctx context.Context, userID int) (*user.User, error) {
+	query := `
+		SELECT id, email, role, disabled_at, created_at
+		FROM users
+		WHERE id = $1
+	`
+
+	u := &user.User{}
+	err := r.db.QueryRow(ctx, query, userID).Scan(&u.ID, &u.Email, &u.Role, &u.DisabledAt, &u.CreatedAt)
+	if err != nil {
+		return nil, db.TranslateError(fmt.Errorf("failed to get user: %w", err), ErrUserNotFound)
+	}
+
+	return u, nil
+}
+
+// SetDisabled disables or re-enables a user account
+func (r *Repository) SetDisabled(ctx context.Context, userID int, disabled bool) error {
+	// Erased accounts stay disabled
+	query := `UPDATE users SET disabled_at = NULL WHERE id = $1 AND deleted_at IS NULL`
+	if disabled {
+		query = `UPDATE users SET disabled_at = COALESCE(disabled_at, NOW()) WHERE id = $1 AND deleted_at IS NULL`
+	}
+
+	result, err := r.db.Exec(ctx, query, userID)
+	if err != nil {
+		return db.TranslateError(fmt.Errorf("failed to update user: %w", err), nil)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
+// SetRole changes the system role of a user
+func (r *Repository) SetRole(ctx context.Context, userID int, role string) error {
+	query := `UPDATE users SET role = $1 WHERE id = $2`
+
+	result, err := r.db.Exec(ctx, query, role, userID)
+	if err != nil {
+		return db.TranslateError(fmt.Errorf("failed to update user role: %w", err), nil)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
+// RecordAction stores an administrative action
+func (r *Repository) RecordAction(ctx context.Context, action *Action) error {
+	query := `
+		INSERT INTO admin_actions (actor_id, action, target_type, target_id, details)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+
+	details := action.Details
+	if details == nil {
+		details = json.RawMessage(`{}`)
+	}
+
+	err := r.db.QueryRow(ctx, query,
+		action.ActorID,
+		action.Action,
+		action.TargetType,
+		action.TargetID,
+		details,
+	).Scan(&action.ID, &action.CreatedAt)
+
+	if err != nil {
+		return db.TranslateError(fmt.Errorf("failed to record admin action: %w", err), nil)
+	}
+
+	return nil
+}
+
+// ListActions retrieves recorded admin actions matching the filter, newest first
+func (r *Repository) ListActions(ctx context.Context, f *ActionsFilter) ([]Action, error) {
+	query := `
+		SELECT id, actor_id, action, target_type, target_id, details, created_at
+		FROM admin_actions
+		WHERE 1 = 1
+	`
+
+	args := []interface{}{}
+	argIdx := 1
+
+	if f.ActorID > 0 {
+		query += fmt.Sprintf(" AND actor_id = $%d", argIdx)
+		args = append(args, f.ActorID)
+		argIdx++
+	}
+
+	if f.TargetType != "" {
+		query += fmt.Sprintf(" AND target_type = $%d", argIdx)
+		args = append(args, f.TargetType)
+		argIdx++
+	}
+
+	if f.TargetID > 0 {
+		query += fmt.Sprintf(" AND target_id = $%d", argIdx)
+		args = append(args, f.TargetID)
+		argIdx++
+	}
+
+	query += fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d", argIdx, argIdx+1)
+	args = append(args, f.Limit, f.Offset)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list admin actions: %w", err)
+	}
+	defer rows.Close()
+
+	var actions []Action
+	for rows.Next() {
+		a := Action{}
+		if err := rows.Scan(&a.ID, &a.ActorID, &a.Action, &a.TargetType, &a.TargetID, &a.Details, &a.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan admin action: %w", err)
+		}
+		actions = append(actions, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating admin actions: %w", err)
+	}
+
+	return actions, nil
+}
@@ -0,0 +1,293 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+
+	"event-planner/internal/apperror"
+	"event-planner/internal/audit"
+	"event-planner/internal/event"
+	"event-planner/internal/logging"
+	"event-planner/internal/tracing"
+	"event-planner/internal/user"
+)
+
+// EventModerator is the subset of the event service used for moderation
+type EventModerator interface {
+	ForceUpdateEvent(ctx context.Context, eventID int, req *event.UpdateEventRequest, editorID int) (*event.Event, *event.Event, error)
+	ForceDeleteEvent(ctx context.Context, eventID int) (*event.Event, error)
+}
+
+// Store is the administration storage used by Service; Repository implements it on PostgreSQL
+type Store interface {
+	ListUsers(ctx context.Context, f *UsersFilter) ([]user.User, error)
+	GetUserByID(ctx context.Context, userID int) (*user.User, error)
+	SetDisabled(ctx context.Context, userID int, disabled bool) error
+	SetRole(ctx context.Context, userID int, role string) error
+	RecordAction(ctx context.Context, action *Action) error
+	ListActions(ctx context.Context, f *ActionsFilter) ([]Action, error)
+}
+
+// Service handles business logic for administration
+type Service struct {
+	repo   Store
+	events EventModerator
+	audit  *audit.Service
+}
+
+// NewService creates a new admin service; the audit log may be nil
+func NewService(repo Store, events EventModerator, auditLog *audit.Service) *Service {
+	return &Service{
+		repo:   repo,
+		events: events,
+		audit:  auditLog,
+	}
+}
+
+// ListUsers lists and searches user accounts
+func (s *Service) ListUsers(ctx context.Context, f *UsersFilter) ([]user.User, error) {
+	ctx, span := tracing.Start(ctx, "admin.Service.ListUsers")
+	defer span.End()
+
+	if f.Role != "" && !user.IsValidRole(f.Role) {
+		return nil, apperror.Validation("role", "invalid role: must be 'admin', 'support', or 'member'")
+	}
+
+	normalizePage(&f.Limit, &f.Offset)
+
+	users, err := s.repo.ListUsers(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+
+	if users == nil {
+		users = []user.User{}
+	}
+
+	return users, nil
+}
+
+// GetUser retrieves a single user account
+func (s *Service) GetUser(ctx context.Context, userID int) (*user.User, error) {
+	ctx, span := tracing.Start(ctx, "admin.Service.GetUser")
+	defer span.End()
+
+	if userID <= 0 {
+		return nil, apperror.Validation("id", "invalid user ID")
+	}
+
+	return s.repo.GetUserByID(ctx, userID)
+}
+
+// DisableUser disables an account so it can no longer sign in or use its tokens
+func (s *Service) DisableUser(ctx context.Context, actorID, userID int, reason string) (*user.User, error) {
+	ctx, span := tracing.Start(ctx, "admin.Service.DisableUser")
+	defer span.End()
+
+	if userID <= 0 {
+		return nil, apperror.Validation("id", "invalid user ID")
+	}
+
+	if userID == actorID {
+		return nil, apperror.Forbidden("self_action", "you cannot disable your own account")
+	}
+
+	if err := s.repo.SetDisabled(ctx, userID, true); err != nil {
+		return nil, err
+	}
+
+	s.record(ctx, actorID, "user.disable", "user", userID, map[string]interface{}{
+		"reason": reason,
+	})
+	s.audit.Record(ctx, audit.Entry{
+		ActorID:    audit.Actor(actorID),
+		Action:     audit.ActionUserDisable,
+		TargetType: audit.TargetUser,
+		TargetID:   userID,
+		After:      audit.Snapshot(map[string]interface{}{"disabled": true, "reason": reason}),
+	})
+
+	return s.repo.GetUserByID(ctx, userID)
+}
+
+// EnableUser re-enables a disabled account
+func (s *Service) EnableUser(ctx context.Context, actorID, userID int) (*user.User, error) {
+	ctx, span := tracing.Start(ctx, "admin.Service.EnableUser")
+	defer span.End()
+
+	if userID <= 0 {
+		return nil, apperror.Validation("id", "invalid user ID")
+	}
+
+	if err := s.repo.SetDisabled(ctx, userID, false); err != nil {
+		return nil, err
+	}
+
+	s.record(ctx, actorID, "user.enable", "user", userID, nil)
+	s.audit.Record(ctx, audit.Entry{
+		ActorID:    audit.Actor(actorID),
+		Action:     audit.ActionUserEnable,
+		TargetType: audit.TargetUser,
+		TargetID:   userID,
+		After:      audit.Snapshot(map[string]bool{"disabled": false}),
+	})
+
+	return s.repo.GetUserByID(ctx, userID)
+}
+
+// SetUserRole changes the system role of a user
+func (s *Service) SetUserRole(ctx context.Context, actorID, userID int, role string) (*user.User, error) {
+	ctx, span := tracing.Start(ctx, "admin.Service.SetUserRole")
+	defer span.End()
+
+	if userID <= 0 {
+		return nil, apperror.Validation("id", "invalid user ID")
+	}
+
+	if !user.IsValidRole(role) {
+		return nil, apperror.Validation("role", "invalid role: must be 'admin', 'support', or 'member'")
+	}
+
+	// Prevents admins from locking themselves (and possibly everyone) out
+	if userID == actorID {
+		return nil, apperror.Forbidden("self_action", "you cannot change your own role")
+	}
+
+	current, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.SetRole(ctx, userID, role); err != nil {
+		return nil, err
+	}
+
+	s.record(ctx, actorID, "user.set_role", "user", userID, map[string]interface{}{
+		"before": current.Role,
+		"after":  role,
+	})
+	s.audit.Record(ctx, audit.Entry{
+		ActorID:    audit.Actor(actorID),
+		Action:     audit.ActionUserSetRole,
+		TargetType: audit.TargetUser,
+		TargetID:   userID,
+		Before:     audit.Snapshot(map[string]string{"role": current.Role}),
+		After:      audit.Snapshot(map[string]string{"role": role}),
+	})
+
+	current.Role = role
+	return current, nil
+}
+
+// UpdateEvent force-edits any event
+func (s *Service) UpdateEvent(ctx context.Context, actorID, eventID int, req *ModerateEventRequest) (*event.Event, error) {
+	ctx, span := tracing.Start(ctx, "admin.Service.UpdateEvent")
+	defer span.End()
+
+	before, after, err := s.events.ForceUpdateEvent(ctx, eventID, &req.UpdateEventRequest, actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.record(ctx, actorID, "event.update", "event", eventID, map[string]interface{}{
+		"reason": req.Reason,
+		"before": before,
+		"after":  after,
+	})
+	changedBefore, changedAfter := audit.Changes(before, after)
+	s.audit.Record(ctx, audit.Entry{
+		ActorID:    audit.Actor(actorID),
+		Action:     audit.ActionEventUpdate,
+		TargetType: audit.TargetEvent,
+		TargetID:   eventID,
+		EventID:    &eventID,
+		Before:     changedBefore,
+		After:      changedAfter,
+	})
+
+	return after, nil
+}
+
+// DeleteEvent force-deletes any event
+func (s *Service) DeleteEvent(ctx context.Context, actorID, eventID int, reason string) error {
+	ctx, span := tracing.Start(ctx, "admin.Service.DeleteEvent")
+	defer span.End()
+
+	deleted, err := s.events.ForceDeleteEvent(ctx, eventID)
+	if err != nil {
+		return err
+	}
+
+	s.record(ctx, actorID, "event.delete", "event", eventID, map[string]interface{}{
+		"reason": reason,
+		"before": deleted,
+	})
+	s.audit.Record(ctx, audit.Entry{
+		ActorID:    audit.Actor(actorID),
+		Action:     audit.ActionEventDelete,
+		TargetType: audit.TargetEvent,
+		TargetID:   eventID,
+		EventID:    &eventID,
+		Before:     audit.Snapshot(deleted),
+	})
+
+	return nil
+}
+
+// ListActions lists recorded admin actions
+func (s *Service) ListActions(ctx context.Context, f *ActionsFilter) ([]Action, error) {
+	ctx, span := tracing.Start(ctx, "admin.Service.ListActions")
+	defer span.End()
+
+	normalizePage(&f.Limit, &f.Offset)
+
+	actions, err := s.repo.ListActions(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+
+	if actions == nil {
+		actions = []Action{}
+	}
+
+	return actions, nil
+}
+
+// record stores an admin action. The action itself already happened, so a
+// failure to record it is logged rather than reported to the caller.
+func (s *Service) record(ctx context.Context, actorID int, action, targetType string, targetID int, details map[string]interface{}) {
+	a := &Action{
+		ActorID:    actorID,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+	}
+
+	if details != nil {
+		raw, err := json.Marshal(details)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to encode admin action details", "action", action, logging.Err(err))
+		} else {
+			a.Details = raw
+		}
+	}
+
+	if err := s.repo.RecordAction(ctx, a); err != nil {
+		slog.ErrorContext(ctx, "failed to record admin action",
+			"action", action, "target_type", targetType, "target_id", targetID, logging.Err(err))
+	}
+}
+
+// normalizePage applies the default and maximum page size
+func normalizePage(limit, offset *int) {
+	if *limit <= 0 {
+		*limit = 50
+	}
+	if *limit > 200 {
+		*limit = 200
+	}
+	if *offset < 0 {
+		*offset = 0
+	}
+}
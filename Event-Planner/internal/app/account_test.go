@@ -0,0 +1,82 @@
+package app_test
+
+import (
+	"bytes"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"testing"
+)
+
+func TestProfileAndAccount(t *testing.T) {
+	run(t, func(t *testing.T, h *harness) {
+		ada := h.register("ada@example.com")
+		bob := h.register("bob@example.com")
+
+		h.do("PUT", "/users/me/profile", ada, map[string]string{"display_name": "Ada L.", "bio": "Engines", "timezone": "Europe/London"}).
+			want(http.StatusOK)
+
+		var public struct {
+			UserID      int    `json:"user_id"`
+			DisplayName string `json:"display_name"`
+			Bio         string `json:"bio"`
+		}
+		h.do("GET", fmt.Sprintf("/users/%d/profile", ada.ID), nil, nil).want(http.StatusOK).data(&public)
+		if public.UserID != ada.ID || public.DisplayName != "Ada L." || public.Bio != "Engines" {
+			t.Errorf("public profile = %+v", public)
+		}
+
+		// Deletion needs the password and can be cancelled during the grace period
+		h.do("POST", "/users/me/deletion", ada, map[string]string{"password": "wrong-password"}).wantError(http.StatusForbidden, "invalid_password")
+		var status struct {
+			Pending bool `json:"pending"`
+		}
+		h.do("POST", "/users/me/deletion", ada, map[string]string{"password": "secret-password"}).want(http.StatusAccepted).data(&status)
+		if !status.Pending {
+			t.Errorf("deletion status = %+v", status)
+		}
+		h.do("DELETE", "/users/me/deletion", ada, nil).want(http.StatusOK)
+		h.do("GET", "/users/me/deletion", ada, nil).want(http.StatusOK).data(&status)
+		if status.Pending {
+			t.Errorf("deletion status after cancelling = %+v", status)
+		}
+
+		// Avatars are served as files, without listing the directories they are kept in
+		var upload bytes.Buffer
+		form := multipart.NewWriter(&upload)
+		part, err := form.CreateFormFile("avatar", "avatar.png")
+		if err != nil {
+			t.Fatal(err)
+		}
+		part.Write([]byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"))
+		form.Close()
+		var avatar struct {
+			AvatarURL string `json:"avatar_url"`
+		}
+		h.doWith("PUT", "/users/me/avatar", ada, http.Header{"Content-Type": {form.FormDataContentType()}}, &upload).
+			want(http.StatusOK).data(&avatar)
+		h.do("GET", avatar.AvatarURL, nil, nil).want(http.StatusOK)
+		h.do("GET", fmt.Sprintf("/avatars/%d/", ada.ID), nil, nil).want(http.StatusNotFound)
+		h.do("GET", fmt.Sprintf("/avatars/%d", ada.ID), nil, nil).want(http.StatusNotFound)
+
+		ev := h.createEvent(ada, "Launch", 7, nil)
+		h.join(bob, ev.ID)
+
+		var export struct {
+			Account struct {
+				Email string `json:"email"`
+			} `json:"account"`
+			Profile struct {
+				DisplayName string `json:"display_name"`
+			} `json:"profile"`
+			OrganizedEvents []eventJSON `json:"organized_events"`
+			RSVPs           []eventJSON `json:"rsvps"`
+		}
+		h.do("GET", "/users/me/export?format=json", ada, nil).want(http.StatusOK).decode(&export)
+		if export.Account.Email != ada.Email || export.Profile.DisplayName != "Ada L." {
+			t.Errorf("export = %+v", export)
+		}
+		wantEvents(t, "organized events in the export", export.OrganizedEvents, ev.ID)
+		wantEvents(t, "RSVPs in the export", export.RSVPs, ev.ID)
+	})
+}
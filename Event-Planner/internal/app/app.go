@@ -0,0 +1,513 @@
+// Package app assembles the repositories, services and HTTP routes of the API
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"event-planner/internal/account"
+	"event-planner/internal/admin"
+	"event-planner/internal/apperror"
+	"event-planner/internal/audit"
+	"event-planner/internal/auth"
+	"event-planner/internal/event"
+	"event-planner/internal/group"
+	"event-planner/internal/health"
+	"event-planner/internal/invitation"
+	"event-planner/internal/logging"
+	"event-planner/internal/metrics"
+	"event-planner/internal/migrate"
+	"event-planner/internal/notification"
+	"event-planner/internal/openapi"
+	"event-planner/internal/organization"
+	"event-planner/internal/profile"
+	"event-planner/internal/response"
+	"event-planner/internal/search"
+	"event-planner/internal/storage"
+	"event-planner/internal/tracing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/cors"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Options configures the App
+type Options struct {
+	Auth                auth.Config      // the JWT secret is required
+	CORSOrigins         []string         // origins allowed to call the API from a browser
+	AvatarDir           string           // where uploaded avatars are stored
+	DeletionGracePeriod time.Duration    // account.DefaultGracePeriod when zero
+	TrashRetention      time.Duration    // event.DefaultTrashRetention when zero
+	Stores              *Stores          // the PostgreSQL repositories when nil
+	Metrics             *metrics.Metrics // served on /metrics; a new set when nil
+}
+
+// Stores is the storage of the services. Tests can serve the API on memstore
+// by passing its views.
+type Stores struct {
+	Users         auth.Store
+	Events        event.Store
+	Invitations   invitation.Store
+	Search        search.Store
+	Notifications notification.Store
+	Audit         audit.Store
+	Organizations organization.Store
+	Groups        group.Store
+	Profiles      profile.Store
+	Admin         admin.Store
+	Accounts      account.Store
+}
+
+// App is the assembled API
+type App struct {
+	router         http.Handler
+	health         *health.Handler
+	authService    *auth.Service
+	accountService *account.Service
+	eventService   *event.Service
+}
+
+// New wires the API on top of the database pool
+func New(pool *pgxpool.Pool, opts Options) (*App, error) {
+	if opts.Auth.JWTSecret == "" {
+		return nil, errors.New("a JWT secret is required")
+	}
+
+	stores := opts.Stores
+	if stores == nil {
+		stores = &Stores{
+			Users:         auth.NewRepository(pool),
+			Events:        event.NewRepository(pool),
+			Invitations:   invitation.NewRepository(pool),
+			Search:        search.NewRepository(pool),
+			Notifications: notification.NewRepository(pool),
+			Audit:         audit.NewRepository(pool),
+			Organizations: organization.NewRepository(pool),
+			Groups:        group.NewRepository(pool),
+			Profiles:      profile.NewRepository(pool),
+			Admin:         admin.NewRepository(pool),
+			Accounts:      account.NewRepository(pool),
+		}
+	}
+
+	// Metrics of the requests, the database pool and business activity
+	m := opts.Metrics
+	if m == nil {
+		m = metrics.New()
+	}
+	if pool != nil {
+		m.ObservePool(pool)
+	}
+
+	// Audit log of security- and data-relevant actions; organizers read the
+	// entries of their events, admins all of them
+	auditService := audit.NewService(stores.Audit)
+	auditHandler := audit.NewHandler(auditService, func(ctx context.Context) (int, bool, bool) {
+		userID, ok := auth.GetUserID(ctx)
+		return userID, auth.Can(ctx, auth.PermViewAuditLog), ok
+	})
+
+	//User Management
+	authService := auth.NewService(stores.Users, opts.Auth, m, auditService)
+	authHandler := auth.NewHandler(authService)
+
+	//Organizations
+	orgService := organization.NewService(stores.Organizations, authService)
+	orgHandler := organization.NewHandler(orgService)
+
+	//User Profiles
+	avatarStorage, err := storage.NewLocalStorage(opts.AvatarDir, "/avatars")
+	if err != nil {
+		return nil, err
+	}
+	profileService := profile.NewService(stores.Profiles, avatarStorage)
+	profileHandler := profile.NewHandler(profileService)
+
+	//Event Management
+	eventRepo := stores.Events
+
+	//Response Management / Invitations
+	invRepo := stores.Invitations
+	invService := invitation.NewService(invRepo, eventRepo, m, auditService)
+	invHandler := invitation.NewHandler(invService)
+
+	eventService := event.NewService(eventRepo, invService, m, auditService, opts.TrashRetention)
+	eventHandler := event.NewHandler(eventService)
+
+	// Notifications of cancelled and postponed events
+	notificationService := notification.NewService(stores.Notifications)
+	notificationHandler := notification.NewHandler(notificationService)
+
+	// Groups / distribution lists
+	groupService := group.NewService(stores.Groups, invService)
+	groupHandler := group.NewHandler(groupService)
+
+	// search & Filtering
+	searchRepo := stores.Search
+	searchService := search.NewService(searchRepo)
+	searchHandler := search.NewHandler(searchService)
+
+	// Administration
+	adminService := admin.NewService(stores.Admin, eventService, auditService)
+	adminHandler := admin.NewHandler(adminService)
+
+	// Account deletion & data export
+	accountService := account.NewService(stores.Accounts, authService, profileService, orgService, opts.DeletionGracePeriod)
+	accountHandler := account.NewHandler(accountService)
+
+	// Liveness and readiness probes; without a pool (tests on memstore) the
+	// server is ready until it drains
+	healthHandler := health.NewHandler()
+	if pool != nil {
+		migrator, err := migrate.New(pool)
+		if err != nil {
+			return nil, err
+		}
+		healthHandler.AddCheck("database", pool.Ping)
+		healthHandler.AddCheck("migrations", func(ctx context.Context) error {
+			pending, err := migrator.Pending(ctx)
+			if err != nil {
+				return err
+			}
+			if len(pending) > 0 {
+				names := make([]string, len(pending))
+				for i, mig := range pending {
+					names[i] = mig.String()
+				}
+				return fmt.Errorf("pending: %s", strings.Join(names, ", "))
+			}
+			return nil
+		})
+	}
+
+	// API specification, also used to validate requests
+	spec, err := openapi.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	// Setup router
+	r := chi.NewRouter()
+
+	// Global middleware; metrics first so they time the whole request, and
+	// the request ID before the span and log line that record it
+	r.Use(m.Middleware)
+	r.Use(middleware.RequestID)
+	r.Use(tracing.Middleware)
+	r.Use(logging.Middleware)
+	r.Use(logging.Recoverer)
+	r.Use(audit.Middleware)
+
+	r.Use(cors.Handler(cors.Options{
+		AllowedOrigins:   opts.CORSOrigins,
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", organization.HeaderOrganizationID, "Traceparent", "Tracestate", "If-Match", "If-None-Match"},
+		ExposedHeaders:   []string{"Link", "ETag", tracing.HeaderTraceID},
+		AllowCredentials: true,
+		MaxAge:           300,
+	}))
+
+	// Identify the user when a token is sent (routes below still require it
+	// with AuthMiddleware) and resolve the organization the request operates in
+	r.Use(authHandler.OptionalAuthMiddleware)
+	r.Use(orgHandler.ScopeMiddleware)
+
+	// Reject requests that don't match the specification before the handlers run
+	r.Use(spec.ValidationMiddleware)
+
+	// Unknown routes get the same JSON error envelope as the handlers
+	r.NotFound(func(w http.ResponseWriter, r *http.Request) {
+		response.Error(w, apperror.NotFound("route_not_found", "route not found"))
+	})
+	r.MethodNotAllowed(func(w http.ResponseWriter, r *http.Request) {
+		response.JSON(w, http.StatusMethodNotAllowed, response.ErrorBody{Error: "method not allowed", Code: "method_not_allowed"})
+	})
+
+	// Health check
+	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("Server is running"))
+	})
+
+	// Probes: the process is alive / it can serve requests
+	r.Get("/livez", healthHandler.Live)
+	r.Get("/readyz", healthHandler.Ready)
+
+	// Prometheus metrics
+	r.Method(http.MethodGet, "/metrics", m.Handler())
+
+	// API specification and its interactive documentation
+	r.Get("/openapi.json", spec.ServeSpec)
+	r.Get("/docs", spec.ServeDocs)
+
+	// Uploaded avatars
+	r.Handle("/avatars/*", http.StripPrefix("/avatars/", avatarStorage.Handler()))
+
+	// Auth routes
+	r.Route("/auth", func(r chi.Router) {
+		r.Post("/register", authHandler.Register)
+		r.Post("/login", authHandler.Login)
+	})
+
+	// Events routes
+	r.Route("/events", func(r chi.Router) {
+		// Public endpoints (no auth required)
+
+		// GET all events
+		r.Get("/", eventHandler.GetAllEvents)
+
+		// Advanced search
+		r.With(authHandler.AuthMiddleware).Get("/search", searchHandler.SearchEvents)
+
+		// GET events by organizer
+		r.Get("/organizer/{id}", eventHandler.GetEventsByOrganizer)
+
+		// GET single event by ID (public)
+		r.Get("/{id}", eventHandler.GetEventByID)
+
+		// GET event attendees
+		r.Get("/{id}/attendees", eventHandler.GetEventAttendees)
+
+		// GET invitations for an event
+		r.With(authHandler.AuthMiddleware).Get("/{id}/invitations", invHandler.GetEventInvitations)
+
+		// POST create new event
+		r.With(authHandler.AuthMiddleware).Post("/", eventHandler.CreateEvent)
+
+		// PUT update event
+		r.With(authHandler.AuthMiddleware).Put("/{id}", eventHandler.UpdateEvent)
+
+		// PATCH update event with a merge patch
+		r.With(authHandler.AuthMiddleware).Patch("/{id}", eventHandler.PatchEvent)
+
+		// DELETE event
+		r.With(authHandler.AuthMiddleware).Delete("/{id}", eventHandler.DeleteEvent)
+
+		// POST join event
+		r.With(authHandler.AuthMiddleware).Post("/{id}/join", eventHandler.JoinEvent)
+
+		// POST invite user to event
+		r.With(authHandler.AuthMiddleware).Post("/{id}/invite", eventHandler.InviteUserToEvent)
+
+		// PUT update attendance status
+		r.With(authHandler.AuthMiddleware).Put("/{id}/attendance", eventHandler.UpdateAttendanceStatus)
+
+		// Call the event off / put it off, notifying attendees and invitees (organizer)
+		r.With(authHandler.AuthMiddleware).Post("/{id}/cancel", eventHandler.CancelEvent)
+		r.With(authHandler.AuthMiddleware).Post("/{id}/postpone", eventHandler.PostponeEvent)
+
+		// Publish a draft now or at a given time (organizer)
+		r.With(authHandler.AuthMiddleware).Post("/{id}/publish", eventHandler.PublishEvent)
+
+		// Revision history: list, diff and restore (organizer)
+		r.With(authHandler.AuthMiddleware).Get("/{id}/revisions", eventHandler.GetRevisions)
+		r.With(authHandler.AuthMiddleware).Get("/{id}/revisions/diff", eventHandler.DiffRevisions)
+		r.With(authHandler.AuthMiddleware).Get("/{id}/revisions/{revision}", eventHandler.GetRevision)
+		r.With(authHandler.AuthMiddleware).Post("/{id}/revisions/{revision}/restore", eventHandler.RestoreRevision)
+
+		// GET what moved the date, time or location (attendees)
+		r.With(authHandler.AuthMiddleware).Get("/{id}/changes", eventHandler.GetChanges)
+
+		r.Route("/my", func(r chi.Router) {
+			r.Use(authHandler.AuthMiddleware)
+
+			// GET events I'm attending
+			r.Get("/attending", eventHandler.GetMyAttendingEvents)
+
+			// GET events I'm organizing
+			r.Get("/organized", eventHandler.GetMyOrganizedEvents)
+
+			// Events I deleted: list and restore them before they are purged
+			r.Get("/trash", eventHandler.GetTrash)
+			r.Post("/trash/{id}/restore", eventHandler.RestoreEvent)
+		})
+	})
+
+	// User profile routes
+	r.Route("/users", func(r chi.Router) {
+		r.Route("/me", func(r chi.Router) {
+			r.Use(authHandler.AuthMiddleware)
+
+			// GET / PUT my profile
+			r.Get("/profile", profileHandler.GetMyProfile)
+			r.Put("/profile", profileHandler.UpdateMyProfile)
+
+			// Upload / remove my avatar
+			r.Put("/avatar", profileHandler.UploadAvatar)
+			r.Delete("/avatar", profileHandler.DeleteAvatar)
+
+			// Download a copy of my data
+			r.Get("/export", accountHandler.ExportData)
+
+			// Request / inspect / cancel the deletion of my account
+			r.Post("/deletion", accountHandler.RequestDeletion)
+			r.Get("/deletion", accountHandler.GetDeletionStatus)
+			r.Delete("/deletion", accountHandler.CancelDeletion)
+		})
+
+		// GET public profile of a user
+		r.Get("/{id}/profile", profileHandler.GetPublicProfile)
+	})
+
+	// Organization routes
+	r.Route("/organizations", func(r chi.Router) {
+		r.Use(authHandler.AuthMiddleware)
+
+		r.Post("/", orgHandler.CreateOrganization)
+		r.Get("/", orgHandler.GetMyOrganizations)
+		r.Get("/{id}", orgHandler.GetOrganization)
+		r.Put("/{id}", orgHandler.UpdateOrganization)
+
+		// Switch the organization bound to the token (0 = personal)
+		r.Post("/{id}/switch", orgHandler.SwitchOrganization)
+
+		// Members
+		r.Get("/{id}/members", orgHandler.GetMembers)
+		r.Post("/{id}/members", orgHandler.AddMember)
+		r.Put("/{id}/members/{userID}", orgHandler.UpdateMember)
+		r.Delete("/{id}/members/{userID}", orgHandler.RemoveMember)
+	})
+
+	// Group routes
+	r.Route("/groups", func(r chi.Router) {
+		r.Use(authHandler.AuthMiddleware)
+
+		r.Post("/", groupHandler.CreateGroup)
+		r.Get("/", groupHandler.GetMyGroups)
+		r.Get("/{id}", groupHandler.GetGroup)
+		r.Put("/{id}", groupHandler.UpdateGroup)
+		r.Delete("/{id}", groupHandler.DeleteGroup)
+
+		// Group membership
+		r.Post("/{id}/members", groupHandler.AddMembers)
+		r.Delete("/{id}/members/{memberID}", groupHandler.RemoveMember)
+	})
+
+	// Invitation routes
+	r.Route("/invitations", func(r chi.Router) {
+		r.Use(authHandler.AuthMiddleware)
+
+		// Send invitation
+		r.Post("/", invHandler.SendInvitation)
+
+		// Get my invitations
+		r.Get("/my", invHandler.GetMyInvitations)
+
+		// Respond to invitation
+		r.Put("/{id}/respond", invHandler.RespondToInvitation)
+	})
+
+	// Notification routes
+	r.Route("/notifications", func(r chi.Router) {
+		r.Use(authHandler.AuthMiddleware)
+
+		// Get my notifications
+		r.Get("/", notificationHandler.GetMyNotifications)
+
+		// Mark a notification as read
+		r.Post("/{id}/read", notificationHandler.MarkRead)
+	})
+
+	// Admin routes
+	r.Route("/admin", func(r chi.Router) {
+		r.Use(authHandler.AuthMiddleware)
+
+		// Users
+		r.With(auth.RequirePermission(auth.PermViewUsers)).Get("/users", adminHandler.ListUsers)
+		r.With(auth.RequirePermission(auth.PermViewUsers)).Get("/users/{id}", adminHandler.GetUser)
+		r.With(auth.RequirePermission(auth.PermManageUsers)).Put("/users/{id}/disable", adminHandler.DisableUser)
+		r.With(auth.RequirePermission(auth.PermManageUsers)).Put("/users/{id}/enable", adminHandler.EnableUser)
+		r.With(auth.RequirePermission(auth.PermManageUsers)).Put("/users/{id}/role", adminHandler.SetUserRole)
+
+		// Event moderation
+		r.With(auth.RequirePermission(auth.PermModerateEvents)).Put("/events/{id}", adminHandler.UpdateEvent)
+		r.With(auth.RequirePermission(auth.PermModerateEvents)).Delete("/events/{id}", adminHandler.DeleteEvent)
+
+		// Record of admin actions
+		r.With(auth.RequirePermission(auth.PermViewAdminActions)).Get("/actions", adminHandler.ListActions)
+	})
+
+	// Audit log
+	r.With(authHandler.AuthMiddleware).Get("/audit", auditHandler.List)
+
+	r.Route("/api", func(r chi.Router) {
+		r.Use(authHandler.AuthMiddleware)
+
+		r.Get("/profile", func(w http.ResponseWriter, r *http.Request) {
+			userID, ok := auth.GetUserID(r.Context())
+			if !ok {
+				response.Error(w, apperror.ErrUnauthorized)
+				return
+			}
+
+			role, _ := auth.GetRole(r.Context())
+
+			resp := map[string]interface{}{
+				"message": "This is a protected route",
+				"user_id": userID,
+				"role":    role,
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(resp)
+		})
+	})
+
+	return &App{
+		router:         r,
+		health:         healthHandler,
+		authService:    authService,
+		accountService: accountService,
+		eventService:   eventService,
+	}, nil
+}
+
+// Handler returns the HTTP handler serving every route
+func (a *App) Handler() http.Handler {
+	return a.router
+}
+
+// PromoteBootstrapAdmins grants the admin role to the accounts listed in ADMIN_EMAILS
+func (a *App) PromoteBootstrapAdmins(ctx context.Context) error {
+	return a.authService.PromoteBootstrapAdmins(ctx)
+}
+
+// Drain makes /readyz fail so load balancers stop routing requests to the
+// server before it shuts down
+func (a *App) Drain() {
+	a.health.Drain()
+}
+
+// RunWorkers runs the background workers until ctx is cancelled and returns
+// once each of them has finished the work it had started
+func (a *App) RunWorkers(ctx context.Context) {
+	var wg sync.WaitGroup
+
+	// Erase accounts whose grace period has ended
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		a.accountService.RunDeletionWorker(ctx, time.Hour)
+	}()
+
+	// Erase events that have been in the trash for the retention period
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		a.eventService.RunPurgeWorker(ctx, time.Hour)
+	}()
+
+	// Mark events that have started as completed
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		a.eventService.RunStatusWorker(ctx, time.Minute)
+	}()
+
+	wg.Wait()
+}
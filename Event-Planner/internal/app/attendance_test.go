@@ -0,0 +1,77 @@
+package app_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestAttendance(t *testing.T) {
+	run(t, func(t *testing.T, h *harness) {
+		ada := h.register("ada@example.com")
+		bob := h.register("bob@example.com")
+		carol := h.register("carol@example.com")
+
+		ev := h.createEvent(ada, "Workshop", 14, nil)
+		attendance := fmt.Sprintf("/events/%d/attendance", ev.ID)
+
+		// Joining
+		h.join(bob, ev.ID)
+		h.do("POST", fmt.Sprintf("/events/%d/join", ev.ID), bob, nil).wantError(http.StatusConflict, "already_attending")
+		h.do("POST", "/events/999999/join", bob, nil).wantError(http.StatusNotFound, "event_not_found")
+		h.do("POST", fmt.Sprintf("/events/%d/join", ev.ID), nil, nil).wantError(http.StatusUnauthorized, "missing_token")
+
+		// Attendance status
+		h.do("PUT", attendance, bob, map[string]string{"status": "maybe"}).want(http.StatusOK)
+		h.do("PUT", attendance, bob, map[string]string{"status": "late"}).wantError(http.StatusBadRequest, "validation_failed")
+		h.do("PUT", attendance, carol, map[string]string{"status": "going"}).wantError(http.StatusNotFound, "attendance_not_found")
+
+		// Inviting a user by ID adds them with the role
+		invite := fmt.Sprintf("/events/%d/invite", ev.ID)
+		h.do("POST", invite, bob, map[string]interface{}{"user_id": carol.ID, "role": "attendee"}).
+			wantError(http.StatusForbidden, "not_event_creator")
+		h.do("POST", invite, ada, map[string]interface{}{"user_id": ada.ID, "role": "attendee"}).
+			wantError(http.StatusBadRequest, "validation_failed")
+		h.do("POST", invite, ada, map[string]interface{}{"user_id": 999999, "role": "attendee"}).
+			wantError(http.StatusUnprocessableEntity, "invalid_reference")
+		h.do("POST", invite, ada, map[string]interface{}{"user_id": carol.ID, "role": "collaborator"}).want(http.StatusOK)
+
+		// Newest first, organizer included; users without a display name don't reveal their email
+		h.do("PUT", "/users/me/profile", carol, map[string]string{"display_name": "Carol C."}).want(http.StatusOK)
+		var attendees []attendeeJSON
+		h.do("GET", fmt.Sprintf("/events/%d/attendees", ev.ID), nil, nil).want(http.StatusOK).data(&attendees)
+		want := []struct {
+			userID       int
+			role, status string
+			name         string
+		}{
+			{carol.ID, "collaborator", "going", "Carol C."},
+			{bob.ID, "attendee", "maybe", fmt.Sprintf("User %d", bob.ID)},
+			{ada.ID, "organizer", "going", fmt.Sprintf("User %d", ada.ID)},
+		}
+		if len(attendees) != len(want) {
+			t.Fatalf("attendees = %+v", attendees)
+		}
+		for i, w := range want {
+			a := attendees[i]
+			if a.UserID != w.userID || a.Role != w.role || a.Status != w.status || a.User.UserID != w.userID || a.User.DisplayName != w.name {
+				t.Errorf("attendee %d = %+v, want %+v", i, a, w)
+			}
+		}
+
+		// Personal views
+		var attending []eventJSON
+		h.do("GET", "/events/my/attending", bob, nil).want(http.StatusOK).data(&attending)
+		wantEvents(t, "attending", attending, ev.ID)
+		if attending[0].Role != "attendee" || attending[0].Status != "maybe" {
+			t.Errorf("attending event = %+v", attending[0])
+		}
+
+		var organized []eventJSON
+		h.do("GET", "/events/my/organized", ada, nil).want(http.StatusOK).data(&organized)
+		wantEvents(t, "organized", organized, ev.ID)
+
+		h.do("GET", "/events/my/organized", bob, nil).want(http.StatusOK).data(&organized)
+		wantEvents(t, "organized by an attendee", organized)
+	})
+}
@@ -0,0 +1,123 @@
+package app_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type auditEntryJSON struct {
+	ActorID    *int            `json:"actor_id"`
+	Action     string          `json:"action"`
+	TargetType string          `json:"target_type"`
+	TargetID   int             `json:"target_id"`
+	EventID    *int            `json:"event_id"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+	RequestID  string          `json:"request_id"`
+	IP         string          `json:"ip"`
+	UserAgent  string          `json:"user_agent"`
+}
+
+func TestAuditLog(t *testing.T) {
+	run(t, func(t *testing.T, h *harness) {
+		ada := h.register("ada@example.com")
+		bob := h.register("bob@example.com")
+		admin := h.register("admin@example.com")
+
+		ev := h.createEvent(ada, "Launch", 14, nil)
+		path := fmt.Sprintf("/events/%d", ev.ID)
+		h.doWith("PUT", path, ada, h.ifMatch(path), map[string]string{"title": "Launch", "time": "19:30:00"}).
+			want(http.StatusOK)
+		h.join(bob, ev.ID)
+		h.do("PUT", fmt.Sprintf("/events/%d/attendance", ev.ID), bob, map[string]string{"status": "maybe"}).
+			want(http.StatusOK)
+		carol := h.register("carol@example.com")
+		inv := h.invite(ada, ev.ID, carol.Email)
+		h.do("PUT", fmt.Sprintf("/invitations/%d/respond?email=%s", inv.ID, carol.Email), carol, map[string]string{"status": "accepted"}).
+			want(http.StatusOK)
+		h.do("POST", "/auth/login", nil, map[string]string{"email": "ada@example.com", "password": "wrong-password"}).
+			want(http.StatusUnauthorized)
+
+		// The organizer sees what happened to their event, newest first
+		var entries []auditEntryJSON
+		h.do("GET", "/audit", ada, nil).want(http.StatusOK).data(&entries)
+		want := []struct {
+			action  string
+			actorID int
+			target  int
+		}{
+			{"attendee.add", carol.ID, carol.ID},
+			{"invitation.respond", carol.ID, inv.ID},
+			{"invitation.send", ada.ID, inv.ID},
+			{"attendee.status", bob.ID, bob.ID},
+			{"attendee.add", bob.ID, bob.ID},
+			{"event.update", ada.ID, ev.ID},
+			{"event.create", ada.ID, ev.ID},
+		}
+		if len(entries) != len(want) {
+			t.Fatalf("got %d entries, want %d: %+v", len(entries), len(want), entries)
+		}
+		for i, w := range want {
+			e := entries[i]
+			if e.Action != w.action || e.ActorID == nil || *e.ActorID != w.actorID || e.TargetID != w.target ||
+				e.EventID == nil || *e.EventID != ev.ID {
+				t.Errorf("entry %d = %+v, want %s by %d on %d", i, e, w.action, w.actorID, w.target)
+			}
+			if e.RequestID == "" || e.IP != "127.0.0.1" || e.UserAgent == "" {
+				t.Errorf("entry %d request metadata = %q %q %q", i, e.RequestID, e.IP, e.UserAgent)
+			}
+		}
+
+		// Updates record the changed fields only
+		if update := entries[5]; !sameJSON(update.Before, `{"time":"18:00:00","version":1}`) ||
+			!sameJSON(update.After, `{"time":"19:30:00","version":2}`) {
+			t.Errorf("event.update changes = %s -> %s", update.Before, update.After)
+		}
+		if status := entries[3]; !sameJSON(status.Before, `{"status":"going"}`) || !sameJSON(status.After, `{"status":"maybe"}`) {
+			t.Errorf("attendee.status changes = %s -> %s", status.Before, status.After)
+		}
+
+		// Erasing an account can't reach the log, so invitees' emails are masked
+		send := entries[2]
+		if !sameJSON(send.After, fmt.Sprintf(`{"id":%d,"event_id":%d,"invitee_id":%d,"invitee_email":"c***@example.com","role":"attendee","status":"pending"}`, inv.ID, ev.ID, carol.ID)) ||
+			strings.Contains(string(send.After), carol.Email) {
+			t.Errorf("invitation.send changes = %s", send.After)
+		}
+
+		// Other users don't see the entries of events they don't organize
+		h.do("GET", "/audit", bob, nil).want(http.StatusOK).data(&entries)
+		if len(entries) != 0 {
+			t.Errorf("bob sees %+v", entries)
+		}
+		h.do("GET", fmt.Sprintf("/audit?event_id=%d", ev.ID), bob, nil).want(http.StatusOK).data(&entries)
+		if len(entries) != 0 {
+			t.Errorf("bob sees %+v", entries)
+		}
+		h.do("GET", "/audit", nil, nil).wantError(http.StatusUnauthorized, "missing_token")
+
+		// Admins see everything, including auth events
+		h.do("GET", "/audit?action=user.login_failed", admin, nil).want(http.StatusOK).data(&entries)
+		if len(entries) != 1 || entries[0].ActorID != nil || entries[0].TargetID != ada.ID ||
+			!sameJSON(entries[0].After, `{"email":"a***@example.com","reason":"invalid_credentials"}`) {
+			t.Errorf("failed logins = %+v", entries)
+		}
+		h.do("GET", fmt.Sprintf("/audit?actor_id=%d&action=user.register", carol.ID), admin, nil).want(http.StatusOK).data(&entries)
+		if len(entries) != 1 || entries[0].TargetType != "user" || entries[0].TargetID != carol.ID {
+			t.Errorf("registrations = %+v", entries)
+		}
+	})
+}
+
+// sameJSON reports whether a document has the value of want, whatever its
+// formatting and key order (PostgreSQL normalizes JSONB)
+func sameJSON(raw json.RawMessage, want string) bool {
+	var got, expected interface{}
+	if json.Unmarshal(raw, &got) != nil || json.Unmarshal([]byte(want), &expected) != nil {
+		return false
+	}
+	return reflect.DeepEqual(got, expected)
+}
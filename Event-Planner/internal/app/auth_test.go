@@ -0,0 +1,77 @@
+package app_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestAuth(t *testing.T) {
+	run(t, func(t *testing.T, h *harness) {
+		ada := h.register("ada@example.com")
+		if ada.ID == 0 || ada.Token == "" {
+			t.Fatalf("register = %+v", ada)
+		}
+
+		credentials := map[string]string{"email": "ada@example.com", "password": "secret-password"}
+
+		h.do("POST", "/auth/register", nil, credentials).wantError(http.StatusConflict, "email_taken")
+		h.do("POST", "/auth/register", nil, map[string]string{"email": "bob@example.com", "password": "short"}).
+			wantError(http.StatusBadRequest, "validation_failed")
+		h.do("POST", "/auth/register", nil, map[string]string{"email": "bob@example.com"}).
+			wantError(http.StatusBadRequest, "validation_failed")
+
+		// Login
+		var auth struct {
+			Token string `json:"token"`
+		}
+		h.do("POST", "/auth/login", nil, credentials).want(http.StatusOK).decode(&auth)
+		if auth.Token == "" {
+			t.Fatal("login returned no token")
+		}
+
+		h.do("POST", "/auth/login", nil, map[string]string{"email": "ada@example.com", "password": "wrong-password"}).
+			wantError(http.StatusUnauthorized, "invalid_credentials")
+		h.do("POST", "/auth/login", nil, map[string]string{"email": "nobody@example.com", "password": "secret-password"}).
+			wantError(http.StatusUnauthorized, "invalid_credentials")
+
+		// The token from logging in identifies the same account
+		var profile struct {
+			UserID int `json:"user_id"`
+		}
+		h.do("GET", "/api/profile", &account{Token: auth.Token}, nil).want(http.StatusOK).decode(&profile)
+		if profile.UserID != ada.ID {
+			t.Errorf("login token is for user %d, want %d", profile.UserID, ada.ID)
+		}
+
+		// Protected routes
+		h.do("GET", "/api/profile", nil, nil).wantError(http.StatusUnauthorized, "missing_token")
+		h.do("GET", "/api/profile", &account{Token: "not-a-token"}, nil).wantError(http.StatusUnauthorized, "invalid_token")
+		h.do("GET", "/events/my/attending", nil, nil).wantError(http.StatusUnauthorized, "missing_token")
+
+		// A stale token does not lock the client out of public routes
+		expired, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"user_id": ada.ID,
+			"exp":     time.Now().Add(-time.Hour).Unix(),
+		}).SignedString([]byte("test-secret"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		stale := &account{Token: expired}
+		h.do("POST", "/auth/login", stale, credentials).want(http.StatusOK)
+		h.do("POST", "/auth/register", &account{Token: "not-a-token"}, map[string]string{"email": "bob@example.com", "password": "secret-password"}).
+			want(http.StatusOK)
+		h.do("GET", "/health", stale, nil).want(http.StatusOK)
+		h.do("GET", "/api/profile", stale, nil).wantError(http.StatusUnauthorized, "invalid_token")
+		h.do("GET", "/events/my/attending", stale, nil).wantError(http.StatusUnauthorized, "invalid_token")
+	})
+}
+
+func TestUnknownRoute(t *testing.T) {
+	run(t, func(t *testing.T, h *harness) {
+		h.do("GET", "/nothing-here", nil, nil).wantError(http.StatusNotFound, "route_not_found")
+		h.do("PATCH", "/auth/login", nil, nil).wantError(http.StatusMethodNotAllowed, "method_not_allowed")
+	})
+}
@@ -0,0 +1,108 @@
+package app_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type draftJSON struct {
+	eventJSON
+	PublishAt *time.Time `json:"publish_at"`
+}
+
+func TestDraftEvents(t *testing.T) {
+	run(t, func(t *testing.T, h *harness) {
+		ada := h.register("ada@example.com")
+		bob := h.register("bob@example.com")
+		carol := h.register("carol@example.com")
+
+		published := h.createEvent(ada, "Published", 10, nil)
+
+		// Only drafts can be published later, before they start
+		h.do("POST", "/events/", ada, map[string]interface{}{
+			"title": "Launch", "date": future(14), "time": "18:00:00", "location": "Main Hall",
+			"publish_at": time.Now().Add(time.Hour),
+		}).wantError(http.StatusBadRequest, "validation_failed")
+		for _, publishAt := range []time.Time{time.Now().Add(-time.Hour), time.Now().AddDate(0, 0, 20)} {
+			h.do("POST", "/events/", ada, map[string]interface{}{
+				"title": "Launch", "date": future(14), "time": "18:00:00", "location": "Main Hall",
+				"draft": true, "publish_at": publishAt,
+			}).want(http.StatusBadRequest)
+		}
+
+		ev := h.createEvent(ada, "Launch", 14, map[string]interface{}{"draft": true})
+		if ev.Status != "draft" {
+			t.Fatalf("status of a draft = %q", ev.Status)
+		}
+		path := fmt.Sprintf("/events/%d", ev.ID)
+
+		// Drafts are hidden from everyone but the organizer and collaborators
+		h.do("GET", path, nil, nil).wantError(http.StatusNotFound, "event_not_found")
+		h.do("GET", path, bob, nil).wantError(http.StatusNotFound, "event_not_found")
+		h.do("GET", path+"/attendees", nil, nil).wantError(http.StatusNotFound, "event_not_found")
+		h.do("GET", path, ada, nil).want(http.StatusOK)
+		h.do("POST", path+"/invite", ada, map[string]interface{}{"user_id": bob.ID, "role": "collaborator"}).want(http.StatusOK)
+		h.do("GET", path, bob, nil).want(http.StatusOK)
+		h.do("GET", path+"/attendees", bob, nil).want(http.StatusOK)
+
+		var events []eventJSON
+		h.do("GET", "/events/", nil, nil).want(http.StatusOK).data(&events)
+		wantEvents(t, "GET /events/", events, published.ID)
+		h.do("GET", fmt.Sprintf("/events/organizer/%d", ada.ID), nil, nil).want(http.StatusOK).data(&events)
+		wantEvents(t, "GET /events/organizer/{id}", events, published.ID)
+		h.do("GET", "/events/search?q=launch", bob, nil).want(http.StatusOK).data(&events)
+		wantEvents(t, "GET /events/search", events)
+		h.do("GET", "/events/my/organized", ada, nil).want(http.StatusOK).data(&events)
+		wantEvents(t, "GET /events/my/organized", events, ev.ID, published.ID)
+
+		// They can't be joined, and only collaborators can be invited, by collaborators
+		h.do("POST", path+"/join", carol, nil).wantError(http.StatusNotFound, "event_not_found")
+		h.do("POST", path+"/join", bob, nil).wantError(http.StatusConflict, "event_draft")
+		h.do("POST", path+"/invite", ada, map[string]interface{}{"user_id": carol.ID, "role": "attendee"}).
+			wantError(http.StatusConflict, "event_draft")
+		h.do("POST", "/invitations", ada, map[string]interface{}{"event_id": ev.ID, "invitee_email": carol.Email, "role": "attendee"}).
+			wantError(http.StatusConflict, "event_draft")
+		h.do("POST", "/invitations", carol, map[string]interface{}{"event_id": ev.ID, "invitee_email": carol.Email, "role": "collaborator"}).
+			wantError(http.StatusNotFound, "event_not_found")
+		dave := h.register("dave@example.com")
+		var inv invitationJSON
+		h.do("POST", "/invitations", bob, map[string]interface{}{"event_id": ev.ID, "invitee_email": dave.Email, "role": "collaborator"}).
+			want(http.StatusCreated).data(&inv)
+		h.do("PUT", fmt.Sprintf("/invitations/%d/respond?email=%s", inv.ID, dave.Email), dave, map[string]string{"status": "accepted"}).want(http.StatusOK)
+		h.do("GET", path, dave, nil).want(http.StatusOK)
+		h.do("GET", path+"/invitations", carol, nil).wantError(http.StatusNotFound, "event_not_found")
+		var invitations []invitationJSON
+		h.do("GET", path+"/invitations", bob, nil).want(http.StatusOK).data(&invitations)
+		if len(invitations) != 1 || invitations[0].ID != inv.ID {
+			t.Errorf("invitations of the draft = %+v", invitations)
+		}
+
+		// Its organizer publishes it later, or now
+		publish := path + "/publish"
+		publishAt := time.Now().Add(24 * time.Hour).Truncate(time.Second).UTC()
+		h.doWith("POST", publish, bob, http.Header{"If-Match": {"*"}}, nil).wantError(http.StatusForbidden, "not_event_organizer")
+		h.do("POST", publish, ada, nil).wantError(http.StatusPreconditionRequired, "if_match_required")
+
+		var scheduled draftJSON
+		res := h.doWith("POST", publish, ada, http.Header{"If-Match": {`"1"`}}, map[string]interface{}{"publish_at": publishAt}).want(http.StatusOK)
+		res.data(&scheduled)
+		if scheduled.Status != "draft" || scheduled.PublishAt == nil || !scheduled.PublishAt.Equal(publishAt) || res.Header.Get("ETag") != `"2"` {
+			t.Errorf("scheduled draft = %+v, publish at %v, ETag %q", scheduled, scheduled.PublishAt, res.Header.Get("ETag"))
+		}
+		h.do("GET", path, nil, nil).wantError(http.StatusNotFound, "event_not_found")
+
+		var now draftJSON
+		h.doWith("POST", publish, ada, http.Header{"If-Match": {`"2"`}}, nil).want(http.StatusOK).data(&now)
+		if now.Status != "scheduled" || now.PublishAt != nil || now.Version != 3 {
+			t.Errorf("published event = %+v, publish at %v", now, now.PublishAt)
+		}
+		h.doWith("POST", publish, ada, http.Header{"If-Match": {"*"}}, nil).wantError(http.StatusConflict, "event_published")
+
+		h.do("GET", path, nil, nil).want(http.StatusOK)
+		h.do("GET", "/events/", nil, nil).want(http.StatusOK).data(&events)
+		wantEvents(t, "GET /events/ after publishing", events, ev.ID, published.ID)
+		h.join(carol, ev.ID)
+	})
+}
@@ -0,0 +1,209 @@
+package app_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestEvents(t *testing.T) {
+	run(t, func(t *testing.T, h *harness) {
+		ada := h.register("ada@example.com")
+		bob := h.register("bob@example.com")
+
+		launch := h.createEvent(ada, "Launch", 30, nil)
+		if launch.ID == 0 || launch.OrganizerID != ada.ID || launch.Date != future(30) || launch.Time != "18:00:00" ||
+			launch.Visibility != "public" || launch.Timezone != "UTC" {
+			t.Errorf("created event = %+v", launch)
+		}
+		party := h.createEvent(bob, "Party", 10, nil)
+
+		// Creating requires a token and a complete event in the future
+		h.do("POST", "/events/", nil, map[string]string{"title": "x"}).wantError(http.StatusUnauthorized, "missing_token")
+		h.do("POST", "/events/", ada, map[string]string{"title": "No date", "time": "18:00:00", "location": "x"}).
+			wantError(http.StatusBadRequest, "validation_failed")
+		h.do("POST", "/events/", ada, map[string]string{"title": "Past", "date": "2001-01-01", "time": "18:00:00", "location": "x"}).
+			wantError(http.StatusBadRequest, "validation_failed")
+		h.do("POST", "/events/", ada, map[string]string{"title": "Hidden", "date": future(5), "time": "18:00:00", "location": "x", "visibility": "organization"}).
+			wantError(http.StatusBadRequest, "validation_failed")
+
+		// Reading is public
+		var got eventJSON
+		h.do("GET", fmt.Sprintf("/events/%d", launch.ID), nil, nil).want(http.StatusOK).data(&got)
+		if got != launch {
+			t.Errorf("GET event = %+v, want %+v", got, launch)
+		}
+		h.do("GET", "/events/999999", nil, nil).wantError(http.StatusNotFound, "event_not_found")
+
+		var all []eventJSON
+		h.do("GET", "/events/", nil, nil).want(http.StatusOK).data(&all)
+		wantEvents(t, "GET /events/", all, launch.ID, party.ID)
+
+		var page []eventJSON
+		h.do("GET", "/events/?limit=1&offset=1", nil, nil).want(http.StatusOK).data(&page)
+		wantEvents(t, "second page", page, party.ID)
+
+		var byOrganizer []eventJSON
+		h.do("GET", fmt.Sprintf("/events/organizer/%d", bob.ID), nil, nil).want(http.StatusOK).data(&byOrganizer)
+		wantEvents(t, "events by organizer", byOrganizer, party.ID)
+
+		// Only the organizer can change or delete an event
+		h.do("PUT", fmt.Sprintf("/events/%d", launch.ID), bob, map[string]string{"title": "Taken over"}).
+			wantError(http.StatusForbidden, "not_event_organizer")
+		h.do("DELETE", fmt.Sprintf("/events/%d", launch.ID), bob, nil).wantError(http.StatusForbidden, "not_event_organizer")
+		h.do("PUT", fmt.Sprintf("/events/%d", launch.ID), nil, map[string]string{"title": "x"}).wantError(http.StatusUnauthorized, "missing_token")
+
+		var updated eventJSON
+		path := fmt.Sprintf("/events/%d", launch.ID)
+		h.doWith("PUT", path, ada, h.ifMatch(path), map[string]string{"title": "Launch party", "time": "19:30:00"}).
+			want(http.StatusOK).data(&updated)
+		if updated.Title != "Launch party" || updated.Time != "19:30:00" || updated.Location != "Main Hall" {
+			t.Errorf("updated event = %+v", updated)
+		}
+		h.do("PUT", "/events/999999", ada, map[string]string{"title": "x"}).wantError(http.StatusNotFound, "event_not_found")
+
+		h.doWith("DELETE", path, ada, h.ifMatch(path), nil).want(http.StatusOK)
+		h.do("GET", fmt.Sprintf("/events/%d", launch.ID), nil, nil).wantError(http.StatusNotFound, "event_not_found")
+		h.do("DELETE", fmt.Sprintf("/events/%d", launch.ID), ada, nil).wantError(http.StatusNotFound, "event_not_found")
+
+		var organized []eventJSON
+		h.do("GET", "/events/my/organized", ada, nil).want(http.StatusOK).data(&organized)
+		wantEvents(t, "organized after delete", organized)
+	})
+}
+
+func TestEventETags(t *testing.T) {
+	run(t, func(t *testing.T, h *harness) {
+		ada := h.register("ada@example.com")
+		bob := h.register("bob@example.com")
+
+		ev := h.createEvent(ada, "Launch", 30, nil)
+		path := fmt.Sprintf("/events/%d", ev.ID)
+		if ev.Version != 1 {
+			t.Errorf("created event version = %d, want 1", ev.Version)
+		}
+
+		// Reads carry the version as ETag; polling with it returns no body while it is current
+		etag := h.do("GET", path, nil, nil).want(http.StatusOK).Header.Get("ETag")
+		if etag != `"1"` {
+			t.Errorf("ETag = %q, want %q", etag, `"1"`)
+		}
+		for _, tag := range []string{etag, "W/" + etag, `"7", ` + etag, "*"} {
+			res := h.doWith("GET", path, nil, http.Header{"If-None-Match": {tag}}, nil).want(http.StatusNotModified)
+			if len(res.Body) != 0 || res.Header.Get("ETag") != etag {
+				t.Errorf("If-None-Match %s: body %q, ETag %q", tag, res.Body, res.Header.Get("ETag"))
+			}
+		}
+		h.doWith("GET", path, nil, http.Header{"If-None-Match": {`"7"`}}, nil).want(http.StatusOK)
+
+		// Changes must name the version they were made to
+		update := map[string]string{"location": "Roof"}
+		h.do("PUT", path, ada, update).wantError(http.StatusPreconditionRequired, "if_match_required")
+		h.do("DELETE", path, ada, nil).wantError(http.StatusPreconditionRequired, "if_match_required")
+		h.doWith("PUT", path, bob, http.Header{"If-Match": {etag}}, update).wantError(http.StatusForbidden, "not_event_organizer")
+
+		res := h.doWith("PUT", path, ada, http.Header{"If-Match": {etag}}, update).want(http.StatusOK)
+		var updated eventJSON
+		res.data(&updated)
+		if updated.Version != 2 || res.Header.Get("ETag") != `"2"` {
+			t.Errorf("updated event version %d, ETag %q; want 2", updated.Version, res.Header.Get("ETag"))
+		}
+
+		// A second client still holding the first version can't overwrite the update
+		h.doWith("PUT", path, ada, http.Header{"If-Match": {etag}}, map[string]string{"location": "Cellar"}).
+			wantError(http.StatusPreconditionFailed, "version_mismatch")
+		h.doWith("DELETE", path, ada, http.Header{"If-Match": {etag}}, nil).wantError(http.StatusPreconditionFailed, "version_mismatch")
+		h.doWith("PUT", path, ada, http.Header{"If-Match": {"W/" + `"2"`}}, update).wantError(http.StatusPreconditionFailed, "version_mismatch")
+
+		var got eventJSON
+		h.doWith("GET", path, nil, http.Header{"If-None-Match": {etag}}, nil).want(http.StatusOK).data(&got)
+		if got.Location != "Roof" || got.Version != 2 {
+			t.Errorf("event after stale writes = %+v", got)
+		}
+
+		// "*" matches any version
+		h.doWith("PUT", path, ada, http.Header{"If-Match": {"*"}}, map[string]string{"location": "Cellar"}).want(http.StatusOK)
+		h.doWith("DELETE", path, ada, http.Header{"If-Match": {"*"}}, nil).want(http.StatusOK)
+	})
+}
+
+func TestPatchEvent(t *testing.T) {
+	run(t, func(t *testing.T, h *harness) {
+		ada := h.register("ada@example.com")
+		bob := h.register("bob@example.com")
+
+		ev := h.createEvent(ada, "Launch", 30, nil)
+		path := fmt.Sprintf("/events/%d", ev.ID)
+		patch := func(as *account, body string) *result {
+			t.Helper()
+
+			header := h.ifMatch(path)
+			header.Set("Content-Type", "application/merge-patch+json")
+			return h.doWith("PATCH", path, as, header, json.RawMessage(body))
+		}
+
+		// Absent fields are kept and null clears the description
+		var patched eventJSON
+		patch(ada, `{"description": null, "location": "Roof"}`).want(http.StatusOK).data(&patched)
+		if patched.Description != "" || patched.Location != "Roof" || patched.Title != "Launch" ||
+			patched.Date != ev.Date || patched.Time != ev.Time || patched.Version != 2 {
+			t.Errorf("patched event = %+v", patched)
+		}
+		patch(ada, `{"description": "Back again"}`).want(http.StatusOK).data(&patched)
+		if patched.Description != "Back again" {
+			t.Errorf("description = %q", patched.Description)
+		}
+
+		// The merged event is validated as a whole
+		patch(ada, `{"date": "2001-01-01"}`).wantError(http.StatusBadRequest, "validation_failed")
+		patch(ada, `{"time": "25:00:00"}`).wantError(http.StatusBadRequest, "validation_failed")
+		patch(ada, `{"title": ""}`).wantError(http.StatusBadRequest, "validation_failed")
+		patch(ada, `{"title": null}`).wantError(http.StatusBadRequest, "validation_failed")
+		patch(ada, `{"visibility": "organization"}`).wantError(http.StatusBadRequest, "validation_failed")
+		patch(ada, `{"organizer_id": 2}`).wantError(http.StatusBadRequest, "validation_failed")
+		patch(ada, `["title"]`).wantError(http.StatusBadRequest, "validation_failed")
+
+		// Like PUT it is for the organizer and needs the current version
+		patch(bob, `{"title": "Taken over"}`).wantError(http.StatusForbidden, "not_event_organizer")
+		h.do("PATCH", path, ada, map[string]string{"title": "x"}).wantError(http.StatusPreconditionRequired, "if_match_required")
+		h.doWith("PATCH", path, ada, http.Header{"If-Match": {`"1"`}}, map[string]string{"title": "x"}).
+			wantError(http.StatusPreconditionFailed, "version_mismatch")
+
+		var got eventJSON
+		h.do("GET", path, nil, nil).want(http.StatusOK).data(&got)
+		if got.Title != "Launch" || got.Description != "Back again" || got.Location != "Roof" || got.Version != 3 {
+			t.Errorf("event after rejected patches = %+v", got)
+		}
+	})
+}
+
+func TestUpdateEventChecksTheResultingStart(t *testing.T) {
+	run(t, func(t *testing.T, h *harness) {
+		ada := h.register("ada@example.com")
+
+		// Moving only the date (or time) still has to leave the event in the future
+		ev := h.createEvent(ada, "Launch", 30, nil)
+		path := fmt.Sprintf("/events/%d", ev.ID)
+		h.doWith("PUT", path, ada, h.ifMatch(path), map[string]string{"date": "2001-01-01"}).
+			wantError(http.StatusBadRequest, "validation_failed")
+
+		today := h.createEvent(ada, "Today", 0, map[string]interface{}{"time": "23:59:59"})
+		path = fmt.Sprintf("/events/%d", today.ID)
+		h.doWith("PUT", path, ada, h.ifMatch(path), map[string]string{"time": "00:00:00"}).
+			wantError(http.StatusBadRequest, "validation_failed")
+	})
+}
+
+// wantEvents checks the IDs and order of a list of events
+func wantEvents(t *testing.T, what string, events []eventJSON, ids ...int) {
+	t.Helper()
+
+	got := make([]int, len(events))
+	for i, ev := range events {
+		got[i] = ev.ID
+	}
+	if fmt.Sprint(got) != fmt.Sprint(ids) {
+		t.Errorf("%s: got events %v, want %v", what, got, ids)
+	}
+}
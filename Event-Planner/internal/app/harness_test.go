@@ -0,0 +1,299 @@
+package app_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"event-planner/internal/app"
+	"event-planner/internal/auth"
+	"event-planner/internal/memstore"
+	"event-planner/internal/storetest"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// run runs an end-to-end test against the in-memory store and, when
+// TEST_DATABASE_URL is set, against a fresh PostgreSQL schema
+func run(t *testing.T, test func(t *testing.T, h *harness)) {
+	t.Run("memory", func(t *testing.T) {
+		s := memstore.New()
+		test(t, newHarness(t, nil, &app.Stores{
+			Users:         s.Users(),
+			Events:        s.Events(),
+			Invitations:   s.Invitations(),
+			Search:        s.Search(),
+			Notifications: s.Notifications(),
+			Audit:         s.Audit(),
+			Organizations: s.Organizations(),
+			Groups:        s.Groups(),
+			Profiles:      s.Profiles(),
+			Admin:         s.Admin(),
+			Accounts:      s.Accounts(),
+		}))
+	})
+
+	t.Run("postgres", func(t *testing.T) {
+		test(t, newHarness(t, storetest.PostgresPool(t), nil))
+	})
+}
+
+// harness serves the real router and sends requests to it
+type harness struct {
+	t   *testing.T
+	app *app.App
+	srv *httptest.Server
+}
+
+func newHarness(t *testing.T, pool *pgxpool.Pool, stores *app.Stores) *harness {
+	t.Helper()
+
+	application, err := app.New(pool, app.Options{
+		// admin@example.com registers as an admin
+		Auth:      auth.Config{JWTSecret: "test-secret", AdminEmails: []string{"admin@example.com"}},
+		AvatarDir: t.TempDir(),
+		Stores:    stores,
+	})
+	if err != nil {
+		t.Fatalf("app.New: %v", err)
+	}
+
+	srv := httptest.NewServer(application.Handler())
+	t.Cleanup(srv.Close)
+	return &harness{t: t, app: application, srv: srv}
+}
+
+// account is a registered user and the token it is logged in with
+type account struct {
+	ID    int
+	Email string
+	Token string
+}
+
+// result is a received response
+type result struct {
+	t      *testing.T
+	req    string
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+// do sends a request with body encoded as JSON (readers are sent as they are),
+// authenticated as the account when it is not nil
+func (h *harness) do(method, path string, as *account, body interface{}) *result {
+	h.t.Helper()
+
+	return h.doWith(method, path, as, nil, body)
+}
+
+// doWith is do with extra request headers
+func (h *harness) doWith(method, path string, as *account, header http.Header, body interface{}) *result {
+	h.t.Helper()
+
+	var reader io.Reader
+	switch b := body.(type) {
+	case nil:
+	case io.Reader:
+		reader = b
+	default:
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			h.t.Fatalf("encode request body: %v", err)
+		}
+		reader = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequest(method, h.srv.URL+path, reader)
+	if err != nil {
+		h.t.Fatalf("new request: %v", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if as != nil {
+		req.Header.Set("Authorization", "Bearer "+as.Token)
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		h.t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		h.t.Fatalf("%s %s: read body: %v", method, path, err)
+	}
+	return &result{t: h.t, req: method + " " + path, Status: resp.StatusCode, Header: resp.Header, Body: data}
+}
+
+// want fails the test unless the response has the status
+func (r *result) want(status int) *result {
+	r.t.Helper()
+
+	if r.Status != status {
+		r.t.Fatalf("%s: got status %d, want %d: %s", r.req, r.Status, status, r.Body)
+	}
+	return r
+}
+
+// wantError fails the test unless the response is an error envelope with the status and code
+func (r *result) wantError(status int, code string) {
+	r.t.Helper()
+
+	r.want(status)
+	var body struct {
+		Error string `json:"error"`
+		Code  string `json:"code"`
+	}
+	r.decode(&body)
+	if body.Code != code || body.Error == "" {
+		r.t.Fatalf("%s: got error %s, want code %q", r.req, r.Body, code)
+	}
+}
+
+// decode decodes the JSON body into v
+func (r *result) decode(v interface{}) {
+	r.t.Helper()
+
+	if err := json.Unmarshal(r.Body, v); err != nil {
+		r.t.Fatalf("%s: decode %s: %v", r.req, r.Body, err)
+	}
+}
+
+// data decodes the "data" member of the body into v
+func (r *result) data(v interface{}) {
+	r.t.Helper()
+
+	envelope := struct {
+		Data interface{} `json:"data"`
+	}{Data: v}
+	r.decode(&envelope)
+}
+
+// Fixtures
+
+type eventJSON struct {
+	ID          int    `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Date        string `json:"date"`
+	Time        string `json:"time"`
+	Location    string `json:"location"`
+	OrganizerID int    `json:"organizer_id"`
+	Visibility  string `json:"visibility"`
+	Timezone    string `json:"timezone"`
+	Version     int    `json:"version"`
+
+	// The status of the event, except in the views of a user's events where
+	// it is their attendance and the status of the event is EventStatus
+	Status       string `json:"status"`
+	StatusReason string `json:"status_reason"`
+	Role         string `json:"role"`
+	EventStatus  string `json:"event_status"`
+}
+
+type attendeeJSON struct {
+	UserID int    `json:"user_id"`
+	Role   string `json:"role"`
+	Status string `json:"status"`
+	User   struct {
+		UserID      int    `json:"user_id"`
+		DisplayName string `json:"display_name"`
+	} `json:"user"`
+}
+
+type invitationJSON struct {
+	ID           int    `json:"id"`
+	EventID      int    `json:"event_id"`
+	InviterID    int    `json:"inviter_id"`
+	InviteeEmail string `json:"invitee_email"`
+	InviteeID    *int   `json:"invitee_id"`
+	Role         string `json:"role"`
+	Status       string `json:"status"`
+	EventTitle   string `json:"event_title"`
+	InviterEmail string `json:"inviter_email"`
+}
+
+// register creates an account and returns it logged in
+func (h *harness) register(email string) *account {
+	h.t.Helper()
+
+	var auth struct {
+		Token string `json:"token"`
+	}
+	h.do("POST", "/auth/register", nil, map[string]string{"email": email, "password": "secret-password"}).want(http.StatusOK).decode(&auth)
+
+	a := &account{Email: email, Token: auth.Token}
+	var profile struct {
+		UserID int `json:"user_id"`
+	}
+	h.do("GET", "/api/profile", a, nil).want(http.StatusOK).decode(&profile)
+	a.ID = profile.UserID
+	return a
+}
+
+// createEvent creates an event days from now; fields override the defaults
+func (h *harness) createEvent(as *account, title string, days int, fields map[string]interface{}) eventJSON {
+	h.t.Helper()
+
+	body := map[string]interface{}{
+		"title":       title,
+		"description": title + " description",
+		"date":        future(days),
+		"time":        "18:00:00",
+		"location":    "Main Hall",
+	}
+	for k, v := range fields {
+		body[k] = v
+	}
+
+	var ev eventJSON
+	h.do("POST", "/events/", as, body).want(http.StatusCreated).data(&ev)
+	return ev
+}
+
+// ifMatch returns an If-Match header with the current ETag of the resource at path
+func (h *harness) ifMatch(path string) http.Header {
+	h.t.Helper()
+
+	etag := h.do("GET", path, nil, nil).want(http.StatusOK).Header.Get("ETag")
+	if etag == "" {
+		h.t.Fatalf("GET %s: no ETag", path)
+	}
+	return http.Header{"If-Match": {etag}}
+}
+
+// join makes the account attend an event
+func (h *harness) join(as *account, eventID int) {
+	h.t.Helper()
+
+	h.do("POST", fmt.Sprintf("/events/%d/join", eventID), as, nil).want(http.StatusOK)
+}
+
+// invite sends an invitation to an email as the account
+func (h *harness) invite(as *account, eventID int, email string) invitationJSON {
+	h.t.Helper()
+
+	var inv invitationJSON
+	h.do("POST", "/invitations", as, map[string]interface{}{
+		"event_id":      eventID,
+		"invitee_email": email,
+		"role":          "attendee",
+	}).want(http.StatusCreated).data(&inv)
+	return inv
+}
+
+// future is the date days from now
+func future(days int) string {
+	return time.Now().AddDate(0, 0, days).Format("2006-01-02")
+}
@@ -0,0 +1,38 @@
+package app_test
+
+import (
+	"net/http"
+	"testing"
+
+	"event-planner/internal/health"
+)
+
+func TestProbes(t *testing.T) {
+	run(t, func(t *testing.T, h *harness) {
+		var live health.Report
+		h.do("GET", "/livez", nil, nil).want(http.StatusOK).decode(&live)
+		if live.Status != "ok" {
+			t.Errorf("livez = %+v", live)
+		}
+
+		var ready health.Report
+		h.do("GET", "/readyz", nil, nil).want(http.StatusOK).decode(&ready)
+		if ready.Status != "ok" {
+			t.Errorf("readyz = %+v", ready)
+		}
+		for name, result := range ready.Checks {
+			if result != "ok" {
+				t.Errorf("readyz check %s = %q", name, result)
+			}
+		}
+
+		// Draining fails readiness but not liveness, and requests are still served
+		h.app.Drain()
+		h.do("GET", "/readyz", nil, nil).want(http.StatusServiceUnavailable).decode(&ready)
+		if ready.Status != "unavailable" || ready.Checks["shutdown"] == "" {
+			t.Errorf("readyz while draining = %+v", ready)
+		}
+		h.do("GET", "/livez", nil, nil).want(http.StatusOK)
+		h.do("GET", "/events/", nil, nil).want(http.StatusOK)
+	})
+}
@@ -0,0 +1,75 @@
+package app_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestInvitations(t *testing.T) {
+	run(t, func(t *testing.T, h *harness) {
+		ada := h.register("ada@example.com")
+		bob := h.register("bob@example.com")
+		carol := h.register("carol@example.com")
+
+		ev := h.createEvent(ada, "Dinner", 7, nil)
+
+		inv := h.invite(ada, ev.ID, "bob@example.com")
+		if inv.ID == 0 || inv.EventID != ev.ID || inv.InviterID != ada.ID || inv.Status != "pending" ||
+			inv.InviteeID == nil || *inv.InviteeID != bob.ID {
+			t.Errorf("invitation = %+v", inv)
+		}
+		guest := h.invite(ada, ev.ID, "guest@example.com")
+		if guest.InviteeID != nil {
+			t.Errorf("invitation to an email without an account = %+v", guest)
+		}
+
+		send := func(body map[string]interface{}) *result {
+			return h.do("POST", "/invitations", ada, body)
+		}
+		send(map[string]interface{}{"event_id": ev.ID, "invitee_email": "not-an-email", "role": "attendee"}).
+			wantError(http.StatusBadRequest, "validation_failed")
+		send(map[string]interface{}{"event_id": ev.ID, "role": "attendee"}).wantError(http.StatusBadRequest, "validation_failed")
+		send(map[string]interface{}{"event_id": 999999, "invitee_email": "x@example.com", "role": "attendee"}).
+			wantError(http.StatusNotFound, "event_not_found")
+		h.do("POST", "/invitations", nil, map[string]interface{}{"event_id": ev.ID, "invitee_email": "x@example.com"}).
+			wantError(http.StatusUnauthorized, "missing_token")
+
+		// Listing
+		var mine []invitationJSON
+		h.do("GET", "/invitations/my?email=bob@example.com", bob, nil).want(http.StatusOK).data(&mine)
+		if len(mine) != 1 || mine[0].ID != inv.ID || mine[0].EventTitle != "Dinner" || mine[0].InviterEmail != "ada@example.com" {
+			t.Errorf("my invitations = %+v", mine)
+		}
+		h.do("GET", "/invitations/my", bob, nil).wantError(http.StatusBadRequest, "validation_failed")
+
+		var forEvent []invitationJSON
+		h.do("GET", fmt.Sprintf("/events/%d/invitations", ev.ID), ada, nil).want(http.StatusOK).data(&forEvent)
+		if len(forEvent) != 2 || forEvent[0].ID != guest.ID || forEvent[1].ID != inv.ID {
+			t.Errorf("event invitations = %+v", forEvent)
+		}
+
+		// Responding
+		respond := func(as *account, id int, status string) *result {
+			return h.do("PUT", fmt.Sprintf("/invitations/%d/respond?email=%s", id, as.Email), as, map[string]string{"status": status})
+		}
+		respond(carol, inv.ID, "accepted").wantError(http.StatusForbidden, "not_invitee")
+		respond(bob, inv.ID, "maybe").wantError(http.StatusBadRequest, "validation_failed")
+		respond(bob, 999999, "accepted").wantError(http.StatusNotFound, "invitation_not_found")
+		respond(bob, inv.ID, "accepted").want(http.StatusOK)
+		respond(bob, inv.ID, "declined").wantError(http.StatusConflict, "invitation_already_responded")
+
+		h.do("GET", "/invitations/my?email=bob@example.com", bob, nil).want(http.StatusOK).data(&mine)
+		if len(mine) != 1 || mine[0].Status != "accepted" {
+			t.Errorf("my invitations after accepting = %+v", mine)
+		}
+
+		// Accepting adds the invitee as an attendee with the invited role
+		var attending []eventJSON
+		h.do("GET", "/events/my/attending", bob, nil).want(http.StatusOK).data(&attending)
+		wantEvents(t, "attending after accepting", attending, ev.ID)
+		if attending[0].Role != "attendee" {
+			t.Errorf("role after accepting = %q", attending[0].Role)
+		}
+	})
+}
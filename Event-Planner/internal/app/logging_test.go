@@ -0,0 +1,79 @@
+package app_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"event-planner/internal/config"
+	"event-planner/internal/logging"
+)
+
+// syncBuffer collects the lines logged by the server goroutines
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestRequestLogs(t *testing.T) {
+	run(t, func(t *testing.T, h *harness) {
+		logs := &syncBuffer{}
+		logger, err := logging.New(logs, config.Log{Level: "info", Format: "json"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		previous := slog.Default()
+		slog.SetDefault(logger)
+		t.Cleanup(func() { slog.SetDefault(previous) })
+
+		ada := h.register("ada@example.com")
+		h.do("GET", "/api/profile", ada, nil).want(http.StatusOK)
+		h.do("POST", "/auth/login", nil, map[string]string{"email": "ada@example.com", "password": "wrong-password"}).
+			want(http.StatusUnauthorized)
+		h.do("GET", "/livez", nil, nil).want(http.StatusOK)
+
+		var request, loginFailed map[string]interface{}
+		for _, raw := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+			var line map[string]interface{}
+			if err := json.Unmarshal([]byte(raw), &line); err != nil {
+				t.Fatalf("decode %s: %v", raw, err)
+			}
+			switch {
+			case line["msg"] == "request" && line["route"] == "/api/profile":
+				request = line
+			case line["msg"] == "login failed":
+				loginFailed = line
+			case line["route"] == "/livez":
+				t.Errorf("probe logged at info level: %s", raw)
+			}
+		}
+
+		if request == nil || request["request_id"] == "" || request["user_id"] != float64(ada.ID) ||
+			request["method"] != "GET" || request["status"] != float64(http.StatusOK) || request["latency"] == nil {
+			t.Errorf("request line = %v", request)
+		}
+		if loginFailed == nil || loginFailed["email"] != "a***@example.com" || loginFailed["reason"] != "invalid_credentials" ||
+			loginFailed["route"] != "/auth/login" {
+			t.Errorf("login failure line = %v", loginFailed)
+		}
+		if strings.Contains(logs.String(), "ada@example.com") {
+			t.Errorf("an email is logged in clear:\n%s", logs)
+		}
+	})
+}
@@ -0,0 +1,48 @@
+package app_test
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestMetrics(t *testing.T) {
+	run(t, func(t *testing.T, h *harness) {
+		ada := h.register("ada@example.com")
+		bob := h.register("bob@example.com")
+
+		ev := h.createEvent(ada, "Dinner", 7, nil)
+		h.join(bob, ev.ID)
+		h.do("GET", fmt.Sprintf("/events/%d", ev.ID), nil, nil).want(http.StatusOK)
+		h.do("GET", "/events/999999", nil, nil).want(http.StatusNotFound)
+		h.do("GET", "/no/such/route", nil, nil).want(http.StatusNotFound)
+
+		inv := h.invite(ada, ev.ID, "bob@example.com")
+		h.do("PUT", fmt.Sprintf("/invitations/%d/respond?email=%s", inv.ID, bob.Email), bob, map[string]string{"status": "declined"}).want(http.StatusOK)
+		h.do("POST", "/auth/login", nil, map[string]string{"email": "ada@example.com", "password": "wrong-password"}).
+			want(http.StatusUnauthorized)
+
+		body := string(h.do("GET", "/metrics", nil, nil).want(http.StatusOK).Body)
+		for _, line := range []string{
+			`eventplanner_http_requests_total{method="GET",route="/events/{id}",status="200"} 1`,
+			`eventplanner_http_requests_total{method="GET",route="/events/{id}",status="404"} 1`,
+			`eventplanner_http_requests_total{method="POST",route="/events",status="201"} 1`,
+			`eventplanner_events_created_total 1`,
+			`eventplanner_event_joins_total 1`,
+			`eventplanner_invitations_sent_total 1`,
+			`eventplanner_invitation_responses_total{status="accepted"} 0`,
+			`eventplanner_invitation_responses_total{status="declined"} 1`,
+			`eventplanner_login_failures_total{reason="invalid_credentials"} 1`,
+		} {
+			if !strings.Contains(body, line+"\n") {
+				t.Errorf("metrics lack %s", line)
+			}
+		}
+
+		// Paths never become labels
+		if strings.Contains(body, "/no/such/route") || strings.Contains(body, fmt.Sprintf(`route="/events/%d"`, ev.ID)) {
+			t.Errorf("metrics are labelled with request paths:\n%s", body)
+		}
+	})
+}
@@ -0,0 +1,151 @@
+package app_test
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+type organizationJSON struct {
+	ID                int    `json:"id"`
+	Name              string `json:"name"`
+	Slug              string `json:"slug"`
+	DefaultVisibility string `json:"default_visibility"`
+	Role              string `json:"role"`
+}
+
+type memberJSON struct {
+	UserID int    `json:"user_id"`
+	Role   string `json:"role"`
+}
+
+func TestOrganizations(t *testing.T) {
+	run(t, func(t *testing.T, h *harness) {
+		ada := h.register("ada@example.com")
+		bob := h.register("bob@example.com")
+		carol := h.register("carol@example.com")
+
+		var org organizationJSON
+		h.do("POST", "/organizations/", ada, map[string]string{"name": "Acme Corp"}).want(http.StatusCreated).data(&org)
+		if org.ID == 0 || org.Slug != "acme-corp" || org.DefaultVisibility != "organization" {
+			t.Errorf("organization = %+v", org)
+		}
+		h.do("POST", "/organizations/", bob, map[string]string{"name": "Acme Corp"}).wantError(http.StatusConflict, "organization_slug_taken")
+
+		path := fmt.Sprintf("/organizations/%d", org.ID)
+		h.do("POST", path+"/members", ada, map[string]string{"email": bob.Email, "role": "admin"}).want(http.StatusCreated)
+		h.do("POST", path+"/members", bob, map[string]string{"email": "nobody@example.com"}).wantError(http.StatusNotFound, "user_not_found")
+		h.do("GET", path, carol, nil).wantError(http.StatusForbidden, "not_organization_member")
+
+		var members []memberJSON
+		h.do("GET", path+"/members", bob, nil).want(http.StatusOK).data(&members)
+		if len(members) != 2 || members[0].UserID != ada.ID || members[0].Role != "owner" || members[1].UserID != bob.ID || members[1].Role != "admin" {
+			t.Errorf("members = %+v", members)
+		}
+
+		var mine []organizationJSON
+		h.do("GET", "/organizations/", bob, nil).want(http.StatusOK).data(&mine)
+		if len(mine) != 1 || mine[0].ID != org.ID || mine[0].Role != "admin" {
+			t.Errorf("my organizations = %+v", mine)
+		}
+
+		// The last owner cannot leave
+		h.do("DELETE", fmt.Sprintf("%s/members/%d", path, ada.ID), ada, nil).wantError(http.StatusConflict, "last_owner")
+
+		// Events created in the organization scope are only visible to members
+		scope := http.Header{"X-Organization-ID": {strconv.Itoa(org.ID)}}
+		var ev eventJSON
+		h.doWith("POST", "/events/", ada, scope, map[string]interface{}{
+			"title": "All hands", "description": "Quarterly", "date": future(7), "time": "10:00:00", "location": "HQ",
+		}).want(http.StatusCreated).data(&ev)
+		h.do("GET", fmt.Sprintf("/events/%d", ev.ID), carol, nil).wantError(http.StatusNotFound, "event_not_found")
+		h.doWith("GET", fmt.Sprintf("/events/%d", ev.ID), carol, scope, nil).wantError(http.StatusForbidden, "not_organization_member")
+		h.doWith("GET", fmt.Sprintf("/events/%d", ev.ID), bob, scope, nil).want(http.StatusOK)
+		h.do("GET", fmt.Sprintf("/events/%d/attendees", ev.ID), nil, nil).wantError(http.StatusNotFound, "event_not_found")
+		h.do("GET", fmt.Sprintf("/events/%d/attendees", ev.ID), carol, nil).wantError(http.StatusNotFound, "event_not_found")
+		h.doWith("GET", fmt.Sprintf("/events/%d/attendees", ev.ID), bob, scope, nil).want(http.StatusOK)
+
+		var switched struct {
+			Token string `json:"token"`
+		}
+		h.do("POST", path+"/switch", bob, nil).want(http.StatusOK).decode(&switched)
+		bobInOrg := &account{ID: bob.ID, Email: bob.Email, Token: switched.Token}
+		h.do("GET", fmt.Sprintf("/events/%d", ev.ID), bobInOrg, nil).want(http.StatusOK)
+
+		h.do("DELETE", fmt.Sprintf("%s/members/%d", path, bob.ID), ada, nil).want(http.StatusOK)
+		h.do("GET", path+"/members", bob, nil).wantError(http.StatusForbidden, "not_organization_member")
+
+		// A token still bound to the organization falls back to the personal scope
+		h.do("GET", "/events/my/attending", bobInOrg, nil).want(http.StatusOK)
+		h.do("GET", fmt.Sprintf("/events/%d", ev.ID), bobInOrg, nil).wantError(http.StatusNotFound, "event_not_found")
+		h.doWith("GET", fmt.Sprintf("/events/%d", ev.ID), bobInOrg, scope, nil).wantError(http.StatusForbidden, "not_organization_member")
+		h.do("POST", "/auth/login", bobInOrg, map[string]string{"email": bob.Email, "password": "secret-password"}).want(http.StatusOK)
+		h.do("POST", "/organizations/0/switch", bobInOrg, nil).want(http.StatusOK)
+		h.doWith("POST", "/organizations/0/switch", bobInOrg, scope, nil).want(http.StatusOK)
+	})
+}
+
+type groupJSON struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	MemberCount int    `json:"member_count"`
+	Members     []struct {
+		ID     int    `json:"id"`
+		Email  string `json:"email"`
+		UserID *int   `json:"user_id"`
+	} `json:"members"`
+}
+
+func TestGroups(t *testing.T) {
+	run(t, func(t *testing.T, h *harness) {
+		ada := h.register("ada@example.com")
+		bob := h.register("bob@example.com")
+
+		var g groupJSON
+		h.do("POST", "/groups/", ada, map[string]string{"name": "Friends"}).want(http.StatusCreated).data(&g)
+		path := fmt.Sprintf("/groups/%d", g.ID)
+
+		h.do("POST", path+"/members", ada, map[string]interface{}{
+			"user_ids": []int{bob.ID},
+			"emails":   []string{"guest@example.com"},
+		}).want(http.StatusOK)
+		h.do("GET", path, bob, nil).wantError(http.StatusNotFound, "group_not_found")
+
+		h.do("GET", path, ada, nil).want(http.StatusOK).data(&g)
+		if g.MemberCount != 2 || len(g.Members) != 2 || g.Members[0].UserID == nil || *g.Members[0].UserID != bob.ID ||
+			g.Members[1].Email != "guest@example.com" || g.Members[1].UserID != nil {
+			t.Errorf("group = %+v", g)
+		}
+
+		// Inviting the group invites every member
+		ev := h.createEvent(ada, "Picnic", 7, nil)
+		h.do("POST", "/invitations", ada, map[string]interface{}{"event_id": ev.ID, "group_id": g.ID, "role": "attendee"}).
+			want(http.StatusCreated)
+		var invitations []invitationJSON
+		h.do("GET", fmt.Sprintf("/events/%d/invitations", ev.ID), ada, nil).want(http.StatusOK).data(&invitations)
+		if len(invitations) != 2 {
+			t.Errorf("invitations of the group = %+v", invitations)
+		}
+
+		// Only the organizer can invite groups, whatever the role
+		var own groupJSON
+		h.do("POST", "/groups/", bob, map[string]string{"name": "Crew"}).want(http.StatusCreated).data(&own)
+		h.do("POST", fmt.Sprintf("/groups/%d/members", own.ID), bob, map[string]interface{}{"emails": []string{"mallory@example.com"}}).
+			want(http.StatusOK)
+		for _, role := range []string{"attendee", "organizer"} {
+			h.do("POST", "/invitations", bob, map[string]interface{}{"event_id": ev.ID, "group_id": own.ID, "role": role, "invite_new_members": true}).
+				wantError(http.StatusForbidden, "not_event_creator")
+		}
+		h.do("POST", fmt.Sprintf("/groups/%d/members", own.ID), bob, map[string]interface{}{"emails": []string{"trent@example.com"}}).
+			want(http.StatusOK)
+		h.do("GET", fmt.Sprintf("/events/%d/invitations", ev.ID), ada, nil).want(http.StatusOK).data(&invitations)
+		if len(invitations) != 2 {
+			t.Errorf("invitations after a non-organizer invited a group = %+v", invitations)
+		}
+
+		h.do("DELETE", fmt.Sprintf("%s/members/%d", path, g.Members[1].ID), ada, nil).want(http.StatusOK)
+		h.do("DELETE", path, ada, nil).want(http.StatusOK)
+		h.do("GET", path, ada, nil).wantError(http.StatusNotFound, "group_not_found")
+	})
+}
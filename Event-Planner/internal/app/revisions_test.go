@@ -0,0 +1,106 @@
+package app_test
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+type revisionJSON struct {
+	Revision     int      `json:"revision"`
+	EditorID     *int     `json:"editor_id"`
+	Changed      []string `json:"changed"`
+	RestoredFrom *int     `json:"restored_from"`
+	Title        string   `json:"title"`
+	Date         string   `json:"date"`
+	Time         string   `json:"time"`
+	Location     string   `json:"location"`
+}
+
+type fieldChangeJSON struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+func TestEventRevisions(t *testing.T) {
+	run(t, func(t *testing.T, h *harness) {
+		ada := h.register("ada@example.com")
+		bob := h.register("bob@example.com")
+
+		ev := h.createEvent(ada, "Launch", 14, nil)
+		path := fmt.Sprintf("/events/%d", ev.ID)
+		h.doWith("PUT", path, ada, h.ifMatch(path), map[string]string{"title": "Launch party", "time": "19:30:00"}).want(http.StatusOK)
+		h.doWith("PUT", path, ada, h.ifMatch(path), map[string]string{"title": "Launch party", "location": "Roof"}).want(http.StatusOK)
+
+		// Newest first, with the fields each edit changed
+		var revisions []revisionJSON
+		h.do("GET", path+"/revisions", ada, nil).want(http.StatusOK).data(&revisions)
+		if len(revisions) != 3 {
+			t.Fatalf("got %d revisions, want 3: %+v", len(revisions), revisions)
+		}
+		for i, want := range [][]string{{"location"}, {"title", "time"}, {"title", "description", "date", "time", "location", "visibility"}} {
+			rev := revisions[i]
+			if rev.Revision != 3-i || rev.EditorID == nil || *rev.EditorID != ada.ID || !reflect.DeepEqual(rev.Changed, want) {
+				t.Errorf("revision %d = %+v, want changed %v", i, rev, want)
+			}
+		}
+
+		var first revisionJSON
+		h.do("GET", path+"/revisions/1", ada, nil).want(http.StatusOK).data(&first)
+		if first.Title != "Launch" || first.Time != "18:00:00" || first.Location != "Main Hall" || first.Date != ev.Date {
+			t.Errorf("revision 1 = %+v", first)
+		}
+		h.do("GET", path+"/revisions/9", ada, nil).wantError(http.StatusNotFound, "revision_not_found")
+
+		var diff struct {
+			From    int                        `json:"from"`
+			To      int                        `json:"to"`
+			Changes map[string]fieldChangeJSON `json:"changes"`
+		}
+		h.do("GET", path+"/revisions/diff?from=1&to=3", ada, nil).want(http.StatusOK).data(&diff)
+		wantDiff := map[string]fieldChangeJSON{
+			"title":    {"Launch", "Launch party"},
+			"time":     {"18:00:00", "19:30:00"},
+			"location": {"Main Hall", "Roof"},
+		}
+		if diff.From != 1 || diff.To != 3 || !reflect.DeepEqual(diff.Changes, wantDiff) {
+			t.Errorf("diff = %+v", diff)
+		}
+		h.do("GET", path+"/revisions/diff?from=1", ada, nil).wantError(http.StatusBadRequest, "validation_failed")
+
+		// Only the organizer sees the history
+		h.do("GET", path+"/revisions", bob, nil).wantError(http.StatusForbidden, "not_event_organizer")
+		h.do("POST", path+"/revisions/1/restore", bob, nil).wantError(http.StatusForbidden, "not_event_organizer")
+
+		// Attendees see the edits that moved the event
+		h.do("GET", path+"/changes", bob, nil).wantError(http.StatusForbidden, "not_attendee")
+		h.join(bob, ev.ID)
+
+		var changes []struct {
+			Revision int                        `json:"revision"`
+			Changes  map[string]fieldChangeJSON `json:"changes"`
+			Summary  string                     `json:"summary"`
+		}
+		h.do("GET", path+"/changes", bob, nil).want(http.StatusOK).data(&changes)
+		if len(changes) != 2 || changes[0].Revision != 3 || changes[1].Revision != 2 ||
+			changes[1].Summary != "Time moved from 18:00:00 to 19:30:00" ||
+			!reflect.DeepEqual(changes[1].Changes, map[string]fieldChangeJSON{"time": {"18:00:00", "19:30:00"}}) {
+			t.Errorf("changes = %+v", changes)
+		}
+
+		// Restoring is an edit of its own
+		var restored eventJSON
+		h.do("POST", path+"/revisions/1/restore", ada, nil).want(http.StatusOK).data(&restored)
+		if restored.Title != "Launch" || restored.Time != "18:00:00" || restored.Location != "Main Hall" {
+			t.Errorf("restored event = %+v", restored)
+		}
+
+		var latest revisionJSON
+		h.do("GET", path+"/revisions/4", ada, nil).want(http.StatusOK).data(&latest)
+		if latest.RestoredFrom == nil || *latest.RestoredFrom != 1 || !reflect.DeepEqual(latest.Changed, []string{"title", "time", "location"}) {
+			t.Errorf("restore revision = %+v", latest)
+		}
+		h.do("POST", path+"/revisions/9/restore", ada, nil).wantError(http.StatusNotFound, "revision_not_found")
+	})
+}
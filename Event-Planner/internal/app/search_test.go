@@ -0,0 +1,57 @@
+package app_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestSearch(t *testing.T) {
+	run(t, func(t *testing.T, h *harness) {
+		ada := h.register("ada@example.com")
+		bob := h.register("bob@example.com")
+
+		review := h.createEvent(ada, "Design review", 10, nil)
+		lunch := h.createEvent(ada, "Team lunch", 20, nil)
+		sprint := h.createEvent(bob, "Design sprint", 30, nil)
+		other := h.createEvent(bob, "Board meeting", 40, nil)
+
+		h.join(ada, sprint.ID)
+		h.do("PUT", fmt.Sprintf("/events/%d/attendance", sprint.ID), ada, map[string]string{"status": "maybe"}).want(http.StatusOK)
+
+		tests := []struct {
+			query string
+			want  []int
+		}{
+			{"", []int{sprint.ID, lunch.ID, review.ID}},
+			{"q=DESIGN", []int{sprint.ID, review.ID}},
+			{"q=lunch+desc", []int{lunch.ID}},
+			{"role=organizer", []int{lunch.ID, review.ID}},
+			{"status=maybe", []int{sprint.ID}},
+			{fmt.Sprintf("date_from=%s&date_to=%s", future(15), future(35)), []int{sprint.ID, lunch.ID}},
+			{"limit=2&offset=1", []int{lunch.ID, review.ID}},
+			{"q=nothing", []int{}},
+		}
+		for _, tt := range tests {
+			var events []eventJSON
+			h.do("GET", "/events/search?"+tt.query, ada, nil).want(http.StatusOK).data(&events)
+			wantEvents(t, "search "+tt.query, events, tt.want...)
+		}
+
+		// Results carry the user's attendance
+		var events []eventJSON
+		h.do("GET", "/events/search?q=sprint", ada, nil).want(http.StatusOK).data(&events)
+		if len(events) != 1 || events[0].Role != "attendee" || events[0].Status != "maybe" {
+			t.Errorf("search result = %+v", events)
+		}
+
+		// Only events the user takes part in are searched
+		h.do("GET", "/events/search?q=board", ada, nil).want(http.StatusOK).data(&events)
+		wantEvents(t, "search for another user's event", events)
+		h.do("GET", "/events/search?q=board", bob, nil).want(http.StatusOK).data(&events)
+		wantEvents(t, "search as the organizer", events, other.ID)
+
+		h.do("GET", "/events/search?role=host", ada, nil).wantError(http.StatusBadRequest, "validation_failed")
+		h.do("GET", "/events/search", nil, nil).wantError(http.StatusUnauthorized, "missing_token")
+	})
+}
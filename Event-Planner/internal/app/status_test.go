@@ -0,0 +1,118 @@
+package app_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+type notificationJSON struct {
+	ID          int     `json:"id"`
+	EventID     int     `json:"event_id"`
+	EventStatus string  `json:"event_status"`
+	EventTitle  string  `json:"event_title"`
+	Reason      string  `json:"reason"`
+	Message     string  `json:"message"`
+	ReadAt      *string `json:"read_at"`
+}
+
+func TestEventStatus(t *testing.T) {
+	run(t, func(t *testing.T, h *harness) {
+		ada := h.register("ada@example.com")
+		bob := h.register("bob@example.com")
+		carol := h.register("carol@example.com")
+
+		ev := h.createEvent(ada, "Launch", 14, nil)
+		if ev.Status != "scheduled" {
+			t.Errorf("status of a new event = %q, want scheduled", ev.Status)
+		}
+		path := fmt.Sprintf("/events/%d", ev.ID)
+		h.join(bob, ev.ID)
+		inv := h.invite(ada, ev.ID, carol.Email)
+
+		reason := map[string]string{"reason": "The venue flooded"}
+		h.doWith("POST", path+"/postpone", bob, http.Header{"If-Match": {"*"}}, reason).wantError(http.StatusForbidden, "not_event_organizer")
+		h.do("POST", path+"/postpone", ada, reason).wantError(http.StatusPreconditionRequired, "if_match_required")
+		h.doWith("POST", path+"/postpone", ada, h.ifMatch(path), map[string]string{}).want(http.StatusBadRequest)
+
+		var postponed eventJSON
+		res := h.doWith("POST", path+"/postpone", ada, h.ifMatch(path), reason).want(http.StatusOK)
+		res.data(&postponed)
+		if postponed.Status != "postponed" || postponed.StatusReason != "The venue flooded" || res.Header.Get("ETag") != `"2"` {
+			t.Errorf("postponed event = %+v, ETag %q", postponed, res.Header.Get("ETag"))
+		}
+		h.doWith("POST", path+"/postpone", ada, h.ifMatch(path), reason).wantError(http.StatusConflict, "invalid_status_transition")
+
+		var cancelled eventJSON
+		h.doWith("POST", path+"/cancel", ada, h.ifMatch(path), map[string]string{"reason": "No new venue"}).want(http.StatusOK).data(&cancelled)
+		if cancelled.Status != "cancelled" || cancelled.StatusReason != "No new venue" {
+			t.Errorf("cancelled event = %+v", cancelled)
+		}
+		h.doWith("POST", path+"/cancel", ada, h.ifMatch(path), reason).wantError(http.StatusConflict, "invalid_status_transition")
+
+		// A cancelled event can no longer be joined, edited or invited to
+		dave := h.register("dave@example.com")
+		h.do("POST", path+"/join", dave, nil).wantError(http.StatusConflict, "event_cancelled")
+		h.doWith("PUT", path, ada, h.ifMatch(path), map[string]interface{}{
+			"title": "Launch", "date": future(20), "time": "18:00:00", "location": "Town hall",
+		}).wantError(http.StatusConflict, "event_cancelled")
+		h.do("POST", "/invitations", ada, map[string]interface{}{"event_id": ev.ID, "invitee_email": dave.Email, "role": "attendee"}).
+			wantError(http.StatusConflict, "event_cancelled")
+		respond := fmt.Sprintf("/invitations/%d/respond?email=%s", inv.ID, carol.Email)
+		h.do("PUT", respond, carol, map[string]string{"status": "accepted"}).wantError(http.StatusConflict, "event_cancelled")
+		h.do("PUT", respond, carol, map[string]string{"status": "declined"}).want(http.StatusOK)
+
+		// Attendees can back out, but not commit again
+		attendance := path + "/attendance"
+		for _, status := range []string{"going", "maybe"} {
+			h.do("PUT", attendance, bob, map[string]string{"status": status}).wantError(http.StatusConflict, "event_cancelled")
+		}
+		h.do("PUT", attendance, bob, map[string]string{"status": "not_going"}).want(http.StatusOK)
+		h.do("PUT", attendance, bob, map[string]string{"status": "going"}).wantError(http.StatusConflict, "event_cancelled")
+
+		// It stays listed, as cancelled
+		var events []eventJSON
+		h.do("GET", "/events/", nil, nil).want(http.StatusOK).data(&events)
+		wantEvents(t, "GET /events/", events, ev.ID)
+		if events[0].Status != "cancelled" {
+			t.Errorf("listed status = %q, want cancelled", events[0].Status)
+		}
+		h.do("GET", "/events/my/attending", bob, nil).want(http.StatusOK).data(&events)
+		wantEvents(t, "GET /events/my/attending", events, ev.ID)
+		if events[0].EventStatus != "cancelled" {
+			t.Errorf("event status of an attended event = %q, want cancelled", events[0].EventStatus)
+		}
+
+		// Attendees and pending invitees are notified, the organizer is not
+		var notifications []notificationJSON
+		h.do("GET", "/notifications", bob, nil).want(http.StatusOK).data(&notifications)
+		if len(notifications) != 2 {
+			t.Fatalf("notifications of bob = %+v, want 2", notifications)
+		}
+		if n := notifications[0]; n.EventID != ev.ID || n.EventStatus != "cancelled" || n.Message != "Launch was cancelled: No new venue" || n.ReadAt != nil {
+			t.Errorf("latest notification = %+v", n)
+		}
+		if n := notifications[1]; n.EventStatus != "postponed" || n.Reason != "The venue flooded" {
+			t.Errorf("first notification = %+v", n)
+		}
+		h.do("GET", "/notifications", carol, nil).want(http.StatusOK).data(&notifications)
+		if len(notifications) != 2 {
+			t.Errorf("notifications of carol = %+v, want 2", notifications)
+		}
+		h.do("GET", "/notifications", ada, nil).want(http.StatusOK).data(&notifications)
+		if len(notifications) != 0 {
+			t.Errorf("notifications of the organizer = %+v", notifications)
+		}
+
+		h.do("GET", "/notifications", bob, nil).want(http.StatusOK).data(&notifications)
+		read := fmt.Sprintf("/notifications/%d/read", notifications[0].ID)
+		h.do("POST", read, carol, nil).wantError(http.StatusNotFound, "notification_not_found")
+		h.do("POST", read, bob, nil).want(http.StatusOK)
+		h.do("GET", "/notifications?unread=true", bob, nil).want(http.StatusOK).data(&notifications)
+		if len(notifications) != 1 || notifications[0].EventStatus != "postponed" {
+			t.Errorf("unread notifications = %+v, want the postponement", notifications)
+		}
+		h.do("GET", "/notifications?unread=maybe", bob, nil).want(http.StatusBadRequest)
+		h.do("GET", "/notifications", nil, nil).want(http.StatusUnauthorized)
+	})
+}
@@ -0,0 +1,81 @@
+package app_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"event-planner/internal/tracing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracing(t *testing.T) {
+	spans := tracetest.NewSpanRecorder()
+	provider, propagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(provider)
+		otel.SetTextMapPropagator(propagator)
+	})
+
+	run(t, func(t *testing.T, h *harness) {
+		ada := h.register("ada@example.com")
+		ev := h.createEvent(ada, "Dinner", 7, nil)
+
+		// The trace of the caller is continued and returned
+		const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+		req, err := http.NewRequest("GET", fmt.Sprintf("%s/events/%d", h.srv.URL, ev.ID), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+		req.Header.Set("X-Request-Id", "req-42")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || resp.Header.Get(tracing.HeaderTraceID) != traceID {
+			t.Fatalf("got status %d and trace %q, want 200 and %s", resp.StatusCode, resp.Header.Get(tracing.HeaderTraceID), traceID)
+		}
+
+		var server, service sdktrace.ReadOnlySpan
+		for _, span := range spans.Ended() {
+			if span.SpanContext().TraceID().String() != traceID {
+				continue
+			}
+			switch span.Name() {
+			case "GET /events/{id}":
+				server = span
+			case "event.Service.GetEventByID":
+				service = span
+			}
+		}
+		if server == nil || service == nil {
+			t.Fatalf("spans of the trace: server %v, service %v", server, service)
+		}
+		if service.Parent().SpanID() != server.SpanContext().SpanID() {
+			t.Errorf("the service span is not a child of the server span")
+		}
+		if !hasAttribute(server, attribute.String("request.id", "req-42")) ||
+			!hasAttribute(server, attribute.String("http.route", "/events/{id}")) ||
+			!hasAttribute(server, attribute.Int("http.response.status_code", http.StatusOK)) {
+			t.Errorf("server span attributes = %v", server.Attributes())
+		}
+	})
+}
+
+func hasAttribute(span sdktrace.ReadOnlySpan, want attribute.KeyValue) bool {
+	for _, kv := range span.Attributes() {
+		if kv == want {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+package app_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type trashedEventJSON struct {
+	eventJSON
+	DeletedAt time.Time `json:"deleted_at"`
+	PurgeAt   time.Time `json:"purge_at"`
+}
+
+func TestEventTrash(t *testing.T) {
+	run(t, func(t *testing.T, h *harness) {
+		ada := h.register("ada@example.com")
+		bob := h.register("bob@example.com")
+		carol := h.register("carol@example.com")
+
+		kept := h.createEvent(ada, "Kept", 10, nil)
+		ev := h.createEvent(ada, "Launch", 14, nil)
+		path := fmt.Sprintf("/events/%d", ev.ID)
+		h.join(bob, ev.ID)
+		inv := h.invite(ada, ev.ID, carol.Email)
+
+		h.doWith("DELETE", path, bob, http.Header{"If-Match": {"*"}}, nil).wantError(http.StatusForbidden, "not_event_organizer")
+		h.doWith("DELETE", path, ada, h.ifMatch(path), nil).want(http.StatusOK)
+
+		// Deleted events are hidden from listings, search and joining
+		h.do("GET", path, ada, nil).wantError(http.StatusNotFound, "event_not_found")
+		var events []eventJSON
+		h.do("GET", "/events/", nil, nil).want(http.StatusOK).data(&events)
+		wantEvents(t, "GET /events/", events, kept.ID)
+		h.do("GET", "/events/my/organized", ada, nil).want(http.StatusOK).data(&events)
+		wantEvents(t, "GET /events/my/organized", events, kept.ID)
+		h.do("GET", "/events/my/attending", bob, nil).want(http.StatusOK).data(&events)
+		wantEvents(t, "GET /events/my/attending", events)
+		h.do("GET", "/events/search?q=launch", bob, nil).want(http.StatusOK).data(&events)
+		wantEvents(t, "GET /events/search", events)
+		h.do("POST", path+"/join", carol, nil).wantError(http.StatusNotFound, "event_not_found")
+		h.do("GET", path+"/attendees", nil, nil).wantError(http.StatusNotFound, "event_not_found")
+		h.doWith("DELETE", path, ada, http.Header{"If-Match": {"*"}}, nil).wantError(http.StatusNotFound, "event_not_found")
+
+		var invitations []invitationJSON
+		h.do("GET", "/invitations/my?email="+carol.Email, carol, nil).want(http.StatusOK).data(&invitations)
+		if len(invitations) != 0 {
+			t.Errorf("invitations to a deleted event = %+v", invitations)
+		}
+		h.do("PUT", fmt.Sprintf("/invitations/%d/respond?email=%s", inv.ID, carol.Email), carol, map[string]string{"status": "accepted"}).
+			wantError(http.StatusNotFound, "event_not_found")
+
+		// Only its organizer finds it in the trash, with the time it is purged
+		var trash []trashedEventJSON
+		h.do("GET", "/events/my/trash", ada, nil).want(http.StatusOK).data(&trash)
+		if len(trash) != 1 || trash[0].ID != ev.ID || trash[0].Title != "Launch" {
+			t.Fatalf("trash = %+v, want the deleted event", trash)
+		}
+		if trash[0].DeletedAt.IsZero() || !trash[0].PurgeAt.Equal(trash[0].DeletedAt.Add(30*24*time.Hour)) {
+			t.Errorf("deleted at %v, purged at %v; want 30 days later", trash[0].DeletedAt, trash[0].PurgeAt)
+		}
+		h.do("GET", "/events/my/trash", bob, nil).want(http.StatusOK).data(&trash)
+		if len(trash) != 0 {
+			t.Errorf("trash of another user = %+v", trash)
+		}
+
+		restore := fmt.Sprintf("/events/my/trash/%d/restore", ev.ID)
+		h.do("POST", restore, bob, nil).wantError(http.StatusNotFound, "trashed_event_not_found")
+		h.do("POST", fmt.Sprintf("/events/my/trash/%d/restore", kept.ID), ada, nil).wantError(http.StatusNotFound, "trashed_event_not_found")
+
+		// Restoring brings it back as it was, attendees and invitations included
+		var restored eventJSON
+		res := h.do("POST", restore, ada, nil).want(http.StatusOK)
+		res.data(&restored)
+		if restored.ID != ev.ID || restored.Title != "Launch" || res.Header.Get("ETag") != `"1"` {
+			t.Errorf("restored event = %+v, ETag %q", restored, res.Header.Get("ETag"))
+		}
+		h.do("POST", restore, ada, nil).wantError(http.StatusNotFound, "trashed_event_not_found")
+		h.do("GET", path, nil, nil).want(http.StatusOK)
+		h.do("GET", "/events/my/attending", bob, nil).want(http.StatusOK).data(&events)
+		wantEvents(t, "GET /events/my/attending after restoring", events, ev.ID)
+		h.do("PUT", fmt.Sprintf("/invitations/%d/respond?email=%s", inv.ID, carol.Email), carol, map[string]string{"status": "accepted"}).want(http.StatusOK)
+		h.do("GET", "/events/my/trash", ada, nil).want(http.StatusOK).data(&trash)
+		if len(trash) != 0 {
+			t.Errorf("trash after restoring = %+v", trash)
+		}
+	})
+}
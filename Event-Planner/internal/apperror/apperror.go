@@ -0,0 +1,108 @@
+package apperror
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Error kinds; every domain error wraps exactly one of them so callers can
+// branch with errors.Is instead of comparing messages
+var (
+	ErrValidation    = errors.New("validation failed")
+	ErrUnauthorized  = errors.New("unauthorized")
+	ErrForbidden     = errors.New("forbidden")
+	ErrNotFound      = errors.New("not found")
+	ErrConflict      = errors.New("conflict")
+	ErrUnprocessable = errors.New("unprocessable")
+
+	ErrPreconditionFailed   = errors.New("precondition failed")
+	ErrPreconditionRequired = errors.New("precondition required")
+)
+
+// FieldError describes why a single request field was rejected
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Error is a domain error with a machine-readable code and a message safe to show to clients
+type Error struct {
+	Kind    error        // one of the Err* kinds above
+	Code    string       // stable identifier, e.g. "event_not_found"
+	Message string       // human-readable description
+	Fields  []FieldError // validation details, if any
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
+
+// New creates a domain error of the given kind
+func New(kind error, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+// Validation reports an invalid request field
+func Validation(field, message string) *Error {
+	return &Error{
+		Kind:    ErrValidation,
+		Code:    "validation_failed",
+		Message: message,
+		Fields:  []FieldError{{Field: field, Message: message}},
+	}
+}
+
+// Validationf is Validation with a formatted message
+func Validationf(field, format string, args ...interface{}) *Error {
+	return Validation(field, fmt.Sprintf(format, args...))
+}
+
+// InvalidFields reports several invalid request fields at once
+func InvalidFields(message string, fields ...FieldError) *Error {
+	return &Error{
+		Kind:    ErrValidation,
+		Code:    "validation_failed",
+		Message: message,
+		Fields:  fields,
+	}
+}
+
+// NotFound reports a missing resource
+func NotFound(code, message string) *Error {
+	return New(ErrNotFound, code, message)
+}
+
+// Forbidden reports an action the user is not allowed to perform
+func Forbidden(code, message string) *Error {
+	return New(ErrForbidden, code, message)
+}
+
+// Conflict reports an action that clashes with the current state
+func Conflict(code, message string) *Error {
+	return New(ErrConflict, code, message)
+}
+
+// Unprocessable reports a well-formed request that violates a data constraint
+func Unprocessable(code, message string) *Error {
+	return New(ErrUnprocessable, code, message)
+}
+
+// PreconditionFailed reports a conditional request whose condition doesn't
+// hold, e.g. an If-Match header naming an outdated version
+func PreconditionFailed(code, message string) *Error {
+	return New(ErrPreconditionFailed, code, message)
+}
+
+// PreconditionRequired reports a request that must be made conditional
+func PreconditionRequired(code, message string) *Error {
+	return New(ErrPreconditionRequired, code, message)
+}
+
+// Unauthorized reports missing or invalid credentials
+func Unauthorized(code, message string) *Error {
+	return New(ErrUnauthorized, code, message)
+}
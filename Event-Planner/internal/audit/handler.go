@@ -0,0 +1,70 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"event-planner/internal/apperror"
+	"event-planner/internal/response"
+)
+
+// Viewer identifies the authenticated user of a request and whether they
+// may read the whole log; the others only read the entries of the events
+// they organize. The auth package depends on this one, so the app provides it.
+type Viewer func(ctx context.Context) (userID int, all bool, ok bool)
+
+// Handler handles HTTP requests for the audit log
+type Handler struct {
+	service *Service
+	viewer  Viewer
+}
+
+// NewHandler creates a new audit log handler
+func NewHandler(service *Service, viewer Viewer) *Handler {
+	return &Handler{service: service, viewer: viewer}
+}
+
+// List handles GET /audit
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	userID, all, ok := h.viewer(r.Context())
+	if !ok {
+		response.Error(w, apperror.ErrUnauthorized)
+		return
+	}
+
+	q := r.URL.Query()
+
+	filter := &Filter{
+		ActorID:    queryInt(q, "actor_id"),
+		Action:     q.Get("action"),
+		TargetType: q.Get("target_type"),
+		TargetID:   queryInt(q, "target_id"),
+		EventID:    queryInt(q, "event_id"),
+		Limit:      queryInt(q, "limit"),
+		Offset:     queryInt(q, "offset"),
+	}
+	if !all {
+		filter.OrganizerID = userID
+	}
+
+	entries, err := h.service.List(r.Context(), filter)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data": entries,
+	})
+}
+
+// queryInt parses an optional integer query parameter (0 when missing or invalid)
+func queryInt(q url.Values, key string) int {
+	v, _ := strconv.Atoi(q.Get(key))
+	return v
+}
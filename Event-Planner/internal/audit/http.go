@@ -0,0 +1,29 @@
+package audit
+
+import (
+	"context"
+	"net"
+	"net/http"
+)
+
+type clientKey struct{}
+
+// client is the request metadata recorded with entries
+type client struct {
+	ip        string
+	userAgent string
+}
+
+// Middleware makes the client address and user agent of requests available
+// to Record
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ip, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			ip = r.RemoteAddr
+		}
+
+		ctx := context.WithValue(r.Context(), clientKey{}, client{ip: ip, userAgent: r.UserAgent()})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
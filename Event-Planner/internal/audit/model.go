@@ -0,0 +1,65 @@
+package audit
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Actions recorded in the log
+const (
+	ActionEventCreate       = "event.create"
+	ActionEventUpdate       = "event.update"
+	ActionEventDelete       = "event.delete"
+	ActionEventRestore      = "event.restore"   // an earlier revision restored
+	ActionEventUndelete     = "event.undelete"  // taken out of the trash
+	ActionEventPurge        = "event.purge"     // erased at the end of its time in the trash
+	ActionEventCancel       = "event.cancel"    // called off by the organizer, with a reason
+	ActionEventPostpone     = "event.postpone"  // put off until a new date or time, with a reason
+	ActionEventPublish      = "event.publish"   // a draft published, or scheduled to be, by its organizer or the status worker
+	ActionAttendeeAdd       = "attendee.add"    // joined, added by the organizer or an accepted invitation; a role change when already attending
+	ActionAttendeeStatus    = "attendee.status" // attendance status change
+	ActionInvitationSend    = "invitation.send"
+	ActionInvitationRespond = "invitation.respond"
+	ActionUserRegister      = "user.register"
+	ActionUserLogin         = "user.login"
+	ActionUserLoginFailed   = "user.login_failed"
+	ActionUserDisable       = "user.disable"
+	ActionUserEnable        = "user.enable"
+	ActionUserSetRole       = "user.set_role"
+)
+
+// Types of the records actions apply to
+const (
+	TargetEvent      = "event"
+	TargetAttendee   = "attendee" // the target ID is the attending user's
+	TargetInvitation = "invitation"
+	TargetUser       = "user"
+)
+
+// Entry is a recorded action
+type Entry struct {
+	ID         int64           `json:"id"`
+	ActorID    *int            `json:"actor_id"` // nil for anonymous requests such as failed logins
+	Action     string          `json:"action"`   // e.g. 'event.update', 'attendee.status'
+	TargetType string          `json:"target_type"`
+	TargetID   int             `json:"target_id"`
+	EventID    *int            `json:"event_id,omitempty"` // the event the action concerns; organizers see the entries of their events
+	Before     json.RawMessage `json:"before,omitempty"`   // the changed fields before the action
+	After      json.RawMessage `json:"after,omitempty"`    // the changed fields after the action
+	RequestID  string          `json:"request_id,omitempty"`
+	IP         string          `json:"ip,omitempty"`
+	UserAgent  string          `json:"user_agent,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// Filter holds filters for listing entries
+type Filter struct {
+	ActorID     int    // optional
+	Action      string // optional
+	TargetType  string // optional
+	TargetID    int    // optional
+	EventID     int    // optional
+	OrganizerID int    // restricts the entries to the events the user organizes (optional)
+	Limit       int
+	Offset      int
+}
@@ -0,0 +1,129 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+
+	"event-planner/internal/db"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository handles all database operations for the audit log
+type Repository struct {
+	db *pgxpool.Pool
+}
+
+// NewRepository creates a new audit log repository
+func NewRepository(db *pgxpool.Pool) *Repository {
+	return &Repository{db: db}
+}
+
+// Append stores an entry
+func (r *Repository) Append(ctx context.Context, e *Entry) error {
+	query := `
+		INSERT INTO audit_log (actor_id, action, target_type, target_id, event_id, before, after, request_id, ip, user_agent)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, created_at
+	`
+
+	err := r.db.QueryRow(ctx, query,
+		e.ActorID,
+		e.Action,
+		e.TargetType,
+		e.TargetID,
+		e.EventID,
+		nullJSON(e.Before),
+		nullJSON(e.After),
+		e.RequestID,
+		e.IP,
+		e.UserAgent,
+	).Scan(&e.ID, &e.CreatedAt)
+
+	if err != nil {
+		return db.TranslateError(fmt.Errorf("failed to append audit entry: %w", err), nil)
+	}
+
+	return nil
+}
+
+// List retrieves the entries matching the filter, newest first
+func (r *Repository) List(ctx context.Context, f *Filter) ([]Entry, error) {
+	query := `
+		SELECT id, actor_id, action, target_type, target_id, event_id, before, after, request_id, ip, user_agent, created_at
+		FROM audit_log
+		WHERE 1 = 1
+	`
+
+	args := []interface{}{}
+	argIdx := 1
+
+	if f.ActorID > 0 {
+		query += fmt.Sprintf(" AND actor_id = $%d", argIdx)
+		args = append(args, f.ActorID)
+		argIdx++
+	}
+
+	if f.Action != "" {
+		query += fmt.Sprintf(" AND action = $%d", argIdx)
+		args = append(args, f.Action)
+		argIdx++
+	}
+
+	if f.TargetType != "" {
+		query += fmt.Sprintf(" AND target_type = $%d", argIdx)
+		args = append(args, f.TargetType)
+		argIdx++
+	}
+
+	if f.TargetID > 0 {
+		query += fmt.Sprintf(" AND target_id = $%d", argIdx)
+		args = append(args, f.TargetID)
+		argIdx++
+	}
+
+	if f.EventID > 0 {
+		query += fmt.Sprintf(" AND event_id = $%d", argIdx)
+		args = append(args, f.EventID)
+		argIdx++
+	}
+
+	if f.OrganizerID > 0 {
+		query += fmt.Sprintf(" AND event_id IN (SELECT id FROM events WHERE organizer_id = $%d)", argIdx)
+		args = append(args, f.OrganizerID)
+		argIdx++
+	}
+
+	query += fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d", argIdx, argIdx+1)
+	args = append(args, f.Limit, f.Offset)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []Entry
+	for rows.Next() {
+		e := Entry{}
+		if err := rows.Scan(&e.ID, &e.ActorID, &e.Action, &e.TargetType, &e.TargetID, &e.EventID,
+			&e.Before, &e.After, &e.RequestID, &e.IP, &e.UserAgent, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+		entries = append(entries, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating audit entries: %w", err)
+	}
+
+	return entries, nil
+}
+
+// nullJSON stores a missing before or after as NULL
+func nullJSON(raw []byte) interface{} {
+	if raw == nil {
+		return nil
+	}
+	return string(raw)
+}
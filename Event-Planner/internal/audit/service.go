@@ -0,0 +1,133 @@
+// Package audit keeps an append-only log of security- and data-relevant
+// actions: who did what to which record, the fields it changed and the
+// request it came from.
+//
+// Services record the actions they perform with Service.Record, passing the
+// actor explicitly; the request ID, client address and user agent are taken
+// from the context (see Middleware).
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+
+	"event-planner/internal/apperror"
+	"event-planner/internal/logging"
+	"event-planner/internal/tracing"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// Store is the audit log storage used by Service; Repository implements it on PostgreSQL
+type Store interface {
+	Append(ctx context.Context, e *Entry) error
+	List(ctx context.Context, f *Filter) ([]Entry, error)
+}
+
+// Service records and queries the audit log
+type Service struct {
+	repo Store
+}
+
+// NewService creates a new audit log service
+func NewService(repo Store) *Service {
+	return &Service{repo: repo}
+}
+
+// Record appends an entry with the metadata of the request in ctx. The action
+// itself already happened, so a failure to record it is logged rather than
+// reported to the caller. A nil Service records nothing.
+func (s *Service) Record(ctx context.Context, e Entry) {
+	if s == nil {
+		return
+	}
+
+	ctx, span := tracing.Start(ctx, "audit.Service.Record")
+	defer span.End()
+
+	e.RequestID = middleware.GetReqID(ctx)
+	if client, ok := ctx.Value(clientKey{}).(client); ok {
+		e.IP, e.UserAgent = client.ip, client.userAgent
+	}
+
+	// Recorded even when the client went away once the action was done
+	if err := s.repo.Append(context.WithoutCancel(ctx), &e); err != nil {
+		slog.ErrorContext(ctx, "failed to record audit entry",
+			"action", e.Action, "target_type", e.TargetType, "target_id", e.TargetID, logging.Err(err))
+	}
+}
+
+// List lists the entries matching the filter, newest first
+func (s *Service) List(ctx context.Context, f *Filter) ([]Entry, error) {
+	ctx, span := tracing.Start(ctx, "audit.Service.List")
+	defer span.End()
+
+	if f.ActorID < 0 || f.TargetID < 0 || f.EventID < 0 {
+		return nil, apperror.Validation("id", "invalid ID")
+	}
+
+	normalizePage(&f.Limit, &f.Offset)
+
+	entries, err := s.repo.List(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+
+	if entries == nil {
+		entries = []Entry{}
+	}
+
+	return entries, nil
+}
+
+// Actor is the actor of an entry for a user ID; 0 is anonymous
+func Actor(userID int) *int {
+	if userID <= 0 {
+		return nil
+	}
+	return &userID
+}
+
+// Snapshot encodes a whole record, as the before of a deletion or the after
+// of a creation
+func Snapshot(v interface{}) json.RawMessage {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	return raw
+}
+
+// Changes encodes the fields that differ between two versions of a record,
+// as the before and after of an update. Both are JSON objects; fields only
+// present in one of the versions are missing from the other.
+func Changes(before, after interface{}) (json.RawMessage, json.RawMessage) {
+	var old, updated map[string]json.RawMessage
+	if json.Unmarshal(Snapshot(before), &old) != nil || json.Unmarshal(Snapshot(after), &updated) != nil {
+		return Snapshot(before), Snapshot(after)
+	}
+
+	for field, value := range old {
+		if v, ok := updated[field]; ok && bytes.Equal(v, value) {
+			delete(old, field)
+			delete(updated, field)
+		}
+	}
+
+	return Snapshot(old), Snapshot(updated)
+}
+
+// normalizePage applies the default and maximum page size
+func normalizePage(limit, offset *int) {
+	if *limit <= 0 {
+		*limit = 50
+	}
+	if *limit > 200 {
+		*limit = 200
+	}
+	if *offset < 0 {
+		*offset = 0
+	}
+}
@@ -4,7 +4,12 @@ import "context"
 
 type contextKey string
 
-const userIDKey contextKey = "user_id"
+const (
+	userIDKey     contextKey = "user_id"
+	roleKey       contextKey = "role"
+	tokenOrgIDKey contextKey = "token_org_id"
+	tokenErrKey   contextKey = "token_error"
+)
 
 // setUserID adds user ID to context
 func setUserID(ctx context.Context, userID int) context.Context {
@@ -16,3 +21,40 @@ func GetUserID(ctx context.Context) (int, bool) {
 	userID, ok := ctx.Value(userIDKey).(int)
 	return userID, ok
 }
+
+// setRole adds the user's system role to context
+func setRole(ctx context.Context, role string) context.Context {
+	return context.WithValue(ctx, roleKey, role)
+}
+
+// GetRole retrieves the user's system role from context
+func GetRole(ctx context.Context) (string, bool) {
+	role, ok := ctx.Value(roleKey).(string)
+	return role, ok
+}
+
+// setTokenOrgID adds the organization claim of the token to context
+func setTokenOrgID(ctx context.Context, orgID int) context.Context {
+	return context.WithValue(ctx, tokenOrgIDKey, orgID)
+}
+
+// GetTokenOrgID retrieves the organization claim of the token from context.
+// The claim is not verified; use the organization scope for authorization.
+func GetTokenOrgID(ctx context.Context) (int, bool) {
+	orgID, ok := ctx.Value(tokenOrgIDKey).(int)
+	return orgID, ok
+}
+
+// setTokenError adds the reason the bearer token of an anonymous request was rejected to context
+func setTokenError(ctx context.Context, err error) context.Context {
+	return context.WithValue(ctx, tokenErrKey, err)
+}
+
+// Unauthenticated returns the error for an anonymous request to a protected
+// route: why its bearer token was rejected, or ErrMissingToken when it had none
+func Unauthenticated(ctx context.Context) error {
+	if err, ok := ctx.Value(tokenErrKey).(error); ok {
+		return err
+	}
+	return ErrMissingToken
+}
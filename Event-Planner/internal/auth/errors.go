@@ -0,0 +1,12 @@
+package auth
+
+import "event-planner/internal/apperror"
+
+// Domain errors returned by the auth service and middleware
+var (
+	ErrInvalidCredentials = apperror.Unauthorized("invalid_credentials", "invalid email or password")
+	ErrMissingToken       = apperror.Unauthorized("missing_token", "authorization header required")
+	ErrInvalidToken       = apperror.Unauthorized("invalid_token", "invalid token")
+	ErrAccountDisabled    = apperror.Forbidden("account_disabled", "account is disabled")
+	ErrInvalidPassword    = apperror.Forbidden("invalid_password", "invalid password")
+)
@@ -1,11 +1,14 @@
 package auth
 
 import (
+	"context"
 	"encoding/json"
-	"log"
 	"net/http"
 	"strings"
 
+	"event-planner/internal/apperror"
+	"event-planner/internal/logging"
+	"event-planner/internal/response"
 	"event-planner/internal/user"
 )
 
@@ -21,31 +24,24 @@ func NewHandler(service *Service) *Handler {
 func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 	var req user.RegisterRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		response.Error(w, apperror.Validation("body", "invalid request body"))
 		return
 	}
 
 	// Basic validation
-	if req.Email == "" || req.Password == "" {
-		http.Error(w, "Email and password are required", http.StatusBadRequest)
+	if err := validateCredentials(req.Email, req.Password); err != nil {
+		response.Error(w, err)
 		return
 	}
 
 	if len(req.Password) < 6 {
-		http.Error(w, "Password must be at least 6 characters", http.StatusBadRequest)
+		response.Error(w, apperror.Validation("password", "password must be at least 6 characters"))
 		return
 	}
 
 	authResp, err := h.service.Register(r.Context(), req)
 	if err != nil {
-		log.Printf("Register error: %v\n", err)
-
-		if strings.Contains(err.Error(), "already exists") {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
-
-		http.Error(w, "failed to register user", http.StatusInternalServerError)
+		response.Error(w, err)
 		return
 	}
 
@@ -57,19 +53,19 @@ func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 	var req user.LoginRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		response.Error(w, apperror.Validation("body", "invalid request body"))
 		return
 	}
 
 	// Basic validation
-	if req.Email == "" || req.Password == "" {
-		http.Error(w, "Email and password are required", http.StatusBadRequest)
+	if err := validateCredentials(req.Email, req.Password); err != nil {
+		response.Error(w, err)
 		return
 	}
 
 	authResp, err := h.service.Login(r.Context(), req)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusUnauthorized)
+		response.Error(w, err)
 		return
 	}
 
@@ -80,28 +76,92 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 // AuthMiddleware validates JWT tokens
 func (h *Handler) AuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		authHeader := r.Header.Get("Authorization")
-		if authHeader == "" {
-			http.Error(w, "Authorization header required", http.StatusUnauthorized)
+		// Already authenticated by OptionalAuthMiddleware
+		if _, ok := GetUserID(r.Context()); ok {
+			next.ServeHTTP(w, r)
 			return
 		}
 
-		// Extract token from "Bearer <token>"
-		parts := strings.SplitN(authHeader, " ", 2)
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			http.Error(w, "Invalid authorization header format", http.StatusUnauthorized)
+		if r.Header.Get("Authorization") == "" {
+			response.Error(w, ErrMissingToken)
 			return
 		}
 
-		userID, err := h.service.ValidateToken(parts[1])
+		ctx, err := h.authenticate(r)
 		if err != nil {
-			http.Error(w, "Invalid token", http.StatusUnauthorized)
+			response.Error(w, err)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// OptionalAuthMiddleware authenticates requests that carry a valid token and
+// lets every other request through anonymously; routes that need a user reject
+// bad tokens in AuthMiddleware, so a stale token cannot lock a client out of
+// public routes such as login
+func (h *Handler) OptionalAuthMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			next.ServeHTTP(w, r)
 			return
 		}
 
-		// Add user ID to request context for use in handlers
-		ctx := r.Context()
-		ctx = setUserID(ctx, userID)
+		ctx, err := h.authenticate(r)
+		if err != nil {
+			next.ServeHTTP(w, r.WithContext(setTokenError(r.Context(), err)))
+			return
+		}
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+// authenticate validates the bearer token and returns the request context with the user added
+func (h *Handler) authenticate(r *http.Request) (context.Context, error) {
+	// Extract token from "Bearer <token>"
+	parts := strings.SplitN(r.Header.Get("Authorization"), " ", 2)
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		return nil, apperror.Unauthorized("invalid_token", "invalid authorization header format")
+	}
+
+	claims, err := h.service.ParseToken(parts[1])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	// The role is looked up on every request so that role changes and
+	// disabled accounts take effect without waiting for the token to expire
+	role, disabled, err := h.service.GetAccountStatus(r.Context(), claims.UserID)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	if disabled {
+		return nil, ErrAccountDisabled
+	}
+
+	// Add user ID, role and organization claim to request context for use in handlers
+	ctx := r.Context()
+	ctx = setUserID(ctx, claims.UserID)
+	logging.SetUserID(ctx, claims.UserID)
+	ctx = setRole(ctx, role)
+	if claims.OrganizationID > 0 {
+		ctx = setTokenOrgID(ctx, claims.OrganizationID)
+	}
+	return ctx, nil
+}
+
+// validateCredentials reports every missing credential field
+func validateCredentials(email, password string) error {
+	var missing []apperror.FieldError
+	if email == "" {
+		missing = append(missing, apperror.FieldError{Field: "email", Message: "email is required"})
+	}
+	if password == "" {
+		missing = append(missing, apperror.FieldError{Field: "password", Message: "password is required"})
+	}
+
+	if len(missing) > 0 {
+		return apperror.InvalidFields("email and password are required", missing...)
+	}
+	return nil
+}
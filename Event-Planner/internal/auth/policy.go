@@ -0,0 +1,75 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+
+	"event-planner/internal/apperror"
+	"event-planner/internal/response"
+	"event-planner/internal/user"
+)
+
+// Permission is an action that is granted to system roles
+type Permission string
+
+const (
+	// PermViewUsers allows listing and inspecting user accounts
+	PermViewUsers Permission = "users:view"
+	// PermManageUsers allows disabling accounts and changing roles
+	PermManageUsers Permission = "users:manage"
+	// PermModerateEvents allows editing and deleting any event
+	PermModerateEvents Permission = "events:moderate"
+	// PermViewAdminActions allows reading the record of admin actions
+	PermViewAdminActions Permission = "admin_actions:view"
+	// PermViewAuditLog allows reading the whole audit log, not only the
+	// entries of one's own events
+	PermViewAuditLog Permission = "audit_log:view"
+)
+
+var rolePermissions = map[string][]Permission{
+	user.RoleAdmin: {
+		PermViewUsers,
+		PermManageUsers,
+		PermModerateEvents,
+		PermViewAdminActions,
+		PermViewAuditLog,
+	},
+	user.RoleSupport: {
+		PermViewUsers,
+		PermViewAdminActions,
+	},
+	user.RoleMember: {},
+}
+
+// HasPermission reports whether the given role grants perm
+func HasPermission(role string, perm Permission) bool {
+	for _, p := range rolePermissions[role] {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// Can reports whether the authenticated user in ctx is granted perm
+func Can(ctx context.Context, perm Permission) bool {
+	role, ok := GetRole(ctx)
+	if !ok {
+		return false
+	}
+	return HasPermission(role, perm)
+}
+
+// RequirePermission rejects requests whose user is not granted perm.
+// It must be mounted after AuthMiddleware.
+func RequirePermission(perm Permission) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !Can(r.Context(), perm) {
+				response.Error(w, apperror.ErrForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+
+	"event-planner/internal/db"
+	"event-planner/internal/user"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository handles the database operations on accounts
+type Repository struct {
+	db *pgxpool.Pool
+}
+
+// NewRepository creates a new account repository
+func NewRepository(db *pgxpool.Pool) *Repository {
+	return &Repository{db: db}
+}
+
+// CreateUser inserts a new account; a taken email is a conflict
+func (r *Repository) CreateUser(ctx context.Context, email, passwordHash, role string) (*user.User, error) {
+	var u user.User
+	query := `INSERT INTO users (email, password_hash, role) VALUES ($1, $2, $3) RETURNING id, email, role, created_at`
+	err := r.db.QueryRow(ctx, query, email, passwordHash, role).Scan(&u.ID, &u.Email, &u.Role, &u.CreatedAt)
+	if err != nil {
+		return nil, db.TranslateError(fmt.Errorf("failed to create user: %w", err), nil)
+	}
+
+	return &u, nil
+}
+
+// GetUserByEmail retrieves an account, including its password hash, by email
+func (r *Repository) GetUserByEmail(ctx context.Context, email string) (*user.User, error) {
+	var u user.User
+	query := `SELECT id, email, password_hash, role, disabled_at, created_at FROM users WHERE email = $1`
+	err := r.db.QueryRow(ctx, query, email).Scan(&u.ID, &u.Email, &u.PasswordHash, &u.Role, &u.DisabledAt, &u.CreatedAt)
+	if err != nil {
+		return nil, db.TranslateError(fmt.Errorf("failed to get user: %w", err), nil)
+	}
+
+	return &u, nil
+}
+
+// GetUserByID retrieves an account, including its password hash, by ID
+func (r *Repository) GetUserByID(ctx context.Context, userID int) (*user.User, error) {
+	var u user.User
+	query := `SELECT id, email, password_hash, role, disabled_at, created_at FROM users WHERE id = $1`
+	err := r.db.QueryRow(ctx, query, userID).Scan(&u.ID, &u.Email, &u.PasswordHash, &u.Role, &u.DisabledAt, &u.CreatedAt)
+	if err != nil {
+		return nil, db.TranslateError(fmt.Errorf("failed to get user: %w", err), nil)
+	}
+
+	return &u, nil
+}
+
+// PromoteToAdmin grants the admin role to the accounts with one of the
+// lower-cased emails
+func (r *Repository) PromoteToAdmin(ctx context.Context, emails []string) error {
+	query := `UPDATE users SET role = 'admin' WHERE lower(email) = ANY($1) AND role <> 'admin'`
+	if _, err := r.db.Exec(ctx, query, emails); err != nil {
+		return fmt.Errorf("failed to promote bootstrap admins: %w", err)
+	}
+
+	return nil
+}
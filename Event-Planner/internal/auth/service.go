@@ -4,42 +4,85 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"os"
+	"log/slog"
+	"strings"
 	"time"
 
+	"event-planner/internal/audit"
+	"event-planner/internal/logging"
+	"event-planner/internal/metrics"
+	"event-planner/internal/tracing"
 	"event-planner/internal/user"
 
 	"github.com/golang-jwt/jwt/v5"
-	"github.com/jackc/pgx/v5/pgxpool"
 	"golang.org/x/crypto/bcrypt"
 )
 
+// Store is the account storage used by Service; Repository implements it on PostgreSQL
+type Store interface {
+	CreateUser(ctx context.Context, email, passwordHash, role string) (*user.User, error)
+	GetUserByEmail(ctx context.Context, email string) (*user.User, error)
+	GetUserByID(ctx context.Context, userID int) (*user.User, error)
+	PromoteToAdmin(ctx context.Context, emails []string) error
+}
+
+// Config holds the token and bootstrap settings of the service
+type Config struct {
+	JWTSecret   string   // signs and verifies access tokens
+	AdminEmails []string // accounts made admins at registration and on start
+}
+
 type Service struct {
-	db *pgxpool.Pool
+	store       Store
+	jwtSecret   []byte
+	adminEmails []string // lower-cased
+	metrics     *metrics.Metrics
+	audit       *audit.Service
 }
 
-func NewService(db *pgxpool.Pool) *Service {
-	return &Service{db: db}
+// NewService creates the service; metrics and the audit log may be nil
+func NewService(store Store, cfg Config, m *metrics.Metrics, auditLog *audit.Service) *Service {
+	var admins []string
+	for _, email := range cfg.AdminEmails {
+		if email = strings.ToLower(strings.TrimSpace(email)); email != "" {
+			admins = append(admins, email)
+		}
+	}
+	return &Service{store: store, jwtSecret: []byte(cfg.JWTSecret), adminEmails: admins, metrics: m, audit: auditLog}
 }
 
 // Register creates a new user account
 func (s *Service) Register(ctx context.Context, req user.RegisterRequest) (*user.AuthResponse, error) {
+	ctx, span := tracing.Start(ctx, "auth.Service.Register")
+	defer span.End()
+
 	// Hash password
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
 	if err != nil {
 		return nil, fmt.Errorf("failed to hash password: %w", err)
 	}
 
-	// Insert user into database
-	var u user.User
-	query := `INSERT INTO users (email, password_hash) VALUES ($1, $2) RETURNING id, email, created_at`
-	err = s.db.QueryRow(ctx, query, req.Email, string(hashedPassword)).Scan(&u.ID, &u.Email, &u.CreatedAt)
+	role := user.RoleMember
+	if s.isBootstrapAdmin(req.Email) {
+		role = user.RoleAdmin
+	}
+
+	// A duplicate email is a 409 conflict
+	u, err := s.store.CreateUser(ctx, req.Email, string(hashedPassword), role)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create user: %w", err)
+		return nil, err
 	}
+	slog.InfoContext(ctx, "account registered", "user_id", u.ID, "email", logging.Email(u.Email), "role", role)
+	s.audit.Record(ctx, audit.Entry{
+		ActorID:    audit.Actor(u.ID),
+		Action:     audit.ActionUserRegister,
+		TargetType: audit.TargetUser,
+		TargetID:   u.ID,
+		After:      audit.Snapshot(map[string]string{"email": logging.Email(u.Email), "role": role}),
+	})
 
 	// Generate JWT token
-	token, err := s.generateToken(u.ID)
+	token, err := s.IssueToken(u.ID, 0)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate token: %w", err)
 	}
@@ -51,25 +94,41 @@ func (s *Service) Register(ctx context.Context, req user.RegisterRequest) (*user
 
 // Login authenticates a user and returns a token
 func (s *Service) Login(ctx context.Context, req user.LoginRequest) (*user.AuthResponse, error) {
-	var u user.User
-	query := `SELECT id, email, password_hash, created_at FROM users WHERE email = $1`
-	err := s.db.QueryRow(ctx, query, req.Email).Scan(&u.ID, &u.Email, &u.PasswordHash, &u.CreatedAt)
+	ctx, span := tracing.Start(ctx, "auth.Service.Login")
+	defer span.End()
+
+	u, err := s.store.GetUserByEmail(ctx, req.Email)
 	if err != nil {
-		return nil, errors.New("invalid email or password")
+		s.loginFailed(ctx, 0, req.Email, "invalid_credentials")
+		return nil, ErrInvalidCredentials
 	}
 
 	// Check password
 	err = bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(req.Password))
 	if err != nil {
-		return nil, errors.New("invalid email or password")
+		s.loginFailed(ctx, u.ID, req.Email, "invalid_credentials")
+		return nil, ErrInvalidCredentials
+	}
+
+	// Disabled accounts cannot sign in (checked after the password so it doesn't leak account state)
+	if u.DisabledAt != nil {
+		s.loginFailed(ctx, u.ID, req.Email, "account_disabled")
+		return nil, ErrAccountDisabled
 	}
 
 	// Generate JWT token
-	token, err := s.generateToken(u.ID)
+	token, err := s.IssueToken(u.ID, 0)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate token: %w", err)
 	}
 
+	s.audit.Record(ctx, audit.Entry{
+		ActorID:    audit.Actor(u.ID),
+		Action:     audit.ActionUserLogin,
+		TargetType: audit.TargetUser,
+		TargetID:   u.ID,
+	})
+
 	// Clear password hash before returning
 	u.PasswordHash = ""
 
@@ -78,47 +137,124 @@ func (s *Service) Login(ctx context.Context, req user.LoginRequest) (*user.AuthR
 	}, nil
 }
 
-// generateToken creates a JWT token for the user
-func (s *Service) generateToken(userID int) (string, error) {
+// loginFailed counts, logs and audits a refused login; userID is 0 when no
+// account has the email
+func (s *Service) loginFailed(ctx context.Context, userID int, email, reason string) {
+	s.metrics.LoginFailed(reason)
+	slog.InfoContext(ctx, "login failed", "email", logging.Email(email), "reason", reason)
+	s.audit.Record(ctx, audit.Entry{
+		Action:     audit.ActionUserLoginFailed,
+		TargetType: audit.TargetUser,
+		TargetID:   userID,
+		After:      audit.Snapshot(map[string]string{"email": logging.Email(email), "reason": reason}),
+	})
+}
+
+// VerifyPassword checks the password of an existing account
+func (s *Service) VerifyPassword(ctx context.Context, userID int, password string) error {
+	ctx, span := tracing.Start(ctx, "auth.Service.VerifyPassword")
+	defer span.End()
+
+	u, err := s.store.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(password)); err != nil {
+		return ErrInvalidPassword
+	}
+
+	return nil
+}
+
+// GetAccountStatus returns the system role of a user and whether the account is disabled
+func (s *Service) GetAccountStatus(ctx context.Context, userID int) (string, bool, error) {
+	ctx, span := tracing.Start(ctx, "auth.Service.GetAccountStatus")
+	defer span.End()
+
+	u, err := s.store.GetUserByID(ctx, userID)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get account status: %w", err)
+	}
+
+	return u.Role, u.DisabledAt != nil, nil
+}
+
+// PromoteBootstrapAdmins grants the admin role to the existing accounts listed in ADMIN_EMAILS
+func (s *Service) PromoteBootstrapAdmins(ctx context.Context) error {
+	ctx, span := tracing.Start(ctx, "auth.Service.PromoteBootstrapAdmins")
+	defer span.End()
+
+	if len(s.adminEmails) == 0 {
+		return nil
+	}
+
+	return s.store.PromoteToAdmin(ctx, s.adminEmails)
+}
+
+func (s *Service) isBootstrapAdmin(email string) bool {
+	for _, admin := range s.adminEmails {
+		if strings.EqualFold(admin, email) {
+			return true
+		}
+	}
+	return false
+}
+
+// TokenClaims are the claims carried by an access token
+type TokenClaims struct {
+	UserID         int
+	OrganizationID int // 0 when the token is not bound to an organization
+}
+
+// IssueToken creates a JWT token for the user, optionally bound to an organization (orgID > 0)
+func (s *Service) IssueToken(userID, orgID int) (string, error) {
 	claims := jwt.MapClaims{
 		"user_id": userID,
 		"exp":     time.Now().Add(time.Hour * 24 * 7).Unix(), // 7 days
 	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	jwtSecret := os.Getenv("JWT_SECRET")
-	if jwtSecret == "" {
-		jwtSecret = "your-secret-key" // Default for development
+	if orgID > 0 {
+		claims["org_id"] = orgID
 	}
 
-	return token.SignedString([]byte(jwtSecret))
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(s.jwtSecret)
 }
 
-// ValidateToken validates and parses a JWT token
+// ValidateToken validates a JWT token and returns the user ID
 func (s *Service) ValidateToken(tokenString string) (int, error) {
-	jwtSecret := os.Getenv("JWT_SECRET")
-	if jwtSecret == "" {
-		jwtSecret = "your-secret-key" // Default for development
+	claims, err := s.ParseToken(tokenString)
+	if err != nil {
+		return 0, err
 	}
+	return claims.UserID, nil
+}
 
+// ParseToken validates and parses a JWT token
+func (s *Service) ParseToken(tokenString string) (*TokenClaims, error) {
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
-		return []byte(jwtSecret), nil
+		return s.jwtSecret, nil
 	})
 
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
 	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
 		userID, ok := claims["user_id"].(float64)
 		if !ok {
-			return 0, errors.New("invalid user ID in token")
+			return nil, errors.New("invalid user ID in token")
+		}
+
+		result := &TokenClaims{UserID: int(userID)}
+		if orgID, ok := claims["org_id"].(float64); ok {
+			result.OrganizationID = int(orgID)
 		}
-		return int(userID), nil
+		return result, nil
 	}
 
-	return 0, errors.New("invalid token")
+	return nil, errors.New("invalid token")
 }
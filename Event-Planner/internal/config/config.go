@@ -0,0 +1,460 @@
+// Package config loads the server configuration into a typed struct.
+//
+// Every setting has an environment variable name, which is also its key in
+// the optional configuration file (KEY=value lines, like .env), and a
+// command-line flag. Flags win over environment variables, which win over the
+// file, which wins over the defaults. The file is named by the -config flag
+// or the CONFIG_FILE variable.
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+// Environments
+const (
+	Development = "development"
+	Production  = "production"
+)
+
+// DevelopmentJWTSecret signs tokens when no JWT_SECRET is set outside production
+const DevelopmentJWTSecret = "your-secret-key"
+
+// minSecretLength is the shortest JWT_SECRET accepted in production
+const minSecretLength = 32
+
+// Config is the configuration of the server
+type Config struct {
+	Env     string // development or production
+	HTTP    HTTP
+	DB      DB
+	Auth    Auth
+	Tracing Tracing
+	Log     Log
+
+	AvatarDir          string // where uploaded avatars are stored
+	DeletionGraceDays  int    // days before a deleted account is erased
+	TrashRetentionDays int    // days a deleted event stays in the trash before it is erased
+
+	// Warnings are problems tolerated outside production
+	Warnings []string
+}
+
+// HTTP configures the HTTP server
+type HTTP struct {
+	Addr        string   // listen address
+	TLSCert     string   // certificate file; serves HTTPS together with TLSKey
+	TLSKey      string   // private key file
+	CORSOrigins []string // origins allowed to call the API from a browser
+
+	ReadHeaderTimeout time.Duration
+	ReadTimeout       time.Duration
+	WriteTimeout      time.Duration // also bounds the slowest handler
+	IdleTimeout       time.Duration // keep-alive connections
+
+	ShutdownDelay   time.Duration // requests are still served while /readyz fails
+	ShutdownTimeout time.Duration // for in-flight requests and workers
+}
+
+// DB configures the PostgreSQL connection pool
+type DB struct {
+	Host     string
+	Port     int
+	User     string
+	Password string
+	Name     string
+
+	SSLMode     string // disable, allow, prefer, require, verify-ca or verify-full
+	SSLRootCert string // CA certificate file to verify the server with
+	SSLCert     string // client certificate file
+	SSLKey      string // client private key file
+
+	MaxConns          int
+	MinConns          int
+	MaxConnLifetime   time.Duration
+	MaxConnIdleTime   time.Duration
+	HealthCheckPeriod time.Duration
+	ConnectTimeout    time.Duration
+
+	AutoMigrate bool // apply pending migrations on start
+}
+
+// Auth configures tokens and bootstrap admins
+type Auth struct {
+	JWTSecret   string
+	AdminEmails []string
+}
+
+// Log configures the server logs
+type Log struct {
+	Level  string // debug, info, warn or error
+	Format string // text or json; text in development and json in production when empty
+}
+
+// Tracing configures the export of OpenTelemetry traces
+type Tracing struct {
+	Exporter     string  // stdout, otlp or none; stdout in development and otlp in production when empty
+	OTLPEndpoint string  // collector URL, e.g. http://collector:4318; OTEL_EXPORTER_OTLP_ENDPOINT when empty
+	SampleRatio  float64 // share of the traces started here that are recorded
+	ServiceName  string
+}
+
+// Default returns the configuration used for settings that aren't set
+func Default() *Config {
+	return &Config{
+		Env: Development,
+		HTTP: HTTP{
+			Addr:              ":8080",
+			CORSOrigins:       []string{"http://localhost:4200", "http://127.0.0.1:4200"},
+			ReadHeaderTimeout: 5 * time.Second,
+			ReadTimeout:       30 * time.Second,
+			WriteTimeout:      60 * time.Second,
+			IdleTimeout:       120 * time.Second,
+			ShutdownDelay:     5 * time.Second,
+			ShutdownTimeout:   30 * time.Second,
+		},
+		DB: DB{
+			Host:              "localhost",
+			Port:              5432,
+			SSLMode:           "prefer",
+			MaxConns:          10,
+			MaxConnLifetime:   time.Hour,
+			MaxConnIdleTime:   30 * time.Minute,
+			HealthCheckPeriod: time.Minute,
+			ConnectTimeout:    5 * time.Second,
+			AutoMigrate:       true,
+		},
+		Tracing: Tracing{
+			SampleRatio: 1,
+			ServiceName: "event-planner",
+		},
+		Log: Log{
+			Level: "info",
+		},
+		AvatarDir:          "./uploads/avatars",
+		DeletionGraceDays:  30,
+		TrashRetentionDays: 30,
+	}
+}
+
+// setting binds a configuration value to its names
+type setting struct {
+	key    string // environment variable and file key
+	flag   string
+	usage  string
+	value  flag.Value
+	secret bool // redacted when printed
+}
+
+func (c *Config) settings() []setting {
+	return []setting{
+		{"APP_ENV", "env", "development or production", stringValue{&c.Env}, false},
+
+		{"HTTP_ADDR", "http-addr", "listen address", stringValue{&c.HTTP.Addr}, false},
+		{"HTTP_TLS_CERT", "http-tls-cert", "TLS certificate file, serves HTTPS with -http-tls-key", stringValue{&c.HTTP.TLSCert}, false},
+		{"HTTP_TLS_KEY", "http-tls-key", "TLS private key file", stringValue{&c.HTTP.TLSKey}, false},
+		{"CORS_ALLOWED_ORIGINS", "cors-allowed-origins", "comma-separated origins allowed to call the API from a browser", listValue{&c.HTTP.CORSOrigins}, false},
+		{"HTTP_READ_HEADER_TIMEOUT", "http-read-header-timeout", "time to read request headers", durationValue{&c.HTTP.ReadHeaderTimeout}, false},
+		{"HTTP_READ_TIMEOUT", "http-read-timeout", "time to read a request", durationValue{&c.HTTP.ReadTimeout}, false},
+		{"HTTP_WRITE_TIMEOUT", "http-write-timeout", "time to handle a request and write the response", durationValue{&c.HTTP.WriteTimeout}, false},
+		{"HTTP_IDLE_TIMEOUT", "http-idle-timeout", "time keep-alive connections stay open", durationValue{&c.HTTP.IdleTimeout}, false},
+		{"SHUTDOWN_DELAY", "shutdown-delay", "time requests are still served after /readyz starts failing", durationValue{&c.HTTP.ShutdownDelay}, false},
+		{"SHUTDOWN_TIMEOUT", "shutdown-timeout", "time for in-flight requests and workers to finish", durationValue{&c.HTTP.ShutdownTimeout}, false},
+
+		{"DB_HOST", "db-host", "database host", stringValue{&c.DB.Host}, false},
+		{"DB_PORT", "db-port", "database port", intValue{&c.DB.Port}, false},
+		{"DB_USER", "db-user", "database user", stringValue{&c.DB.User}, false},
+		{"DB_PASSWORD", "db-password", "database password", stringValue{&c.DB.Password}, true},
+		{"DB_NAME", "db-name", "database name", stringValue{&c.DB.Name}, false},
+		{"DB_SSLMODE", "db-sslmode", "disable, allow, prefer, require, verify-ca or verify-full", stringValue{&c.DB.SSLMode}, false},
+		{"DB_SSLROOTCERT", "db-sslrootcert", "CA certificate file to verify the database server", stringValue{&c.DB.SSLRootCert}, false},
+		{"DB_SSLCERT", "db-sslcert", "client certificate file", stringValue{&c.DB.SSLCert}, false},
+		{"DB_SSLKEY", "db-sslkey", "client private key file", stringValue{&c.DB.SSLKey}, false},
+		{"DB_MAX_CONNS", "db-max-conns", "maximum open connections", intValue{&c.DB.MaxConns}, false},
+		{"DB_MIN_CONNS", "db-min-conns", "connections kept open when idle", intValue{&c.DB.MinConns}, false},
+		{"DB_MAX_CONN_LIFETIME", "db-max-conn-lifetime", "time after which a connection is replaced", durationValue{&c.DB.MaxConnLifetime}, false},
+		{"DB_MAX_CONN_IDLE_TIME", "db-max-conn-idle-time", "time after which an idle connection is closed", durationValue{&c.DB.MaxConnIdleTime}, false},
+		{"DB_HEALTH_CHECK_PERIOD", "db-health-check-period", "interval of the idle connection checks", durationValue{&c.DB.HealthCheckPeriod}, false},
+		{"DB_CONNECT_TIMEOUT", "db-connect-timeout", "time to establish a connection", durationValue{&c.DB.ConnectTimeout}, false},
+		{"DB_AUTO_MIGRATE", "db-auto-migrate", "apply pending migrations on start", boolValue{&c.DB.AutoMigrate}, false},
+
+		{"JWT_SECRET", "jwt-secret", "key signing access tokens, required in production", stringValue{&c.Auth.JWTSecret}, true},
+		{"ADMIN_EMAILS", "admin-emails", "comma-separated emails of accounts made admins", listValue{&c.Auth.AdminEmails}, false},
+
+		{"TRACING_EXPORTER", "tracing-exporter", "stdout, otlp or none (default stdout in development, otlp in production)", stringValue{&c.Tracing.Exporter}, false},
+		{"TRACING_OTLP_ENDPOINT", "tracing-otlp-endpoint", "OTLP/HTTP collector URL", stringValue{&c.Tracing.OTLPEndpoint}, false},
+		{"TRACING_SAMPLE_RATIO", "tracing-sample-ratio", "share of new traces recorded, between 0 and 1", floatValue{&c.Tracing.SampleRatio}, false},
+		{"TRACING_SERVICE_NAME", "tracing-service-name", "service name of the spans", stringValue{&c.Tracing.ServiceName}, false},
+
+		{"LOG_LEVEL", "log-level", "debug, info, warn or error", stringValue{&c.Log.Level}, false},
+		{"LOG_FORMAT", "log-format", "text or json (default text in development, json in production)", stringValue{&c.Log.Format}, false},
+
+		{"AVATAR_DIR", "avatar-dir", "directory of uploaded avatars", stringValue{&c.AvatarDir}, false},
+		{"ACCOUNT_DELETION_GRACE_DAYS", "account-deletion-grace-days", "days before a deleted account is erased", intValue{&c.DeletionGraceDays}, false},
+		{"EVENT_TRASH_RETENTION_DAYS", "event-trash-retention-days", "days a deleted event can be restored before it is erased", intValue{&c.TrashRetentionDays}, false},
+	}
+}
+
+// Load reads the configuration from the defaults, the optional file, the
+// environment and the command-line arguments, and validates it. It returns
+// flag.ErrHelp when args ask for help.
+func Load(args []string) (*Config, error) {
+	cfg := Default()
+	settings := cfg.settings()
+
+	// Flags are applied last, so only collect them here
+	fs := flag.NewFlagSet("server", flag.ContinueOnError)
+	file := fs.String("config", os.Getenv("CONFIG_FILE"), "configuration file of KEY=value lines (CONFIG_FILE)")
+	flags := map[string]string{}
+	for _, s := range settings {
+		key := s.key
+		fs.Func(s.flag, fmt.Sprintf("%s (%s)", s.usage, s.key), func(v string) error {
+			flags[key] = v
+			return nil
+		})
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+
+	var fileValues map[string]string
+	if *file != "" {
+		values, err := godotenv.Read(*file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+		fileValues = values
+	}
+
+	var errs []error
+	for _, s := range settings {
+		if v, ok := fileValues[s.key]; ok {
+			errs = append(errs, set(s, v, *file))
+		}
+		if v, ok := os.LookupEnv(s.key); ok {
+			errs = append(errs, set(s, v, "environment"))
+		}
+		if v, ok := flags[s.key]; ok {
+			errs = append(errs, set(s, v, "-"+s.flag))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func set(s setting, value, source string) error {
+	if err := s.value.Set(value); err != nil {
+		return fmt.Errorf("%s (from %s): %w", s.key, source, err)
+	}
+	return nil
+}
+
+// validate checks the values and fills in what development can do without
+func (c *Config) validate() error {
+	var errs []error
+	fail := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if c.Env != Development && c.Env != Production {
+		fail("APP_ENV must be %q or %q, not %q", Development, Production, c.Env)
+	}
+
+	// Secrets
+	switch {
+	case c.Auth.JWTSecret == "" && c.Env == Production:
+		fail("JWT_SECRET is required in production")
+	case c.Auth.JWTSecret == "":
+		c.Auth.JWTSecret = DevelopmentJWTSecret
+		c.Warnings = append(c.Warnings, "JWT_SECRET is not set, tokens are signed with a well-known development key")
+	case c.Env == Production && (len(c.Auth.JWTSecret) < minSecretLength || c.Auth.JWTSecret == DevelopmentJWTSecret):
+		fail("JWT_SECRET must be a random value of at least %d characters in production", minSecretLength)
+	}
+	if c.Env == Production && c.DB.Password == "" && c.DB.SSLCert == "" {
+		fail("DB_PASSWORD (or a DB_SSLCERT client certificate) is required in production")
+	}
+
+	// HTTP
+	if c.HTTP.Addr == "" {
+		fail("HTTP_ADDR is required")
+	}
+	if (c.HTTP.TLSCert == "") != (c.HTTP.TLSKey == "") {
+		fail("HTTP_TLS_CERT and HTTP_TLS_KEY must be set together")
+	}
+
+	// Database
+	if c.DB.Host == "" {
+		fail("DB_HOST is required")
+	}
+	if c.DB.Port < 1 || c.DB.Port > 65535 {
+		fail("DB_PORT must be between 1 and 65535")
+	}
+	if c.DB.User == "" {
+		fail("DB_USER is required")
+	}
+	if c.DB.Name == "" {
+		fail("DB_NAME is required")
+	}
+	switch c.DB.SSLMode {
+	case "disable", "allow", "prefer", "require", "verify-ca", "verify-full":
+	default:
+		fail("DB_SSLMODE must be disable, allow, prefer, require, verify-ca or verify-full, not %q", c.DB.SSLMode)
+	}
+	if (c.DB.SSLCert == "") != (c.DB.SSLKey == "") {
+		fail("DB_SSLCERT and DB_SSLKEY must be set together")
+	}
+	if c.DB.MaxConns < 1 {
+		fail("DB_MAX_CONNS must be at least 1")
+	}
+	if c.DB.MinConns < 0 || c.DB.MinConns > c.DB.MaxConns {
+		fail("DB_MIN_CONNS must be between 0 and DB_MAX_CONNS")
+	}
+
+	// Tracing
+	switch {
+	case c.Tracing.Exporter == "" && c.Env == Production:
+		c.Tracing.Exporter = "otlp"
+	case c.Tracing.Exporter == "":
+		c.Tracing.Exporter = "stdout"
+	case c.Tracing.Exporter != "stdout" && c.Tracing.Exporter != "otlp" && c.Tracing.Exporter != "none":
+		fail("TRACING_EXPORTER must be stdout, otlp or none, not %q", c.Tracing.Exporter)
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		fail("TRACING_SAMPLE_RATIO must be between 0 and 1")
+	}
+	if c.Tracing.ServiceName == "" {
+		fail("TRACING_SERVICE_NAME is required")
+	}
+
+	// Logs
+	switch strings.ToLower(c.Log.Level) {
+	case "debug", "info", "warn", "error":
+	default:
+		fail("LOG_LEVEL must be debug, info, warn or error, not %q", c.Log.Level)
+	}
+	switch {
+	case c.Log.Format == "" && c.Env == Production:
+		c.Log.Format = "json"
+	case c.Log.Format == "":
+		c.Log.Format = "text"
+	case c.Log.Format != "text" && c.Log.Format != "json":
+		fail("LOG_FORMAT must be text or json, not %q", c.Log.Format)
+	}
+
+	if c.DeletionGraceDays < 1 {
+		fail("ACCOUNT_DELETION_GRACE_DAYS must be at least 1")
+	}
+	if c.TrashRetentionDays < 1 {
+		fail("EVENT_TRASH_RETENTION_DAYS must be at least 1")
+	}
+
+	return errors.Join(errs...)
+}
+
+// Redacted lists every setting as KEY=value, with secrets masked
+func (c *Config) Redacted() []string {
+	var lines []string
+	for _, s := range c.settings() {
+		value := s.value.String()
+		if s.secret && value != "" {
+			value = "[redacted]"
+		}
+		lines = append(lines, s.key+"="+value)
+	}
+	return lines
+}
+
+// IsProduction reports whether the server runs in production mode
+func (c *Config) IsProduction() bool {
+	return c.Env == Production
+}
+
+// Values
+
+type stringValue struct{ p *string }
+
+func (v stringValue) Set(s string) error { *v.p = s; return nil }
+func (v stringValue) String() string     { return *v.p }
+
+type intValue struct{ p *int }
+
+func (v intValue) Set(s string) error {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return fmt.Errorf("invalid number %q", s)
+	}
+	*v.p = n
+	return nil
+}
+func (v intValue) String() string { return fmt.Sprint(*v.p) }
+
+type floatValue struct{ p *float64 }
+
+func (v floatValue) Set(s string) error {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fmt.Errorf("invalid number %q", s)
+	}
+	*v.p = f
+	return nil
+}
+func (v floatValue) String() string { return strconv.FormatFloat(*v.p, 'g', -1, 64) }
+
+type boolValue struct{ p *bool }
+
+func (v boolValue) Set(s string) error {
+	switch strings.ToLower(s) {
+	case "true", "1", "yes":
+		*v.p = true
+	case "false", "0", "no":
+		*v.p = false
+	default:
+		return fmt.Errorf("invalid boolean %q", s)
+	}
+	return nil
+}
+func (v boolValue) String() string { return fmt.Sprint(*v.p) }
+
+type durationValue struct{ p *time.Duration }
+
+func (v durationValue) Set(s string) error {
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return fmt.Errorf("invalid duration %q, use a value such as 30s or 5m", s)
+	}
+	*v.p = d
+	return nil
+}
+func (v durationValue) String() string { return v.p.String() }
+
+// listValue is a comma-separated list
+type listValue struct{ p *[]string }
+
+func (v listValue) Set(s string) error {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	*v.p = items
+	return nil
+}
+func (v listValue) String() string { return strings.Join(*v.p, ",") }
@@ -0,0 +1,132 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// setenv sets the variables for the test and unsets every other setting, so
+// the environment of the machine running the tests doesn't leak in
+func setenv(t *testing.T, vars map[string]string) {
+	t.Helper()
+
+	for _, s := range Default().settings() {
+		t.Setenv(s.key, "")
+		os.Unsetenv(s.key)
+	}
+	t.Setenv("CONFIG_FILE", "")
+	for k, v := range vars {
+		t.Setenv(k, v)
+	}
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "server.env")
+	content := "DB_USER=file-user\nDB_NAME=file-db\nDB_HOST=file-host\nDB_MAX_CONNS=20\n"
+	if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	setenv(t, map[string]string{"CONFIG_FILE": file, "DB_HOST": "env-host", "DB_MAX_CONNS": "30"})
+
+	cfg, err := Load([]string{"-db-max-conns", "40", "-cors-allowed-origins", "https://a.example, https://b.example"})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if cfg.DB.User != "file-user" || cfg.DB.Name != "file-db" {
+		t.Errorf("file values not applied: %+v", cfg.DB)
+	}
+	if cfg.DB.Host != "env-host" {
+		t.Errorf("DB.Host = %q, the environment should win over the file", cfg.DB.Host)
+	}
+	if cfg.DB.MaxConns != 40 {
+		t.Errorf("DB.MaxConns = %d, flags should win over the environment", cfg.DB.MaxConns)
+	}
+	if !slices.Equal(cfg.HTTP.CORSOrigins, []string{"https://a.example", "https://b.example"}) {
+		t.Errorf("HTTP.CORSOrigins = %q", cfg.HTTP.CORSOrigins)
+	}
+
+	// Untouched settings keep their defaults
+	if cfg.HTTP.Addr != ":8080" || cfg.DB.Port != 5432 || cfg.HTTP.ShutdownTimeout != 30*time.Second || !cfg.DB.AutoMigrate {
+		t.Errorf("defaults not kept: %+v", cfg)
+	}
+}
+
+func TestLoadDevelopmentSecret(t *testing.T) {
+	setenv(t, map[string]string{"DB_USER": "u", "DB_NAME": "n"})
+
+	cfg, err := Load(nil)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Auth.JWTSecret != DevelopmentJWTSecret || len(cfg.Warnings) != 1 {
+		t.Errorf("got secret %q and warnings %q, want the development secret with a warning", cfg.Auth.JWTSecret, cfg.Warnings)
+	}
+}
+
+func TestLoadValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want string // part of the error, none when valid
+	}{
+		{"production without secrets", map[string]string{"APP_ENV": "production"}, "JWT_SECRET is required in production"},
+		{"production without a database password", map[string]string{"APP_ENV": "production"}, "DB_PASSWORD"},
+		{"short secret in production", map[string]string{"APP_ENV": "production", "JWT_SECRET": "short", "DB_PASSWORD": "p"}, "at least 32 characters"},
+		{"valid production", map[string]string{"APP_ENV": "production", "JWT_SECRET": strings.Repeat("s", 32), "DB_PASSWORD": "p"}, ""},
+		{"client certificate instead of a password", map[string]string{"APP_ENV": "production", "JWT_SECRET": strings.Repeat("s", 32), "DB_SSLCERT": "c.pem", "DB_SSLKEY": "k.pem"}, ""},
+		{"unknown environment", map[string]string{"APP_ENV": "staging"}, "APP_ENV"},
+		{"invalid duration", map[string]string{"SHUTDOWN_TIMEOUT": "soon"}, "SHUTDOWN_TIMEOUT (from environment): invalid duration"},
+		{"invalid number", map[string]string{"DB_PORT": "54x"}, "DB_PORT (from environment): invalid number"},
+		{"invalid boolean", map[string]string{"DB_AUTO_MIGRATE": "maybe"}, "DB_AUTO_MIGRATE"},
+		{"sslmode", map[string]string{"DB_SSLMODE": "on"}, "DB_SSLMODE"},
+		{"TLS key without certificate", map[string]string{"HTTP_TLS_KEY": "k.pem"}, "HTTP_TLS_CERT and HTTP_TLS_KEY"},
+		{"pool sizes", map[string]string{"DB_MIN_CONNS": "20"}, "DB_MIN_CONNS"},
+		{"tracing exporter", map[string]string{"TRACING_EXPORTER": "jaeger"}, "TRACING_EXPORTER"},
+		{"sample ratio", map[string]string{"TRACING_SAMPLE_RATIO": "1.5"}, "TRACING_SAMPLE_RATIO"},
+		{"log level", map[string]string{"LOG_LEVEL": "verbose"}, "LOG_LEVEL"},
+		{"log format", map[string]string{"LOG_FORMAT": "xml"}, "LOG_FORMAT"},
+		{"trash retention", map[string]string{"EVENT_TRASH_RETENTION_DAYS": "0"}, "EVENT_TRASH_RETENTION_DAYS"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := map[string]string{"DB_USER": "u", "DB_NAME": "n"}
+			for k, v := range tt.env {
+				env[k] = v
+			}
+			setenv(t, env)
+
+			_, err := Load(nil)
+			switch {
+			case tt.want == "" && err != nil:
+				t.Errorf("Load: %v", err)
+			case tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)):
+				t.Errorf("Load error = %v, want one containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedacted(t *testing.T) {
+	setenv(t, map[string]string{"DB_USER": "u", "DB_NAME": "n", "DB_PASSWORD": "hunter2", "JWT_SECRET": "jwt-secret-value"})
+
+	cfg, err := Load(nil)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	lines := cfg.Redacted()
+	printed := strings.Join(lines, "\n")
+	if strings.Contains(printed, "hunter2") || strings.Contains(printed, "jwt-secret-value") {
+		t.Errorf("secrets printed:\n%s", printed)
+	}
+	for _, want := range []string{"DB_PASSWORD=[redacted]", "JWT_SECRET=[redacted]", "DB_USER=u", "HTTP_ADDR=:8080"} {
+		if !slices.Contains(lines, want) {
+			t.Errorf("Redacted() has no %q:\n%s", want, printed)
+		}
+	}
+}
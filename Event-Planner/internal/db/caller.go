@@ -0,0 +1,48 @@
+package db
+
+import (
+	"runtime"
+	"strings"
+)
+
+// callerPrefix is the package path of the repositories running the queries
+const callerPrefix = "event-planner/internal/"
+
+// instrumentation are the packages calling pgx on behalf of the repositories
+var instrumentation = []string{"db.", "metrics.", "tracing."}
+
+// QueryMethod names the repository method running the current statement,
+// e.g. event.Repository.CreateEvent, by looking for the first function of the
+// application on the call stack; "other" when there is none. Query tracers use
+// it as a label with a bounded number of values.
+func QueryMethod() string {
+	var pcs [32]uintptr
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs[:])])
+	for {
+		frame, more := frames.Next()
+		if name, ok := strings.CutPrefix(frame.Function, callerPrefix); ok && !isInstrumentation(name) {
+			return methodName(name)
+		}
+		if !more {
+			return "other"
+		}
+	}
+}
+
+func isInstrumentation(function string) bool {
+	for _, pkg := range instrumentation {
+		if strings.HasPrefix(function, pkg) {
+			return true
+		}
+	}
+	return false
+}
+
+// methodName shortens event.(*Repository).CreateEvent.func1 to
+// event.Repository.CreateEvent
+func methodName(function string) string {
+	if i := strings.Index(function, ".func"); i >= 0 {
+		function = function[:i]
+	}
+	return strings.NewReplacer("(*", "", ")", "").Replace(function)
+}
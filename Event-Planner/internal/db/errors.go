@@ -0,0 +1,106 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"event-planner/internal/apperror"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// PostgreSQL error codes translated by TranslateError
+const (
+	codeNotNullViolation    = "23502"
+	codeForeignKeyViolation = "23503"
+	codeUniqueViolation     = "23505"
+	codeCheckViolation      = "23514"
+)
+
+// constraintErrors gives known constraints a domain-specific error
+var constraintErrors = map[string]*apperror.Error{
+	"users_email_key":                      apperror.Conflict("email_taken", "a user with this email already exists"),
+	"organizations_slug_key":               apperror.Conflict("organization_slug_taken", "an organization with this slug already exists"),
+	"organization_members_pkey":            apperror.Conflict("already_member", "user is already a member of this organization"),
+	"event_attendees_user_id_event_id_key": apperror.Conflict("already_attending", "user is already attending this event"),
+	"group_members_group_id_email_key":     apperror.Conflict("already_group_member", "email is already a member of this group"),
+	"events_check":                         apperror.Unprocessable("invalid_visibility", "only organization events can have 'organization' visibility"),
+}
+
+// "Key (user_id, event_id)=(1, 2) already exists." / "... is not present in table "users"."
+var keyDetailRegex = regexp.MustCompile(`^Key \(([^)]+)\)=\(.*\) (?:already exists|is not present in table "([^"]+)")`)
+
+// TranslateError maps pgx and PostgreSQL errors to domain errors:
+// no rows becomes notFound (or a generic not found error when nil), unique
+// violations become conflicts, and foreign key, check and not-null violations
+// become unprocessable errors. Any other error is returned unchanged.
+func TranslateError(err error, notFound error) error {
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		if notFound != nil {
+			return notFound
+		}
+		return apperror.NotFound("not_found", "resource not found")
+	}
+
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	if known, ok := constraintErrors[pgErr.ConstraintName]; ok {
+		return known
+	}
+
+	switch pgErr.Code {
+	case codeUniqueViolation:
+		columns, _ := parseKeyDetail(pgErr.Detail)
+		message := "resource already exists"
+		if columns != "" {
+			message = fmt.Sprintf("a record with this %s already exists", columns)
+		}
+		return withField(apperror.Conflict("already_exists", message), columns)
+
+	case codeForeignKeyViolation:
+		columns, table := parseKeyDetail(pgErr.Detail)
+		message := "referenced resource does not exist"
+		if table != "" {
+			message = fmt.Sprintf("referenced %s does not exist", strings.TrimSuffix(table, "s"))
+		}
+		return withField(apperror.Unprocessable("invalid_reference", message), columns)
+
+	case codeCheckViolation:
+		column := strings.TrimSuffix(strings.TrimPrefix(pgErr.ConstraintName, pgErr.TableName+"_"), "_check")
+		if column == "" || column == pgErr.ConstraintName {
+			return apperror.Unprocessable("constraint_violation", "value violates a data constraint")
+		}
+		return withField(apperror.Unprocessable("constraint_violation", fmt.Sprintf("invalid value for %s", column)), column)
+
+	case codeNotNullViolation:
+		return withField(apperror.Unprocessable("constraint_violation", fmt.Sprintf("%s is required", pgErr.ColumnName)), pgErr.ColumnName)
+	}
+
+	return err
+}
+
+// parseKeyDetail extracts the key columns and, for foreign keys, the referenced table
+func parseKeyDetail(detail string) (string, string) {
+	m := keyDetailRegex.FindStringSubmatch(detail)
+	if m == nil {
+		return "", ""
+	}
+	return m[1], m[2]
+}
+
+func withField(err *apperror.Error, field string) *apperror.Error {
+	if field != "" {
+		err.Fields = []apperror.FieldError{{Field: field, Message: err.Message}}
+	}
+	return err
+}
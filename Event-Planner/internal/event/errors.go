@@ -0,0 +1,19 @@
+package event
+
+import "event-planner/internal/apperror"
+
+// Domain errors returned by the event service
+var (
+	ErrEventNotFound        = apperror.NotFound("event_not_found", "event not found")
+	ErrEventArchived        = apperror.Conflict("event_archived", "event is archived")
+	ErrEventCancelled       = apperror.Conflict("event_cancelled", "event is cancelled")
+	ErrEventCompleted       = apperror.Conflict("event_completed", "event has already taken place")
+	ErrEventDraft           = apperror.Conflict("event_draft", "event is not published yet")
+	ErrEventPublished       = apperror.Conflict("event_published", "event is already published")
+	ErrNotEventCreator      = apperror.Forbidden("not_event_creator", "only the event creator can invite users to this event")
+	ErrNotAttendee          = apperror.Forbidden("not_attendee", "only attendees can see the changes of this event")
+	ErrRevisionNotFound     = apperror.NotFound("revision_not_found", "revision not found")
+	ErrTrashedEventNotFound = apperror.NotFound("trashed_event_not_found", "event is not in your trash")
+	ErrVersionMismatch      = apperror.PreconditionFailed("version_mismatch", "the event has changed since it was read; fetch it again and retry")
+	ErrIfMatchRequired      = apperror.PreconditionRequired("if_match_required", "send the ETag of the event in an If-Match header")
+)
@@ -0,0 +1,54 @@
+package event
+
+import (
+	"strconv"
+	"strings"
+)
+
+// AnyVersion makes an update or delete unconditional; it is what If-Match: *
+// asks for
+const AnyVersion = 0
+
+// ETag returns the entity tag of the current version of the event
+func (e *Event) ETag() string {
+	return `"` + strconv.Itoa(e.Version) + `"`
+}
+
+// CheckVersion fails unless the event is at the version (or version is AnyVersion)
+func (e *Event) CheckVersion(version int) error {
+	if version != AnyVersion && e.Version != version {
+		return ErrVersionMismatch
+	}
+	return nil
+}
+
+// parseIfMatch returns the version an If-Match header names, or AnyVersion
+// for "*". Only a single strong entity tag as issued by ETag is understood;
+// anything else cannot match the current version.
+func parseIfMatch(header string) (int, error) {
+	header = strings.TrimSpace(header)
+	switch {
+	case header == "":
+		return 0, ErrIfMatchRequired
+	case header == "*":
+		return AnyVersion, nil
+	}
+
+	version, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(header, `"`), `"`))
+	if err != nil || version <= 0 || !strings.HasPrefix(header, `"`) || !strings.HasSuffix(header, `"`) {
+		return 0, ErrVersionMismatch
+	}
+	return version, nil
+}
+
+// IfNoneMatch reports whether an If-None-Match header lists etag, or is "*".
+// As the header requires, entity tags are compared weakly.
+func IfNoneMatch(header, etag string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
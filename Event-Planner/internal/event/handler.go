@@ -1,12 +1,17 @@
 package event
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
+	"errors"
+	"io"
 	"net/http"
+	"net/url"
 	"strconv"
 
+	"event-planner/internal/apperror"
 	"event-planner/internal/auth"
+	"event-planner/internal/response"
 )
 
 // Handler handles HTTP requests for events
@@ -24,19 +29,19 @@ func (h *Handler) CreateEvent(w http.ResponseWriter, r *http.Request) {
 	// Get organizer ID from context (set by auth middleware)
 	organizerID, ok := auth.GetUserID(r.Context())
 	if !ok {
-		http.Error(w, `{"error": "unauthorized"}`, http.StatusUnauthorized)
+		response.Error(w, apperror.ErrUnauthorized)
 		return
 	}
 
 	var req CreateEventRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, `{"error": "invalid request body"}`, http.StatusBadRequest)
+		response.Error(w, apperror.Validation("body", "invalid request body"))
 		return
 	}
 
 	event, err := h.service.CreateEvent(r.Context(), &req, organizerID)
 	if err != nil {
-		http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusBadRequest)
+		response.Error(w, err)
 		return
 	}
 
@@ -53,13 +58,22 @@ func (h *Handler) GetEventByID(w http.ResponseWriter, r *http.Request) {
 	idStr := r.PathValue("id")
 	eventID, err := strconv.Atoi(idStr)
 	if err != nil {
-		http.Error(w, `{"error": "invalid event ID"}`, http.StatusBadRequest)
+		response.Error(w, apperror.Validation("id", "invalid event ID"))
 		return
 	}
 
-	event, err := h.service.GetEventByID(r.Context(), eventID)
+	// Anonymous requests can see every event but drafts
+	userID, _ := auth.GetUserID(r.Context())
+	event, err := h.service.GetEventByID(r.Context(), eventID, userID)
 	if err != nil {
-		http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusNotFound)
+		response.Error(w, err)
+		return
+	}
+
+	// Polling clients send the ETag they have and get no body while it is current
+	w.Header().Set("ETag", event.ETag())
+	if IfNoneMatch(r.Header.Get("If-None-Match"), event.ETag()) {
+		w.WriteHeader(http.StatusNotModified)
 		return
 	}
 
@@ -72,9 +86,15 @@ func (h *Handler) GetEventByID(w http.ResponseWriter, r *http.Request) {
 
 // GetAllEvents handles GET /events
 func (h *Handler) GetAllEvents(w http.ResponseWriter, r *http.Request) {
-	events, err := h.service.GetAllEvents(r.Context())
+	page, err := ParsePage(r.URL.Query())
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	events, err := h.service.GetAllEvents(r.Context(), page)
 	if err != nil {
-		http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusInternalServerError)
+		response.Error(w, err)
 		return
 	}
 
@@ -90,13 +110,13 @@ func (h *Handler) GetEventsByOrganizer(w http.ResponseWriter, r *http.Request) {
 	idStr := r.PathValue("id")
 	organizerID, err := strconv.Atoi(idStr)
 	if err != nil {
-		http.Error(w, `{"error": "invalid organizer ID"}`, http.StatusBadRequest)
+		response.Error(w, apperror.Validation("id", "invalid organizer ID"))
 		return
 	}
 
 	events, err := h.service.GetEventsByOrganizerID(r.Context(), organizerID)
 	if err != nil {
-		http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusInternalServerError)
+		response.Error(w, err)
 		return
 	}
 
@@ -112,33 +132,65 @@ func (h *Handler) UpdateEvent(w http.ResponseWriter, r *http.Request) {
 	// Get user ID from context (set by auth middleware)
 	userID, ok := auth.GetUserID(r.Context())
 	if !ok {
-		http.Error(w, `{"error": "unauthorized"}`, http.StatusUnauthorized)
+		response.Error(w, apperror.ErrUnauthorized)
 		return
 	}
 
 	idStr := r.PathValue("id")
 	eventID, err := strconv.Atoi(idStr)
 	if err != nil {
-		http.Error(w, `{"error": "invalid event ID"}`, http.StatusBadRequest)
+		response.Error(w, apperror.Validation("id", "invalid event ID"))
 		return
 	}
 
 	var req UpdateEventRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, `{"error": "invalid request body"}`, http.StatusBadRequest)
+		response.Error(w, apperror.Validation("body", "invalid request body"))
 		return
 	}
 
-	event, err := h.service.UpdateEvent(r.Context(), eventID, &req, userID)
+	event, err := h.service.UpdateEvent(r.Context(), eventID, &req, userID, r.Header.Get("If-Match"))
 	if err != nil {
-		if err.Error() == "you are not authorized to update this event" {
-			http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusForbidden)
-		} else {
-			http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusBadRequest)
-		}
+		response.Error(w, err)
 		return
 	}
 
+	w.Header().Set("ETag", event.ETag())
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "event updated successfully",
+		"data":    event,
+	})
+}
+
+// PatchEvent handles PATCH /events/:id with a JSON merge patch (RFC 7396)
+func (h *Handler) PatchEvent(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, apperror.ErrUnauthorized)
+		return
+	}
+
+	eventID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		response.Error(w, apperror.Validation("id", "invalid event ID"))
+		return
+	}
+
+	patch, err := DecodeEventPatch(r.Body)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	event, err := h.service.PatchEvent(r.Context(), eventID, patch, userID, r.Header.Get("If-Match"))
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", event.ETag())
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]interface{}{
@@ -152,24 +204,20 @@ func (h *Handler) DeleteEvent(w http.ResponseWriter, r *http.Request) {
 	// Get user ID from context (set by auth middleware)
 	userID, ok := auth.GetUserID(r.Context())
 	if !ok {
-		http.Error(w, `{"error": "unauthorized"}`, http.StatusUnauthorized)
+		response.Error(w, apperror.ErrUnauthorized)
 		return
 	}
 
 	idStr := r.PathValue("id")
 	eventID, err := strconv.Atoi(idStr)
 	if err != nil {
-		http.Error(w, `{"error": "invalid event ID"}`, http.StatusBadRequest)
+		response.Error(w, apperror.Validation("id", "invalid event ID"))
 		return
 	}
 
-	err = h.service.DeleteEvent(r.Context(), eventID, userID)
+	err = h.service.DeleteEvent(r.Context(), eventID, userID, r.Header.Get("If-Match"))
 	if err != nil {
-		if err.Error() == "you are not authorized to delete this event" {
-			http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusForbidden)
-		} else {
-			http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusBadRequest)
-		}
+		response.Error(w, err)
 		return
 	}
 
@@ -180,25 +228,112 @@ func (h *Handler) DeleteEvent(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// CancelEvent handles POST /events/{id}/cancel
+func (h *Handler) CancelEvent(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, h.service.CancelEvent, "event cancelled successfully")
+}
+
+// PostponeEvent handles POST /events/{id}/postpone
+func (h *Handler) PostponeEvent(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, h.service.PostponeEvent, "event postponed successfully")
+}
+
+// changeStatus serves a status change of the organizer with the reason in the body
+func (h *Handler) changeStatus(w http.ResponseWriter, r *http.Request,
+	change func(ctx context.Context, eventID, organizerID int, req *StatusRequest, ifMatch string) (*Event, error), message string) {
+	userID, ok := auth.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, apperror.ErrUnauthorized)
+		return
+	}
+
+	eventID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		response.Error(w, apperror.Validation("id", "invalid event ID"))
+		return
+	}
+
+	var req StatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, apperror.Validation("body", "invalid request body"))
+		return
+	}
+
+	event, err := change(r.Context(), eventID, userID, &req, r.Header.Get("If-Match"))
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", event.ETag())
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": message,
+		"data":    event,
+	})
+}
+
+// PublishEvent handles POST /events/{id}/publish; without a body the draft is
+// published right away
+func (h *Handler) PublishEvent(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, apperror.ErrUnauthorized)
+		return
+	}
+
+	eventID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		response.Error(w, apperror.Validation("id", "invalid event ID"))
+		return
+	}
+
+	var req PublishRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		response.Error(w, apperror.Validation("body", "invalid request body"))
+		return
+	}
+
+	event, err := h.service.PublishEvent(r.Context(), eventID, userID, &req, r.Header.Get("If-Match"))
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	message := "event published successfully"
+	if event.Status == StatusDraft {
+		message = "event publication scheduled successfully"
+	}
+
+	w.Header().Set("ETag", event.ETag())
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": message,
+		"data":    event,
+	})
+}
+
 // JoinEvent handles POST /events/:id/join
 func (h *Handler) JoinEvent(w http.ResponseWriter, r *http.Request) {
 	// Get user ID from context (set by auth middleware)
 	userID, ok := auth.GetUserID(r.Context())
 	if !ok {
-		http.Error(w, `{"error": "unauthorized"}`, http.StatusUnauthorized)
+		response.Error(w, apperror.ErrUnauthorized)
 		return
 	}
 
 	idStr := r.PathValue("id")
 	eventID, err := strconv.Atoi(idStr)
 	if err != nil {
-		http.Error(w, `{"error": "invalid event ID"}`, http.StatusBadRequest)
+		response.Error(w, apperror.Validation("id", "invalid event ID"))
 		return
 	}
 
 	err = h.service.JoinEvent(r.Context(), userID, eventID)
 	if err != nil {
-		http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusBadRequest)
+		response.Error(w, err)
 		return
 	}
 
@@ -214,13 +349,13 @@ func (h *Handler) GetMyAttendingEvents(w http.ResponseWriter, r *http.Request) {
 	// Get user ID from context (set by auth middleware)
 	userID, ok := auth.GetUserID(r.Context())
 	if !ok {
-		http.Error(w, `{"error": "unauthorized"}`, http.StatusUnauthorized)
+		response.Error(w, apperror.ErrUnauthorized)
 		return
 	}
 
 	events, err := h.service.GetMyAttendingEvents(r.Context(), userID)
 	if err != nil {
-		http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusInternalServerError)
+		response.Error(w, err)
 		return
 	}
 
@@ -236,13 +371,13 @@ func (h *Handler) GetMyOrganizedEvents(w http.ResponseWriter, r *http.Request) {
 	// Get user ID from context (set by auth middleware)
 	userID, ok := auth.GetUserID(r.Context())
 	if !ok {
-		http.Error(w, `{"error": "unauthorized"}`, http.StatusUnauthorized)
+		response.Error(w, apperror.ErrUnauthorized)
 		return
 	}
 
 	events, err := h.service.GetMyOrganizedEvents(r.Context(), userID)
 	if err != nil {
-		http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusInternalServerError)
+		response.Error(w, err)
 		return
 	}
 
@@ -253,36 +388,97 @@ func (h *Handler) GetMyOrganizedEvents(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// GetTrash handles GET /events/my/trash
+func (h *Handler) GetTrash(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, apperror.ErrUnauthorized)
+		return
+	}
+
+	events, err := h.service.GetTrash(r.Context(), userID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data": events,
+	})
+}
+
+// RestoreEvent handles POST /events/my/trash/{id}/restore
+func (h *Handler) RestoreEvent(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, apperror.ErrUnauthorized)
+		return
+	}
+
+	eventID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		response.Error(w, apperror.Validation("id", "invalid event ID"))
+		return
+	}
+
+	event, err := h.service.RestoreEvent(r.Context(), eventID, userID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", event.ETag())
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "event restored successfully",
+		"data":    event,
+	})
+}
+
 // InviteUserToEvent handles POST /events/{id}/invite
 func (h *Handler) InviteUserToEvent(w http.ResponseWriter, r *http.Request) {
 	// Get user ID from context (set by auth middleware)
 	inviterID, ok := auth.GetUserID(r.Context())
 	if !ok {
-		http.Error(w, `{"error": "unauthorized"}`, http.StatusUnauthorized)
+		response.Error(w, apperror.ErrUnauthorized)
 		return
 	}
 
 	idStr := r.PathValue("id")
 	eventID, err := strconv.Atoi(idStr)
 	if err != nil {
-		http.Error(w, `{"error": "invalid event ID"}`, http.StatusBadRequest)
+		response.Error(w, apperror.Validation("id", "invalid event ID"))
 		return
 	}
 
 	var req AddAttendeeRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, `{"error": "invalid request body"}`, http.StatusBadRequest)
+		response.Error(w, apperror.Validation("body", "invalid request body"))
+		return
+	}
+
+	if req.GroupID > 0 {
+		result, err := h.service.InviteGroupToEvent(r.Context(), eventID, inviterID, &req)
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"message": "group invited to event successfully",
+			"data":    result,
+		})
 		return
 	}
 
 	err = h.service.InviteUserToEvent(r.Context(), eventID, inviterID, &req)
 	if err != nil {
-		// Check for specific authorization errors
-		if err.Error() == "only the event creator can invite users to this event" {
-			http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusForbidden)
-		} else {
-			http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusBadRequest)
-		}
+		response.Error(w, err)
 		return
 	}
 
@@ -298,26 +494,26 @@ func (h *Handler) UpdateAttendanceStatus(w http.ResponseWriter, r *http.Request)
 	// Get user ID from context (set by auth middleware)
 	userID, ok := auth.GetUserID(r.Context())
 	if !ok {
-		http.Error(w, `{"error": "unauthorized"}`, http.StatusUnauthorized)
+		response.Error(w, apperror.ErrUnauthorized)
 		return
 	}
 
 	idStr := r.PathValue("id")
 	eventID, err := strconv.Atoi(idStr)
 	if err != nil {
-		http.Error(w, `{"error": "invalid event ID"}`, http.StatusBadRequest)
+		response.Error(w, apperror.Validation("id", "invalid event ID"))
 		return
 	}
 
 	var req UpdateAttendanceRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, `{"error": "invalid request body"}`, http.StatusBadRequest)
+		response.Error(w, apperror.Validation("body", "invalid request body"))
 		return
 	}
 
 	err = h.service.UpdateAttendanceStatus(r.Context(), userID, eventID, req.Status)
 	if err != nil {
-		http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusBadRequest)
+		response.Error(w, err)
 		return
 	}
 
@@ -333,13 +529,14 @@ func (h *Handler) GetEventAttendees(w http.ResponseWriter, r *http.Request) {
 	idStr := r.PathValue("id")
 	eventID, err := strconv.Atoi(idStr)
 	if err != nil {
-		http.Error(w, `{"error": "invalid event ID"}`, http.StatusBadRequest)
+		response.Error(w, apperror.Validation("id", "invalid event ID"))
 		return
 	}
 
-	attendees, err := h.service.GetEventAttendees(r.Context(), eventID)
+	userID, _ := auth.GetUserID(r.Context())
+	attendees, err := h.service.GetEventAttendees(r.Context(), eventID, userID)
 	if err != nil {
-		http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusInternalServerError)
+		response.Error(w, err)
 		return
 	}
 
@@ -349,3 +546,184 @@ func (h *Handler) GetEventAttendees(w http.ResponseWriter, r *http.Request) {
 		"data": attendees,
 	})
 }
+
+// GetRevisions handles GET /events/{id}/revisions
+func (h *Handler) GetRevisions(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, apperror.ErrUnauthorized)
+		return
+	}
+
+	eventID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		response.Error(w, apperror.Validation("id", "invalid event ID"))
+		return
+	}
+
+	revisions, err := h.service.GetRevisions(r.Context(), eventID, userID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data": revisions,
+	})
+}
+
+// GetRevision handles GET /events/{id}/revisions/{revision}
+func (h *Handler) GetRevision(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, apperror.ErrUnauthorized)
+		return
+	}
+
+	eventID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		response.Error(w, apperror.Validation("id", "invalid event ID"))
+		return
+	}
+
+	revision, err := strconv.Atoi(r.PathValue("revision"))
+	if err != nil {
+		response.Error(w, apperror.Validation("revision", "invalid revision"))
+		return
+	}
+
+	rev, err := h.service.GetRevision(r.Context(), eventID, revision, userID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data": rev,
+	})
+}
+
+// DiffRevisions handles GET /events/{id}/revisions/diff?from=&to=
+func (h *Handler) DiffRevisions(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, apperror.ErrUnauthorized)
+		return
+	}
+
+	eventID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		response.Error(w, apperror.Validation("id", "invalid event ID"))
+		return
+	}
+
+	// Missing or invalid revisions are reported by the service
+	q := r.URL.Query()
+	from, _ := strconv.Atoi(q.Get("from"))
+	to, _ := strconv.Atoi(q.Get("to"))
+
+	diff, err := h.service.DiffRevisions(r.Context(), eventID, from, to, userID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data": diff,
+	})
+}
+
+// RestoreRevision handles POST /events/{id}/revisions/{revision}/restore
+func (h *Handler) RestoreRevision(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, apperror.ErrUnauthorized)
+		return
+	}
+
+	eventID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		response.Error(w, apperror.Validation("id", "invalid event ID"))
+		return
+	}
+
+	revision, err := strconv.Atoi(r.PathValue("revision"))
+	if err != nil {
+		response.Error(w, apperror.Validation("revision", "invalid revision"))
+		return
+	}
+
+	event, err := h.service.RestoreRevision(r.Context(), eventID, revision, userID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "revision restored successfully",
+		"data":    event,
+	})
+}
+
+// GetChanges handles GET /events/{id}/changes
+func (h *Handler) GetChanges(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, apperror.ErrUnauthorized)
+		return
+	}
+
+	eventID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		response.Error(w, apperror.Validation("id", "invalid event ID"))
+		return
+	}
+
+	changes, err := h.service.GetChanges(r.Context(), eventID, userID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data": changes,
+	})
+}
+
+// ParsePage reads the optional limit and offset query parameters; lists are
+// unpaginated when limit is omitted and larger pages are capped at MaxPageSize
+func ParsePage(q url.Values) (Page, error) {
+	var page Page
+	var invalid []apperror.FieldError
+
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 0 {
+			invalid = append(invalid, apperror.FieldError{Field: "limit", Message: "limit must be a non-negative integer"})
+		}
+		page.Limit = min(limit, MaxPageSize)
+	}
+
+	if v := q.Get("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			invalid = append(invalid, apperror.FieldError{Field: "offset", Message: "offset must be a non-negative integer"})
+		}
+		page.Offset = offset
+	}
+
+	if len(invalid) > 0 {
+		return Page{}, apperror.InvalidFields(invalid[0].Message, invalid...)
+	}
+	return page, nil
+}
@@ -3,17 +3,34 @@ package event
 import (
 	"encoding/json"
 	"time"
+
+	"event-planner/internal/user"
+)
+
+// Event visibilities
+const (
+	VisibilityPublic       = "public"       // visible to everyone
+	VisibilityOrganization = "organization" // visible to members of the event's organization
 )
 
 type Event struct {
-	ID          int       `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	Date        time.Time `json:"-"` 
-	Time        time.Time `json:"-"` 
-	Location    string    `json:"location"`
-	OrganizerID int       `json:"organizer_id"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID             int        `json:"id"`
+	Title          string     `json:"title"`
+	Description    string     `json:"description"`
+	Date           time.Time  `json:"-"` 
+	Time           time.Time  `json:"-"` 
+	Location       string     `json:"location"`
+	OrganizerID    int        `json:"organizer_id"`
+	OrganizationID *int       `json:"organization_id,omitempty"` // nil for personal events
+	Visibility     string     `json:"visibility"`                // 'public' or 'organization'
+	Timezone       string     `json:"timezone"`                  // IANA name the date and time are local to
+	CreatedAt      time.Time  `json:"created_at"`
+	ArchivedAt     *time.Time `json:"archived_at,omitempty"`   // set when the organizer deleted their account
+	Version        int        `json:"version"`                 // incremented by every change, see ETag
+	DeletedAt      *time.Time `json:"deleted_at,omitempty"`    // set while the event is in the organizer's trash
+	Status         string     `json:"status"`                  // see StatusScheduled and the other statuses
+	StatusReason   string     `json:"status_reason,omitempty"` // why the event was cancelled or postponed
+	PublishAt      *time.Time `json:"publish_at,omitempty"`    // when the status worker publishes a draft
 }
 
 //format date and time properly
@@ -31,11 +48,15 @@ func (e Event) MarshalJSON() ([]byte, error) {
 }
 
 type CreateEventRequest struct {
-	Title       string `json:"title" binding:"required"`
-	Description string `json:"description"`
-	Date        string `json:"date" binding:"required"` // YYYY-MM-DD
-	Time        string `json:"time" binding:"required"` // HH:MM:SS
-	Location    string `json:"location" binding:"required"`
+	Title       string     `json:"title" binding:"required"`
+	Description string     `json:"description"`
+	Date        string     `json:"date" binding:"required"` // YYYY-MM-DD
+	Time        string     `json:"time" binding:"required"` // HH:MM:SS
+	Location    string     `json:"location" binding:"required"`
+	Visibility  string     `json:"visibility,omitempty"` // defaults to the organization setting
+	Timezone    string     `json:"timezone,omitempty"`   // defaults to the organization setting
+	Draft       bool       `json:"draft,omitempty"`      // only the organizer and collaborators see it until it is published
+	PublishAt   *time.Time `json:"publish_at,omitempty"` // publishes the draft at this time
 }
 
 type UpdateEventRequest struct {
@@ -44,15 +65,17 @@ type UpdateEventRequest struct {
 	Date        string `json:"date"`
 	Time        string `json:"time"`
 	Location    string `json:"location"`
+	Visibility  string `json:"visibility"`
 }
 
 type EventAttendee struct {
-	ID        int       `json:"id"`
-	UserID    int       `json:"user_id"`
-	EventID   int       `json:"event_id"`
-	Role      string    `json:"role"`   // 'organizer', 'attendee', 'collaborator'
-	Status    string    `json:"status"` // 'going', 'maybe', 'not_going'
-	CreatedAt time.Time `json:"created_at"`
+	ID        int                `json:"id"`
+	UserID    int                `json:"user_id"`
+	EventID   int                `json:"event_id"`
+	Role      string             `json:"role"`   // 'organizer', 'attendee', 'collaborator'
+	Status    string             `json:"status"` // 'going', 'maybe', 'not_going'
+	CreatedAt time.Time          `json:"created_at"`
+	User      user.PublicProfile `json:"user"`
 }
 
 type EventWithAttendeeInfo struct {
@@ -61,9 +84,65 @@ type EventWithAttendeeInfo struct {
 	Status string `json:"status"`
 }
 
+// MarshalJSON formats the event like Event.MarshalJSON, which would otherwise
+// be promoted and leave out the role and status. The attendance status takes
+// the status key, so the status of the event is sent as event_status.
+func (e EventWithAttendeeInfo) MarshalJSON() ([]byte, error) {
+	type Alias Event
+	return json.Marshal(&struct {
+		Date string `json:"date"`
+		Time string `json:"time"`
+		*Alias
+		Role        string `json:"role"`
+		Status      string `json:"status"`
+		EventStatus string `json:"event_status"`
+	}{
+		Date:        e.Date.Format("2006-01-02"),
+		Time:        e.Time.Format("15:04:05"),
+		Alias:       (*Alias)(&e.Event),
+		Role:        e.Role,
+		Status:      e.Status,
+		EventStatus: e.Event.Status,
+	})
+}
+
+// TrashedEvent is a deleted event in its organizer's trash
+type TrashedEvent struct {
+	Event
+	PurgeAt time.Time `json:"purge_at"` // when the event is erased unless it is restored
+}
+
+// MarshalJSON formats the event like Event.MarshalJSON, which would otherwise
+// be promoted and leave out the purge time
+func (e TrashedEvent) MarshalJSON() ([]byte, error) {
+	type Alias Event
+	return json.Marshal(&struct {
+		Date string `json:"date"`
+		Time string `json:"time"`
+		*Alias
+		PurgeAt time.Time `json:"purge_at"`
+	}{
+		Date:    e.Date.Format("2006-01-02"),
+		Time:    e.Time.Format("15:04:05"),
+		Alias:   (*Alias)(&e.Event),
+		PurgeAt: e.PurgeAt,
+	})
+}
+
 type AddAttendeeRequest struct {
-	UserID int    `json:"user_id" binding:"required"`
-	Role   string `json:"role" binding:"required"` // 'attendee', 'collaborator', or 'organizer'
+	UserID           int    `json:"user_id"`
+	GroupID          int    `json:"group_id,omitempty"` // invite every member of a group instead of a single user
+	Role             string `json:"role" binding:"required"` // 'attendee', 'collaborator', or 'organizer'
+	InviteNewMembers bool   `json:"invite_new_members,omitempty"`
+}
+
+// MaxPageSize bounds the page size of paginated lists
+const MaxPageSize = 200
+
+// Page selects a slice of a list; a zero Limit returns every item from Offset on
+type Page struct {
+	Limit  int
+	Offset int
 }
 
 type UpdateAttendanceRequest struct {
@@ -0,0 +1,132 @@
+package event
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"event-planner/internal/apperror"
+)
+
+// PatchString is a member of a JSON merge patch (RFC 7396). Set tells a
+// member that is present from an absent one; Null is set when it is null,
+// which removes the field.
+type PatchString struct {
+	Set   bool
+	Null  bool
+	Value string
+}
+
+// UnmarshalJSON is only called for members present in the patch, including null ones
+func (p *PatchString) UnmarshalJSON(data []byte) error {
+	p.Set = true
+	if bytes.Equal(data, []byte("null")) {
+		p.Null = true
+		return nil
+	}
+	return json.Unmarshal(data, &p.Value)
+}
+
+// EventPatch is a merge patch of the editable fields of an event, as sent to
+// PATCH /events/{id}. Absent fields are kept; a null description is cleared.
+type EventPatch struct {
+	Title       PatchString `json:"title"`
+	Description PatchString `json:"description"`
+	Date        PatchString `json:"date"` // YYYY-MM-DD
+	Time        PatchString `json:"time"` // HH:MM:SS
+	Location    PatchString `json:"location"`
+	Visibility  PatchString `json:"visibility"`
+}
+
+// DecodeEventPatch reads a merge patch; it must be an object of editable fields
+func DecodeEventPatch(r io.Reader) (*EventPatch, error) {
+	var members map[string]json.RawMessage
+	if err := json.NewDecoder(r).Decode(&members); err != nil || members == nil {
+		return nil, apperror.Validation("body", "invalid request body: the merge patch must be a JSON object")
+	}
+
+	var unknown []apperror.FieldError
+	for name := range members {
+		if !slices.Contains(RevisionFields, name) {
+			unknown = append(unknown, apperror.FieldError{Field: name, Message: "field cannot be changed"})
+		}
+	}
+	if len(unknown) > 0 {
+		slices.SortFunc(unknown, func(a, b apperror.FieldError) int { return cmp.Compare(a.Field, b.Field) })
+		return nil, apperror.InvalidFields("the merge patch changes fields that cannot be changed", unknown...)
+	}
+
+	patch := &EventPatch{}
+	for name, raw := range members {
+		if err := json.Unmarshal(raw, patch.field(name)); err != nil {
+			return nil, apperror.Validationf(name, "%s must be a string or null", name)
+		}
+	}
+	return patch, nil
+}
+
+// Changes reports whether the patch sets the field
+func (p *EventPatch) Changes(field string) bool {
+	return p.field(field).Set
+}
+
+func (p *EventPatch) field(name string) *PatchString {
+	switch name {
+	case "title":
+		return &p.Title
+	case "description":
+		return &p.Description
+	case "date":
+		return &p.Date
+	case "time":
+		return &p.Time
+	case "location":
+		return &p.Location
+	case "visibility":
+		return &p.Visibility
+	}
+	panic(fmt.Sprintf("event: %q is not a field of EventPatch", name))
+}
+
+// Apply merges the patch into the event. Only the description can be removed;
+// the formats of the date and time are checked, the rest of the merged event
+// is validated by the service.
+func (p *EventPatch) Apply(e *Event) error {
+	for _, name := range RevisionFields {
+		if f := p.field(name); f.Set && f.Null && name != "description" {
+			return apperror.Validationf(name, "%s is required and cannot be removed", name)
+		}
+	}
+
+	if p.Title.Set {
+		e.Title = p.Title.Value
+	}
+	if p.Description.Set {
+		e.Description = p.Description.Value // "" when null
+	}
+	if p.Date.Set {
+		date, err := time.Parse("2006-01-02", p.Date.Value)
+		if err != nil {
+			return apperror.Validation("date", "invalid date format, use YYYY-MM-DD")
+		}
+		e.Date = date
+	}
+	if p.Time.Set {
+		t, err := time.Parse("15:04:05", p.Time.Value)
+		if err != nil {
+			return apperror.Validation("time", "invalid time format, use HH:MM:SS")
+		}
+		e.Time = t
+	}
+	if p.Location.Set {
+		e.Location = p.Location.Value
+	}
+	if p.Visibility.Set {
+		e.Visibility = p.Visibility.Value
+	}
+	return nil
+}
@@ -5,6 +5,10 @@ import (
 	"fmt"
 	"time"
 
+	"event-planner/internal/apperror"
+	"event-planner/internal/db"
+
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -18,66 +22,131 @@ func NewRepository(db *pgxpool.Pool) *Repository {
 	return &Repository{db: db}
 }
 
-// CreateEvent inserts a new event into the database
+// EventColumns is the column list selected for an event aliased as "e",
+// in the order expected by ScanEvent
+const EventColumns = `e.id, e.title, e.description, e.date, e.time, e.location, e.organizer_id, e.created_at,
+		e.organization_id, e.visibility, e.timezone, e.archived_at, e.version, e.deleted_at, e.status, e.status_reason,
+		e.publish_at`
+
+// listScopeCondition restricts event listings to the organization in $1,
+// or when $1 is NULL to personal events and public organization events
+const listScopeCondition = `(
+			($1::int IS NOT NULL AND e.organization_id = $1)
+			OR ($1::int IS NULL AND (e.organization_id IS NULL OR e.visibility = 'public'))
+		)`
+
+// LimitArg converts a page size into a LIMIT argument; NULL means no limit
+func LimitArg(limit int) *int {
+	if limit <= 0 {
+		return nil
+	}
+	return &limit
+}
+
+// ScanEvent scans a row selected with EventColumns into event, followed by any extra destinations
+func ScanEvent(row pgx.Row, event *Event, extra ...interface{}) error {
+	dest := []interface{}{
+		&event.ID,
+		&event.Title,
+		&event.Description,
+		&event.Date,
+		&event.Time,
+		&event.Location,
+		&event.OrganizerID,
+		&event.CreatedAt,
+		&event.OrganizationID,
+		&event.Visibility,
+		&event.Timezone,
+		&event.ArchivedAt,
+		&event.Version,
+		&event.DeletedAt,
+		&event.Status,
+		&event.StatusReason,
+		&event.PublishAt,
+	}
+	return row.Scan(append(dest, extra...)...)
+}
+
+// CreateEvent inserts a new event into the database with its first revision;
+// an empty status is StatusScheduled
 func (r *Repository) CreateEvent(ctx context.Context, event *Event) error {
+	if event.Status == "" {
+		event.Status = StatusScheduled
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
 	query := `
-		INSERT INTO events (title, description, date, time, location, organizer_id)
-		VALUES ($1, $2, $3, $4, $5, $6)
-		RETURNING id, created_at
+		INSERT INTO events (title, description, date, time, location, organizer_id, organization_id, visibility, timezone, status, publish_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id, created_at, version
 	`
 
-	err := r.db.QueryRow(ctx, query,
+	err = tx.QueryRow(ctx, query,
 		event.Title,
 		event.Description,
 		event.Date,
 		event.Time,
 		event.Location,
 		event.OrganizerID,
-	).Scan(&event.ID, &event.CreatedAt)
+		event.OrganizationID,
+		event.Visibility,
+		event.Timezone,
+		event.Status,
+		event.PublishAt,
+	).Scan(&event.ID, &event.CreatedAt, &event.Version)
 
 	if err != nil {
-		return fmt.Errorf("failed to create event: %w", err)
+		return db.TranslateError(fmt.Errorf("failed to create event: %w", err), nil)
+	}
+
+	if err := recordRevision(ctx, tx, event, nil, event.OrganizerID, nil); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit event: %w", err)
 	}
 
 	return nil
 }
 
-// GetEventByID retrieves a single event by ID
+// GetEventByID retrieves a single event by ID; events in the trash are not found
 func (r *Repository) GetEventByID(ctx context.Context, eventID int) (*Event, error) {
 	query := `
-		SELECT id, title, description, date, time, location, organizer_id, created_at
-		FROM events
-		WHERE id = $1
+		SELECT ` + EventColumns + `
+		FROM events e
+		WHERE e.id = $1 AND e.deleted_at IS NULL
 	`
 
 	event := &Event{}
-	err := r.db.QueryRow(ctx, query, eventID).Scan(
-		&event.ID,
-		&event.Title,
-		&event.Description,
-		&event.Date,
-		&event.Time,
-		&event.Location,
-		&event.OrganizerID,
-		&event.CreatedAt,
-	)
+	err := ScanEvent(r.db.QueryRow(ctx, query, eventID), event)
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to get event: %w", err)
+		return nil, db.TranslateError(fmt.Errorf("failed to get event: %w", err), ErrEventNotFound)
 	}
 
 	return event, nil
 }
 
-// GetAllEvents retrieves all events from the database
-func (r *Repository) GetAllEvents(ctx context.Context) ([]Event, error) {
+// GetAllEvents retrieves a page of the events of an organization, or when orgID
+// is nil the personal events plus public events of any organization; drafts
+// are left out
+func (r *Repository) GetAllEvents(ctx context.Context, orgID *int, page Page) ([]Event, error) {
+	// The id breaks ties between events on the same date so pages don't overlap
 	query := `
-		SELECT id, title, description, date, time, location, organizer_id, created_at
-		FROM events
-		ORDER BY date DESC
+		SELECT ` + EventColumns + `
+		FROM events e
+		WHERE e.deleted_at IS NULL AND e.status <> 'draft' AND ` + listScopeCondition + `
+		ORDER BY e.date DESC, e.id DESC
+		LIMIT $2 OFFSET $3
 	`
 
-	rows, err := r.db.Query(ctx, query)
+	rows, err := r.db.Query(ctx, query, orgID, LimitArg(page.Limit), page.Offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get events: %w", err)
 	}
@@ -86,16 +155,7 @@ func (r *Repository) GetAllEvents(ctx context.Context) ([]Event, error) {
 	var events []Event
 	for rows.Next() {
 		event := Event{}
-		err := rows.Scan(
-			&event.ID,
-			&event.Title,
-			&event.Description,
-			&event.Date,
-			&event.Time,
-			&event.Location,
-			&event.OrganizerID,
-			&event.CreatedAt,
-		)
+		err := ScanEvent(rows, &event)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan event: %w", err)
 		}
@@ -109,16 +169,16 @@ func (r *Repository) GetAllEvents(ctx context.Context) ([]Event, error) {
 	return events, nil
 }
 
-// GetEventsByOrganizerID retrieves all events created by a specific user
-func (r *Repository) GetEventsByOrganizerID(ctx context.Context, organizerID int) ([]Event, error) {
+// GetEventsByOrganizerID retrieves all events created by a specific user within a scope, but drafts (see GetAllEvents)
+func (r *Repository) GetEventsByOrganizerID(ctx context.Context, organizerID int, orgID *int) ([]Event, error) {
 	query := `
-		SELECT id, title, description, date, time, location, organizer_id, created_at
-		FROM events
-		WHERE organizer_id = $1
-		ORDER BY date DESC
+		SELECT ` + EventColumns + `
+		FROM events e
+		WHERE e.organizer_id = $2 AND e.deleted_at IS NULL AND e.status <> 'draft' AND ` + listScopeCondition + `
+		ORDER BY e.date DESC
 	`
 
-	rows, err := r.db.Query(ctx, query, organizerID)
+	rows, err := r.db.Query(ctx, query, orgID, organizerID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get organizer events: %w", err)
 	}
@@ -127,16 +187,7 @@ func (r *Repository) GetEventsByOrganizerID(ctx context.Context, organizerID int
 	var events []Event
 	for rows.Next() {
 		event := Event{}
-		err := rows.Scan(
-			&event.ID,
-			&event.Title,
-			&event.Description,
-			&event.Date,
-			&event.Time,
-			&event.Location,
-			&event.OrganizerID,
-			&event.CreatedAt,
-		)
+		err := ScanEvent(rows, &event)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan event: %w", err)
 		}
@@ -150,103 +201,601 @@ func (r *Repository) GetEventsByOrganizerID(ctx context.Context, organizerID int
 	return events, nil
 }
 
-// UpdateEvent updates an existing event
-func (r *Repository) UpdateEvent(ctx context.Context, eventID int, updates *UpdateEventRequest) (*Event, error) {
-	// First, get the current event
-	currentEvent, err := r.GetEventByID(ctx, eventID)
+// UpdateEvent applies the non-empty fields of updates to an event at the given
+// version (or AnyVersion) and records the revision
+func (r *Repository) UpdateEvent(ctx context.Context, eventID int, updates *UpdateEventRequest, editorID, version int) (*Event, error) {
+	return r.editEvent(ctx, eventID, editorID, version, nil, func(tx pgx.Tx, currentEvent *Event) error {
+		// Apply updates (only non-empty fields)
+		if updates.Title != "" {
+			currentEvent.Title = updates.Title
+		}
+		if updates.Description != "" {
+			currentEvent.Description = updates.Description
+		}
+		if updates.Date != "" {
+			eventDate, err := time.Parse("2006-01-02", updates.Date)
+			if err != nil {
+				return fmt.Errorf("invalid date format: %w", err)
+			}
+			currentEvent.Date = eventDate
+		}
+		if updates.Time != "" {
+			eventTime, err := time.Parse("15:04:05", updates.Time)
+			if err != nil {
+				return fmt.Errorf("invalid time format: %w", err)
+			}
+			currentEvent.Time = eventTime
+		}
+		if updates.Location != "" {
+			currentEvent.Location = updates.Location
+		}
+		if updates.Visibility != "" {
+			currentEvent.Visibility = updates.Visibility
+		}
+		return nil
+	})
+}
+
+// PatchEvent merges a patch into an event at the given version (or
+// AnyVersion) and records the revision
+func (r *Repository) PatchEvent(ctx context.Context, eventID int, patch *EventPatch, editorID, version int) (*Event, error) {
+	return r.editEvent(ctx, eventID, editorID, version, nil, func(tx pgx.Tx, currentEvent *Event) error {
+		return patch.Apply(currentEvent)
+	})
+}
+
+// RestoreRevision sets the editable fields of an event back to those of one
+// of its revisions, which is recorded as a new revision
+func (r *Repository) RestoreRevision(ctx context.Context, eventID, revision, editorID int) (*Event, error) {
+	return r.editEvent(ctx, eventID, editorID, AnyVersion, &revision, func(tx pgx.Tx, currentEvent *Event) error {
+		rev, err := getRevision(ctx, tx, eventID, revision)
+		if err != nil {
+			return err
+		}
+		rev.Apply(currentEvent)
+		return nil
+	})
+}
+
+// editEvent changes the editable fields of an event with apply, holding a lock
+// on the event, and records the revision. Edits that change nothing keep the
+// version and add no revision; those that move a postponed event schedule it
+// again.
+func (r *Repository) editEvent(ctx context.Context, eventID, editorID, version int, restoredFrom *int, apply func(tx pgx.Tx, currentEvent *Event) error) (*Event, error) {
+	tx, err := r.db.Begin(ctx)
 	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// First, get the current event; the lock orders concurrent edits
+	currentEvent, err := lockEvent(ctx, tx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if err := currentEvent.CheckVersion(version); err != nil {
+		return nil, err
+	}
+	previous := currentEvent.Snapshot()
+
+	if err := apply(tx, currentEvent); err != nil {
 		return nil, err
 	}
+	currentEvent.Reschedule(&previous)
+
+	if unchanged(currentEvent, &previous) {
+		return currentEvent, nil
+	}
+
+	if err := saveEvent(ctx, tx, currentEvent); err != nil {
+		return nil, err
+	}
+
+	if err := recordRevision(ctx, tx, currentEvent, &previous, editorID, restoredFrom); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit event: %w", err)
+	}
+
+	return currentEvent, nil
+}
+
+// GetRevisions retrieves the revisions of an event, newest first
+func (r *Repository) GetRevisions(ctx context.Context, eventID int) ([]Revision, error) {
+	query := `
+		SELECT ` + revisionColumns + `
+		FROM event_revisions
+		WHERE event_id = $1
+		ORDER BY revision DESC
+	`
+
+	rows, err := r.db.Query(ctx, query, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get revisions: %w", err)
+	}
+	defer rows.Close()
+
+	var revisions []Revision
+	for rows.Next() {
+		var rev Revision
+		if err := scanRevision(rows, &rev); err != nil {
+			return nil, fmt.Errorf("failed to scan revision: %w", err)
+		}
+		revisions = append(revisions, rev)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating revisions: %w", err)
+	}
+
+	return revisions, nil
+}
+
+// GetRevision retrieves a single revision of an event
+func (r *Repository) GetRevision(ctx context.Context, eventID, revision int) (*Revision, error) {
+	return getRevision(ctx, r.db, eventID, revision)
+}
+
+// revisionColumns is the column list selected for a revision, in the order
+// expected by scanRevision
+const revisionColumns = `event_id, revision, editor_id, changed_fields, restored_from, title, description,
+		date, time, location, visibility, created_at`
+
+func scanRevision(row pgx.Row, rev *Revision) error {
+	return row.Scan(
+		&rev.EventID,
+		&rev.Revision,
+		&rev.EditorID,
+		&rev.Changed,
+		&rev.RestoredFrom,
+		&rev.Title,
+		&rev.Description,
+		&rev.Date,
+		&rev.Time,
+		&rev.Location,
+		&rev.Visibility,
+		&rev.CreatedAt,
+	)
+}
+
+// querier runs queries on the pool or in a transaction
+type querier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+func getRevision(ctx context.Context, q querier, eventID, revision int) (*Revision, error) {
+	query := `
+		SELECT ` + revisionColumns + `
+		FROM event_revisions
+		WHERE event_id = $1 AND revision = $2
+	`
+
+	rev := &Revision{}
+	if err := scanRevision(q.QueryRow(ctx, query, eventID, revision), rev); err != nil {
+		return nil, db.TranslateError(fmt.Errorf("failed to get revision: %w", err), ErrRevisionNotFound)
+	}
+
+	return rev, nil
+}
+
+// lockEvent reads an event that is not in the trash for update
+func lockEvent(ctx context.Context, tx pgx.Tx, eventID int) (*Event, error) {
+	query := `
+		SELECT ` + EventColumns + `
+		FROM events e
+		WHERE e.id = $1 AND e.deleted_at IS NULL
+		FOR UPDATE
+	`
+
+	event := &Event{}
+	if err := ScanEvent(tx.QueryRow(ctx, query, eventID), event); err != nil {
+		return nil, db.TranslateError(fmt.Errorf("failed to get event: %w", err), ErrEventNotFound)
+	}
+
+	return event, nil
+}
+
+// unchanged reports whether the editable fields of an event are those of a revision
+func unchanged(event *Event, previous *Revision) bool {
+	current := event.Snapshot()
+	return len(previous.ChangedFields(&current)) == 0
+}
+
+// saveEvent writes the editable fields, the status and the publication time
+// of an event and increments its version
+func saveEvent(ctx context.Context, tx pgx.Tx, event *Event) error {
+	query := `
+		UPDATE events e
+		SET title = $1, description = $2, date = $3, time = $4, location = $5, visibility = $6,
+			status = $7, status_reason = $8, publish_at = $9, version = e.version + 1
+		WHERE e.id = $10
+		RETURNING ` + EventColumns + `
+	`
+
+	err := ScanEvent(tx.QueryRow(ctx, query,
+		event.Title,
+		event.Description,
+		event.Date,
+		event.Time,
+		event.Location,
+		event.Visibility,
+		event.Status,
+		event.StatusReason,
+		event.PublishAt,
+		event.ID,
+	), event)
+
+	if err != nil {
+		return db.TranslateError(fmt.Errorf("failed to update event: %w", err), ErrEventNotFound)
+	}
+
+	return nil
+}
+
+// recordRevision records the editable fields of an event as its next
+// revision. Nothing is recorded when they are those of previous; every field
+// counts as changed in the first revision (previous is nil).
+func recordRevision(ctx context.Context, tx pgx.Tx, event *Event, previous *Revision, editorID int, restoredFrom *int) error {
+	rev := event.Snapshot()
+	rev.Changed = RevisionFields
+	if previous != nil {
+		if rev.Changed = previous.ChangedFields(&rev); len(rev.Changed) == 0 {
+			return nil
+		}
+	}
+
+	var editor *int
+	if editorID > 0 {
+		editor = &editorID
+	}
+
+	query := `
+		INSERT INTO event_revisions (event_id, revision, editor_id, changed_fields, restored_from,
+			title, description, date, time, location, visibility)
+		VALUES ($1, (SELECT COALESCE(MAX(revision), 0) + 1 FROM event_revisions WHERE event_id = $1),
+			$2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+
+	_, err := tx.Exec(ctx, query,
+		event.ID,
+		editor,
+		rev.Changed,
+		restoredFrom,
+		rev.Title,
+		rev.Description,
+		rev.Date,
+		rev.Time,
+		rev.Location,
+		rev.Visibility,
+	)
+	if err != nil {
+		return db.TranslateError(fmt.Errorf("failed to record revision: %w", err), nil)
+	}
+
+	return nil
+}
+
+// TrashEvent moves an event at the given version (or AnyVersion) to its
+// organizer's trash; its attendees, invitations and revisions are kept
+func (r *Repository) TrashEvent(ctx context.Context, eventID, version int) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	currentEvent, err := lockEvent(ctx, tx, eventID)
+	if err != nil {
+		return err
+	}
+	if err := currentEvent.CheckVersion(version); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE events SET deleted_at = NOW() WHERE id = $1`, eventID); err != nil {
+		return db.TranslateError(fmt.Errorf("failed to trash event: %w", err), nil)
+	}
 
-	// Apply updates (only non-empty fields)
-	if updates.Title != "" {
-		currentEvent.Title = updates.Title
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit event deletion: %w", err)
 	}
-	if updates.Description != "" {
-		currentEvent.Description = updates.Description
+
+	return nil
+}
+
+// GetTrashedEvents retrieves the events in an organizer's trash, most recently deleted first
+func (r *Repository) GetTrashedEvents(ctx context.Context, organizerID int) ([]Event, error) {
+	query := `
+		SELECT ` + EventColumns + `
+		FROM events e
+		WHERE e.organizer_id = $1 AND e.deleted_at IS NOT NULL
+		ORDER BY e.deleted_at DESC, e.id DESC
+	`
+
+	rows, err := r.db.Query(ctx, query, organizerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trashed events: %w", err)
 	}
-	if updates.Date != "" {
-		eventDate, err := time.Parse("2006-01-02", updates.Date)
+	defer rows.Close()
+
+	var events []Event
+	for rows.Next() {
+		event := Event{}
+		err := ScanEvent(rows, &event)
 		if err != nil {
-			return nil, fmt.Errorf("invalid date format: %w", err)
+			return nil, fmt.Errorf("failed to scan trashed event: %w", err)
 		}
-		currentEvent.Date = eventDate
+		events = append(events, event)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating trashed events: %w", err)
+	}
+
+	return events, nil
+}
+
+// RestoreEvent takes an event out of its organizer's trash. Events that are
+// not in the trash of that organizer are not found.
+func (r *Repository) RestoreEvent(ctx context.Context, eventID, organizerID int) (*Event, error) {
+	query := `
+		UPDATE events e
+		SET deleted_at = NULL
+		WHERE e.id = $1 AND e.organizer_id = $2 AND e.deleted_at IS NOT NULL
+		RETURNING ` + EventColumns + `
+	`
+
+	event := &Event{}
+	if err := ScanEvent(r.db.QueryRow(ctx, query, eventID, organizerID), event); err != nil {
+		return nil, db.TranslateError(fmt.Errorf("failed to restore event: %w", err), ErrTrashedEventNotFound)
+	}
+
+	return event, nil
+}
+
+// PurgeTrashedEvents erases the events deleted before a time, cascading to
+// their attendees, invitations and revisions, and returns them
+func (r *Repository) PurgeTrashedEvents(ctx context.Context, deletedBefore time.Time) ([]Event, error) {
+	query := `
+		DELETE FROM events e
+		WHERE e.deleted_at < $1
+		RETURNING ` + EventColumns + `
+	`
+
+	rows, err := r.db.Query(ctx, query, deletedBefore)
+	if err != nil {
+		return nil, db.TranslateError(fmt.Errorf("failed to purge trashed events: %w", err), nil)
 	}
-	if updates.Time != "" {
-		eventTime, err := time.Parse("15:04:05", updates.Time)
+	defer rows.Close()
+
+	var events []Event
+	for rows.Next() {
+		event := Event{}
+		err := ScanEvent(rows, &event)
 		if err != nil {
-			return nil, fmt.Errorf("invalid time format: %w", err)
+			return nil, fmt.Errorf("failed to scan purged event: %w", err)
 		}
-		currentEvent.Time = eventTime
+		events = append(events, event)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, db.TranslateError(fmt.Errorf("error iterating purged events: %w", err), nil)
+	}
+
+	return events, nil
+}
+
+// DeleteEvent erases an event at the given version (or AnyVersion) from the
+// database at once, bypassing the trash
+func (r *Repository) DeleteEvent(ctx context.Context, eventID, version int) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	currentEvent, err := lockEvent(ctx, tx, eventID)
+	if err != nil {
+		return err
+	}
+	if err := currentEvent.CheckVersion(version); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM events WHERE id = $1`, eventID); err != nil {
+		return db.TranslateError(fmt.Errorf("failed to delete event: %w", err), nil)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit event deletion: %w", err)
+	}
+
+	return nil
+}
+
+// ChangeStatus gives an event at the given version (or AnyVersion) a new
+// status and reason, and notifies its attendees other than the actor and its
+// pending invitees in the same transaction
+func (r *Repository) ChangeStatus(ctx context.Context, eventID int, status, reason string, actorID, version int) (*Event, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	currentEvent, err := lockEvent(ctx, tx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if err := currentEvent.CheckVersion(version); err != nil {
+		return nil, err
+	}
+	if err := CheckTransition(currentEvent.Status, status); err != nil {
+		return nil, err
 	}
-	if updates.Location != "" {
-		currentEvent.Location = updates.Location
+
+	currentEvent.Status, currentEvent.StatusReason = status, reason
+	if err := saveEvent(ctx, tx, currentEvent); err != nil {
+		return nil, err
 	}
 
+	// Attendees are notified at the email of their account, invitees at the
+	// one they were invited with; UNION sends one notification per email
 	query := `
-		UPDATE events
-		SET title = $1, description = $2, date = $3, time = $4, location = $5
-		WHERE id = $6
-		RETURNING id, title, description, date, time, location, organizer_id, created_at
+		INSERT INTO notifications (recipient_email, event_id, event_status, event_title, reason)
+		SELECT recipient, $1, $2, $3, $4
+		FROM (
+			SELECT u.email AS recipient
+			FROM event_attendees ea
+			JOIN users u ON u.id = ea.user_id
+			WHERE ea.event_id = $1 AND ea.user_id <> $5 AND u.deleted_at IS NULL
+			UNION
+			SELECT i.invitee_email
+			FROM invitations i
+			WHERE i.event_id = $1 AND i.status = 'pending'
+		) recipients
+		ORDER BY recipient
 	`
+	if _, err := tx.Exec(ctx, query, eventID, status, currentEvent.Title, reason, actorID); err != nil {
+		return nil, db.TranslateError(fmt.Errorf("failed to notify attendees: %w", err), nil)
+	}
 
-	err = r.db.QueryRow(ctx, query,
-		currentEvent.Title,
-		currentEvent.Description,
-		currentEvent.Date,
-		currentEvent.Time,
-		currentEvent.Location,
-		eventID,
-	).Scan(
-		&currentEvent.ID,
-		&currentEvent.Title,
-		&currentEvent.Description,
-		&currentEvent.Date,
-		&currentEvent.Time,
-		&currentEvent.Location,
-		&currentEvent.OrganizerID,
-		&currentEvent.CreatedAt,
-	)
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit event status: %w", err)
+	}
+
+	return currentEvent, nil
+}
+
+// CompleteEvents marks the scheduled events that started before a time as
+// completed and returns them
+func (r *Repository) CompleteEvents(ctx context.Context, startedBefore time.Time) ([]Event, error) {
+	query := `
+		UPDATE events e
+		SET status = 'completed', version = e.version + 1
+		WHERE e.status = 'scheduled' AND e.deleted_at IS NULL
+			AND (e.date + e.time) AT TIME ZONE e.timezone < $1
+		RETURNING ` + EventColumns + `
+	`
 
+	rows, err := r.db.Query(ctx, query, startedBefore)
 	if err != nil {
-		return nil, fmt.Errorf("failed to update event: %w", err)
+		return nil, db.TranslateError(fmt.Errorf("failed to complete events: %w", err), nil)
+	}
+	defer rows.Close()
+
+	var events []Event
+	for rows.Next() {
+		event := Event{}
+		err := ScanEvent(rows, &event)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan completed event: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, db.TranslateError(fmt.Errorf("error iterating completed events: %w", err), nil)
+	}
+
+	return events, nil
+}
+
+// PublishEvent publishes a draft at the given version (or AnyVersion), or
+// when publishAt is not nil sets the time the status worker publishes it
+func (r *Repository) PublishEvent(ctx context.Context, eventID int, publishAt *time.Time, version int) (*Event, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	currentEvent, err := lockEvent(ctx, tx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if err := currentEvent.CheckVersion(version); err != nil {
+		return nil, err
+	}
+	if currentEvent.Status != StatusDraft {
+		return nil, ErrEventPublished
+	}
+
+	currentEvent.PublishAt = publishAt
+	if publishAt == nil {
+		currentEvent.Status = StatusScheduled
+	}
+	if err := saveEvent(ctx, tx, currentEvent); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit event publication: %w", err)
 	}
 
 	return currentEvent, nil
 }
 
-// DeleteEvent removes an event from the database
-func (r *Repository) DeleteEvent(ctx context.Context, eventID int) error {
-	query := `DELETE FROM events WHERE id = $1`
+// PublishDueEvents publishes the drafts to be published before a time and
+// returns them; drafts in the trash wait until they are restored
+func (r *Repository) PublishDueEvents(ctx context.Context, dueBefore time.Time) ([]Event, error) {
+	query := `
+		UPDATE events e
+		SET status = 'scheduled', publish_at = NULL, version = e.version + 1
+		WHERE e.status = 'draft' AND e.publish_at <= $1 AND e.deleted_at IS NULL
+		RETURNING ` + EventColumns + `
+	`
 
-	result, err := r.db.Exec(ctx, query, eventID)
+	rows, err := r.db.Query(ctx, query, dueBefore)
 	if err != nil {
-		return fmt.Errorf("failed to delete event: %w", err)
+		return nil, db.TranslateError(fmt.Errorf("failed to publish events: %w", err), nil)
 	}
+	defer rows.Close()
 
-	if result.RowsAffected() == 0 {
-		return fmt.Errorf("event not found")
+	var events []Event
+	for rows.Next() {
+		event := Event{}
+		err := ScanEvent(rows, &event)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan published event: %w", err)
+		}
+		events = append(events, event)
 	}
 
-	return nil
+	if err = rows.Err(); err != nil {
+		return nil, db.TranslateError(fmt.Errorf("error iterating published events: %w", err), nil)
+	}
+
+	return events, nil
 }
 
-// JoinEvent adds a user as an attendee to an event
+// JoinEvent adds a user as an attendee to an event; joining twice is a conflict
 func (r *Repository) JoinEvent(ctx context.Context, userID, eventID int) error {
-	return r.AddAttendee(ctx, eventID, userID, "attendee")
+	query := `
+		INSERT INTO event_attendees (user_id, event_id, role, status)
+		VALUES ($1, $2, 'attendee', 'going')
+	`
+	if _, err := r.db.Exec(ctx, query, userID, eventID); err != nil {
+		return db.TranslateError(fmt.Errorf("failed to join event: %w", err), nil)
+	}
+
+	return nil
 }
 
-// GetEventsByAttendeeID retrieves all events where the user is an attendee (including as organizer)
-func (r *Repository) GetEventsByAttendeeID(ctx context.Context, userID int) ([]EventWithAttendeeInfo, error) {
+// GetEventsByAttendeeID retrieves all events where the user is an attendee (including as organizer).
+// A non-nil orgID restricts the result to that organization's events.
+func (r *Repository) GetEventsByAttendeeID(ctx context.Context, userID int, orgID *int) ([]EventWithAttendeeInfo, error) {
 	query := `
-		SELECT e.id, e.title, e.description, e.date, e.time, e.location, e.organizer_id, e.created_at, ea.role, ea.status
+		SELECT ` + EventColumns + `, ea.role, ea.status
 		FROM events e
 		JOIN event_attendees ea ON e.id = ea.event_id
-		WHERE ea.user_id = $1
+		WHERE ea.user_id = $1 AND e.deleted_at IS NULL AND ($2::int IS NULL OR e.organization_id = $2)
 		ORDER BY e.date DESC, e.time DESC
 	`
 
-	rows, err := r.db.Query(ctx, query, userID)
+	rows, err := r.db.Query(ctx, query, userID, orgID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get attendee events: %w", err)
 	}
@@ -255,18 +804,7 @@ func (r *Repository) GetEventsByAttendeeID(ctx context.Context, userID int) ([]E
 	var events []EventWithAttendeeInfo
 	for rows.Next() {
 		event := EventWithAttendeeInfo{}
-		err := rows.Scan(
-			&event.ID,
-			&event.Title,
-			&event.Description,
-			&event.Date,
-			&event.Time,
-			&event.Location,
-			&event.OrganizerID,
-			&event.CreatedAt,
-			&event.Role,
-			&event.Status,
-		)
+		err := ScanEvent(rows, &event.Event, &event.Role, &event.Status)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan attendee event: %w", err)
 		}
@@ -280,16 +818,17 @@ func (r *Repository) GetEventsByAttendeeID(ctx context.Context, userID int) ([]E
 	return events, nil
 }
 
-// GetMyOrganizedEvents retrieves all events organized by a specific user
-func (r *Repository) GetMyOrganizedEvents(ctx context.Context, organizerID int) ([]Event, error) {
+// GetMyOrganizedEvents retrieves all events organized by a specific user.
+// A non-nil orgID restricts the result to that organization's events.
+func (r *Repository) GetMyOrganizedEvents(ctx context.Context, organizerID int, orgID *int) ([]Event, error) {
 	query := `
-		SELECT id, title, description, date, time, location, organizer_id, created_at
-		FROM events
-		WHERE organizer_id = $1
-		ORDER BY date DESC
+		SELECT ` + EventColumns + `
+		FROM events e
+		WHERE e.organizer_id = $1 AND e.deleted_at IS NULL AND ($2::int IS NULL OR e.organization_id = $2)
+		ORDER BY e.date DESC
 	`
 
-	rows, err := r.db.Query(ctx, query, organizerID)
+	rows, err := r.db.Query(ctx, query, organizerID, orgID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get organized events: %w", err)
 	}
@@ -298,16 +837,7 @@ func (r *Repository) GetMyOrganizedEvents(ctx context.Context, organizerID int)
 	var events []Event
 	for rows.Next() {
 		event := Event{}
-		err := rows.Scan(
-			&event.ID,
-			&event.Title,
-			&event.Description,
-			&event.Date,
-			&event.Time,
-			&event.Location,
-			&event.OrganizerID,
-			&event.CreatedAt,
-		)
+		err := ScanEvent(rows, &event)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan organized event: %w", err)
 		}
@@ -328,36 +858,13 @@ func (r *Repository) AddOrganizerAsAttendee(ctx context.Context, userID, eventID
 
 // AddAttendee adds a user to an event (idempotent + updates role if already exists)
 func (r *Repository) AddAttendee(ctx context.Context, eventID, userID int, role string) error {
-	// Check if attendee already exists
-	var exists bool
-	checkQuery := `
-		SELECT EXISTS(
-			SELECT 1 FROM event_attendees
-			WHERE user_id = $1 AND event_id = $2
-		)
-	`
-	if err := r.db.QueryRow(ctx, checkQuery, userID, eventID).Scan(&exists); err != nil {
-		return fmt.Errorf("failed to check existing attendee: %w", err)
-	}
-
-	if exists {
-		updateQuery := `
-			UPDATE event_attendees
-			SET role = $1
-			WHERE user_id = $2 AND event_id = $3
-		`
-		if _, err := r.db.Exec(ctx, updateQuery, role, userID, eventID); err != nil {
-			return fmt.Errorf("failed to update attendee role: %w", err)
-		}
-		return nil
-	}
-
-	insertQuery := `
+	query := `
 		INSERT INTO event_attendees (user_id, event_id, role, status)
 		VALUES ($1, $2, $3, 'going')
+		ON CONFLICT (user_id, event_id) DO UPDATE SET role = EXCLUDED.role
 	`
-	if _, err := r.db.Exec(ctx, insertQuery, userID, eventID, role); err != nil {
-		return fmt.Errorf("failed to add user to event: %w", err)
+	if _, err := r.db.Exec(ctx, query, userID, eventID, role); err != nil {
+		return db.TranslateError(fmt.Errorf("failed to add user to event: %w", err), nil)
 	}
 
 	return nil
@@ -373,11 +880,11 @@ func (r *Repository) UpdateAttendanceStatus(ctx context.Context, userID, eventID
 
 	result, err := r.db.Exec(ctx, query, status, userID, eventID)
 	if err != nil {
-		return fmt.Errorf("failed to update attendance status: %w", err)
+		return db.TranslateError(fmt.Errorf("failed to update attendance status: %w", err), nil)
 	}
 
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("attendance record not found")
+		return apperror.NotFound("attendance_not_found", "attendance record not found")
 	}
 
 	return nil
@@ -386,10 +893,20 @@ func (r *Repository) UpdateAttendanceStatus(ctx context.Context, userID, eventID
 // GetEventAttendees retrieves all attendees for an event
 func (r *Repository) GetEventAttendees(ctx context.Context, eventID int) ([]EventAttendee, error) {
 	query := `
-		SELECT id, user_id, event_id, role, status, created_at
-		FROM event_attendees
-		WHERE event_id = $1
-		ORDER BY created_at DESC
+		SELECT
+			ea.id,
+			ea.user_id,
+			ea.event_id,
+			ea.role,
+			ea.status,
+			ea.created_at,
+			COALESCE(NULLIF(p.display_name, ''), 'User ' || u.id),
+			COALESCE(p.avatar_url, '')
+		FROM event_attendees ea
+		JOIN users u ON u.id = ea.user_id
+		LEFT JOIN user_profiles p ON p.user_id = ea.user_id
+		WHERE ea.event_id = $1
+		ORDER BY ea.created_at DESC
 	`
 
 	rows, err := r.db.Query(ctx, query, eventID)
@@ -408,10 +925,13 @@ func (r *Repository) GetEventAttendees(ctx context.Context, eventID int) ([]Even
 			&attendee.Role,
 			&attendee.Status,
 			&attendee.CreatedAt,
+			&attendee.User.DisplayName,
+			&attendee.User.AvatarURL,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan attendee: %w", err)
 		}
+		attendee.User.UserID = attendee.UserID
 		attendees = append(attendees, attendee)
 	}
 
@@ -0,0 +1,153 @@
+package event
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Revision is a version of the editable fields of an event. Revision 1 is
+// recorded when the event is created, then one more on every edit that
+// changes it.
+type Revision struct {
+	EventID      int       `json:"event_id"`
+	Revision     int       `json:"revision"`
+	EditorID     *int      `json:"editor_id"`               // who made the edit; nil once their account is gone
+	Changed      []string  `json:"changed"`                 // fields that differ from the previous revision
+	RestoredFrom *int      `json:"restored_from,omitempty"` // set when the edit restored an earlier revision
+	Title        string    `json:"title"`
+	Description  string    `json:"description"`
+	Date         time.Time `json:"-"`
+	Time         time.Time `json:"-"`
+	Location     string    `json:"location"`
+	Visibility   string    `json:"visibility"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// format date and time like Event
+func (r Revision) MarshalJSON() ([]byte, error) {
+	type Alias Revision
+	return json.Marshal(&struct {
+		Date string `json:"date"`
+		Time string `json:"time"`
+		*Alias
+	}{
+		Date:  r.Date.Format("2006-01-02"),
+		Time:  r.Time.Format("15:04:05"),
+		Alias: (*Alias)(&r),
+	})
+}
+
+// FieldChange is the value of a field before and after an edit
+type FieldChange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// RevisionDiff lists the fields that differ between two revisions
+type RevisionDiff struct {
+	From    int                    `json:"from"`
+	To      int                    `json:"to"`
+	Changes map[string]FieldChange `json:"changes"`
+}
+
+// Change is an edit that moved an event, as shown to its attendees
+type Change struct {
+	Revision  int                    `json:"revision"`
+	ChangedAt time.Time              `json:"changed_at"`
+	Changes   map[string]FieldChange `json:"changes"` // date, time and location only
+	Summary   string                 `json:"summary"` // e.g. "Time moved from 18:00:00 to 19:30:00"
+}
+
+// RevisionFields are the editable fields of an event, in the order they are reported
+var RevisionFields = []string{"title", "description", "date", "time", "location", "visibility"}
+
+// movingFields are the fields whose changes attendees are shown
+var movingFields = []string{"date", "time", "location"}
+
+// Snapshot returns the editable fields of the event as a revision
+func (e *Event) Snapshot() Revision {
+	return Revision{
+		EventID:     e.ID,
+		Title:       e.Title,
+		Description: e.Description,
+		Date:        e.Date,
+		Time:        e.Time,
+		Location:    e.Location,
+		Visibility:  e.Visibility,
+	}
+}
+
+// Apply sets the editable fields of the event to those of the revision
+func (r *Revision) Apply(e *Event) {
+	e.Title = r.Title
+	e.Description = r.Description
+	e.Date = r.Date
+	e.Time = r.Time
+	e.Location = r.Location
+	e.Visibility = r.Visibility
+}
+
+// values returns the editable fields as text
+func (r *Revision) values() map[string]string {
+	return map[string]string{
+		"title":       r.Title,
+		"description": r.Description,
+		"date":        r.Date.Format("2006-01-02"),
+		"time":        r.Time.Format("15:04:05"),
+		"location":    r.Location,
+		"visibility":  r.Visibility,
+	}
+}
+
+// ChangedFields lists the fields that differ in another revision, in the
+// order of RevisionFields
+func (r *Revision) ChangedFields(to *Revision) []string {
+	from, updated := r.values(), to.values()
+
+	changed := []string{}
+	for _, field := range RevisionFields {
+		if from[field] != updated[field] {
+			changed = append(changed, field)
+		}
+	}
+	return changed
+}
+
+// Diff returns the old and new values of the fields that differ in another revision
+func (r *Revision) Diff(to *Revision) map[string]FieldChange {
+	from, updated := r.values(), to.values()
+
+	changes := map[string]FieldChange{}
+	for _, field := range r.ChangedFields(to) {
+		changes[field] = FieldChange{From: from[field], To: updated[field]}
+	}
+	return changes
+}
+
+// movingChange describes how a revision moved the event compared with the
+// previous one; nil when its date, time and location are unchanged
+func movingChange(previous, rev *Revision) *Change {
+	diff := previous.Diff(rev)
+
+	changes := map[string]FieldChange{}
+	var parts []string
+	for _, field := range movingFields {
+		if c, ok := diff[field]; ok {
+			changes[field] = c
+			parts = append(parts, fmt.Sprintf("%s moved from %s to %s", field, c.From, c.To))
+		}
+	}
+	if len(parts) == 0 {
+		return nil
+	}
+
+	summary := strings.Join(parts, "; ")
+	return &Change{
+		Revision:  rev.Revision,
+		ChangedAt: rev.CreatedAt,
+		Changes:   changes,
+		Summary:   strings.ToUpper(summary[:1]) + summary[1:],
+	}
+}
@@ -2,22 +2,76 @@ package event
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"time"
+
+	"event-planner/internal/apperror"
+	"event-planner/internal/audit"
+	"event-planner/internal/invitation"
+	"event-planner/internal/metrics"
+	"event-planner/internal/organization"
+	"event-planner/internal/tracing"
 )
 
+// Store is the event storage used by Service; Repository implements it on PostgreSQL
+type Store interface {
+	CreateEvent(ctx context.Context, event *Event) error
+	GetEventByID(ctx context.Context, eventID int) (*Event, error)
+	GetAllEvents(ctx context.Context, orgID *int, page Page) ([]Event, error)
+	GetEventsByOrganizerID(ctx context.Context, organizerID int, orgID *int) ([]Event, error)
+	UpdateEvent(ctx context.Context, eventID int, updates *UpdateEventRequest, editorID, version int) (*Event, error)
+	PatchEvent(ctx context.Context, eventID int, patch *EventPatch, editorID, version int) (*Event, error)
+	RestoreRevision(ctx context.Context, eventID, revision, editorID int) (*Event, error)
+	GetRevisions(ctx context.Context, eventID int) ([]Revision, error)
+	GetRevision(ctx context.Context, eventID, revision int) (*Revision, error)
+	TrashEvent(ctx context.Context, eventID, version int) error
+	GetTrashedEvents(ctx context.Context, organizerID int) ([]Event, error)
+	RestoreEvent(ctx context.Context, eventID, organizerID int) (*Event, error)
+	PurgeTrashedEvents(ctx context.Context, deletedBefore time.Time) ([]Event, error)
+	ChangeStatus(ctx context.Context, eventID int, status, reason string, actorID, version int) (*Event, error)
+	CompleteEvents(ctx context.Context, startedBefore time.Time) ([]Event, error)
+	PublishEvent(ctx context.Context, eventID int, publishAt *time.Time, version int) (*Event, error)
+	PublishDueEvents(ctx context.Context, dueBefore time.Time) ([]Event, error)
+	DeleteEvent(ctx context.Context, eventID, version int) error
+	JoinEvent(ctx context.Context, userID, eventID int) error
+	GetEventsByAttendeeID(ctx context.Context, userID int, orgID *int) ([]EventWithAttendeeInfo, error)
+	GetMyOrganizedEvents(ctx context.Context, organizerID int, orgID *int) ([]Event, error)
+	AddOrganizerAsAttendee(ctx context.Context, userID, eventID int) error
+	AddAttendee(ctx context.Context, eventID, userID int, role string) error
+	UpdateAttendanceStatus(ctx context.Context, userID, eventID int, status string) error
+	GetEventAttendees(ctx context.Context, eventID int) ([]EventAttendee, error)
+}
+
 // Service handles business logic for events
 type Service struct {
-	repo *Repository
+	repo           Store
+	groups         GroupInviter
+	metrics        *metrics.Metrics
+	audit          *audit.Service
+	trashRetention time.Duration
 }
 
-// NewService creates a new event service
-func NewService(repo *Repository) *Service {
-	return &Service{repo: repo}
+// GroupInviter expands a user group into individual invitations to an event
+type GroupInviter interface {
+	InviteGroupToEvent(ctx context.Context, eventID, groupID, inviterID int, role string, inviteNewMembers bool) (*invitation.GroupInvitationResult, error)
+}
+
+// NewService creates a new event service; metrics and the audit log may be
+// nil. Deleted events are purged trashRetention after they were deleted.
+func NewService(repo Store, groups GroupInviter, m *metrics.Metrics, auditLog *audit.Service, trashRetention time.Duration) *Service {
+	if trashRetention <= 0 {
+		trashRetention = DefaultTrashRetention
+	}
+
+	return &Service{repo: repo, groups: groups, metrics: m, audit: auditLog, trashRetention: trashRetention}
 }
 
 // CreateEvent validates and creates a new event
 func (s *Service) CreateEvent(ctx context.Context, req *CreateEventRequest, organizerID int) (*Event, error) {
+	ctx, span := tracing.Start(ctx, "event.Service.CreateEvent")
+	defer span.End()
+
 	// Validate required fields
 	if err := s.validateCreateRequest(req); err != nil {
 		return nil, err
@@ -28,29 +82,71 @@ func (s *Service) CreateEvent(ctx context.Context, req *CreateEventRequest, orga
 		return nil, err
 	}
 
+	// Events created in an organization scope belong to it and inherit its defaults
+	var organizationID *int
+	visibility, timezone := VisibilityPublic, "UTC"
+	if scope, ok := organization.CurrentScope(ctx); ok {
+		organizationID = &scope.OrganizationID
+		visibility, timezone = scope.DefaultVisibility, scope.Timezone
+	}
+	if req.Visibility != "" {
+		visibility = req.Visibility
+	}
+	if req.Timezone != "" {
+		timezone = req.Timezone
+	}
+
+	if err := s.validateVisibility(visibility, organizationID); err != nil {
+		return nil, err
+	}
+
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return nil, apperror.Validation("timezone", "invalid timezone: must be an IANA name such as 'Europe/Berlin'")
+	}
+
 	// Parse dates to ensure the event is in the future
-	if err := s.validateFutureEvent(req.Date, req.Time); err != nil {
+	if err := s.validateFutureEvent(req.Date, req.Time, timezone); err != nil {
 		return nil, err
 	}
 
 	// Parse date and time strings
 	eventDate, err := time.Parse("2006-01-02", req.Date)
 	if err != nil {
-		return nil, fmt.Errorf("invalid date format: %w", err)
+		return nil, apperror.Validation("date", "invalid date format, use YYYY-MM-DD")
 	}
 
 	eventTime, err := time.Parse("15:04:05", req.Time)
 	if err != nil {
-		return nil, fmt.Errorf("invalid time format: %w", err)
+		return nil, apperror.Validation("time", "invalid time format, use HH:MM:SS")
+	}
+
+	// Drafts are published by their organizer, or by the status worker at publish_at
+	status := StatusScheduled
+	if req.Draft {
+		status = StatusDraft
 	}
 
 	event := &Event{
-		Title:       req.Title,
-		Description: req.Description,
-		Date:        eventDate,
-		Time:        eventTime,
-		Location:    req.Location,
-		OrganizerID: organizerID,
+		Title:          req.Title,
+		Description:    req.Description,
+		Date:           eventDate,
+		Time:           eventTime,
+		Location:       req.Location,
+		OrganizerID:    organizerID,
+		OrganizationID: organizationID,
+		Visibility:     visibility,
+		Timezone:       timezone,
+		Status:         status,
+		PublishAt:      inUTC(req.PublishAt),
+	}
+
+	if req.PublishAt != nil {
+		if !req.Draft {
+			return nil, apperror.Validation("publish_at", "only drafts can be published later")
+		}
+		if err := validatePublishAt(*req.PublishAt, event.StartsAt()); err != nil {
+			return nil, err
+		}
 	}
 
 	if err := s.repo.CreateEvent(ctx, event); err != nil {
@@ -62,13 +158,18 @@ func (s *Service) CreateEvent(ctx context.Context, req *CreateEventRequest, orga
 		return nil, fmt.Errorf("failed to add organizer as attendee: %w", err)
 	}
 
+	s.metrics.EventCreated()
+	s.record(ctx, organizerID, audit.ActionEventCreate, audit.TargetEvent, event.ID, event.ID, nil, audit.Snapshot(event))
 	return event, nil
 }
 
-// GetEventByID retrieves an event by ID
-func (s *Service) GetEventByID(ctx context.Context, eventID int) (*Event, error) {
+// GetEventByID retrieves an event by ID for a user (0 for anonymous requests)
+func (s *Service) GetEventByID(ctx context.Context, eventID, userID int) (*Event, error) {
+	ctx, span := tracing.Start(ctx, "event.Service.GetEventByID")
+	defer span.End()
+
 	if eventID <= 0 {
-		return nil, fmt.Errorf("invalid event ID")
+		return nil, apperror.Validation("id", "invalid event ID")
 	}
 
 	event, err := s.repo.GetEventByID(ctx, eventID)
@@ -76,12 +177,19 @@ func (s *Service) GetEventByID(ctx context.Context, eventID int) (*Event, error)
 		return nil, err
 	}
 
+	if !s.canSee(ctx, event, userID) {
+		return nil, ErrEventNotFound
+	}
+
 	return event, nil
 }
 
-// GetAllEvents retrieves all events
-func (s *Service) GetAllEvents(ctx context.Context) ([]Event, error) {
-	events, err := s.repo.GetAllEvents(ctx)
+// GetAllEvents retrieves a page of the events of the current organization scope
+func (s *Service) GetAllEvents(ctx context.Context, page Page) ([]Event, error) {
+	ctx, span := tracing.Start(ctx, "event.Service.GetAllEvents")
+	defer span.End()
+
+	events, err := s.repo.GetAllEvents(ctx, organization.CurrentID(ctx), page)
 	if err != nil {
 		return nil, err
 	}
@@ -95,11 +203,14 @@ func (s *Service) GetAllEvents(ctx context.Context) ([]Event, error) {
 
 // GetEventsByOrganizerID retrieves all events created by a specific user
 func (s *Service) GetEventsByOrganizerID(ctx context.Context, organizerID int) ([]Event, error) {
+	ctx, span := tracing.Start(ctx, "event.Service.GetEventsByOrganizerID")
+	defer span.End()
+
 	if organizerID <= 0 {
-		return nil, fmt.Errorf("invalid organizer ID")
+		return nil, apperror.Validation("id", "invalid organizer ID")
 	}
 
-	events, err := s.repo.GetEventsByOrganizerID(ctx, organizerID)
+	events, err := s.repo.GetEventsByOrganizerID(ctx, organizerID, organization.CurrentID(ctx))
 	if err != nil {
 		return nil, err
 	}
@@ -111,9 +222,15 @@ func (s *Service) GetEventsByOrganizerID(ctx context.Context, organizerID int) (
 	return events, nil
 }
 
-func (s *Service) UpdateEvent(ctx context.Context, eventID int, req *UpdateEventRequest, organizerID int) (*Event, error) {
+// UpdateEvent validates and applies an update of the organizer. ifMatch is
+// the If-Match header of the request: the ETag of the version the update was
+// made from, or "*" to overwrite any version.
+func (s *Service) UpdateEvent(ctx context.Context, eventID int, req *UpdateEventRequest, organizerID int, ifMatch string) (*Event, error) {
+	ctx, span := tracing.Start(ctx, "event.Service.UpdateEvent")
+	defer span.End()
+
 	if eventID <= 0 {
-		return nil, fmt.Errorf("invalid event ID")
+		return nil, apperror.Validation("id", "invalid event ID")
 	}
 
 	// Get the event to check ownership
@@ -124,39 +241,96 @@ func (s *Service) UpdateEvent(ctx context.Context, eventID int, req *UpdateEvent
 
 	// Check if the user is the organizer
 	if event.OrganizerID != organizerID {
-		return nil, fmt.Errorf("you are not authorized to update this event")
+		return nil, apperror.Forbidden("not_event_organizer", "you are not authorized to update this event")
 	}
 
-	// Validate date and time if provided
-	if req.Date != "" && req.Time != "" {
-		if err := s.validateDateTime(req.Date, req.Time); err != nil {
-			return nil, err
-		}
-		if err := s.validateFutureEvent(req.Date, req.Time); err != nil {
-			return nil, err
-		}
-	} else if req.Date != "" {
-		if err := s.validateDateFormat(req.Date); err != nil {
-			return nil, err
-		}
-	} else if req.Time != "" {
-		if err := s.validateTimeFormat(req.Time); err != nil {
-			return nil, err
-		}
+	// Cancelled events are kept as they were called off
+	if event.Status == StatusCancelled {
+		return nil, ErrEventCancelled
 	}
 
-	updatedEvent, err := s.repo.UpdateEvent(ctx, eventID, req)
+	// A stale version fails before validation, since the client has to fetch the event again anyway
+	version, err := parseIfMatch(ifMatch)
 	if err != nil {
 		return nil, err
 	}
+	if err := event.CheckVersion(version); err != nil {
+		return nil, err
+	}
+
+	if err := s.validateUpdateRequest(req, event); err != nil {
+		return nil, err
+	}
+
+	updatedEvent, err := s.repo.UpdateEvent(ctx, eventID, req, organizerID, version)
+	if err != nil {
+		return nil, err
+	}
+
+	before, after := audit.Changes(event, updatedEvent)
+	s.record(ctx, organizerID, audit.ActionEventUpdate, audit.TargetEvent, eventID, eventID, before, after)
+
+	return updatedEvent, nil
+}
+
+// PatchEvent applies a merge patch of the organizer; ifMatch is as for
+// UpdateEvent. The event resulting from the patch is validated as a whole.
+func (s *Service) PatchEvent(ctx context.Context, eventID int, patch *EventPatch, organizerID int, ifMatch string) (*Event, error) {
+	ctx, span := tracing.Start(ctx, "event.Service.PatchEvent")
+	defer span.End()
+
+	if eventID <= 0 {
+		return nil, apperror.Validation("id", "invalid event ID")
+	}
+
+	event, err := s.repo.GetEventByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	if event.OrganizerID != organizerID {
+		return nil, apperror.Forbidden("not_event_organizer", "you are not authorized to update this event")
+	}
+
+	if event.Status == StatusCancelled {
+		return nil, ErrEventCancelled
+	}
+
+	version, err := parseIfMatch(ifMatch)
+	if err != nil {
+		return nil, err
+	}
+	if err := event.CheckVersion(version); err != nil {
+		return nil, err
+	}
+
+	merged := *event
+	if err := patch.Apply(&merged); err != nil {
+		return nil, err
+	}
+	if err := s.validateMergedEvent(&merged, event, patch.Changes("date") || patch.Changes("time")); err != nil {
+		return nil, err
+	}
+
+	updatedEvent, err := s.repo.PatchEvent(ctx, eventID, patch, organizerID, version)
+	if err != nil {
+		return nil, err
+	}
+
+	before, after := audit.Changes(event, updatedEvent)
+	s.record(ctx, organizerID, audit.ActionEventUpdate, audit.TargetEvent, eventID, eventID, before, after)
 
 	return updatedEvent, nil
 }
 
-// DeleteEvent validates and deletes an event
-func (s *Service) DeleteEvent(ctx context.Context, eventID int, organizerID int) error {
+// DeleteEvent validates and moves an event to the organizer's trash; ifMatch
+// is as for UpdateEvent
+func (s *Service) DeleteEvent(ctx context.Context, eventID int, organizerID int, ifMatch string) error {
+	ctx, span := tracing.Start(ctx, "event.Service.DeleteEvent")
+	defer span.End()
+
 	if eventID <= 0 {
-		return fmt.Errorf("invalid event ID")
+		return apperror.Validation("id", "invalid event ID")
 	}
 
 	// Get the event to check ownership
@@ -167,45 +341,335 @@ func (s *Service) DeleteEvent(ctx context.Context, eventID int, organizerID int)
 
 	// Check if the user is the organizer
 	if event.OrganizerID != organizerID {
-		return fmt.Errorf("you are not authorized to delete this event")
+		return apperror.Forbidden("not_event_organizer", "you are not authorized to delete this event")
 	}
 
-	if err := s.repo.DeleteEvent(ctx, eventID); err != nil {
+	version, err := parseIfMatch(ifMatch)
+	if err != nil {
 		return err
 	}
 
+	if err := s.repo.TrashEvent(ctx, eventID, version); err != nil {
+		return err
+	}
+
+	s.record(ctx, organizerID, audit.ActionEventDelete, audit.TargetEvent, eventID, eventID, audit.Snapshot(event), nil)
+
 	return nil
 }
 
+// ForceUpdateEvent updates any event regardless of ownership (moderation).
+// Callers must check the moderation permission.
+func (s *Service) ForceUpdateEvent(ctx context.Context, eventID int, req *UpdateEventRequest, editorID int) (*Event, *Event, error) {
+	ctx, span := tracing.Start(ctx, "event.Service.ForceUpdateEvent")
+	defer span.End()
+
+	if eventID <= 0 {
+		return nil, nil, apperror.Validation("id", "invalid event ID")
+	}
+
+	before, err := s.repo.GetEventByID(ctx, eventID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.validateUpdateRequest(req, before); err != nil {
+		return nil, nil, err
+	}
+
+	after, err := s.repo.UpdateEvent(ctx, eventID, req, editorID, AnyVersion)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return before, after, nil
+}
+
+// GetRevisions lists the revisions of an event, newest first; only its
+// organizer can see them
+func (s *Service) GetRevisions(ctx context.Context, eventID, userID int) ([]Revision, error) {
+	ctx, span := tracing.Start(ctx, "event.Service.GetRevisions")
+	defer span.End()
+
+	if _, err := s.organizedEvent(ctx, eventID, userID); err != nil {
+		return nil, err
+	}
+
+	revisions, err := s.repo.GetRevisions(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	if revisions == nil {
+		revisions = []Revision{}
+	}
+
+	return revisions, nil
+}
+
+// GetRevision retrieves a single revision of an event for its organizer
+func (s *Service) GetRevision(ctx context.Context, eventID, revision, userID int) (*Revision, error) {
+	ctx, span := tracing.Start(ctx, "event.Service.GetRevision")
+	defer span.End()
+
+	if _, err := s.organizedEvent(ctx, eventID, userID); err != nil {
+		return nil, err
+	}
+
+	if revision <= 0 {
+		return nil, apperror.Validation("revision", "invalid revision")
+	}
+
+	return s.repo.GetRevision(ctx, eventID, revision)
+}
+
+// DiffRevisions lists the fields that differ between two revisions of an event
+func (s *Service) DiffRevisions(ctx context.Context, eventID, from, to, userID int) (*RevisionDiff, error) {
+	ctx, span := tracing.Start(ctx, "event.Service.DiffRevisions")
+	defer span.End()
+
+	if _, err := s.organizedEvent(ctx, eventID, userID); err != nil {
+		return nil, err
+	}
+
+	var invalid []apperror.FieldError
+	if from <= 0 {
+		invalid = append(invalid, apperror.FieldError{Field: "from", Message: "invalid revision"})
+	}
+	if to <= 0 {
+		invalid = append(invalid, apperror.FieldError{Field: "to", Message: "invalid revision"})
+	}
+	if len(invalid) > 0 {
+		return nil, apperror.InvalidFields(invalid[0].Message, invalid...)
+	}
+
+	fromRev, err := s.repo.GetRevision(ctx, eventID, from)
+	if err != nil {
+		return nil, err
+	}
+	toRev, err := s.repo.GetRevision(ctx, eventID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RevisionDiff{From: from, To: to, Changes: fromRev.Diff(toRev)}, nil
+}
+
+// RestoreRevision sets an event back to one of its revisions. The restored
+// date and time must still be in the future.
+func (s *Service) RestoreRevision(ctx context.Context, eventID, revision, userID int) (*Event, error) {
+	ctx, span := tracing.Start(ctx, "event.Service.RestoreRevision")
+	defer span.End()
+
+	event, err := s.organizedEvent(ctx, eventID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if event.Status == StatusCancelled {
+		return nil, ErrEventCancelled
+	}
+
+	if revision <= 0 {
+		return nil, apperror.Validation("revision", "invalid revision")
+	}
+
+	rev, err := s.repo.GetRevision(ctx, eventID, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	// The revision is checked like an update setting all of its fields
+	req := &UpdateEventRequest{
+		Date:       rev.Date.Format("2006-01-02"),
+		Time:       rev.Time.Format("15:04:05"),
+		Visibility: rev.Visibility,
+	}
+	if err := s.validateUpdateRequest(req, event); err != nil {
+		return nil, err
+	}
+
+	restored, err := s.repo.RestoreRevision(ctx, eventID, revision, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	before, after := audit.Changes(event, restored)
+	s.record(ctx, userID, audit.ActionEventRestore, audit.TargetEvent, eventID, eventID, before, after)
+
+	return restored, nil
+}
+
+// GetChanges lists the edits that moved the date, time or location of an
+// event, newest first, for its attendees
+func (s *Service) GetChanges(ctx context.Context, eventID, userID int) ([]Change, error) {
+	ctx, span := tracing.Start(ctx, "event.Service.GetChanges")
+	defer span.End()
+
+	if eventID <= 0 {
+		return nil, apperror.Validation("id", "invalid event ID")
+	}
+
+	event, err := s.repo.GetEventByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !canView(ctx, event) {
+		return nil, ErrEventNotFound
+	}
+
+	if event.OrganizerID != userID && s.attendance(ctx, eventID, userID) == nil {
+		return nil, ErrNotAttendee
+	}
+
+	revisions, err := s.repo.GetRevisions(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Revisions are newest first; each is compared with the one before it
+	changes := []Change{}
+	for i := 0; i+1 < len(revisions); i++ {
+		if change := movingChange(&revisions[i+1], &revisions[i]); change != nil {
+			changes = append(changes, *change)
+		}
+	}
+
+	return changes, nil
+}
+
+// organizedEvent retrieves an event and checks that the user organizes it
+func (s *Service) organizedEvent(ctx context.Context, eventID, userID int) (*Event, error) {
+	if eventID <= 0 {
+		return nil, apperror.Validation("id", "invalid event ID")
+	}
+
+	event, err := s.repo.GetEventByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	if event.OrganizerID != userID {
+		return nil, apperror.Forbidden("not_event_organizer", "only the event organizer can see and restore its revisions")
+	}
+
+	return event, nil
+}
+
+// ForceDeleteEvent erases any event regardless of ownership (moderation),
+// bypassing the trash, and returns the deleted event. Callers must check the
+// moderation permission.
+func (s *Service) ForceDeleteEvent(ctx context.Context, eventID int) (*Event, error) {
+	ctx, span := tracing.Start(ctx, "event.Service.ForceDeleteEvent")
+	defer span.End()
+
+	if eventID <= 0 {
+		return nil, apperror.Validation("id", "invalid event ID")
+	}
+
+	event, err := s.repo.GetEventByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.DeleteEvent(ctx, eventID, AnyVersion); err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
 
 func (s *Service) validateCreateRequest(req *CreateEventRequest) error {
 	if req.Title == "" {
-		return fmt.Errorf("event title is required")
+		return apperror.Validation("title", "event title is required")
 	}
 
 	if req.Date == "" {
-		return fmt.Errorf("event date is required")
+		return apperror.Validation("date", "event date is required")
 	}
 
 	if req.Time == "" {
-		return fmt.Errorf("event time is required")
+		return apperror.Validation("time", "event time is required")
 	}
 
 	if req.Location == "" {
-		return fmt.Errorf("event location is required")
+		return apperror.Validation("location", "event location is required")
 	}
 
 	if len(req.Title) > 255 {
-		return fmt.Errorf("event title must not exceed 255 characters")
+		return apperror.Validation("title", "event title must not exceed 255 characters")
 	}
 
 	if len(req.Description) > 1000 {
-		return fmt.Errorf("event description must not exceed 1000 characters")
+		return apperror.Validation("description", "event description must not exceed 1000 characters")
+	}
+
+	return nil
+}
+
+// validateUpdateRequest validates the date, time and visibility of an update if provided
+func (s *Service) validateUpdateRequest(req *UpdateEventRequest, current *Event) error {
+	if req.Visibility != "" {
+		if err := s.validateVisibility(req.Visibility, current.OrganizationID); err != nil {
+			return err
+		}
+	}
+
+	if req.Date != "" {
+		if err := s.validateDateFormat(req.Date); err != nil {
+			return err
+		}
+	}
+	if req.Time != "" {
+		if err := s.validateTimeFormat(req.Time); err != nil {
+			return err
+		}
+	}
+
+	// A new date or time alone moves the event too, so check the start it results in
+	if req.Date != "" || req.Time != "" {
+		date, timeStr := current.Date.Format("2006-01-02"), current.Time.Format("15:04:05")
+		if req.Date != "" {
+			date = req.Date
+		}
+		if req.Time != "" {
+			timeStr = req.Time
+		}
+		if err := s.validateFutureEvent(date, timeStr, current.Timezone); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
+// validateMergedEvent validates an event as a change would leave it, with the
+// rules for new events. The start only has to be in the future when the
+// change moves it, so past events can still be edited otherwise.
+func (s *Service) validateMergedEvent(merged, current *Event, moved bool) error {
+	req := &CreateEventRequest{
+		Title:       merged.Title,
+		Description: merged.Description,
+		Date:        merged.Date.Format("2006-01-02"),
+		Time:        merged.Time.Format("15:04:05"),
+		Location:    merged.Location,
+	}
+	if err := s.validateCreateRequest(req); err != nil {
+		return err
+	}
+
+	if err := s.validateVisibility(merged.Visibility, current.OrganizationID); err != nil {
+		return err
+	}
+
+	if moved {
+		return s.validateFutureEvent(req.Date, req.Time, current.Timezone)
+	}
+	return nil
+}
+
 func (s *Service) validateDateTime(date, timeStr string) error {
 	if err := s.validateDateFormat(date); err != nil {
 		return err
@@ -221,7 +685,7 @@ func (s *Service) validateDateTime(date, timeStr string) error {
 func (s *Service) validateDateFormat(date string) error {
 	_, err := time.Parse("2006-01-02", date)
 	if err != nil {
-		return fmt.Errorf("invalid date format, use YYYY-MM-DD")
+		return apperror.Validation("date", "invalid date format, use YYYY-MM-DD")
 	}
 	return nil
 }
@@ -229,54 +693,123 @@ func (s *Service) validateDateFormat(date string) error {
 func (s *Service) validateTimeFormat(timeStr string) error {
 	_, err := time.Parse("15:04:05", timeStr)
 	if err != nil {
-		return fmt.Errorf("invalid time format, use HH:MM:SS")
+		return apperror.Validation("time", "invalid time format, use HH:MM:SS")
 	}
 	return nil
 }
 
-func (s *Service) validateFutureEvent(date, timeStr string) error {
-	eventDateTime, err := time.Parse("2006-01-02 15:04:05", date+" "+timeStr)
+func (s *Service) validateFutureEvent(date, timeStr, timezone string) error {
+	loc, err := time.LoadLocation(timezone)
 	if err != nil {
-		return fmt.Errorf("failed to parse event date and time")
+		loc = time.UTC
+	}
+
+	eventDateTime, err := time.ParseInLocation("2006-01-02 15:04:05", date+" "+timeStr, loc)
+	if err != nil {
+		return apperror.Validation("date", "failed to parse event date and time")
 	}
 
 	if eventDateTime.Before(time.Now()) {
-		return fmt.Errorf("event date and time must be in the future")
+		return apperror.Validation("date", "event date and time must be in the future")
 	}
 
 	return nil
 }
 
+func (s *Service) validateVisibility(visibility string, organizationID *int) error {
+	switch visibility {
+	case VisibilityPublic:
+		return nil
+	case VisibilityOrganization:
+		if organizationID == nil {
+			return apperror.Validation("visibility", "only organization events can have 'organization' visibility")
+		}
+		return nil
+	}
+
+	return apperror.Validation("visibility", "invalid visibility: must be 'public' or 'organization'")
+}
+
+// canView reports whether the event is visible in the organization scope of ctx
+func canView(ctx context.Context, event *Event) bool {
+	if event.Visibility != VisibilityOrganization || event.OrganizationID == nil {
+		return true
+	}
+
+	orgID := organization.CurrentID(ctx)
+	return orgID != nil && *orgID == *event.OrganizationID
+}
+
+// canSee reports whether a user (0 for anonymous requests) can see the event:
+// it must be visible in the organization scope of ctx, and drafts only to
+// their organizer and collaborators
+func (s *Service) canSee(ctx context.Context, event *Event, userID int) bool {
+	if !canView(ctx, event) {
+		return false
+	}
+
+	if event.Status != StatusDraft || userID == event.OrganizerID {
+		return true
+	}
+
+	if userID <= 0 {
+		return false
+	}
+	a := s.attendance(ctx, event.ID, userID)
+	return a != nil && a.Role != "attendee"
+}
+
 // JoinEvent allows a user to join an event as an attendee
 func (s *Service) JoinEvent(ctx context.Context, userID, eventID int) error {
+	ctx, span := tracing.Start(ctx, "event.Service.JoinEvent")
+	defer span.End()
+
 	if userID <= 0 {
-		return fmt.Errorf("invalid user ID")
+		return apperror.Validation("user_id", "invalid user ID")
 	}
 
 	if eventID <= 0 {
-		return fmt.Errorf("invalid event ID")
+		return apperror.Validation("id", "invalid event ID")
 	}
 
 	// Check if event exists
-	_, err := s.repo.GetEventByID(ctx, eventID)
+	event, err := s.repo.GetEventByID(ctx, eventID)
 	if err != nil {
-		return fmt.Errorf("event not found")
+		return err
+	}
+
+	if !s.canSee(ctx, event, userID) {
+		return ErrEventNotFound
+	}
+
+	if event.ArchivedAt != nil {
+		return ErrEventArchived
+	}
+
+	if err := event.CheckOpen(); err != nil {
+		return err
 	}
 
 	if err := s.repo.JoinEvent(ctx, userID, eventID); err != nil {
 		return err
 	}
 
+	s.metrics.EventJoined()
+	s.record(ctx, userID, audit.ActionAttendeeAdd, audit.TargetAttendee, userID, eventID,
+		nil, audit.Snapshot(attendance{Role: "attendee", Status: "going"}))
 	return nil
 }
 
 // GetMyAttendingEvents retrieves all events where the user is an attendee 
 func (s *Service) GetMyAttendingEvents(ctx context.Context, userID int) ([]EventWithAttendeeInfo, error) {
+	ctx, span := tracing.Start(ctx, "event.Service.GetMyAttendingEvents")
+	defer span.End()
+
 	if userID <= 0 {
-		return nil, fmt.Errorf("invalid user ID")
+		return nil, apperror.Validation("user_id", "invalid user ID")
 	}
 
-	events, err := s.repo.GetEventsByAttendeeID(ctx, userID)
+	events, err := s.repo.GetEventsByAttendeeID(ctx, userID, organization.CurrentID(ctx))
 	if err != nil {
 		return nil, err
 	}
@@ -291,52 +824,108 @@ func (s *Service) GetMyAttendingEvents(ctx context.Context, userID int) ([]Event
 
 // InviteUserToEvent invites a user to an event 
 func (s *Service) InviteUserToEvent(ctx context.Context, eventID, inviterID int, req *AddAttendeeRequest) error {
+	ctx, span := tracing.Start(ctx, "event.Service.InviteUserToEvent")
+	defer span.End()
+
 	if eventID <= 0 {
-		return fmt.Errorf("invalid event ID")
+		return apperror.Validation("id", "invalid event ID")
 	}
 
 	if inviterID <= 0 {
-		return fmt.Errorf("invalid inviter ID")
+		return apperror.Validation("inviter_id", "invalid inviter ID")
 	}
 
 	if req.UserID <= 0 {
-		return fmt.Errorf("invalid user ID")
+		return apperror.Validation("user_id", "invalid user ID")
 	}
 
 	// Validate role 
 	if req.Role != "attendee" && req.Role != "collaborator" && req.Role != "organizer" {
-		return fmt.Errorf("invalid role: must be 'attendee', 'collaborator', or 'organizer'")
+		return apperror.Validation("role", "invalid role: must be 'attendee', 'collaborator', or 'organizer'")
 	}
 
 	// Check if event exists and get event details
 	event, err := s.repo.GetEventByID(ctx, eventID)
 	if err != nil {
-		return fmt.Errorf("event not found")
+		return err
 	}
 
 	if event.OrganizerID != inviterID {
-		return fmt.Errorf("only the event creator can invite users to this event")
+		return ErrNotEventCreator
+	}
+
+	if err := event.CheckInvite(req.Role); err != nil {
+		return err
 	}
 
 	if req.UserID == inviterID {
-		return fmt.Errorf("you cannot invite yourself to the event")
+		return apperror.Validation("user_id", "you cannot invite yourself to the event")
 	}
 
+	current := s.attendance(ctx, eventID, req.UserID)
+
 	if err := s.repo.AddAttendee(ctx, eventID, req.UserID, req.Role); err != nil {
 		return err
 	}
 
+	// Attendees already at the event keep their status and get the new role
+	updated := attendance{Role: req.Role, Status: "going"}
+	var before, after json.RawMessage
+	if current != nil {
+		updated.Status = current.Status
+		before, after = audit.Changes(current, updated)
+	} else {
+		after = audit.Snapshot(updated)
+	}
+	s.record(ctx, inviterID, audit.ActionAttendeeAdd, audit.TargetAttendee, req.UserID, eventID, before, after)
+
 	return nil
 }
 
+// InviteGroupToEvent invites every member of one of the organizer's groups to an event
+func (s *Service) InviteGroupToEvent(ctx context.Context, eventID, inviterID int, req *AddAttendeeRequest) (*invitation.GroupInvitationResult, error) {
+	ctx, span := tracing.Start(ctx, "event.Service.InviteGroupToEvent")
+	defer span.End()
+
+	if eventID <= 0 {
+		return nil, apperror.Validation("id", "invalid event ID")
+	}
+
+	if req.GroupID <= 0 {
+		return nil, apperror.Validation("group_id", "invalid group ID")
+	}
+
+	if req.Role != "attendee" && req.Role != "collaborator" && req.Role != "organizer" {
+		return nil, apperror.Validation("role", "invalid role: must be 'attendee', 'collaborator', or 'organizer'")
+	}
+
+	event, err := s.repo.GetEventByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	if event.OrganizerID != inviterID {
+		return nil, ErrNotEventCreator
+	}
+
+	if err := event.CheckInvite(req.Role); err != nil {
+		return nil, err
+	}
+
+	return s.groups.InviteGroupToEvent(ctx, eventID, req.GroupID, inviterID, req.Role, req.InviteNewMembers)
+}
+
 // UpdateAttendanceStatus updates a user's attendance status for an event
 func (s *Service) UpdateAttendanceStatus(ctx context.Context, userID, eventID int, status string) error {
+	ctx, span := tracing.Start(ctx, "event.Service.UpdateAttendanceStatus")
+	defer span.End()
+
 	if userID <= 0 {
-		return fmt.Errorf("invalid user ID")
+		return apperror.Validation("user_id", "invalid user ID")
 	}
 
 	if eventID <= 0 {
-		return fmt.Errorf("invalid event ID")
+		return apperror.Validation("id", "invalid event ID")
 	}
 
 	// Validate status
@@ -349,20 +938,58 @@ func (s *Service) UpdateAttendanceStatus(ctx context.Context, userID, eventID in
 		}
 	}
 	if !isValid {
-		return fmt.Errorf("invalid status: must be 'going', 'maybe', or 'not_going'")
+		return apperror.Validation("status", "invalid status: must be 'going', 'maybe', or 'not_going'")
 	}
 
+	// Attendees can always back out, but not commit to an event they could not join
+	if status != "not_going" {
+		event, err := s.repo.GetEventByID(ctx, eventID)
+		if err != nil {
+			return err
+		}
+
+		if event.ArchivedAt != nil {
+			return ErrEventArchived
+		}
+
+		if err := event.CheckOpen(); err != nil {
+			return err
+		}
+	}
+
+	current := s.attendance(ctx, eventID, userID)
+
 	if err := s.repo.UpdateAttendanceStatus(ctx, userID, eventID, status); err != nil {
 		return err
 	}
 
+	if current != nil {
+		before, after := audit.Changes(current, attendance{Role: current.Role, Status: status})
+		s.record(ctx, userID, audit.ActionAttendeeStatus, audit.TargetAttendee, userID, eventID, before, after)
+	}
+
 	return nil
 }
 
-// GetEventAttendees retrieves all attendees for an event
-func (s *Service) GetEventAttendees(ctx context.Context, eventID int) ([]EventAttendee, error) {
+// GetEventAttendees retrieves all attendees for an event the user (0 for
+// anonymous requests) can see
+func (s *Service) GetEventAttendees(ctx context.Context, eventID, userID int) ([]EventAttendee, error) {
+	ctx, span := tracing.Start(ctx, "event.Service.GetEventAttendees")
+	defer span.End()
+
 	if eventID <= 0 {
-		return nil, fmt.Errorf("invalid event ID")
+		return nil, apperror.Validation("id", "invalid event ID")
+	}
+
+	// Fails for events in the trash too
+	event, err := s.repo.GetEventByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Drafts and events of other organizations are hidden as by GetEventByID
+	if !s.canSee(ctx, event, userID) {
+		return nil, ErrEventNotFound
 	}
 
 	attendees, err := s.repo.GetEventAttendees(ctx, eventID)
@@ -378,11 +1005,14 @@ func (s *Service) GetEventAttendees(ctx context.Context, eventID int) ([]EventAt
 }
 
 func (s *Service) GetMyOrganizedEvents(ctx context.Context, organizerID int) ([]Event, error) {
+	ctx, span := tracing.Start(ctx, "event.Service.GetMyOrganizedEvents")
+	defer span.End()
+
 	if organizerID <= 0 {
-		return nil, fmt.Errorf("invalid organizer ID")
+		return nil, apperror.Validation("id", "invalid organizer ID")
 	}
 
-	events, err := s.repo.GetMyOrganizedEvents(ctx, organizerID)
+	events, err := s.repo.GetMyOrganizedEvents(ctx, organizerID, organization.CurrentID(ctx))
 	if err != nil {
 		return nil, err
 	}
@@ -394,3 +1024,37 @@ func (s *Service) GetMyOrganizedEvents(ctx context.Context, organizerID int) ([]
 	return events, nil
 }
 
+
+// attendance is the role and status of an attendee as recorded in the audit log
+type attendance struct {
+	Role   string `json:"role"`
+	Status string `json:"status"`
+}
+
+// attendance looks up the attendance of a user at an event before it
+// changes; nil when the user does not attend or it cannot be read
+func (s *Service) attendance(ctx context.Context, eventID, userID int) *attendance {
+	attendees, err := s.repo.GetEventAttendees(ctx, eventID)
+	if err != nil {
+		return nil
+	}
+	for _, a := range attendees {
+		if a.UserID == userID {
+			return &attendance{Role: a.Role, Status: a.Status}
+		}
+	}
+	return nil
+}
+
+// record appends an action on an event to the audit log
+func (s *Service) record(ctx context.Context, actorID int, action, targetType string, targetID, eventID int, before, after json.RawMessage) {
+	s.audit.Record(ctx, audit.Entry{
+		ActorID:    audit.Actor(actorID),
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		EventID:    &eventID,
+		Before:     before,
+		After:      after,
+	})
+}
@@ -0,0 +1,289 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"event-planner/internal/apperror"
+	"event-planner/internal/audit"
+	"event-planner/internal/logging"
+	"event-planner/internal/tracing"
+)
+
+// Event statuses
+const (
+	StatusDraft     = "draft"     // only visible to its organizer and collaborators until published
+	StatusScheduled = "scheduled" // the status of new events
+	StatusPostponed = "postponed" // put off until its organizer sets a new date or time
+	StatusCancelled = "cancelled"
+	StatusCompleted = "completed" // set by the status worker once the event has started
+)
+
+// StatusRequest is the request payload for cancelling or postponing an event
+type StatusRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// PublishRequest is the request payload for publishing a draft; a PublishAt
+// schedules the publication instead
+type PublishRequest struct {
+	PublishAt *time.Time `json:"publish_at"`
+}
+
+// transitions lists, per status the organizer can give an event, the
+// statuses it can be given from
+var transitions = map[string][]string{
+	StatusCancelled: {StatusScheduled, StatusPostponed},
+	StatusPostponed: {StatusScheduled},
+}
+
+// CheckTransition fails unless an event with status from can be given status to
+func CheckTransition(from, to string) error {
+	for _, allowed := range transitions[to] {
+		if from == allowed {
+			return nil
+		}
+	}
+	return apperror.Conflict("invalid_status_transition", fmt.Sprintf("a %s event cannot be %s", from, to))
+}
+
+// CheckOpen fails for events that can't be joined or invited to: drafts, and
+// events that can no longer be
+func (e *Event) CheckOpen() error {
+	switch e.Status {
+	case StatusDraft:
+		return ErrEventDraft
+	case StatusCancelled:
+		return ErrEventCancelled
+	case StatusCompleted:
+		return ErrEventCompleted
+	}
+	return nil
+}
+
+// CheckInvite fails for events that can't be invited to with a role; drafts
+// are open to collaborators and organizers, so that they can review them
+func (e *Event) CheckInvite(role string) error {
+	if e.Status == StatusDraft && role != "attendee" {
+		return nil
+	}
+	return e.CheckOpen()
+}
+
+// Reschedule schedules a postponed event again when an edit gave it a new
+// date or time; previous is the event before the edit
+func (e *Event) Reschedule(previous *Revision) {
+	if e.Status != StatusPostponed || (e.Date.Equal(previous.Date) && e.Time.Equal(previous.Time)) {
+		return
+	}
+	e.Status = StatusScheduled
+	e.StatusReason = ""
+}
+
+// StartsAt returns the start of the event in its timezone
+func (e *Event) StartsAt() time.Time {
+	loc, err := time.LoadLocation(e.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	return time.Date(e.Date.Year(), e.Date.Month(), e.Date.Day(),
+		e.Time.Hour(), e.Time.Minute(), e.Time.Second(), 0, loc)
+}
+
+// CancelEvent calls an event off; ifMatch is as for UpdateEvent. The event
+// stays listed as cancelled, and its attendees and pending invitees are notified.
+func (s *Service) CancelEvent(ctx context.Context, eventID, organizerID int, req *StatusRequest, ifMatch string) (*Event, error) {
+	ctx, span := tracing.Start(ctx, "event.Service.CancelEvent")
+	defer span.End()
+
+	return s.changeStatus(ctx, eventID, organizerID, StatusCancelled, req, ifMatch, audit.ActionEventCancel)
+}
+
+// PostponeEvent puts an event off until its organizer gives it a new date or
+// time; ifMatch is as for UpdateEvent. Its attendees and pending invitees are
+// notified.
+func (s *Service) PostponeEvent(ctx context.Context, eventID, organizerID int, req *StatusRequest, ifMatch string) (*Event, error) {
+	ctx, span := tracing.Start(ctx, "event.Service.PostponeEvent")
+	defer span.End()
+
+	return s.changeStatus(ctx, eventID, organizerID, StatusPostponed, req, ifMatch, audit.ActionEventPostpone)
+}
+
+// changeStatus gives an event of the organizer a new status with the reason of the request
+func (s *Service) changeStatus(ctx context.Context, eventID, organizerID int, status string, req *StatusRequest, ifMatch, action string) (*Event, error) {
+	if eventID <= 0 {
+		return nil, apperror.Validation("id", "invalid event ID")
+	}
+
+	event, err := s.repo.GetEventByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	if event.OrganizerID != organizerID {
+		return nil, apperror.Forbidden("not_event_organizer", "only the event organizer can change its status")
+	}
+
+	version, err := parseIfMatch(ifMatch)
+	if err != nil {
+		return nil, err
+	}
+	if err := event.CheckVersion(version); err != nil {
+		return nil, err
+	}
+
+	if req.Reason == "" {
+		return nil, apperror.Validation("reason", "a reason is required")
+	}
+	if len(req.Reason) > 1000 {
+		return nil, apperror.Validation("reason", "reason must not exceed 1000 characters")
+	}
+
+	if err := CheckTransition(event.Status, status); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.repo.ChangeStatus(ctx, eventID, status, req.Reason, organizerID, version)
+	if err != nil {
+		return nil, err
+	}
+
+	before, after := audit.Changes(event, updated)
+	s.record(ctx, organizerID, action, audit.TargetEvent, eventID, eventID, before, after)
+
+	return updated, nil
+}
+
+// PublishEvent publishes a draft of the organizer, or schedules the status
+// worker to publish it at the PublishAt of the request; ifMatch is as for
+// UpdateEvent
+func (s *Service) PublishEvent(ctx context.Context, eventID, organizerID int, req *PublishRequest, ifMatch string) (*Event, error) {
+	ctx, span := tracing.Start(ctx, "event.Service.PublishEvent")
+	defer span.End()
+
+	if eventID <= 0 {
+		return nil, apperror.Validation("id", "invalid event ID")
+	}
+
+	event, err := s.repo.GetEventByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	if event.OrganizerID != organizerID {
+		return nil, apperror.Forbidden("not_event_organizer", "only the event organizer can publish it")
+	}
+
+	version, err := parseIfMatch(ifMatch)
+	if err != nil {
+		return nil, err
+	}
+	if err := event.CheckVersion(version); err != nil {
+		return nil, err
+	}
+
+	if event.Status != StatusDraft {
+		return nil, ErrEventPublished
+	}
+
+	if req.PublishAt != nil {
+		if err := validatePublishAt(*req.PublishAt, event.StartsAt()); err != nil {
+			return nil, err
+		}
+	}
+
+	updated, err := s.repo.PublishEvent(ctx, eventID, inUTC(req.PublishAt), version)
+	if err != nil {
+		return nil, err
+	}
+
+	before, after := audit.Changes(event, updated)
+	s.record(ctx, organizerID, audit.ActionEventPublish, audit.TargetEvent, eventID, eventID, before, after)
+
+	return updated, nil
+}
+
+// PublishDueEvents publishes the drafts whose publication time has come
+func (s *Service) PublishDueEvents(ctx context.Context) (int, error) {
+	ctx, span := tracing.Start(ctx, "event.Service.PublishDueEvents")
+	defer span.End()
+
+	published, err := s.repo.PublishDueEvents(ctx, time.Now().UTC())
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range published {
+		event := &published[i]
+		s.record(ctx, 0, audit.ActionEventPublish, audit.TargetEvent, event.ID, event.ID,
+			audit.Snapshot(map[string]string{"status": StatusDraft}), audit.Snapshot(map[string]string{"status": event.Status}))
+		slog.InfoContext(ctx, "published event", "event_id", event.ID, "organizer_id", event.OrganizerID)
+	}
+
+	return len(published), nil
+}
+
+// validatePublishAt checks that a draft is published later, but before it starts
+func validatePublishAt(publishAt, startsAt time.Time) error {
+	if !publishAt.After(time.Now()) {
+		return apperror.Validation("publish_at", "publish_at must be in the future")
+	}
+	if !publishAt.Before(startsAt) {
+		return apperror.Validation("publish_at", "publish_at must be before the event starts")
+	}
+	return nil
+}
+
+// inUTC returns a time that may be nil in UTC, as TIMESTAMP columns only keep
+// the wall clock of a time
+func inUTC(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
+
+// CompleteEvents marks the scheduled events that have started as completed
+func (s *Service) CompleteEvents(ctx context.Context) (int, error) {
+	ctx, span := tracing.Start(ctx, "event.Service.CompleteEvents")
+	defer span.End()
+
+	completed, err := s.repo.CompleteEvents(ctx, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	if len(completed) > 0 {
+		slog.InfoContext(ctx, "completed events", "count", len(completed))
+	}
+
+	return len(completed), nil
+}
+
+// RunStatusWorker updates the statuses that change with time every interval
+// until ctx is cancelled: it publishes the drafts that are due and completes
+// the events that have started
+func (s *Service) RunStatusWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		// A started pass is finished even when ctx is cancelled meanwhile
+		passCtx := context.WithoutCancel(ctx)
+		if _, err := s.PublishDueEvents(passCtx); err != nil {
+			slog.ErrorContext(ctx, "event status worker failed to publish drafts", logging.Err(err))
+		}
+		if _, err := s.CompleteEvents(passCtx); err != nil {
+			slog.ErrorContext(ctx, "event status worker failed to complete events", logging.Err(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package event
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"event-planner/internal/apperror"
+	"event-planner/internal/audit"
+	"event-planner/internal/logging"
+	"event-planner/internal/tracing"
+)
+
+// DefaultTrashRetention is how long a deleted event can still be restored
+const DefaultTrashRetention = 30 * 24 * time.Hour
+
+// GetTrash lists the events in the organizer's trash, most recently deleted
+// first, with the time each of them is purged
+func (s *Service) GetTrash(ctx context.Context, organizerID int) ([]TrashedEvent, error) {
+	ctx, span := tracing.Start(ctx, "event.Service.GetTrash")
+	defer span.End()
+
+	if organizerID <= 0 {
+		return nil, apperror.Validation("id", "invalid organizer ID")
+	}
+
+	events, err := s.repo.GetTrashedEvents(ctx, organizerID)
+	if err != nil {
+		return nil, err
+	}
+
+	trash := make([]TrashedEvent, 0, len(events))
+	for _, event := range events {
+		trash = append(trash, TrashedEvent{Event: event, PurgeAt: event.DeletedAt.Add(s.trashRetention)})
+	}
+
+	return trash, nil
+}
+
+// RestoreEvent takes an event out of the organizer's trash, with its
+// attendees and invitations as they were when it was deleted
+func (s *Service) RestoreEvent(ctx context.Context, eventID, organizerID int) (*Event, error) {
+	ctx, span := tracing.Start(ctx, "event.Service.RestoreEvent")
+	defer span.End()
+
+	if eventID <= 0 {
+		return nil, apperror.Validation("id", "invalid event ID")
+	}
+
+	event, err := s.repo.RestoreEvent(ctx, eventID, organizerID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.record(ctx, organizerID, audit.ActionEventUndelete, audit.TargetEvent, eventID, eventID, nil, audit.Snapshot(event))
+
+	return event, nil
+}
+
+// PurgeTrash erases the events that have been in the trash for longer than
+// the retention period
+func (s *Service) PurgeTrash(ctx context.Context) (int, error) {
+	ctx, span := tracing.Start(ctx, "event.Service.PurgeTrash")
+	defer span.End()
+
+	purged, err := s.repo.PurgeTrashedEvents(ctx, time.Now().UTC().Add(-s.trashRetention))
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range purged {
+		event := &purged[i]
+		s.record(ctx, 0, audit.ActionEventPurge, audit.TargetEvent, event.ID, event.ID, audit.Snapshot(event), nil)
+		slog.InfoContext(ctx, "purged event", "event_id", event.ID, "organizer_id", event.OrganizerID)
+	}
+
+	return len(purged), nil
+}
+
+// RunPurgeWorker purges the trash every interval until ctx is cancelled
+func (s *Service) RunPurgeWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		// A started pass is finished even when ctx is cancelled meanwhile
+		if _, err := s.PurgeTrash(context.WithoutCancel(ctx)); err != nil {
+			slog.ErrorContext(ctx, "event purge worker failed", logging.Err(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,9 @@
+package group
+
+import "event-planner/internal/apperror"
+
+// Domain errors returned by the group service
+var (
+	ErrGroupNotFound  = apperror.NotFound("group_not_found", "group not found")
+	ErrMemberNotFound = apperror.NotFound("group_member_not_found", "group member not found")
+)
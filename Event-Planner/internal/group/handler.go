@@ -0,0 +1,223 @@
+package group
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"event-planner/internal/apperror"
+	"event-planner/internal/auth"
+	"event-planner/internal/response"
+)
+
+// Handler handles HTTP requests for groups
+type Handler struct {
+	service *Service
+}
+
+// NewHandler creates a new group handler
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// CreateGroup handles POST /groups
+func (h *Handler) CreateGroup(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, apperror.ErrUnauthorized)
+		return
+	}
+
+	var req CreateGroupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, apperror.Validation("body", "invalid request body"))
+		return
+	}
+
+	group, err := h.service.CreateGroup(r.Context(), &req, userID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "group created successfully",
+		"data":    group,
+	})
+}
+
+// GetMyGroups handles GET /groups
+func (h *Handler) GetMyGroups(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, apperror.ErrUnauthorized)
+		return
+	}
+
+	groups, err := h.service.GetMyGroups(r.Context(), userID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data": groups,
+	})
+}
+
+// GetGroup handles GET /groups/{id}
+func (h *Handler) GetGroup(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, apperror.ErrUnauthorized)
+		return
+	}
+
+	groupID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		response.Error(w, apperror.Validation("id", "invalid group ID"))
+		return
+	}
+
+	group, err := h.service.GetGroup(r.Context(), groupID, userID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data": group,
+	})
+}
+
+// UpdateGroup handles PUT /groups/{id}
+func (h *Handler) UpdateGroup(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, apperror.ErrUnauthorized)
+		return
+	}
+
+	groupID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		response.Error(w, apperror.Validation("id", "invalid group ID"))
+		return
+	}
+
+	var req UpdateGroupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, apperror.Validation("body", "invalid request body"))
+		return
+	}
+
+	group, err := h.service.UpdateGroup(r.Context(), groupID, userID, &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "group updated successfully",
+		"data":    group,
+	})
+}
+
+// DeleteGroup handles DELETE /groups/{id}
+func (h *Handler) DeleteGroup(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, apperror.ErrUnauthorized)
+		return
+	}
+
+	groupID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		response.Error(w, apperror.Validation("id", "invalid group ID"))
+		return
+	}
+
+	if err := h.service.DeleteGroup(r.Context(), groupID, userID); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "group deleted successfully",
+	})
+}
+
+// AddMembers handles POST /groups/{id}/members
+func (h *Handler) AddMembers(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, apperror.ErrUnauthorized)
+		return
+	}
+
+	groupID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		response.Error(w, apperror.Validation("id", "invalid group ID"))
+		return
+	}
+
+	var req AddMembersRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, apperror.Validation("body", "invalid request body"))
+		return
+	}
+
+	members, err := h.service.AddMembers(r.Context(), groupID, userID, &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "members added successfully",
+		"data":    members,
+	})
+}
+
+// RemoveMember handles DELETE /groups/{id}/members/{memberID}
+func (h *Handler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, apperror.ErrUnauthorized)
+		return
+	}
+
+	groupID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		response.Error(w, apperror.Validation("id", "invalid group ID"))
+		return
+	}
+
+	memberID, err := strconv.Atoi(r.PathValue("memberID"))
+	if err != nil {
+		response.Error(w, apperror.Validation("memberID", "invalid member ID"))
+		return
+	}
+
+	if err := h.service.RemoveMember(r.Context(), groupID, userID, memberID); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "member removed successfully",
+	})
+}
@@ -0,0 +1,51 @@
+package group
+
+import (
+	"time"
+
+	"event-planner/internal/user"
+)
+
+// Group is a user-defined distribution list used to invite several people at once
+type Group struct {
+	ID          int       `json:"id"`
+	OwnerID     int       `json:"owner_id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	MemberCount int       `json:"member_count"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// GroupWithMembers is a group together with its members
+type GroupWithMembers struct {
+	Group
+	Members []Member `json:"members"`
+}
+
+// Member is an entry of a group, either a registered user or a plain email address
+type Member struct {
+	ID        int                 `json:"id"`
+	GroupID   int                 `json:"group_id"`
+	UserID    *int                `json:"user_id,omitempty"` // nil for email-only members
+	Email     string              `json:"email"`
+	CreatedAt time.Time           `json:"created_at"`
+	User      *user.PublicProfile `json:"user,omitempty"`
+}
+
+// CreateGroupRequest is the request payload for creating a group
+type CreateGroupRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+}
+
+// UpdateGroupRequest is the request payload for updating a group
+type UpdateGroupRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// AddMembersRequest is the request payload for adding members by user ID and/or email
+type AddMembersRequest struct {
+	UserIDs []int    `json:"user_ids"`
+	Emails  []string `json:"emails"`
+}
@@ -0,0 +1,233 @@
+package group
+
+import (
+	"context"
+	"fmt"
+
+	"event-planner/internal/apperror"
+	"event-planner/internal/db"
+	"event-planner/internal/user"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository handles all database operations for groups
+type Repository struct {
+	db *pgxpool.Pool
+}
+
+// NewRepository creates a new group repository
+func NewRepository(db *pgxpool.Pool) *Repository {
+	return &Repository{db: db}
+}
+
+// CreateGroup inserts a new group
+func (r *Repository) CreateGroup(ctx context.Context, group *Group) error {
+	query := `
+		INSERT INTO user_groups (owner_id, name, description)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at
+	`
+
+	err := r.db.QueryRow(ctx, query, group.OwnerID, group.Name, group.Description).Scan(&group.ID, &group.CreatedAt)
+	if err != nil {
+		return db.TranslateError(fmt.Errorf("failed to create group: %w", err), nil)
+	}
+
+	return nil
+}
+
+// GetGroupByID retrieves a single group by ID
+func (r *Repository) GetGroupByID(ctx context.Context, groupID int) (*Group, error) {
+	query := `
+		SELECT g.id, g.owner_id, g.name, g.description, g.created_at,
+			(SELECT COUNT(*) FROM group_members m WHERE m.group_id = g.id)
+		FROM user_groups g
+		WHERE g.id = $1
+	`
+
+	group := &Group{}
+	err := r.db.QueryRow(ctx, query, groupID).Scan(
+		&group.ID,
+		&group.OwnerID,
+		&group.Name,
+		&group.Description,
+		&group.CreatedAt,
+		&group.MemberCount,
+	)
+
+	if err != nil {
+		return nil, db.TranslateError(fmt.Errorf("failed to get group: %w", err), ErrGroupNotFound)
+	}
+
+	return group, nil
+}
+
+// GetGroupsByOwnerID retrieves all groups owned by a user
+func (r *Repository) GetGroupsByOwnerID(ctx context.Context, ownerID int) ([]Group, error) {
+	query := `
+		SELECT g.id, g.owner_id, g.name, g.description, g.created_at,
+			(SELECT COUNT(*) FROM group_members m WHERE m.group_id = g.id)
+		FROM user_groups g
+		WHERE g.owner_id = $1
+		ORDER BY g.name
+	`
+
+	rows, err := r.db.Query(ctx, query, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get groups: %w", err)
+	}
+	defer rows.Close()
+
+	var groups []Group
+	for rows.Next() {
+		group := Group{}
+		err := rows.Scan(
+			&group.ID,
+			&group.OwnerID,
+			&group.Name,
+			&group.Description,
+			&group.CreatedAt,
+			&group.MemberCount,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan group: %w", err)
+		}
+		groups = append(groups, group)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating groups: %w", err)
+	}
+
+	return groups, nil
+}
+
+// UpdateGroup saves the name and description of a group
+func (r *Repository) UpdateGroup(ctx context.Context, group *Group) error {
+	query := `UPDATE user_groups SET name = $1, description = $2 WHERE id = $3`
+
+	if _, err := r.db.Exec(ctx, query, group.Name, group.Description, group.ID); err != nil {
+		return db.TranslateError(fmt.Errorf("failed to update group: %w", err), nil)
+	}
+
+	return nil
+}
+
+// DeleteGroup removes a group and its members
+func (r *Repository) DeleteGroup(ctx context.Context, groupID int) error {
+	query := `DELETE FROM user_groups WHERE id = $1`
+
+	result, err := r.db.Exec(ctx, query, groupID)
+	if err != nil {
+		return db.TranslateError(fmt.Errorf("failed to delete group: %w", err), nil)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrGroupNotFound
+	}
+
+	return nil
+}
+
+// GetMembers retrieves all members of a group
+func (r *Repository) GetMembers(ctx context.Context, groupID int) ([]Member, error) {
+	query := `
+		SELECT
+			m.id,
+			m.group_id,
+			u.id,
+			m.email,
+			m.created_at,
+			COALESCE(NULLIF(p.display_name, ''), 'User ' || u.id),
+			p.avatar_url
+		FROM group_members m
+		LEFT JOIN users u ON u.email = m.email
+		LEFT JOIN user_profiles p ON p.user_id = u.id
+		WHERE m.group_id = $1
+		ORDER BY m.created_at, m.id
+	`
+
+	rows, err := r.db.Query(ctx, query, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group members: %w", err)
+	}
+	defer rows.Close()
+
+	var members []Member
+	for rows.Next() {
+		m := Member{}
+		var displayName, avatarURL *string
+		err := rows.Scan(
+			&m.ID,
+			&m.GroupID,
+			&m.UserID,
+			&m.Email,
+			&m.CreatedAt,
+			&displayName,
+			&avatarURL,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan group member: %w", err)
+		}
+
+		if m.UserID != nil && displayName != nil {
+			m.User = &user.PublicProfile{UserID: *m.UserID, DisplayName: *displayName}
+			if avatarURL != nil {
+				m.User.AvatarURL = *avatarURL
+			}
+		}
+		members = append(members, m)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating group members: %w", err)
+	}
+
+	return members, nil
+}
+
+// AddMember adds an email address to a group. It reports false when the
+// address already was a member.
+func (r *Repository) AddMember(ctx context.Context, groupID int, email string) (bool, error) {
+	query := `
+		INSERT INTO group_members (group_id, email)
+		VALUES ($1, $2)
+		ON CONFLICT (group_id, email) DO NOTHING
+	`
+
+	result, err := r.db.Exec(ctx, query, groupID, email)
+	if err != nil {
+		return false, db.TranslateError(fmt.Errorf("failed to add group member: %w", err), nil)
+	}
+
+	return result.RowsAffected() > 0, nil
+}
+
+// RemoveMember removes a member from a group
+func (r *Repository) RemoveMember(ctx context.Context, groupID, memberID int) error {
+	query := `DELETE FROM group_members WHERE group_id = $1 AND id = $2`
+
+	result, err := r.db.Exec(ctx, query, groupID, memberID)
+	if err != nil {
+		return db.TranslateError(fmt.Errorf("failed to remove group member: %w", err), nil)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrMemberNotFound
+	}
+
+	return nil
+}
+
+// GetUserEmail retrieves the email of a user (helper function)
+func (r *Repository) GetUserEmail(ctx context.Context, userID int) (string, error) {
+	query := `SELECT email FROM users WHERE id = $1`
+
+	var email string
+	if err := r.db.QueryRow(ctx, query, userID).Scan(&email); err != nil {
+		return "", apperror.NotFound("user_not_found", fmt.Sprintf("user %d not found", userID))
+	}
+
+	return email, nil
+}
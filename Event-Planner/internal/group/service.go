@@ -0,0 +1,260 @@
+package group
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"event-planner/internal/apperror"
+	"event-planner/internal/logging"
+	"event-planner/internal/tracing"
+)
+
+var emailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
+
+// LateJoinerInviter invites members that joined a group after it was invited to events
+type LateJoinerInviter interface {
+	InviteNewGroupMembers(ctx context.Context, groupID int, emails []string) error
+}
+
+// Store is the group storage used by Service; Repository implements it on PostgreSQL
+type Store interface {
+	CreateGroup(ctx context.Context, group *Group) error
+	GetGroupByID(ctx context.Context, groupID int) (*Group, error)
+	GetGroupsByOwnerID(ctx context.Context, ownerID int) ([]Group, error)
+	UpdateGroup(ctx context.Context, group *Group) error
+	DeleteGroup(ctx context.Context, groupID int) error
+	GetMembers(ctx context.Context, groupID int) ([]Member, error)
+	AddMember(ctx context.Context, groupID int, email string) (bool, error)
+	RemoveMember(ctx context.Context, groupID, memberID int) error
+	GetUserEmail(ctx context.Context, userID int) (string, error)
+}
+
+// Service handles business logic for groups
+type Service struct {
+	repo    Store
+	inviter LateJoinerInviter
+}
+
+// NewService creates a new group service
+func NewService(repo Store, inviter LateJoinerInviter) *Service {
+	return &Service{
+		repo:    repo,
+		inviter: inviter,
+	}
+}
+
+// CreateGroup validates and creates a group owned by ownerID
+func (s *Service) CreateGroup(ctx context.Context, req *CreateGroupRequest, ownerID int) (*Group, error) {
+	ctx, span := tracing.Start(ctx, "group.Service.CreateGroup")
+	defer span.End()
+
+	req.Name = strings.TrimSpace(req.Name)
+	if err := s.validateGroup(req.Name, req.Description); err != nil {
+		return nil, err
+	}
+
+	group := &Group{
+		OwnerID:     ownerID,
+		Name:        req.Name,
+		Description: req.Description,
+	}
+
+	if err := s.repo.CreateGroup(ctx, group); err != nil {
+		return nil, err
+	}
+
+	return group, nil
+}
+
+// GetMyGroups retrieves all groups owned by the user
+func (s *Service) GetMyGroups(ctx context.Context, ownerID int) ([]Group, error) {
+	ctx, span := tracing.Start(ctx, "group.Service.GetMyGroups")
+	defer span.End()
+
+	groups, err := s.repo.GetGroupsByOwnerID(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if groups == nil {
+		groups = []Group{}
+	}
+
+	return groups, nil
+}
+
+// GetGroup retrieves a group with its members
+func (s *Service) GetGroup(ctx context.Context, groupID, ownerID int) (*GroupWithMembers, error) {
+	ctx, span := tracing.Start(ctx, "group.Service.GetGroup")
+	defer span.End()
+
+	group, err := s.getOwnedGroup(ctx, groupID, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := s.repo.GetMembers(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	if members == nil {
+		members = []Member{}
+	}
+
+	return &GroupWithMembers{Group: *group, Members: members}, nil
+}
+
+// UpdateGroup updates the name and description of a group (only non-empty fields)
+func (s *Service) UpdateGroup(ctx context.Context, groupID, ownerID int, req *UpdateGroupRequest) (*Group, error) {
+	ctx, span := tracing.Start(ctx, "group.Service.UpdateGroup")
+	defer span.End()
+
+	group, err := s.getOwnedGroup(ctx, groupID, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if name := strings.TrimSpace(req.Name); name != "" {
+		group.Name = name
+	}
+	if req.Description != "" {
+		group.Description = req.Description
+	}
+
+	if err := s.validateGroup(group.Name, group.Description); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateGroup(ctx, group); err != nil {
+		return nil, err
+	}
+
+	return group, nil
+}
+
+// DeleteGroup deletes a group; invitations already sent through it are kept
+func (s *Service) DeleteGroup(ctx context.Context, groupID, ownerID int) error {
+	ctx, span := tracing.Start(ctx, "group.Service.DeleteGroup")
+	defer span.End()
+
+	if _, err := s.getOwnedGroup(ctx, groupID, ownerID); err != nil {
+		return err
+	}
+
+	return s.repo.DeleteGroup(ctx, groupID)
+}
+
+// AddMembers adds users (by ID) and email addresses to a group and invites the
+// new members to the events the group was invited to with late joiners enabled
+func (s *Service) AddMembers(ctx context.Context, groupID, ownerID int, req *AddMembersRequest) ([]Member, error) {
+	ctx, span := tracing.Start(ctx, "group.Service.AddMembers")
+	defer span.End()
+
+	if _, err := s.getOwnedGroup(ctx, groupID, ownerID); err != nil {
+		return nil, err
+	}
+
+	if len(req.UserIDs) == 0 && len(req.Emails) == 0 {
+		return nil, apperror.Validation("user_ids", "at least one user ID or email is required")
+	}
+
+	if len(req.UserIDs)+len(req.Emails) > 500 {
+		return nil, apperror.Validation("user_ids", "at most 500 members can be added at once")
+	}
+
+	emails := make([]string, 0, len(req.UserIDs)+len(req.Emails))
+	for _, userID := range req.UserIDs {
+		email, err := s.repo.GetUserEmail(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		emails = append(emails, email)
+	}
+
+	for _, email := range req.Emails {
+		email = strings.TrimSpace(email)
+		if len(email) > 254 || !emailRegex.MatchString(email) {
+			return nil, apperror.Validationf("emails", "invalid email format: %s", email)
+		}
+		emails = append(emails, email)
+	}
+
+	var added []string
+	for _, email := range emails {
+		ok, err := s.repo.AddMember(ctx, groupID, email)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			added = append(added, email)
+		}
+	}
+
+	if len(added) > 0 && s.inviter != nil {
+		// Membership changes are already stored; a failed late invitation must not undo them
+		if err := s.inviter.InviteNewGroupMembers(ctx, groupID, added); err != nil {
+			slog.ErrorContext(ctx, "failed to invite new members of group", "group_id", groupID, logging.Err(err))
+		}
+	}
+
+	members, err := s.repo.GetMembers(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	if members == nil {
+		members = []Member{}
+	}
+
+	return members, nil
+}
+
+// RemoveMember removes a member from a group
+func (s *Service) RemoveMember(ctx context.Context, groupID, ownerID, memberID int) error {
+	ctx, span := tracing.Start(ctx, "group.Service.RemoveMember")
+	defer span.End()
+
+	if _, err := s.getOwnedGroup(ctx, groupID, ownerID); err != nil {
+		return err
+	}
+
+	return s.repo.RemoveMember(ctx, groupID, memberID)
+}
+
+// getOwnedGroup retrieves a group owned by ownerID; groups of other users are reported as missing
+func (s *Service) getOwnedGroup(ctx context.Context, groupID, ownerID int) (*Group, error) {
+	if groupID <= 0 {
+		return nil, apperror.Validation("id", "invalid group ID")
+	}
+
+	group, err := s.repo.GetGroupByID(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Other users' groups are reported as missing rather than forbidden
+	if group.OwnerID != ownerID {
+		return nil, ErrGroupNotFound
+	}
+
+	return group, nil
+}
+
+func (s *Service) validateGroup(name, description string) error {
+	if name == "" {
+		return apperror.Validation("name", "group name is required")
+	}
+
+	if len(name) > 100 {
+		return apperror.Validation("name", "group name must not exceed 100 characters")
+	}
+
+	if len(description) > 500 {
+		return apperror.Validation("description", "group description must not exceed 500 characters")
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+// Package health serves the liveness and readiness probes
+package health
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"event-planner/internal/response"
+)
+
+// checkTimeout bounds every readiness check, so a hanging dependency fails
+// the probe instead of the probe timing out
+const checkTimeout = 2 * time.Second
+
+// Check reports whether a dependency can serve requests
+type Check func(ctx context.Context) error
+
+// Report is the body of the probe responses
+type Report struct {
+	Status string            `json:"status"`           // 'ok' or 'unavailable'
+	Checks map[string]string `json:"checks,omitempty"` // 'ok' or the failure, by check name
+}
+
+// Handler serves /livez and /readyz
+type Handler struct {
+	checks   []namedCheck
+	draining atomic.Bool
+}
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// NewHandler creates a handler without readiness checks
+func NewHandler() *Handler {
+	return &Handler{}
+}
+
+// AddCheck adds a check that must pass for the server to be ready
+func (h *Handler) AddCheck(name string, check Check) {
+	h.checks = append(h.checks, namedCheck{name: name, check: check})
+}
+
+// Drain makes the server report not ready from now on, so load balancers
+// stop sending it requests while it shuts down
+func (h *Handler) Drain() {
+	h.draining.Store(true)
+}
+
+// Live handles GET /livez; the process is alive as long as it answers
+func (h *Handler) Live(w http.ResponseWriter, r *http.Request) {
+	response.JSON(w, http.StatusOK, Report{Status: "ok"})
+}
+
+// Ready handles GET /readyz by running every check
+func (h *Handler) Ready(w http.ResponseWriter, r *http.Request) {
+	report := Report{Status: "ok", Checks: map[string]string{}}
+
+	if h.draining.Load() {
+		report.Status = "unavailable"
+		report.Checks["shutdown"] = "server is shutting down"
+	}
+
+	for _, c := range h.checks {
+		ctx, cancel := context.WithTimeout(r.Context(), checkTimeout)
+		err := c.check(ctx)
+		cancel()
+
+		if err != nil {
+			report.Status = "unavailable"
+			report.Checks[c.name] = err.Error()
+			continue
+		}
+		report.Checks[c.name] = "ok"
+	}
+
+	status := http.StatusOK
+	if report.Status != "ok" {
+		status = http.StatusServiceUnavailable
+	}
+	response.JSON(w, status, report)
+}
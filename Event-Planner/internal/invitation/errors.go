@@ -0,0 +1,16 @@
+package invitation
+
+import "event-planner/internal/apperror"
+
+// Domain errors returned by the invitation service
+var (
+	ErrInvitationNotFound = apperror.NotFound("invitation_not_found", "invitation not found")
+	ErrEventNotFound      = apperror.NotFound("event_not_found", "event not found")
+	ErrEventCancelled     = apperror.Conflict("event_cancelled", "event is cancelled")
+	ErrEventCompleted     = apperror.Conflict("event_completed", "event has already taken place")
+	ErrEventDraft         = apperror.Conflict("event_draft", "event is not published yet: only collaborators can be invited")
+	ErrGroupNotFound      = apperror.NotFound("group_not_found", "group not found")
+	ErrAlreadyResponded   = apperror.Conflict("invitation_already_responded", "invitation has already been responded to")
+	ErrNotInvitee         = apperror.Forbidden("not_invitee", "you are not authorized to respond to this invitation")
+	ErrNotEventCreator    = apperror.Forbidden("not_event_creator", "only the event creator can invite groups to this event")
+)
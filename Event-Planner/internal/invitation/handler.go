@@ -2,11 +2,12 @@ package invitation
 
 import (
 	"encoding/json"
-	"fmt"
 	"net/http"
 	"strconv"
 
+	"event-planner/internal/apperror"
 	"event-planner/internal/auth"
+	"event-planner/internal/response"
 )
 
 // Handler handles HTTP requests for invitations
@@ -24,19 +25,36 @@ func (h *Handler) SendInvitation(w http.ResponseWriter, r *http.Request) {
 	// Get inviter ID from context (set by auth middleware)
 	inviterID, ok := auth.GetUserID(r.Context())
 	if !ok {
-		http.Error(w, `{"error": "unauthorized"}`, http.StatusUnauthorized)
+		response.Error(w, apperror.ErrUnauthorized)
 		return
 	}
 
 	var req SendInvitationRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, `{"error": "invalid request body"}`, http.StatusBadRequest)
+		response.Error(w, apperror.Validation("body", "invalid request body"))
+		return
+	}
+
+	// Group targets expand into one invitation per member
+	if req.GroupID > 0 {
+		result, err := h.service.InviteGroup(r.Context(), &req, inviterID)
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"message": "group invited successfully",
+			"data":    result,
+		})
 		return
 	}
 
 	invitation, err := h.service.SendInvitation(r.Context(), &req, inviterID)
 	if err != nil {
-		http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusBadRequest)
+		response.Error(w, err)
 		return
 	}
 
@@ -54,13 +72,13 @@ func (h *Handler) GetMyInvitations(w http.ResponseWriter, r *http.Request) {
 	// For now, we'll get it from query parameter as a workaround
 	email := r.URL.Query().Get("email")
 	if email == "" {
-		http.Error(w, `{"error": "email parameter is required"}`, http.StatusBadRequest)
+		response.Error(w, apperror.Validation("email", "email parameter is required"))
 		return
 	}
 
 	invitations, err := h.service.GetMyInvitations(r.Context(), email)
 	if err != nil {
-		http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusInternalServerError)
+		response.Error(w, err)
 		return
 	}
 
@@ -73,16 +91,22 @@ func (h *Handler) GetMyInvitations(w http.ResponseWriter, r *http.Request) {
 
 // GetEventInvitations handles GET /events/{id}/invitations
 func (h *Handler) GetEventInvitations(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, apperror.ErrUnauthorized)
+		return
+	}
+
 	idStr := r.PathValue("id")
 	eventID, err := strconv.Atoi(idStr)
 	if err != nil {
-		http.Error(w, `{"error": "invalid event ID"}`, http.StatusBadRequest)
+		response.Error(w, apperror.Validation("id", "invalid event ID"))
 		return
 	}
 
-	invitations, err := h.service.GetEventInvitations(r.Context(), eventID)
+	invitations, err := h.service.GetEventInvitations(r.Context(), eventID, userID)
 	if err != nil {
-		http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusInternalServerError)
+		response.Error(w, err)
 		return
 	}
 
@@ -99,30 +123,26 @@ func (h *Handler) RespondToInvitation(w http.ResponseWriter, r *http.Request) {
 	// In a complete implementation, you'd get this from the auth context
 	email := r.URL.Query().Get("email")
 	if email == "" {
-		http.Error(w, `{"error": "email parameter is required"}`, http.StatusBadRequest)
+		response.Error(w, apperror.Validation("email", "email parameter is required"))
 		return
 	}
 
 	idStr := r.PathValue("id")
 	invitationID, err := strconv.Atoi(idStr)
 	if err != nil {
-		http.Error(w, `{"error": "invalid invitation ID"}`, http.StatusBadRequest)
+		response.Error(w, apperror.Validation("id", "invalid invitation ID"))
 		return
 	}
 
 	var req RespondToInvitationRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, `{"error": "invalid request body"}`, http.StatusBadRequest)
+		response.Error(w, apperror.Validation("body", "invalid request body"))
 		return
 	}
 
 	err = h.service.RespondToInvitation(r.Context(), invitationID, req.Status, email)
 	if err != nil {
-		if err.Error() == "you are not authorized to respond to this invitation" {
-			http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusForbidden)
-		} else {
-			http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusBadRequest)
-		}
+		response.Error(w, err)
 		return
 	}
 
@@ -1,6 +1,10 @@
 package invitation
 
-import "time"
+import (
+	"time"
+
+	"event-planner/internal/user"
+)
 
 // Invitation represents an invitation to an event
 type Invitation struct {
@@ -9,8 +13,9 @@ type Invitation struct {
 	InviterID    int        `json:"inviter_id"`
 	InviteeEmail string     `json:"invitee_email"`
 	InviteeID    *int       `json:"invitee_id,omitempty"`
-	Role         string     `json:"role"`   // 'attendee', 'collaborator', or 'organizer'
-	Status       string     `json:"status"` // 'pending', 'accepted', 'declined'
+	GroupID      *int       `json:"group_id,omitempty"` // set when sent to a group
+	Role         string     `json:"role"`               // 'attendee', 'collaborator', or 'organizer'
+	Status       string     `json:"status"`             // 'pending', 'accepted', 'declined'
 	Message      string     `json:"message,omitempty"`
 	CreatedAt    time.Time  `json:"created_at"`
 	RespondedAt  *time.Time `json:"responded_at,omitempty"`
@@ -23,15 +28,41 @@ type InvitationWithDetails struct {
 	EventDate     string `json:"event_date"`
 	EventTime     string `json:"event_time"`
 	EventLocation string `json:"event_location"`
+	EventStatus   string `json:"event_status"` // invitations to cancelled or completed events can only be declined
 	InviterEmail  string `json:"inviter_email"`
+
+	Inviter user.PublicProfile  `json:"inviter"`
+	Invitee *user.PublicProfile `json:"invitee,omitempty"` // nil until the invitee has an account
 }
 
-// SendInvitationRequest is the request payload for sending invitations
+// SendInvitationRequest is the request payload for sending invitations.
+// Either InviteeEmail or GroupID must be set.
 type SendInvitationRequest struct {
 	EventID      int    `json:"event_id" binding:"required"`
-	InviteeEmail string `json:"invitee_email" binding:"required"`
+	InviteeEmail string `json:"invitee_email"`
+	GroupID      int    `json:"group_id,omitempty"`
 	Role         string `json:"role" binding:"required"` // 'attendee', 'collaborator', or 'organizer'
 	Message      string `json:"message,omitempty"`
+
+	// InviteNewMembers also invites people who join the group later
+	InviteNewMembers bool `json:"invite_new_members,omitempty"`
+}
+
+// GroupLink records that a group was invited to an event
+type GroupLink struct {
+	EventID          int       `json:"event_id"`
+	GroupID          int       `json:"group_id"`
+	InviterID        int       `json:"inviter_id"`
+	Role             string    `json:"role"`
+	Message          string    `json:"message,omitempty"`
+	InviteNewMembers bool      `json:"invite_new_members"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// GroupInvitationResult is the outcome of inviting a group
+type GroupInvitationResult struct {
+	Invitations []Invitation `json:"invitations"`
+	Skipped     []string     `json:"skipped"` // emails that already had an invitation to the event
 }
 
 // RespondToInvitationRequest is the request payload for responding to invitations
@@ -3,8 +3,12 @@ package invitation
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
+	"event-planner/internal/db"
+	"event-planner/internal/user"
+
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -21,8 +25,8 @@ func NewRepository(db *pgxpool.Pool) *Repository {
 // SendInvitation creates a new invitation
 func (r *Repository) SendInvitation(ctx context.Context, invitation *Invitation) error {
 	query := `
-        INSERT INTO invitations (event_id, inviter_id, invitee_email, invitee_id, role, message)
-        VALUES ($1, $2, $3, $4, $5, $6)
+        INSERT INTO invitations (event_id, inviter_id, invitee_email, invitee_id, group_id, role, message)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
         RETURNING id, created_at
     `
 
@@ -31,12 +35,13 @@ func (r *Repository) SendInvitation(ctx context.Context, invitation *Invitation)
 		invitation.InviterID,
 		invitation.InviteeEmail,
 		invitation.InviteeID,
+		invitation.GroupID,
 		invitation.Role,
 		invitation.Message,
 	).Scan(&invitation.ID, &invitation.CreatedAt)
 
 	if err != nil {
-		return fmt.Errorf("failed to send invitation: %w", err)
+		return db.TranslateError(fmt.Errorf("failed to send invitation: %w", err), nil)
 	}
 
 	return nil
@@ -45,7 +50,7 @@ func (r *Repository) SendInvitation(ctx context.Context, invitation *Invitation)
 // GetInvitationByID retrieves a single invitation by ID
 func (r *Repository) GetInvitationByID(ctx context.Context, invitationID int) (*Invitation, error) {
 	query := `
-        SELECT id, event_id, inviter_id, invitee_email, invitee_id, role, status, message, created_at, responded_at
+        SELECT id, event_id, inviter_id, invitee_email, invitee_id, group_id, role, status, message, created_at, responded_at
         FROM invitations
         WHERE id = $1
     `
@@ -57,6 +62,7 @@ func (r *Repository) GetInvitationByID(ctx context.Context, invitationID int) (*
 		&invitation.InviterID,
 		&invitation.InviteeEmail,
 		&invitation.InviteeID,
+		&invitation.GroupID,
 		&invitation.Role,
 		&invitation.Status,
 		&invitation.Message,
@@ -65,14 +71,15 @@ func (r *Repository) GetInvitationByID(ctx context.Context, invitationID int) (*
 	)
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to get invitation: %w", err)
+		return nil, db.TranslateError(fmt.Errorf("failed to get invitation: %w", err), ErrInvitationNotFound)
 	}
 
 	return invitation, nil
 }
 
-// GetInvitationsByEmail retrieves all invitations for a specific email
-func (r *Repository) GetInvitationsByEmail(ctx context.Context, email string) ([]InvitationWithDetails, error) {
+// GetInvitationsByEmail retrieves all invitations for a specific email.
+// A non-nil orgID restricts the result to invitations to that organization's events.
+func (r *Repository) GetInvitationsByEmail(ctx context.Context, email string, orgID *int) ([]InvitationWithDetails, error) {
 	query := `
         SELECT 
             i.id,
@@ -80,6 +87,7 @@ func (r *Repository) GetInvitationsByEmail(ctx context.Context, email string) ([
             i.inviter_id,
             i.invitee_email,
             i.invitee_id,
+            i.group_id,
             i.role,
             i.status,
             i.message,
@@ -89,15 +97,23 @@ func (r *Repository) GetInvitationsByEmail(ctx context.Context, email string) ([
             to_char(e.date, 'YYYY-MM-DD') AS event_date,
             to_char(e.time, 'HH24:MI:SS') AS event_time,
             e.location,
-            u.email AS inviter_email
+            e.status,
+            u.email AS inviter_email,
+            COALESCE(NULLIF(up.display_name, ''), 'User ' || u.id) AS inviter_display_name,
+            COALESCE(up.avatar_url, '') AS inviter_avatar_url,
+            COALESCE(NULLIF(vp.display_name, ''), 'User ' || v.id) AS invitee_display_name,
+            vp.avatar_url AS invitee_avatar_url
         FROM invitations i
         JOIN events e ON i.event_id = e.id
         JOIN users u ON i.inviter_id = u.id
-        WHERE i.invitee_email = $1
+        LEFT JOIN user_profiles up ON up.user_id = i.inviter_id
+        LEFT JOIN users v ON v.id = i.invitee_id
+        LEFT JOIN user_profiles vp ON vp.user_id = i.invitee_id
+        WHERE i.invitee_email = $1 AND e.deleted_at IS NULL AND ($2::int IS NULL OR e.organization_id = $2)
         ORDER BY i.created_at DESC
     `
 
-	rows, err := r.db.Query(ctx, query, email)
+	rows, err := r.db.Query(ctx, query, email, orgID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get invitations by email: %w", err)
 	}
@@ -106,12 +122,14 @@ func (r *Repository) GetInvitationsByEmail(ctx context.Context, email string) ([
 	var invitations []InvitationWithDetails
 	for rows.Next() {
 		inv := InvitationWithDetails{}
+		var inviteeName, inviteeAvatar *string
 		err := rows.Scan(
 			&inv.ID,
 			&inv.EventID,
 			&inv.InviterID,
 			&inv.InviteeEmail,
 			&inv.InviteeID,
+			&inv.GroupID,
 			&inv.Role,
 			&inv.Status,
 			&inv.Message,
@@ -121,11 +139,18 @@ func (r *Repository) GetInvitationsByEmail(ctx context.Context, email string) ([
 			&inv.EventDate,
 			&inv.EventTime,
 			&inv.EventLocation,
+			&inv.EventStatus,
 			&inv.InviterEmail,
+			&inv.Inviter.DisplayName,
+			&inv.Inviter.AvatarURL,
+			&inviteeName,
+			&inviteeAvatar,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan invitation: %w", err)
 		}
+		inv.Inviter.UserID = inv.InviterID
+		inv.Invitee = publicProfile(inv.InviteeID, inviteeName, inviteeAvatar)
 		invitations = append(invitations, inv)
 	}
 
@@ -149,6 +174,7 @@ func (r *Repository) GetInvitationsByEventID(ctx context.Context, eventID int) (
             i.inviter_id,
             i.invitee_email,
             i.invitee_id,
+            i.group_id,
             i.role,
             i.status,
             i.message,
@@ -158,10 +184,18 @@ func (r *Repository) GetInvitationsByEventID(ctx context.Context, eventID int) (
             to_char(e.date, 'YYYY-MM-DD') AS event_date,
             to_char(e.time, 'HH24:MI:SS') AS event_time,
             e.location,
-            u.email AS inviter_email
+            e.status,
+            u.email AS inviter_email,
+            COALESCE(NULLIF(up.display_name, ''), 'User ' || u.id) AS inviter_display_name,
+            COALESCE(up.avatar_url, '') AS inviter_avatar_url,
+            COALESCE(NULLIF(vp.display_name, ''), 'User ' || v.id) AS invitee_display_name,
+            vp.avatar_url AS invitee_avatar_url
         FROM invitations i
         JOIN events e ON i.event_id = e.id
         JOIN users u ON i.inviter_id = u.id
+        LEFT JOIN user_profiles up ON up.user_id = i.inviter_id
+        LEFT JOIN users v ON v.id = i.invitee_id
+        LEFT JOIN user_profiles vp ON vp.user_id = i.invitee_id
         WHERE i.event_id = $1
         ORDER BY i.created_at DESC
     `
@@ -175,12 +209,14 @@ func (r *Repository) GetInvitationsByEventID(ctx context.Context, eventID int) (
 	var invitations []InvitationWithDetails
 	for rows.Next() {
 		inv := InvitationWithDetails{}
+		var inviteeName, inviteeAvatar *string
 		err := rows.Scan(
 			&inv.ID,
 			&inv.EventID,
 			&inv.InviterID,
 			&inv.InviteeEmail,
 			&inv.InviteeID,
+			&inv.GroupID,
 			&inv.Role,
 			&inv.Status,
 			&inv.Message,
@@ -190,11 +226,18 @@ func (r *Repository) GetInvitationsByEventID(ctx context.Context, eventID int) (
 			&inv.EventDate,
 			&inv.EventTime,
 			&inv.EventLocation,
+			&inv.EventStatus,
 			&inv.InviterEmail,
+			&inv.Inviter.DisplayName,
+			&inv.Inviter.AvatarURL,
+			&inviteeName,
+			&inviteeAvatar,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan invitation: %w", err)
 		}
+		inv.Inviter.UserID = inv.InviterID
+		inv.Invitee = publicProfile(inv.InviteeID, inviteeName, inviteeAvatar)
 		invitations = append(invitations, inv)
 	}
 
@@ -219,12 +262,204 @@ func (r *Repository) UpdateInvitationStatus(ctx context.Context, invitationID in
 
 	_, err := r.db.Exec(ctx, query, status, time.Now(), invitationID)
 	if err != nil {
-		return fmt.Errorf("failed to update invitation status: %w", err)
+		return db.TranslateError(fmt.Errorf("failed to update invitation status: %w", err), nil)
 	}
 
 	return nil
 }
 
+// GetEventVisibility retrieves the organization and visibility of an event
+// (helper function); events in the trash are not found
+func (r *Repository) GetEventVisibility(ctx context.Context, eventID int) (*int, string, error) {
+	query := `SELECT organization_id, visibility FROM events WHERE id = $1 AND deleted_at IS NULL`
+
+	var orgID *int
+	var visibility string
+	if err := r.db.QueryRow(ctx, query, eventID).Scan(&orgID, &visibility); err != nil {
+		return nil, "", db.TranslateError(fmt.Errorf("failed to get event: %w", err), ErrEventNotFound)
+	}
+
+	return orgID, visibility, nil
+}
+
+// GetEventStatus retrieves the status of an event; events in the trash are not found
+func (r *Repository) GetEventStatus(ctx context.Context, eventID int) (string, error) {
+	query := `SELECT status FROM events WHERE id = $1 AND deleted_at IS NULL`
+
+	var status string
+	if err := r.db.QueryRow(ctx, query, eventID).Scan(&status); err != nil {
+		return "", db.TranslateError(fmt.Errorf("failed to get event: %w", err), ErrEventNotFound)
+	}
+
+	return status, nil
+}
+
+// IsEventOrganizer reports whether a user is the organizer of an event (helper function)
+func (r *Repository) IsEventOrganizer(ctx context.Context, eventID, userID int) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM events WHERE id = $1 AND organizer_id = $2)`
+
+	var organizer bool
+	if err := r.db.QueryRow(ctx, query, eventID, userID).Scan(&organizer); err != nil {
+		return false, db.TranslateError(fmt.Errorf("failed to check event organizer: %w", err), nil)
+	}
+
+	return organizer, nil
+}
+
+// IsEventCollaborator reports whether a user organizes an event or
+// collaborates on it (helper function)
+func (r *Repository) IsEventCollaborator(ctx context.Context, eventID, userID int) (bool, error) {
+	query := `
+        SELECT EXISTS (
+            SELECT 1 FROM events e WHERE e.id = $1 AND e.organizer_id = $2
+            UNION ALL
+            SELECT 1 FROM event_attendees ea
+            WHERE ea.event_id = $1 AND ea.user_id = $2 AND ea.role IN ('organizer', 'collaborator')
+        )
+    `
+
+	var collaborator bool
+	if err := r.db.QueryRow(ctx, query, eventID, userID).Scan(&collaborator); err != nil {
+		return false, db.TranslateError(fmt.Errorf("failed to check event collaborator: %w", err), nil)
+	}
+
+	return collaborator, nil
+}
+
+// GetInvitedEmails retrieves the emails that already have an invitation to an event
+func (r *Repository) GetInvitedEmails(ctx context.Context, eventID int) (map[string]bool, error) {
+	query := `SELECT invitee_email FROM invitations WHERE event_id = $1`
+
+	rows, err := r.db.Query(ctx, query, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invited emails: %w", err)
+	}
+	defer rows.Close()
+
+	emails := map[string]bool{}
+	for rows.Next() {
+		var email string
+		if err := rows.Scan(&email); err != nil {
+			return nil, fmt.Errorf("failed to scan invited email: %w", err)
+		}
+		emails[strings.ToLower(email)] = true
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating invited emails: %w", err)
+	}
+
+	return emails, nil
+}
+
+// GetGroupEmails retrieves the member emails of a group owned by ownerID
+func (r *Repository) GetGroupEmails(ctx context.Context, groupID, ownerID int) ([]string, error) {
+	var exists bool
+	checkQuery := `SELECT EXISTS(SELECT 1 FROM user_groups WHERE id = $1 AND owner_id = $2)`
+	if err := r.db.QueryRow(ctx, checkQuery, groupID, ownerID).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("failed to check group: %w", err)
+	}
+
+	if !exists {
+		return nil, ErrGroupNotFound
+	}
+
+	query := `SELECT email FROM group_members WHERE group_id = $1 ORDER BY created_at, id`
+
+	rows, err := r.db.Query(ctx, query, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group members: %w", err)
+	}
+	defer rows.Close()
+
+	var emails []string
+	for rows.Next() {
+		var email string
+		if err := rows.Scan(&email); err != nil {
+			return nil, fmt.Errorf("failed to scan group member: %w", err)
+		}
+		emails = append(emails, email)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating group members: %w", err)
+	}
+
+	return emails, nil
+}
+
+// SaveGroupLink records (or refreshes) the invitation of a group to an event
+func (r *Repository) SaveGroupLink(ctx context.Context, link *GroupLink) error {
+	query := `
+        INSERT INTO event_group_invitations (event_id, group_id, inviter_id, role, message, invite_new_members)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        ON CONFLICT (event_id, group_id) DO UPDATE
+        SET inviter_id = EXCLUDED.inviter_id,
+            role = EXCLUDED.role,
+            message = EXCLUDED.message,
+            invite_new_members = EXCLUDED.invite_new_members
+        RETURNING created_at
+    `
+
+	err := r.db.QueryRow(ctx, query,
+		link.EventID,
+		link.GroupID,
+		link.InviterID,
+		link.Role,
+		link.Message,
+		link.InviteNewMembers,
+	).Scan(&link.CreatedAt)
+
+	if err != nil {
+		return db.TranslateError(fmt.Errorf("failed to save group invitation: %w", err), nil)
+	}
+
+	return nil
+}
+
+// GetLateJoinerLinks retrieves the links of a group to upcoming events that
+// invite new members; cancelled and completed events are left out, and so
+// are drafts the link could not invite to
+func (r *Repository) GetLateJoinerLinks(ctx context.Context, groupID int) ([]GroupLink, error) {
+	query := `
+        SELECT l.event_id, l.group_id, l.inviter_id, l.role, l.message, l.invite_new_members, l.created_at
+        FROM event_group_invitations l
+        JOIN events e ON e.id = l.event_id
+        WHERE l.group_id = $1 AND l.invite_new_members AND e.date >= CURRENT_DATE AND e.deleted_at IS NULL
+            AND e.status NOT IN ('cancelled', 'completed') AND (e.status <> 'draft' OR l.role <> 'attendee')
+    `
+
+	rows, err := r.db.Query(ctx, query, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group invitations: %w", err)
+	}
+	defer rows.Close()
+
+	var links []GroupLink
+	for rows.Next() {
+		link := GroupLink{}
+		err := rows.Scan(
+			&link.EventID,
+			&link.GroupID,
+			&link.InviterID,
+			&link.Role,
+			&link.Message,
+			&link.InviteNewMembers,
+			&link.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan group invitation: %w", err)
+		}
+		links = append(links, link)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating group invitations: %w", err)
+	}
+
+	return links, nil
+}
+
 // GetUserIDByEmail retrieves user ID by email (helper function)
 func (r *Repository) GetUserIDByEmail(ctx context.Context, email string) (*int, error) {
 	query := `SELECT id FROM users WHERE email = $1`
@@ -238,3 +473,20 @@ func (r *Repository) GetUserIDByEmail(ctx context.Context, email string) (*int,
 
 	return &userID, nil
 }
+
+// publicProfile builds the embedded profile of a registered invitee
+func publicProfile(userID *int, displayName, avatarURL *string) *user.PublicProfile {
+	if userID == nil || displayName == nil {
+		return nil
+	}
+
+	profile := &user.PublicProfile{
+		UserID:      *userID,
+		DisplayName: *displayName,
+	}
+	if avatarURL != nil {
+		profile.AvatarURL = *avatarURL
+	}
+
+	return profile
+}
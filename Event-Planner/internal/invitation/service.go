@@ -3,35 +3,80 @@ package invitation
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"regexp"
 	"strings"
+
+	"event-planner/internal/apperror"
+	"event-planner/internal/audit"
+	"event-planner/internal/logging"
+	"event-planner/internal/metrics"
+	"event-planner/internal/organization"
+	"event-planner/internal/tracing"
 )
 
 type EventAttendeeService interface {
 	AddAttendee(ctx context.Context, eventID, userID int, role string) error
 }
 
+// Store is the invitation storage used by Service; Repository implements it on PostgreSQL
+type Store interface {
+	SendInvitation(ctx context.Context, invitation *Invitation) error
+	GetInvitationByID(ctx context.Context, invitationID int) (*Invitation, error)
+	GetInvitationsByEmail(ctx context.Context, email string, orgID *int) ([]InvitationWithDetails, error)
+	GetInvitationsByEventID(ctx context.Context, eventID int) ([]InvitationWithDetails, error)
+	UpdateInvitationStatus(ctx context.Context, invitationID int, status string) error
+	GetEventVisibility(ctx context.Context, eventID int) (*int, string, error)
+	GetEventStatus(ctx context.Context, eventID int) (string, error)
+	IsEventOrganizer(ctx context.Context, eventID, userID int) (bool, error)
+	IsEventCollaborator(ctx context.Context, eventID, userID int) (bool, error)
+	GetInvitedEmails(ctx context.Context, eventID int) (map[string]bool, error)
+	GetGroupEmails(ctx context.Context, groupID, ownerID int) ([]string, error)
+	SaveGroupLink(ctx context.Context, link *GroupLink) error
+	GetLateJoinerLinks(ctx context.Context, groupID int) ([]GroupLink, error)
+	GetUserIDByEmail(ctx context.Context, email string) (*int, error)
+}
+
 // Service handles business logic for invitations
 type Service struct {
-	repo            *Repository
+	repo            Store
 	attendeeService EventAttendeeService
+	metrics         *metrics.Metrics
+	audit           *audit.Service
 }
 
-// NewService creates a new invitation service
-func NewService(repo *Repository, attendeeService EventAttendeeService) *Service {
+// NewService creates a new invitation service; metrics and the audit log may be nil
+func NewService(repo Store, attendeeService EventAttendeeService, m *metrics.Metrics, auditLog *audit.Service) *Service {
 	return &Service{
 		repo:            repo,
 		attendeeService: attendeeService,
+		metrics:         m,
+		audit:           auditLog,
 	}
 }
 
 // SendInvitation validates and sends an invitation
 func (s *Service) SendInvitation(ctx context.Context, req *SendInvitationRequest, inviterID int) (*Invitation, error) {
+	ctx, span := tracing.Start(ctx, "invitation.Service.SendInvitation")
+	defer span.End()
+
 	// Validate input
 	if err := s.validateSendInvitationRequest(req); err != nil {
 		return nil, err
 	}
 
+	if req.InviteeEmail == "" {
+		return nil, apperror.Validation("invitee_email", "invitee email is required")
+	}
+
+	if err := s.checkEventVisible(ctx, req.EventID); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkEventInvitable(ctx, req.EventID, inviterID, req.Role); err != nil {
+		return nil, err
+	}
+
 	// Check if invitee user exists
 	inviteeID, err := s.repo.GetUserIDByEmail(ctx, req.InviteeEmail)
 	if err != nil {
@@ -52,12 +97,148 @@ func (s *Service) SendInvitation(ctx context.Context, req *SendInvitationRequest
 		return nil, err
 	}
 
+	s.metrics.InvitationSent()
+	s.recordSent(ctx, invitation)
+	slog.InfoContext(ctx, "invitation sent", "invitation_id", invitation.ID, "event_id", invitation.EventID,
+		"invitee_email", logging.Email(invitation.InviteeEmail))
 	return invitation, nil
 }
 
+// InviteGroup expands a group into individual invitations and keeps the link
+// between the group and the event so that late joiners can be invited too.
+// Members that already have an invitation to the event are skipped.
+func (s *Service) InviteGroup(ctx context.Context, req *SendInvitationRequest, inviterID int) (*GroupInvitationResult, error) {
+	ctx, span := tracing.Start(ctx, "invitation.Service.InviteGroup")
+	defer span.End()
+
+	if req.GroupID <= 0 {
+		return nil, apperror.Validation("group_id", "invalid group ID")
+	}
+
+	if err := s.validateSendInvitationRequest(req); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkEventVisible(ctx, req.EventID); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkEventInvitable(ctx, req.EventID, inviterID, req.Role); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkGroupInviter(ctx, req.EventID, inviterID); err != nil {
+		return nil, err
+	}
+
+	emails, err := s.repo.GetGroupEmails(ctx, req.GroupID, inviterID)
+	if err != nil {
+		return nil, err
+	}
+
+	link := &GroupLink{
+		EventID:          req.EventID,
+		GroupID:          req.GroupID,
+		InviterID:        inviterID,
+		Role:             req.Role,
+		Message:          req.Message,
+		InviteNewMembers: req.InviteNewMembers,
+	}
+
+	if err := s.repo.SaveGroupLink(ctx, link); err != nil {
+		return nil, err
+	}
+
+	return s.inviteGroupMembers(ctx, link, emails)
+}
+
+// InviteGroupToEvent invites a group on behalf of the event organizer (see InviteGroup)
+func (s *Service) InviteGroupToEvent(ctx context.Context, eventID, groupID, inviterID int, role string, inviteNewMembers bool) (*GroupInvitationResult, error) {
+	ctx, span := tracing.Start(ctx, "invitation.Service.InviteGroupToEvent")
+	defer span.End()
+
+	return s.InviteGroup(ctx, &SendInvitationRequest{
+		EventID:          eventID,
+		GroupID:          groupID,
+		Role:             role,
+		InviteNewMembers: inviteNewMembers,
+	}, inviterID)
+}
+
+// InviteNewGroupMembers invites members that joined a group to the upcoming
+// events the group was invited to with invite_new_members enabled
+func (s *Service) InviteNewGroupMembers(ctx context.Context, groupID int, emails []string) error {
+	ctx, span := tracing.Start(ctx, "invitation.Service.InviteNewGroupMembers")
+	defer span.End()
+
+	links, err := s.repo.GetLateJoinerLinks(ctx, groupID)
+	if err != nil {
+		return err
+	}
+
+	for i := range links {
+		if _, err := s.inviteGroupMembers(ctx, &links[i], emails); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// inviteGroupMembers sends an invitation per email on behalf of a group link
+func (s *Service) inviteGroupMembers(ctx context.Context, link *GroupLink, emails []string) (*GroupInvitationResult, error) {
+	invited, err := s.repo.GetInvitedEmails(ctx, link.EventID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &GroupInvitationResult{
+		Invitations: []Invitation{},
+		Skipped:     []string{},
+	}
+
+	groupID := link.GroupID
+	for _, email := range emails {
+		if invited[strings.ToLower(email)] {
+			result.Skipped = append(result.Skipped, email)
+			continue
+		}
+
+		inviteeID, err := s.repo.GetUserIDByEmail(ctx, email)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check invitee: %w", err)
+		}
+
+		invitation := &Invitation{
+			EventID:      link.EventID,
+			InviterID:    link.InviterID,
+			InviteeEmail: email,
+			InviteeID:    inviteeID,
+			GroupID:      &groupID,
+			Role:         link.Role,
+			Message:      link.Message,
+			Status:       "pending",
+		}
+
+		if err := s.repo.SendInvitation(ctx, invitation); err != nil {
+			return nil, err
+		}
+		s.metrics.InvitationSent()
+		s.recordSent(ctx, invitation)
+
+		invited[strings.ToLower(email)] = true
+		result.Invitations = append(result.Invitations, *invitation)
+	}
+
+	return result, nil
+}
+
 // GetMyInvitations retrieves all invitations for a user by email
 func (s *Service) GetMyInvitations(ctx context.Context, email string) ([]InvitationWithDetails, error) {
-	invitations, err := s.repo.GetInvitationsByEmail(ctx, email)
+	ctx, span := tracing.Start(ctx, "invitation.Service.GetMyInvitations")
+	defer span.End()
+
+	invitations, err := s.repo.GetInvitationsByEmail(ctx, email, organization.CurrentID(ctx))
 	if err != nil {
 		return nil, err
 	}
@@ -70,8 +251,19 @@ func (s *Service) GetMyInvitations(ctx context.Context, email string) ([]Invitat
 	return invitations, nil
 }
 
-// GetEventInvitations retrieves all invitations for a specific event
-func (s *Service) GetEventInvitations(ctx context.Context, eventID int) ([]InvitationWithDetails, error) {
+// GetEventInvitations retrieves all invitations for an event the user can see
+func (s *Service) GetEventInvitations(ctx context.Context, eventID, userID int) ([]InvitationWithDetails, error) {
+	ctx, span := tracing.Start(ctx, "invitation.Service.GetEventInvitations")
+	defer span.End()
+
+	if err := s.checkEventVisible(ctx, eventID); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkDraftVisible(ctx, eventID, userID); err != nil {
+		return nil, err
+	}
+
 	invitations, err := s.repo.GetInvitationsByEventID(ctx, eventID)
 	if err != nil {
 		return nil, err
@@ -87,37 +279,186 @@ func (s *Service) GetEventInvitations(ctx context.Context, eventID int) ([]Invit
 
 // RespondToInvitation allows a user to accept or decline an invitation
 func (s *Service) RespondToInvitation(ctx context.Context, invitationID int, status string, userEmail string) error {
+	ctx, span := tracing.Start(ctx, "invitation.Service.RespondToInvitation")
+	defer span.End()
+
 	// Validate status
 	if status != "accepted" && status != "declined" {
-		return fmt.Errorf("invalid status: must be 'accepted' or 'declined'")
+		return apperror.Validation("status", "invalid status: must be 'accepted' or 'declined'")
 	}
 
 	// Get the invitation to verify ownership
 	invitation, err := s.repo.GetInvitationByID(ctx, invitationID)
 	if err != nil {
-		return fmt.Errorf("invitation not found: %w", err)
+		return err
 	}
 
 	// Check if the user is the invitee
 	if invitation.InviteeEmail != userEmail {
-		return fmt.Errorf("you are not authorized to respond to this invitation")
+		return ErrNotInvitee
 	}
 
 	// Check if invitation is still pending
 	if invitation.Status != "pending" {
-		return fmt.Errorf("invitation has already been responded to")
+		return ErrAlreadyResponded
+	}
+
+	// Invitations to events in the trash can't be answered, and those to
+	// cancelled or completed events can only be declined
+	if status == "accepted" {
+		if _, err := s.checkEventOpen(ctx, invitation.EventID, invitation.Role); err != nil {
+			return err
+		}
+	} else if _, err := s.repo.GetEventStatus(ctx, invitation.EventID); err != nil {
+		return err
 	}
 
 	// Update invitation status
 	if err := s.repo.UpdateInvitationStatus(ctx, invitationID, status); err != nil {
 		return err
 	}
+	s.metrics.InvitationAnswered(status)
+	s.audit.Record(ctx, audit.Entry{
+		ActorID:    invitation.InviteeID,
+		Action:     audit.ActionInvitationRespond,
+		TargetType: audit.TargetInvitation,
+		TargetID:   invitation.ID,
+		EventID:    &invitation.EventID,
+		Before:     audit.Snapshot(map[string]string{"status": invitation.Status}),
+		After:      audit.Snapshot(map[string]string{"status": status}),
+	})
 
 	// If accepted and we know the user ID, add them to event attendees
 	if status == "accepted" && invitation.InviteeID != nil && s.attendeeService != nil {
 		if err := s.attendeeService.AddAttendee(ctx, invitation.EventID, *invitation.InviteeID, invitation.Role); err != nil {
 			return fmt.Errorf("failed to add invitee as attendee: %w", err)
 		}
+		s.audit.Record(ctx, audit.Entry{
+			ActorID:    invitation.InviteeID,
+			Action:     audit.ActionAttendeeAdd,
+			TargetType: audit.TargetAttendee,
+			TargetID:   *invitation.InviteeID,
+			EventID:    &invitation.EventID,
+			After:      audit.Snapshot(map[string]string{"role": invitation.Role, "status": "going"}),
+		})
+	}
+
+	return nil
+}
+
+// recordSent appends a sent invitation to the audit log. The log is
+// append-only, so erasing the invitee's account could not remove their email:
+// it is recorded masked, and the message is left out.
+func (s *Service) recordSent(ctx context.Context, invitation *Invitation) {
+	s.audit.Record(ctx, audit.Entry{
+		ActorID:    audit.Actor(invitation.InviterID),
+		Action:     audit.ActionInvitationSend,
+		TargetType: audit.TargetInvitation,
+		TargetID:   invitation.ID,
+		EventID:    &invitation.EventID,
+		After: audit.Snapshot(map[string]interface{}{
+			"id":            invitation.ID,
+			"event_id":      invitation.EventID,
+			"invitee_id":    invitation.InviteeID,
+			"invitee_email": logging.Email(invitation.InviteeEmail),
+			"role":          invitation.Role,
+			"status":        invitation.Status,
+		}),
+	})
+}
+
+// checkEventVisible fails for events of another organization than the one in scope
+func (s *Service) checkEventVisible(ctx context.Context, eventID int) error {
+	orgID, visibility, err := s.repo.GetEventVisibility(ctx, eventID)
+	if err != nil {
+		return err
+	}
+
+	if orgID != nil && visibility != "public" {
+		current := organization.CurrentID(ctx)
+		if current == nil || *current != *orgID {
+			return ErrEventNotFound
+		}
+	}
+
+	return nil
+}
+
+// checkDraftVisible hides drafts from everyone but their organizer and
+// collaborators, like event.Service.canSee
+func (s *Service) checkDraftVisible(ctx context.Context, eventID, userID int) error {
+	status, err := s.repo.GetEventStatus(ctx, eventID)
+	if err != nil || status != "draft" {
+		return err
+	}
+
+	collaborator, err := s.repo.IsEventCollaborator(ctx, eventID, userID)
+	if err != nil {
+		return err
+	}
+	if !collaborator {
+		return ErrEventNotFound
+	}
+
+	return nil
+}
+
+// checkEventOpen fails for events that can't be invited to with a role, see
+// event.Event.CheckInvite, and returns the status of the event
+func (s *Service) checkEventOpen(ctx context.Context, eventID int, role string) (string, error) {
+	status, err := s.repo.GetEventStatus(ctx, eventID)
+	if err != nil {
+		return "", err
+	}
+
+	switch status {
+	case "draft":
+		if role == "attendee" {
+			return "", ErrEventDraft
+		}
+	case "cancelled":
+		return "", ErrEventCancelled
+	case "completed":
+		return "", ErrEventCompleted
+	}
+
+	return status, nil
+}
+
+// checkEventInvitable is checkEventOpen for invitations being sent; drafts
+// are not found unless the inviter organizes the event or collaborates on it
+func (s *Service) checkEventInvitable(ctx context.Context, eventID, inviterID int, role string) error {
+	status, err := s.checkEventOpen(ctx, eventID, role)
+	if err != nil || status != "draft" {
+		return err
+	}
+
+	collaborator, err := s.repo.IsEventCollaborator(ctx, eventID, inviterID)
+	if err != nil {
+		return err
+	}
+	if !collaborator {
+		return ErrEventNotFound
+	}
+
+	return nil
+}
+
+// checkGroupInviter checks that the inviter may invite a group, whose link
+// keeps inviting new members: the organizer, or on drafts a collaborator
+// (checkEventInvitable only lets those through)
+func (s *Service) checkGroupInviter(ctx context.Context, eventID, inviterID int) error {
+	organizer, err := s.repo.IsEventOrganizer(ctx, eventID, inviterID)
+	if err != nil || organizer {
+		return err
+	}
+
+	status, err := s.repo.GetEventStatus(ctx, eventID)
+	if err != nil {
+		return err
+	}
+	if status != "draft" {
+		return ErrNotEventCreator
 	}
 
 	return nil
@@ -127,23 +468,27 @@ func (s *Service) RespondToInvitation(ctx context.Context, invitationID int, sta
 
 func (s *Service) validateSendInvitationRequest(req *SendInvitationRequest) error {
 	if req.EventID <= 0 {
-		return fmt.Errorf("invalid event ID")
+		return apperror.Validation("event_id", "invalid event ID")
 	}
 
-	if req.InviteeEmail == "" {
-		return fmt.Errorf("invitee email is required")
+	if req.InviteeEmail == "" && req.GroupID <= 0 {
+		return apperror.Validation("invitee_email", "invitee email or group ID is required")
+	}
+
+	if req.InviteeEmail != "" && req.GroupID > 0 {
+		return apperror.Validation("group_id", "use either invitee email or group ID, not both")
 	}
 
-	if !s.isValidEmail(req.InviteeEmail) {
-		return fmt.Errorf("invalid email format")
+	if req.InviteeEmail != "" && !s.isValidEmail(req.InviteeEmail) {
+		return apperror.Validation("invitee_email", "invalid email format")
 	}
 
 	if req.Role != "attendee" && req.Role != "collaborator" && req.Role != "organizer" {
-		return fmt.Errorf("invalid role: must be 'attendee', 'collaborator', or 'organizer'")
+		return apperror.Validation("role", "invalid role: must be 'attendee', 'collaborator', or 'organizer'")
 	}
 
 	if len(req.Message) > 500 {
-		return fmt.Errorf("message must not exceed 500 characters")
+		return apperror.Validation("message", "message must not exceed 500 characters")
 	}
 
 	return nil
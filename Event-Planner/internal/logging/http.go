@@ -0,0 +1,122 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// quietRoutes are polled by orchestrators and scrapers; their requests are
+// logged at debug level
+var quietRoutes = map[string]bool{"/livez": true, "/readyz": true, "/metrics": true, "/health": true}
+
+type requestKey struct{}
+
+// request collects what is learnt about a request while it is served
+type request struct {
+	userID atomic.Int64
+	err    atomic.Pointer[error]
+}
+
+// SetUserID records the authenticated user of the request, which is added to
+// the lines logged from then on
+func SetUserID(ctx context.Context, userID int) {
+	if req, ok := ctx.Value(requestKey{}).(*request); ok {
+		req.userID.Store(int64(userID))
+	}
+}
+
+// Middleware logs a line for every request with its method, path, route,
+// status, size and latency, and the internal error it failed with (see
+// RecordError). It must be used on the root router after middleware.RequestID.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		req := &request{}
+		ctx := context.WithValue(r.Context(), requestKey{}, req)
+
+		ww := &recorder{WrapResponseWriter: middleware.NewWrapResponseWriter(w, r.ProtoMajor), request: req}
+		next.ServeHTTP(ww, r.WithContext(ctx))
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		level := slog.LevelInfo
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case quietRoutes[chi.RouteContext(r.Context()).RoutePattern()]:
+			level = slog.LevelDebug
+		}
+
+		attrs := []slog.Attr{
+			slog.String("method", r.Method),
+			slog.String("path", r.URL.Path),
+			slog.Int("status", status),
+			slog.Int("bytes", ww.BytesWritten()),
+			slog.Duration("latency", time.Since(start)),
+		}
+		if err := req.err.Load(); err != nil {
+			attrs = append(attrs, Err(*err))
+		}
+		slog.LogAttrs(ctx, level, "request", attrs...)
+	})
+}
+
+// RecordError attaches the internal error a request failed with to its log
+// line; without the middleware it is logged on its own line
+func RecordError(w http.ResponseWriter, err error) {
+	for {
+		if rec, ok := w.(*recorder); ok {
+			rec.request.err.Store(&err)
+			return
+		}
+		wrapper, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			break
+		}
+		w = wrapper.Unwrap()
+	}
+	slog.Error("internal error", Err(err))
+}
+
+// Recoverer turns a panic of a handler into a 500 response; the panic and its
+// stack are logged on the line of the request
+func Recoverer(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			if err, ok := rec.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+				panic(rec)
+			}
+
+			RecordError(w, fmt.Errorf("panic: %v\n%s", rec, debug.Stack()))
+			w.WriteHeader(http.StatusInternalServerError)
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// recorder is the response writer handlers get from Middleware
+type recorder struct {
+	middleware.WrapResponseWriter
+	request *request
+}
+
+func (r *recorder) Unwrap() http.ResponseWriter {
+	return r.WrapResponseWriter
+}
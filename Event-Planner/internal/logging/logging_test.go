@@ -0,0 +1,60 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"event-planner/internal/config"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+func TestRedaction(t *testing.T) {
+	for email, want := range map[string]string{
+		"ada@example.com": "a***@example.com",
+		"a@b.io":          "a***@b.io",
+		"not-an-email":    "***",
+		"@example.com":    "***",
+	} {
+		if got := Email(email); got != want {
+			t.Errorf("Email(%q) = %q, want %q", email, got, want)
+		}
+	}
+
+	err := errors.New(`duplicate key value violates unique constraint "users_email_key": Key (email)=(ada.lovelace@example.com) already exists`)
+	if got := Err(err).Value.String(); got != `duplicate key value violates unique constraint "users_email_key": Key (email)=(a***@example.com) already exists` {
+		t.Errorf("Err = %q", got)
+	}
+}
+
+func TestContextAttributes(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, config.Log{Level: "info", Format: "json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.WithValue(context.Background(), middleware.RequestIDKey, "req-1")
+	req := &request{}
+	ctx = context.WithValue(ctx, requestKey{}, req)
+	SetUserID(ctx, 42)
+
+	logger.DebugContext(ctx, "hidden")
+	logger.InfoContext(ctx, "shown", slog.Int("n", 1))
+
+	var line map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("decode %s: %v", buf.Bytes(), err)
+	}
+	if line["msg"] != "shown" || line["request_id"] != "req-1" || line["user_id"] != float64(42) || line["n"] != float64(1) {
+		t.Errorf("line = %v", line)
+	}
+
+	if _, err := New(&buf, config.Log{Level: "verbose", Format: "json"}); err == nil {
+		t.Error("New accepted an unknown level")
+	}
+}
@@ -0,0 +1,365 @@
+package memstore
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"event-planner/internal/account"
+	"event-planner/internal/event"
+	"event-planner/internal/invitation"
+	"event-planner/internal/organization"
+	"event-planner/internal/user"
+)
+
+var _ account.Store = (*Accounts)(nil)
+
+// Accounts stores account deletions and reads the data export
+type Accounts struct {
+	s *Store
+}
+
+// GetUser retrieves an account that has not been erased
+func (r *Accounts) GetUser(ctx context.Context, userID int) (*user.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	u, ok := r.s.users[userID]
+	if !ok || r.s.erased(userID) {
+		return nil, notFound(account.ErrUserNotFound)
+	}
+	found := *u
+	found.PasswordHash = ""
+	return &found, nil
+}
+
+// GetDeletionStatus retrieves the pending deletion of an account, if any
+func (r *Accounts) GetDeletionStatus(ctx context.Context, userID int) (*account.DeletionStatus, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.users[userID]; !ok || r.s.erased(userID) {
+		return nil, notFound(account.ErrUserNotFound)
+	}
+
+	status := &account.DeletionStatus{}
+	if d, ok := r.s.deletions[userID]; ok {
+		status.RequestedAt, status.ScheduledFor = d.requestedAt, d.scheduledFor
+	}
+	status.Pending = status.ScheduledFor != nil
+	return status, nil
+}
+
+// ScheduleDeletion marks an account for erasure at scheduledFor
+func (r *Accounts) ScheduleDeletion(ctx context.Context, userID int, scheduledFor time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.users[userID]; !ok || r.s.erased(userID) {
+		return account.ErrUserNotFound
+	}
+
+	requestedAt, scheduled := now(), timestamp(scheduledFor)
+	d := r.s.deletion(userID)
+	d.requestedAt, d.scheduledFor = &requestedAt, &scheduled
+	return nil
+}
+
+// CancelDeletion clears a pending deletion
+func (r *Accounts) CancelDeletion(ctx context.Context, userID int) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	d, ok := r.s.deletions[userID]
+	if !ok || d.deletedAt != nil || d.scheduledFor == nil {
+		return false, nil
+	}
+	d.requestedAt, d.scheduledFor = nil, nil
+	return true, nil
+}
+
+// GetDueDeletions returns the accounts whose grace period ended before now
+func (r *Accounts) GetDueDeletions(ctx context.Context, now time.Time) ([]int, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var ids []int
+	for userID, d := range r.s.deletions {
+		if d.deletedAt == nil && d.scheduledFor != nil && !d.scheduledFor.After(now) {
+			ids = append(ids, userID)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		a, b := r.s.deletions[ids[i]].scheduledFor, r.s.deletions[ids[j]].scheduledFor
+		if !a.Equal(*b) {
+			return a.Before(*b)
+		}
+		return ids[i] < ids[j]
+	})
+	return ids, nil
+}
+
+// EraseUser removes the personal data of an account like the repository does
+// in its transaction, keeping the user as a pseudonymous tombstone
+func (r *Accounts) EraseUser(ctx context.Context, userID int) (*account.ErasureResult, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	u, ok := r.s.users[userID]
+	if !ok || r.s.erased(userID) {
+		return nil, notFound(account.ErrUserNotFound)
+	}
+	email := u.Email
+
+	result := &account.ErasureResult{UserID: userID, TransferredEvents: []int{}, ArchivedEvents: []int{}}
+
+	// Hand organized events over to the longest-standing co-organizer, or else
+	// collaborator; events in the trash stay with the account until they are purged
+	for _, ev := range r.s.events {
+		if ev.OrganizerID != userID || ev.DeletedAt != nil {
+			continue
+		}
+		if successor := r.s.successor(ev.ID, userID); successor != nil {
+			ev.OrganizerID = successor.userID
+			ev.Version++
+			successor.role = "organizer"
+			result.TransferredEvents = append(result.TransferredEvents, ev.ID)
+		}
+	}
+
+	// Events nobody can take over stay available read-only
+	for _, ev := range r.s.events {
+		if ev.OrganizerID == userID && ev.ArchivedAt == nil {
+			archivedAt := now()
+			ev.ArchivedAt = &archivedAt
+			ev.Version++
+			result.ArchivedEvents = append(result.ArchivedEvents, ev.ID)
+		}
+	}
+	sort.Ints(result.TransferredEvents)
+	sort.Ints(result.ArchivedEvents)
+
+	// Organizations the user solely owns get a new owner, preferring admins
+	for key, m := range r.s.orgMembers {
+		if key[1] != userID || m.role != organization.RoleOwner || r.s.otherOwner(key[0], userID) {
+			continue
+		}
+		if successor := r.s.orgSuccessor(key[0], userID); successor != nil {
+			successor.role = organization.RoleOwner
+		}
+	}
+
+	for key := range r.s.orgMembers {
+		if key[1] == userID {
+			delete(r.s.orgMembers, key)
+		}
+	}
+	for id, a := range r.s.attendees {
+		if a.userID == userID {
+			delete(r.s.attendees, id)
+		}
+	}
+	pseudo := fmt.Sprintf("deleted-user-%d@deleted.invalid", userID)
+	for _, inv := range r.s.invitations {
+		if (inv.InviteeID != nil && *inv.InviteeID == userID) || strings.EqualFold(inv.InviteeEmail, email) {
+			inv.InviteeEmail = pseudo
+		}
+	}
+	for id, m := range r.s.groupMembers {
+		if strings.EqualFold(m.email, email) {
+			delete(r.s.groupMembers, id)
+		}
+	}
+	for id, n := range r.s.notifications {
+		if strings.EqualFold(n.recipient, email) {
+			delete(r.s.notifications, id)
+		}
+	}
+	for id, g := range r.s.groups {
+		if g.ownerID == userID {
+			r.s.deleteGroup(id)
+		}
+	}
+	delete(r.s.profiles, userID)
+
+	deletedAt := now()
+	u.Email, u.PasswordHash = pseudo, ""
+	if u.DisabledAt == nil {
+		u.DisabledAt = &deletedAt
+	}
+	r.s.deletions[userID] = &deletion{deletedAt: &deletedAt}
+
+	return result, nil
+}
+
+// GetOrganizedEvents retrieves every event organized by a user, regardless of organization
+func (r *Accounts) GetOrganizedEvents(ctx context.Context, userID int) ([]event.Event, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	events := []event.Event{}
+	for _, ev := range r.s.events {
+		if ev.OrganizerID == userID {
+			events = append(events, *ev)
+		}
+	}
+	sortChronologically(events, func(e event.Event) *event.Event { return &e })
+	return events, nil
+}
+
+// GetRSVPs retrieves every event a user is attending, with role and status
+func (r *Accounts) GetRSVPs(ctx context.Context, userID int) ([]event.EventWithAttendeeInfo, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	rsvps := []event.EventWithAttendeeInfo{}
+	for _, a := range r.s.attendees {
+		if a.userID == userID {
+			rsvps = append(rsvps, event.EventWithAttendeeInfo{Event: *r.s.events[a.eventID], Role: a.role, Status: a.status})
+		}
+	}
+	sortChronologically(rsvps, func(e event.EventWithAttendeeInfo) *event.Event { return &e.Event })
+	return rsvps, nil
+}
+
+// GetInvitations retrieves the invitations a user received (by ID or email) and sent
+func (r *Accounts) GetInvitations(ctx context.Context, userID int, email string) ([]invitation.Invitation, []invitation.Invitation, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var all []invitation.Invitation
+	for _, inv := range r.s.invitations {
+		if inv.InviterID == userID || (inv.InviteeID != nil && *inv.InviteeID == userID) || strings.EqualFold(inv.InviteeEmail, email) {
+			all = append(all, *inv)
+		}
+	}
+	sort.Slice(all, func(i, j int) bool {
+		if !all[i].CreatedAt.Equal(all[j].CreatedAt) {
+			return all[i].CreatedAt.Before(all[j].CreatedAt)
+		}
+		return all[i].ID < all[j].ID
+	})
+
+	received := []invitation.Invitation{}
+	sent := []invitation.Invitation{}
+	for _, inv := range all {
+		if inv.InviterID == userID {
+			sent = append(sent, inv)
+		} else {
+			received = append(received, inv)
+		}
+	}
+	return received, sent, nil
+}
+
+// GetGroups retrieves the groups owned by a user with their member emails
+func (r *Accounts) GetGroups(ctx context.Context, userID int) ([]account.ExportGroup, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	groups := []account.ExportGroup{}
+	for _, g := range r.s.groups {
+		if g.ownerID != userID {
+			continue
+		}
+		exported := account.ExportGroup{ID: g.id, Name: g.name, Description: g.description, Members: []string{}, CreatedAt: g.createdAt}
+		for _, m := range r.s.membersOf(g.id) {
+			exported.Members = append(exported.Members, m.email)
+		}
+		groups = append(groups, exported)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if !groups[i].CreatedAt.Equal(groups[j].CreatedAt) {
+			return groups[i].CreatedAt.Before(groups[j].CreatedAt)
+		}
+		return groups[i].ID < groups[j].ID
+	})
+	return groups, nil
+}
+
+// deletion returns the deletion columns of a user, creating them when unset
+func (s *Store) deletion(userID int) *deletion {
+	d, ok := s.deletions[userID]
+	if !ok {
+		d = &deletion{}
+		s.deletions[userID] = d
+	}
+	return d
+}
+
+// successor picks who takes over an event from an erased organizer: the
+// longest-standing other organizer, or else collaborator, that was not erased
+func (s *Store) successor(eventID, userID int) *attendee {
+	var candidates []*attendee
+	for _, a := range s.attendees {
+		if a.eventID == eventID && a.userID != userID && !s.erased(a.userID) &&
+			(a.role == "organizer" || a.role == "collaborator") {
+			candidates = append(candidates, a)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if (a.role == "organizer") != (b.role == "organizer") {
+			return a.role == "organizer"
+		}
+		if !a.createdAt.Equal(b.createdAt) {
+			return a.createdAt.Before(b.createdAt)
+		}
+		return a.id < b.id
+	})
+	if len(candidates) == 0 {
+		return nil
+	}
+	return candidates[0]
+}
+
+// otherOwner reports whether an organization has an owner besides userID
+func (s *Store) otherOwner(orgID, userID int) bool {
+	for key, m := range s.orgMembers {
+		if key[0] == orgID && key[1] != userID && m.role == organization.RoleOwner {
+			return true
+		}
+	}
+	return false
+}
+
+// orgSuccessor picks who takes over an organization from its erased sole
+// owner: the longest-standing admin, or else member
+func (s *Store) orgSuccessor(orgID, userID int) *orgMember {
+	var candidates []*orgMember
+	for key, m := range s.orgMembers {
+		if key[0] == orgID && key[1] != userID {
+			candidates = append(candidates, m)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if (a.role == organization.RoleAdmin) != (b.role == organization.RoleAdmin) {
+			return a.role == organization.RoleAdmin
+		}
+		if !a.createdAt.Equal(b.createdAt) {
+			return a.createdAt.Before(b.createdAt)
+		}
+		return a.userID < b.userID
+	})
+	if len(candidates) == 0 {
+		return nil
+	}
+	return candidates[0]
+}
+
+// sortChronologically orders events by ascending date, time, then ID
+func sortChronologically[T any](items []T, ev func(T) *event.Event) {
+	sort.Slice(items, func(i, j int) bool {
+		a, b := ev(items[i]), ev(items[j])
+		if !a.Date.Equal(b.Date) {
+			return a.Date.Before(b.Date)
+		}
+		if !a.Time.Equal(b.Time) {
+			return a.Time.Before(b.Time)
+		}
+		return a.ID < b.ID
+	})
+}
@@ -0,0 +1,146 @@
+package memstore
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"event-planner/internal/admin"
+	"event-planner/internal/user"
+)
+
+var _ admin.Store = (*Admin)(nil)
+
+// Admin stores system roles, disabled accounts and the admin action log
+type Admin struct {
+	s *Store
+}
+
+// ListUsers retrieves users matching the filter, newest first
+func (r *Admin) ListUsers(ctx context.Context, f *admin.UsersFilter) ([]user.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var users []user.User
+	for _, u := range r.s.users {
+		if f.Query != "" && !strings.Contains(strings.ToLower(u.Email), strings.ToLower(f.Query)) {
+			continue
+		}
+		if f.Role != "" && u.Role != f.Role {
+			continue
+		}
+		if f.Disabled != nil && *f.Disabled != (u.DisabledAt != nil) {
+			continue
+		}
+		found := *u
+		found.PasswordHash = ""
+		users = append(users, found)
+	}
+	sort.Slice(users, func(i, j int) bool {
+		if !users[i].CreatedAt.Equal(users[j].CreatedAt) {
+			return users[i].CreatedAt.After(users[j].CreatedAt)
+		}
+		return users[i].ID > users[j].ID
+	})
+	return pageOf(users, f.Limit, f.Offset), nil
+}
+
+// GetUserByID retrieves a single user by ID
+func (r *Admin) GetUserByID(ctx context.Context, userID int) (*user.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	u, ok := r.s.users[userID]
+	if !ok {
+		return nil, notFound(admin.ErrUserNotFound)
+	}
+	found := *u
+	found.PasswordHash = ""
+	return &found, nil
+}
+
+// SetDisabled disables or re-enables a user account
+func (r *Admin) SetDisabled(ctx context.Context, userID int, disabled bool) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	// Erased accounts stay disabled
+	u, ok := r.s.users[userID]
+	if !ok || r.s.erased(userID) {
+		return admin.ErrUserNotFound
+	}
+
+	switch {
+	case !disabled:
+		u.DisabledAt = nil
+	case u.DisabledAt == nil:
+		disabledAt := now()
+		u.DisabledAt = &disabledAt
+	}
+	return nil
+}
+
+// SetRole changes the system role of a user
+func (r *Admin) SetRole(ctx context.Context, userID int, role string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	u, ok := r.s.users[userID]
+	if !ok {
+		return admin.ErrUserNotFound
+	}
+	if err := checkIn("users", "role", role, user.RoleAdmin, user.RoleSupport, user.RoleMember); err != nil {
+		return err
+	}
+	u.Role = role
+	return nil
+}
+
+// RecordAction stores an administrative action
+func (r *Admin) RecordAction(ctx context.Context, action *admin.Action) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if err := r.s.userExists("admin_actions", "actor_id", action.ActorID); err != nil {
+		return err
+	}
+
+	stored := *action
+	if stored.Details == nil {
+		stored.Details = json.RawMessage(`{}`)
+	}
+	stored.ID = r.s.nextID("admin_actions")
+	stored.CreatedAt = now()
+	r.s.adminActions = append(r.s.adminActions, stored)
+
+	action.ID, action.CreatedAt = stored.ID, stored.CreatedAt
+	return nil
+}
+
+// ListActions retrieves recorded admin actions matching the filter, newest first
+func (r *Admin) ListActions(ctx context.Context, f *admin.ActionsFilter) ([]admin.Action, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var actions []admin.Action
+	for _, a := range r.s.adminActions {
+		if f.ActorID > 0 && a.ActorID != f.ActorID {
+			continue
+		}
+		if f.TargetType != "" && a.TargetType != f.TargetType {
+			continue
+		}
+		if f.TargetID > 0 && a.TargetID != f.TargetID {
+			continue
+		}
+		actions = append(actions, a)
+	}
+	sort.Slice(actions, func(i, j int) bool {
+		if !actions[i].CreatedAt.Equal(actions[j].CreatedAt) {
+			return actions[i].CreatedAt.After(actions[j].CreatedAt)
+		}
+		return actions[i].ID > actions[j].ID
+	})
+	return pageOf(actions, f.Limit, f.Offset), nil
+}
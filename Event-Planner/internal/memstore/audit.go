@@ -0,0 +1,71 @@
+package memstore
+
+import (
+	"context"
+	"encoding/json"
+
+	"event-planner/internal/audit"
+)
+
+var _ audit.Store = (*Audit)(nil)
+
+// Audit stores the audit log
+type Audit struct {
+	s *Store
+}
+
+// Append stores an entry and sets its ID and creation time
+func (r *Audit) Append(ctx context.Context, e *audit.Entry) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	e.ID = int64(r.s.nextID("audit_log"))
+	e.CreatedAt = now()
+
+	stored := *e
+	stored.Before = cloneJSON(e.Before)
+	stored.After = cloneJSON(e.After)
+	r.s.auditLog = append(r.s.auditLog, stored)
+	return nil
+}
+
+// List retrieves the entries matching the filter, newest first
+func (r *Audit) List(ctx context.Context, f *audit.Filter) ([]audit.Entry, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var entries []audit.Entry
+	for i := len(r.s.auditLog) - 1; i >= 0; i-- {
+		e := r.s.auditLog[i]
+		switch {
+		case f.ActorID > 0 && (e.ActorID == nil || *e.ActorID != f.ActorID):
+		case f.Action != "" && e.Action != f.Action:
+		case f.TargetType != "" && e.TargetType != f.TargetType:
+		case f.TargetID > 0 && e.TargetID != f.TargetID:
+		case f.EventID > 0 && (e.EventID == nil || *e.EventID != f.EventID):
+		case f.OrganizerID > 0 && !r.organizes(f.OrganizerID, e.EventID):
+		default:
+			e.Before = cloneJSON(e.Before)
+			e.After = cloneJSON(e.After)
+			entries = append(entries, e)
+		}
+	}
+
+	return pageOf(entries, f.Limit, f.Offset), nil
+}
+
+// organizes reports whether the user organizes the event
+func (r *Audit) organizes(userID int, eventID *int) bool {
+	if eventID == nil {
+		return false
+	}
+	ev, ok := r.s.events[*eventID]
+	return ok && ev.OrganizerID == userID
+}
+
+func cloneJSON(raw json.RawMessage) json.RawMessage {
+	if raw == nil {
+		return nil
+	}
+	return append(json.RawMessage{}, raw...)
+}
@@ -0,0 +1,720 @@
+package memstore
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"event-planner/internal/apperror"
+	"event-planner/internal/event"
+	"event-planner/internal/notification"
+)
+
+var _ event.Store = (*Events)(nil)
+
+// Events stores events and their attendees
+type Events struct {
+	s *Store
+}
+
+// CreateEvent inserts a new event with its first revision and sets its ID and
+// creation time; an empty status is event.StatusScheduled
+func (r *Events) CreateEvent(ctx context.Context, ev *event.Event) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if ev.Status == "" {
+		ev.Status = event.StatusScheduled
+	}
+
+	stored := normalize(*ev)
+	if err := r.s.checkEvent(&stored); err != nil {
+		return err
+	}
+
+	stored.ID = r.s.nextID("events")
+	stored.CreatedAt = now()
+	stored.Version = 1
+	r.s.events[stored.ID] = &stored
+	r.s.recordRevision(&stored, nil, stored.OrganizerID, nil)
+
+	ev.ID, ev.CreatedAt, ev.Version = stored.ID, stored.CreatedAt, stored.Version
+	return nil
+}
+
+// GetEventByID retrieves a single event by ID; events in the trash are not found
+func (r *Events) GetEventByID(ctx context.Context, eventID int) (*event.Event, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	ev, ok := r.s.event(eventID)
+	if !ok {
+		return nil, notFound(event.ErrEventNotFound)
+	}
+	found := *ev
+	return &found, nil
+}
+
+// GetAllEvents retrieves a page of the events of an organization, or when orgID
+// is nil the personal events plus public events of any organization; drafts
+// are left out
+func (r *Events) GetAllEvents(ctx context.Context, orgID *int, page event.Page) ([]event.Event, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	events := r.s.listEvents(func(ev *event.Event) bool {
+		return ev.Status != event.StatusDraft && inListScope(ev, orgID)
+	})
+	return pageOf(events, page.Limit, page.Offset), nil
+}
+
+// GetEventsByOrganizerID retrieves all events created by a specific user within a scope, but drafts (see GetAllEvents)
+func (r *Events) GetEventsByOrganizerID(ctx context.Context, organizerID int, orgID *int) ([]event.Event, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	return r.s.listEvents(func(ev *event.Event) bool {
+		return ev.OrganizerID == organizerID && ev.Status != event.StatusDraft && inListScope(ev, orgID)
+	}), nil
+}
+
+// UpdateEvent applies the non-empty fields of updates to an event at the given
+// version (or AnyVersion) and records the revision
+func (r *Events) UpdateEvent(ctx context.Context, eventID int, updates *event.UpdateEventRequest, editorID, version int) (*event.Event, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	current, ok := r.s.event(eventID)
+	if !ok {
+		return nil, notFound(event.ErrEventNotFound)
+	}
+	if err := current.CheckVersion(version); err != nil {
+		return nil, err
+	}
+	updated := *current
+
+	if updates.Title != "" {
+		updated.Title = updates.Title
+	}
+	if updates.Description != "" {
+		updated.Description = updates.Description
+	}
+	if updates.Date != "" {
+		eventDate, err := time.Parse("2006-01-02", updates.Date)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date format: %w", err)
+		}
+		updated.Date = eventDate
+	}
+	if updates.Time != "" {
+		eventTime, err := time.Parse("15:04:05", updates.Time)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time format: %w", err)
+		}
+		updated.Time = eventTime
+	}
+	if updates.Location != "" {
+		updated.Location = updates.Location
+	}
+	if updates.Visibility != "" {
+		updated.Visibility = updates.Visibility
+	}
+
+	updated = normalize(updated)
+	if err := r.s.checkEvent(&updated); err != nil {
+		return nil, err
+	}
+
+	return r.s.saveEvent(current, updated, editorID, nil), nil
+}
+
+// PatchEvent merges a patch into an event at the given version (or
+// AnyVersion) and records the revision
+func (r *Events) PatchEvent(ctx context.Context, eventID int, patch *event.EventPatch, editorID, version int) (*event.Event, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	current, ok := r.s.event(eventID)
+	if !ok {
+		return nil, notFound(event.ErrEventNotFound)
+	}
+	if err := current.CheckVersion(version); err != nil {
+		return nil, err
+	}
+
+	updated := *current
+	if err := patch.Apply(&updated); err != nil {
+		return nil, err
+	}
+	updated = normalize(updated)
+	if err := r.s.checkEvent(&updated); err != nil {
+		return nil, err
+	}
+
+	return r.s.saveEvent(current, updated, editorID, nil), nil
+}
+
+// RestoreRevision sets the editable fields of an event back to those of one
+// of its revisions, which is recorded as a new revision
+func (r *Events) RestoreRevision(ctx context.Context, eventID, revision, editorID int) (*event.Event, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	current, ok := r.s.event(eventID)
+	if !ok {
+		return nil, notFound(event.ErrEventNotFound)
+	}
+
+	rev, err := r.s.revision(eventID, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := *current
+	rev.Apply(&updated)
+	updated = normalize(updated)
+	if err := r.s.checkEvent(&updated); err != nil {
+		return nil, err
+	}
+
+	return r.s.saveEvent(current, updated, editorID, &revision), nil
+}
+
+// GetRevisions retrieves the revisions of an event, newest first
+func (r *Events) GetRevisions(ctx context.Context, eventID int) ([]event.Revision, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var revisions []event.Revision
+	stored := r.s.revisions[eventID]
+	for i := len(stored) - 1; i >= 0; i-- {
+		revisions = append(revisions, cloneRevision(stored[i]))
+	}
+	return revisions, nil
+}
+
+// GetRevision retrieves a single revision of an event
+func (r *Events) GetRevision(ctx context.Context, eventID, revision int) (*event.Revision, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	return r.s.revision(eventID, revision)
+}
+
+// TrashEvent moves an event at the given version (or AnyVersion) to its
+// organizer's trash; its attendees, invitations and revisions are kept
+func (r *Events) TrashEvent(ctx context.Context, eventID, version int) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	current, ok := r.s.event(eventID)
+	if !ok {
+		return event.ErrEventNotFound
+	}
+	if err := current.CheckVersion(version); err != nil {
+		return err
+	}
+
+	deletedAt := now()
+	current.DeletedAt = &deletedAt
+	return nil
+}
+
+// GetTrashedEvents retrieves the events in an organizer's trash, most recently deleted first
+func (r *Events) GetTrashedEvents(ctx context.Context, organizerID int) ([]event.Event, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var events []event.Event
+	for _, ev := range r.s.events {
+		if ev.OrganizerID == organizerID && ev.DeletedAt != nil {
+			events = append(events, *ev)
+		}
+	}
+	sort.Slice(events, func(i, j int) bool {
+		a, b := events[i], events[j]
+		if !a.DeletedAt.Equal(*b.DeletedAt) {
+			return a.DeletedAt.After(*b.DeletedAt)
+		}
+		return a.ID > b.ID
+	})
+	return events, nil
+}
+
+// RestoreEvent takes an event out of its organizer's trash. Events that are
+// not in the trash of that organizer are not found.
+func (r *Events) RestoreEvent(ctx context.Context, eventID, organizerID int) (*event.Event, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	ev, ok := r.s.events[eventID]
+	if !ok || ev.OrganizerID != organizerID || ev.DeletedAt == nil {
+		return nil, notFound(event.ErrTrashedEventNotFound)
+	}
+
+	ev.DeletedAt = nil
+	restored := *ev
+	return &restored, nil
+}
+
+// PurgeTrashedEvents erases the events deleted before a time together with
+// their attendees, invitations and group links, and returns them by ID
+func (r *Events) PurgeTrashedEvents(ctx context.Context, deletedBefore time.Time) ([]event.Event, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var purged []event.Event
+	for id, ev := range r.s.events {
+		if ev.DeletedAt != nil && ev.DeletedAt.Before(deletedBefore) {
+			purged = append(purged, *ev)
+			r.s.deleteEvent(id)
+		}
+	}
+	sort.Slice(purged, func(i, j int) bool { return purged[i].ID < purged[j].ID })
+	return purged, nil
+}
+
+// DeleteEvent erases an event at the given version (or AnyVersion) at once,
+// bypassing the trash, together with its attendees, invitations and group links
+func (r *Events) DeleteEvent(ctx context.Context, eventID, version int) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	current, ok := r.s.event(eventID)
+	if !ok {
+		return event.ErrEventNotFound
+	}
+	if err := current.CheckVersion(version); err != nil {
+		return err
+	}
+
+	r.s.deleteEvent(eventID)
+	return nil
+}
+
+// ChangeStatus gives an event at the given version (or AnyVersion) a new
+// status and reason, and notifies its attendees other than the actor and its
+// pending invitees
+func (r *Events) ChangeStatus(ctx context.Context, eventID int, status, reason string, actorID, version int) (*event.Event, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	current, ok := r.s.event(eventID)
+	if !ok {
+		return nil, notFound(event.ErrEventNotFound)
+	}
+	if err := current.CheckVersion(version); err != nil {
+		return nil, err
+	}
+	if err := event.CheckTransition(current.Status, status); err != nil {
+		return nil, err
+	}
+
+	current.Status, current.StatusReason = status, reason
+	current.Version++
+	r.s.notify(current, actorID)
+
+	changed := *current
+	return &changed, nil
+}
+
+// CompleteEvents marks the scheduled events that started before a time as
+// completed and returns them by ID
+func (r *Events) CompleteEvents(ctx context.Context, startedBefore time.Time) ([]event.Event, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var completed []event.Event
+	for _, ev := range r.s.events {
+		if ev.DeletedAt == nil && ev.Status == event.StatusScheduled && ev.StartsAt().Before(startedBefore) {
+			ev.Status = event.StatusCompleted
+			ev.Version++
+			completed = append(completed, *ev)
+		}
+	}
+	sort.Slice(completed, func(i, j int) bool { return completed[i].ID < completed[j].ID })
+	return completed, nil
+}
+
+// PublishEvent publishes a draft at the given version (or AnyVersion), or
+// when publishAt is not nil sets the time the status worker publishes it
+func (r *Events) PublishEvent(ctx context.Context, eventID int, publishAt *time.Time, version int) (*event.Event, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	current, ok := r.s.event(eventID)
+	if !ok {
+		return nil, notFound(event.ErrEventNotFound)
+	}
+	if err := current.CheckVersion(version); err != nil {
+		return nil, err
+	}
+	if current.Status != event.StatusDraft {
+		return nil, event.ErrEventPublished
+	}
+
+	current.PublishAt = publishAt
+	if publishAt == nil {
+		current.Status = event.StatusScheduled
+	}
+	current.Version++
+
+	published := *current
+	return &published, nil
+}
+
+// PublishDueEvents publishes the drafts to be published before a time and
+// returns them by ID; drafts in the trash wait until they are restored
+func (r *Events) PublishDueEvents(ctx context.Context, dueBefore time.Time) ([]event.Event, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var published []event.Event
+	for _, ev := range r.s.events {
+		if ev.DeletedAt == nil && ev.Status == event.StatusDraft && ev.PublishAt != nil && !ev.PublishAt.After(dueBefore) {
+			ev.Status, ev.PublishAt = event.StatusScheduled, nil
+			ev.Version++
+			published = append(published, *ev)
+		}
+	}
+	sort.Slice(published, func(i, j int) bool { return published[i].ID < published[j].ID })
+	return published, nil
+}
+
+// JoinEvent adds a user as an attendee to an event; joining twice is a conflict
+func (r *Events) JoinEvent(ctx context.Context, userID, eventID int) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if err := r.s.checkAttendeeRefs(userID, eventID); err != nil {
+		return err
+	}
+	if r.s.attendance(userID, eventID) != nil {
+		return uniqueViolation("event_attendees", "event_attendees_user_id_event_id_key", "user_id, event_id", fmt.Sprintf("%d, %d", userID, eventID))
+	}
+
+	r.s.addAttendance(userID, eventID, "attendee")
+	return nil
+}
+
+// GetEventsByAttendeeID retrieves all events where the user is an attendee (including as organizer).
+// A non-nil orgID restricts the result to that organization's events.
+func (r *Events) GetEventsByAttendeeID(ctx context.Context, userID int, orgID *int) ([]event.EventWithAttendeeInfo, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var events []event.EventWithAttendeeInfo
+	for _, a := range r.s.attendees {
+		ev := r.s.events[a.eventID]
+		if a.userID != userID || ev.DeletedAt != nil || !inOrganization(ev, orgID) {
+			continue
+		}
+		events = append(events, event.EventWithAttendeeInfo{Event: *ev, Role: a.role, Status: a.status})
+	}
+
+	sortEvents(events, func(e event.EventWithAttendeeInfo) *event.Event { return &e.Event }, true)
+	return events, nil
+}
+
+// GetMyOrganizedEvents retrieves all events organized by a specific user.
+// A non-nil orgID restricts the result to that organization's events.
+func (r *Events) GetMyOrganizedEvents(ctx context.Context, organizerID int, orgID *int) ([]event.Event, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	return r.s.listEvents(func(ev *event.Event) bool {
+		return ev.OrganizerID == organizerID && inOrganization(ev, orgID)
+	}), nil
+}
+
+// AddOrganizerAsAttendee adds the organizer of a new event as an attendee
+func (r *Events) AddOrganizerAsAttendee(ctx context.Context, userID, eventID int) error {
+	return r.AddAttendee(ctx, eventID, userID, "organizer")
+}
+
+// AddAttendee adds a user to an event, or changes their role when they already attend
+func (r *Events) AddAttendee(ctx context.Context, eventID, userID int, role string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if err := checkIn("event_attendees", "role", role, "organizer", "attendee", "collaborator"); err != nil {
+		return err
+	}
+	if err := r.s.checkAttendeeRefs(userID, eventID); err != nil {
+		return err
+	}
+
+	if a := r.s.attendance(userID, eventID); a != nil {
+		a.role = role
+		return nil
+	}
+	r.s.addAttendance(userID, eventID, role)
+	return nil
+}
+
+// UpdateAttendanceStatus updates a user's attendance status for an event
+func (r *Events) UpdateAttendanceStatus(ctx context.Context, userID, eventID int, status string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	a := r.s.attendance(userID, eventID)
+	if a == nil {
+		return apperror.NotFound("attendance_not_found", "attendance record not found")
+	}
+	if err := checkIn("event_attendees", "status", status, "going", "maybe", "not_going"); err != nil {
+		return err
+	}
+
+	a.status = status
+	return nil
+}
+
+// GetEventAttendees retrieves all attendees for an event, newest first
+func (r *Events) GetEventAttendees(ctx context.Context, eventID int) ([]event.EventAttendee, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var rows []*attendee
+	for _, a := range r.s.attendees {
+		if a.eventID == eventID {
+			rows = append(rows, a)
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if !rows[i].createdAt.Equal(rows[j].createdAt) {
+			return rows[i].createdAt.After(rows[j].createdAt)
+		}
+		return rows[i].id > rows[j].id
+	})
+
+	var attendees []event.EventAttendee
+	for _, a := range rows {
+		attendees = append(attendees, event.EventAttendee{
+			ID:        a.id,
+			UserID:    a.userID,
+			EventID:   a.eventID,
+			Role:      a.role,
+			Status:    a.status,
+			CreatedAt: a.createdAt,
+			User:      r.s.publicProfile(a.userID),
+		})
+	}
+	return attendees, nil
+}
+
+// event looks up an event that is not in the trash
+func (s *Store) event(eventID int) (*event.Event, bool) {
+	ev, ok := s.events[eventID]
+	if !ok || ev.DeletedAt != nil {
+		return nil, false
+	}
+	return ev, true
+}
+
+// deleteEvent removes an event and cascades like the foreign keys to it do
+func (s *Store) deleteEvent(eventID int) {
+	delete(s.events, eventID)
+	delete(s.revisions, eventID)
+	for id, a := range s.attendees {
+		if a.eventID == eventID {
+			delete(s.attendees, id)
+		}
+	}
+	for id, inv := range s.invitations {
+		if inv.EventID == eventID {
+			delete(s.invitations, id)
+		}
+	}
+	for key := range s.groupLinks {
+		if key[0] == eventID {
+			delete(s.groupLinks, key)
+		}
+	}
+	for id, n := range s.notifications {
+		if n.EventID == eventID {
+			delete(s.notifications, id)
+		}
+	}
+}
+
+// notify sends a notification of the status of an event to its attendees
+// other than the actor and its pending invitees, once per email
+func (s *Store) notify(ev *event.Event, actorID int) {
+	recipients := map[string]bool{}
+	for _, a := range s.attendees {
+		if a.eventID == ev.ID && a.userID != actorID {
+			recipients[s.users[a.userID].Email] = true
+		}
+	}
+	for _, inv := range s.invitations {
+		if inv.EventID == ev.ID && inv.Status == "pending" {
+			recipients[inv.InviteeEmail] = true
+		}
+	}
+
+	emails := make([]string, 0, len(recipients))
+	for email := range recipients {
+		emails = append(emails, email)
+	}
+	sort.Strings(emails)
+
+	for _, email := range emails {
+		id := s.nextID("notifications")
+		s.notifications[id] = &sentNotification{
+			recipient: email,
+			Notification: notification.Notification{
+				ID:          id,
+				EventID:     ev.ID,
+				EventStatus: ev.Status,
+				EventTitle:  ev.Title,
+				Reason:      ev.StatusReason,
+				CreatedAt:   now(),
+			},
+		}
+	}
+}
+
+// checkEvent enforces the constraints of the events table; like PostgreSQL
+// it checks CHECK constraints by name before the foreign keys
+func (s *Store) checkEvent(ev *event.Event) error {
+	if ev.OrganizationID == nil && ev.Visibility != event.VisibilityPublic {
+		return checkViolation("events", "events_check")
+	}
+	if err := checkIn("events", "visibility", ev.Visibility, event.VisibilityPublic, event.VisibilityOrganization); err != nil {
+		return err
+	}
+	if err := checkIn("events", "status", ev.Status, event.StatusDraft, event.StatusScheduled, event.StatusPostponed,
+		event.StatusCancelled, event.StatusCompleted); err != nil {
+		return err
+	}
+	if ev.PublishAt != nil && ev.Status != event.StatusDraft {
+		return checkViolation("events", "events_publish_at_check")
+	}
+	if err := s.userExists("events", "organizer_id", ev.OrganizerID); err != nil {
+		return err
+	}
+	if ev.OrganizationID != nil {
+		if _, ok := s.organizations[*ev.OrganizationID]; !ok {
+			return foreignKeyViolation("events", "organization_id", *ev.OrganizationID, "organizations")
+		}
+	}
+	return nil
+}
+
+// checkAttendeeRefs checks the foreign keys of event_attendees
+func (s *Store) checkAttendeeRefs(userID, eventID int) error {
+	if err := s.userExists("event_attendees", "user_id", userID); err != nil {
+		return err
+	}
+	return s.eventExists("event_attendees", "event_id", eventID)
+}
+
+func (s *Store) attendance(userID, eventID int) *attendee {
+	for _, a := range s.attendees {
+		if a.userID == userID && a.eventID == eventID {
+			return a
+		}
+	}
+	return nil
+}
+
+func (s *Store) addAttendance(userID, eventID int, role string) {
+	id := s.nextID("event_attendees")
+	s.attendees[id] = &attendee{id: id, userID: userID, eventID: eventID, role: role, status: "going", createdAt: now()}
+}
+
+// saveEvent replaces the stored event with updated, incrementing its version
+// and recording the revision; like the repository it keeps the event as is
+// when none of the revision fields changed, and schedules a postponed event
+// that was moved again
+func (s *Store) saveEvent(current *event.Event, updated event.Event, editorID int, restoredFrom *int) *event.Event {
+	previous := current.Snapshot()
+	updated.Reschedule(&previous)
+	if rev := updated.Snapshot(); len(previous.ChangedFields(&rev)) == 0 {
+		unchanged := *current
+		return &unchanged
+	}
+
+	updated.Version++
+	*current = updated
+	s.recordRevision(current, &previous, editorID, restoredFrom)
+	return &updated
+}
+
+// recordRevision appends the state of an event to its history; like the
+// repository it skips edits that change none of the revision fields
+func (s *Store) recordRevision(ev *event.Event, previous *event.Revision, editorID int, restoredFrom *int) {
+	rev := ev.Snapshot()
+	rev.Changed = event.RevisionFields
+	if previous != nil {
+		if rev.Changed = previous.ChangedFields(&rev); len(rev.Changed) == 0 {
+			return
+		}
+	}
+
+	if editorID > 0 {
+		rev.EditorID = &editorID
+	}
+	rev.RestoredFrom = restoredFrom
+	rev.Revision = len(s.revisions[ev.ID]) + 1
+	rev.CreatedAt = now()
+	s.revisions[ev.ID] = append(s.revisions[ev.ID], cloneRevision(rev))
+}
+
+func (s *Store) revision(eventID, revision int) (*event.Revision, error) {
+	for _, rev := range s.revisions[eventID] {
+		if rev.Revision == revision {
+			rev = cloneRevision(rev)
+			return &rev, nil
+		}
+	}
+	return nil, notFound(event.ErrRevisionNotFound)
+}
+
+// cloneRevision copies the pointer and slice fields so callers cannot modify the stored revision
+func cloneRevision(rev event.Revision) event.Revision {
+	rev.Changed = append([]string(nil), rev.Changed...)
+	if rev.EditorID != nil {
+		id := *rev.EditorID
+		rev.EditorID = &id
+	}
+	if rev.RestoredFrom != nil {
+		from := *rev.RestoredFrom
+		rev.RestoredFrom = &from
+	}
+	return rev
+}
+
+// listEvents returns copies of the matching events that are not in the trash
+// by descending date
+func (s *Store) listEvents(match func(*event.Event) bool) []event.Event {
+	var events []event.Event
+	for _, ev := range s.events {
+		if ev.DeletedAt == nil && match(ev) {
+			events = append(events, *ev)
+		}
+	}
+	sortEvents(events, func(e event.Event) *event.Event { return &e }, false)
+	return events
+}
+
+// inListScope mirrors the scope of event listings, see event.GetAllEvents
+func inListScope(ev *event.Event, orgID *int) bool {
+	if orgID != nil {
+		return ev.OrganizationID != nil && *ev.OrganizationID == *orgID
+	}
+	return ev.OrganizationID == nil || ev.Visibility == event.VisibilityPublic
+}
+
+// inOrganization reports whether an event belongs to the organization, or any event for nil
+func inOrganization(ev *event.Event, orgID *int) bool {
+	return orgID == nil || (ev.OrganizationID != nil && *ev.OrganizationID == *orgID)
+}
+
+// normalize stores the date and time the way PostgreSQL returns DATE and TIME columns
+func normalize(ev event.Event) event.Event {
+	ev.Date = time.Date(ev.Date.Year(), ev.Date.Month(), ev.Date.Day(), 0, 0, 0, 0, time.UTC)
+	ev.Time = time.Date(2000, 1, 1, ev.Time.Hour(), ev.Time.Minute(), ev.Time.Second(), ev.Time.Nanosecond(), time.UTC)
+	return ev
+}
@@ -0,0 +1,209 @@
+package memstore
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"event-planner/internal/apperror"
+	"event-planner/internal/group"
+)
+
+var _ group.Store = (*Groups)(nil)
+
+// Groups stores user groups and their members
+type Groups struct {
+	s *Store
+}
+
+// CreateGroup inserts a new group and sets its ID and creation time
+func (r *Groups) CreateGroup(ctx context.Context, g *group.Group) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if err := r.s.userExists("user_groups", "owner_id", g.OwnerID); err != nil {
+		return err
+	}
+
+	stored := &userGroup{
+		id:          r.s.nextID("user_groups"),
+		ownerID:     g.OwnerID,
+		name:        g.Name,
+		description: g.Description,
+		createdAt:   now(),
+	}
+	r.s.groups[stored.id] = stored
+
+	g.ID, g.CreatedAt = stored.id, stored.createdAt
+	return nil
+}
+
+// GetGroupByID retrieves a single group by ID with its member count
+func (r *Groups) GetGroupByID(ctx context.Context, groupID int) (*group.Group, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	g, ok := r.s.groups[groupID]
+	if !ok {
+		return nil, notFound(group.ErrGroupNotFound)
+	}
+	found := r.s.groupOf(g)
+	return &found, nil
+}
+
+// GetGroupsByOwnerID retrieves all groups owned by a user, by name
+func (r *Groups) GetGroupsByOwnerID(ctx context.Context, ownerID int) ([]group.Group, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var groups []group.Group
+	for _, g := range r.s.groups {
+		if g.ownerID == ownerID {
+			groups = append(groups, r.s.groupOf(g))
+		}
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Name != groups[j].Name {
+			return groups[i].Name < groups[j].Name
+		}
+		return groups[i].ID < groups[j].ID
+	})
+	return groups, nil
+}
+
+// UpdateGroup saves the name and description of a group; a missing group is ignored
+func (r *Groups) UpdateGroup(ctx context.Context, g *group.Group) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if stored, ok := r.s.groups[g.ID]; ok {
+		stored.name = g.Name
+		stored.description = g.Description
+	}
+	return nil
+}
+
+// DeleteGroup removes a group and its members
+func (r *Groups) DeleteGroup(ctx context.Context, groupID int) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.groups[groupID]; !ok {
+		return group.ErrGroupNotFound
+	}
+	r.s.deleteGroup(groupID)
+	return nil
+}
+
+// GetMembers retrieves all members of a group in the order they were added;
+// members with an account have its public profile
+func (r *Groups) GetMembers(ctx context.Context, groupID int) ([]group.Member, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var members []group.Member
+	for _, m := range r.s.membersOf(groupID) {
+		member := group.Member{ID: m.id, GroupID: m.groupID, Email: m.email, CreatedAt: m.createdAt}
+		if u, ok := r.s.userByEmail(m.email); ok {
+			id := u.ID
+			profile := r.s.publicProfile(id)
+			member.UserID, member.User = &id, &profile
+		}
+		members = append(members, member)
+	}
+	return members, nil
+}
+
+// AddMember adds an email address to a group. It reports false when the
+// address already was a member.
+func (r *Groups) AddMember(ctx context.Context, groupID int, email string) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.groups[groupID]; !ok {
+		return false, foreignKeyViolation("group_members", "group_id", groupID, "user_groups")
+	}
+	for _, m := range r.s.groupMembers {
+		if m.groupID == groupID && m.email == email {
+			return false, nil
+		}
+	}
+
+	id := r.s.nextID("group_members")
+	r.s.groupMembers[id] = &groupMember{id: id, groupID: groupID, email: email, createdAt: now()}
+	return true, nil
+}
+
+// RemoveMember removes a member from a group
+func (r *Groups) RemoveMember(ctx context.Context, groupID, memberID int) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	m, ok := r.s.groupMembers[memberID]
+	if !ok || m.groupID != groupID {
+		return group.ErrMemberNotFound
+	}
+	delete(r.s.groupMembers, memberID)
+	return nil
+}
+
+// GetUserEmail retrieves the email of a user
+func (r *Groups) GetUserEmail(ctx context.Context, userID int) (string, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	u, ok := r.s.users[userID]
+	if !ok {
+		return "", apperror.NotFound("user_not_found", fmt.Sprintf("user %d not found", userID))
+	}
+	return u.Email, nil
+}
+
+// groupOf returns a stored group with its member count
+func (s *Store) groupOf(g *userGroup) group.Group {
+	return group.Group{
+		ID:          g.id,
+		OwnerID:     g.ownerID,
+		Name:        g.name,
+		Description: g.description,
+		MemberCount: len(s.membersOf(g.id)),
+		CreatedAt:   g.createdAt,
+	}
+}
+
+// membersOf returns the members of a group in the order they were added
+func (s *Store) membersOf(groupID int) []*groupMember {
+	var members []*groupMember
+	for _, m := range s.groupMembers {
+		if m.groupID == groupID {
+			members = append(members, m)
+		}
+	}
+	sort.Slice(members, func(i, j int) bool {
+		if !members[i].createdAt.Equal(members[j].createdAt) {
+			return members[i].createdAt.Before(members[j].createdAt)
+		}
+		return members[i].id < members[j].id
+	})
+	return members
+}
+
+// deleteGroup removes a group and cascades like the foreign keys to it do
+func (s *Store) deleteGroup(groupID int) {
+	delete(s.groups, groupID)
+	for id, m := range s.groupMembers {
+		if m.groupID == groupID {
+			delete(s.groupMembers, id)
+		}
+	}
+	for key := range s.groupLinks {
+		if key[1] == groupID {
+			delete(s.groupLinks, key)
+		}
+	}
+	for _, inv := range s.invitations {
+		if inv.GroupID != nil && *inv.GroupID == groupID {
+			inv.GroupID = nil
+		}
+	}
+}
@@ -0,0 +1,298 @@
+package memstore
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"event-planner/internal/invitation"
+)
+
+var _ invitation.Store = (*Invitations)(nil)
+
+// Invitations stores invitations and the links of groups invited to events
+type Invitations struct {
+	s *Store
+}
+
+// SendInvitation creates a pending invitation and sets its ID and creation time
+func (r *Invitations) SendInvitation(ctx context.Context, inv *invitation.Invitation) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if err := checkIn("invitations", "role", inv.Role, "attendee", "collaborator", "organizer"); err != nil {
+		return err
+	}
+	if err := r.s.eventExists("invitations", "event_id", inv.EventID); err != nil {
+		return err
+	}
+	if err := r.s.userExists("invitations", "inviter_id", inv.InviterID); err != nil {
+		return err
+	}
+	if inv.InviteeID != nil {
+		if err := r.s.userExists("invitations", "invitee_id", *inv.InviteeID); err != nil {
+			return err
+		}
+	}
+	if inv.GroupID != nil {
+		if _, ok := r.s.groups[*inv.GroupID]; !ok {
+			return foreignKeyViolation("invitations", "group_id", *inv.GroupID, "user_groups")
+		}
+	}
+
+	stored := *inv
+	stored.ID = r.s.nextID("invitations")
+	stored.Status = "pending"
+	stored.CreatedAt = now()
+	stored.RespondedAt = nil
+	r.s.invitations[stored.ID] = &stored
+
+	inv.ID, inv.CreatedAt = stored.ID, stored.CreatedAt
+	return nil
+}
+
+// GetInvitationByID retrieves a single invitation by ID
+func (r *Invitations) GetInvitationByID(ctx context.Context, invitationID int) (*invitation.Invitation, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	inv, ok := r.s.invitations[invitationID]
+	if !ok {
+		return nil, notFound(invitation.ErrInvitationNotFound)
+	}
+	found := *inv
+	return &found, nil
+}
+
+// GetInvitationsByEmail retrieves all invitations for a specific email, newest first.
+// A non-nil orgID restricts the result to invitations to that organization's events.
+func (r *Invitations) GetInvitationsByEmail(ctx context.Context, email string, orgID *int) ([]invitation.InvitationWithDetails, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	return r.s.listInvitations(func(inv *invitation.Invitation) bool {
+		ev := r.s.events[inv.EventID]
+		return inv.InviteeEmail == email && ev.DeletedAt == nil && inOrganization(ev, orgID)
+	}), nil
+}
+
+// GetInvitationsByEventID retrieves all invitations for a specific event, newest first
+func (r *Invitations) GetInvitationsByEventID(ctx context.Context, eventID int) ([]invitation.InvitationWithDetails, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	return r.s.listInvitations(func(inv *invitation.Invitation) bool {
+		return inv.EventID == eventID
+	}), nil
+}
+
+// UpdateInvitationStatus updates the status of an invitation; a missing invitation is ignored
+func (r *Invitations) UpdateInvitationStatus(ctx context.Context, invitationID int, status string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	inv, ok := r.s.invitations[invitationID]
+	if !ok {
+		return nil
+	}
+	if err := checkIn("invitations", "status", status, "pending", "accepted", "declined"); err != nil {
+		return err
+	}
+
+	respondedAt := now()
+	inv.Status = status
+	inv.RespondedAt = &respondedAt
+	return nil
+}
+
+// GetEventVisibility retrieves the organization and visibility of an event;
+// events in the trash are not found
+func (r *Invitations) GetEventVisibility(ctx context.Context, eventID int) (*int, string, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	ev, ok := r.s.event(eventID)
+	if !ok {
+		return nil, "", notFound(invitation.ErrEventNotFound)
+	}
+	return ev.OrganizationID, ev.Visibility, nil
+}
+
+// GetEventStatus retrieves the status of an event; events in the trash are not found
+func (r *Invitations) GetEventStatus(ctx context.Context, eventID int) (string, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	ev, ok := r.s.event(eventID)
+	if !ok {
+		return "", notFound(invitation.ErrEventNotFound)
+	}
+	return ev.Status, nil
+}
+
+// IsEventOrganizer reports whether a user is the organizer of an event
+func (r *Invitations) IsEventOrganizer(ctx context.Context, eventID, userID int) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	ev, ok := r.s.events[eventID]
+	return ok && ev.OrganizerID == userID, nil
+}
+
+// IsEventCollaborator reports whether a user organizes an event or collaborates on it
+func (r *Invitations) IsEventCollaborator(ctx context.Context, eventID, userID int) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if ev, ok := r.s.events[eventID]; ok && ev.OrganizerID == userID {
+		return true, nil
+	}
+	a := r.s.attendance(userID, eventID)
+	return a != nil && (a.role == "organizer" || a.role == "collaborator"), nil
+}
+
+// GetInvitedEmails retrieves the lower-cased emails that already have an invitation to an event
+func (r *Invitations) GetInvitedEmails(ctx context.Context, eventID int) (map[string]bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	emails := map[string]bool{}
+	for _, inv := range r.s.invitations {
+		if inv.EventID == eventID {
+			emails[strings.ToLower(inv.InviteeEmail)] = true
+		}
+	}
+	return emails, nil
+}
+
+// GetGroupEmails retrieves the member emails of a group owned by ownerID
+func (r *Invitations) GetGroupEmails(ctx context.Context, groupID, ownerID int) ([]string, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	g, ok := r.s.groups[groupID]
+	if !ok || g.ownerID != ownerID {
+		return nil, invitation.ErrGroupNotFound
+	}
+
+	var members []*groupMember
+	for _, m := range r.s.groupMembers {
+		if m.groupID == groupID {
+			members = append(members, m)
+		}
+	}
+	sort.Slice(members, func(i, j int) bool {
+		if !members[i].createdAt.Equal(members[j].createdAt) {
+			return members[i].createdAt.Before(members[j].createdAt)
+		}
+		return members[i].id < members[j].id
+	})
+
+	var emails []string
+	for _, m := range members {
+		emails = append(emails, m.email)
+	}
+	return emails, nil
+}
+
+// SaveGroupLink records (or refreshes) the invitation of a group to an event
+func (r *Invitations) SaveGroupLink(ctx context.Context, link *invitation.GroupLink) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if err := checkIn("event_group_invitations", "role", link.Role, "attendee", "collaborator", "organizer"); err != nil {
+		return err
+	}
+	if err := r.s.eventExists("event_group_invitations", "event_id", link.EventID); err != nil {
+		return err
+	}
+	if _, ok := r.s.groups[link.GroupID]; !ok {
+		return foreignKeyViolation("event_group_invitations", "group_id", link.GroupID, "user_groups")
+	}
+	if err := r.s.userExists("event_group_invitations", "inviter_id", link.InviterID); err != nil {
+		return err
+	}
+
+	key := [2]int{link.EventID, link.GroupID}
+	stored := *link
+	if existing, ok := r.s.groupLinks[key]; ok {
+		stored.CreatedAt = existing.CreatedAt
+	} else {
+		stored.CreatedAt = now()
+	}
+	r.s.groupLinks[key] = &stored
+
+	link.CreatedAt = stored.CreatedAt
+	return nil
+}
+
+// GetLateJoinerLinks retrieves the links of a group to upcoming events that
+// invite new members; cancelled and completed events are left out, and so
+// are drafts the link could not invite to
+func (r *Invitations) GetLateJoinerLinks(ctx context.Context, groupID int) ([]invitation.GroupLink, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	y, m, d := time.Now().UTC().Date()
+	today := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+
+	var links []invitation.GroupLink
+	for key, link := range r.s.groupLinks {
+		ev := r.s.events[key[0]]
+		if key[1] == groupID && link.InviteNewMembers && ev.DeletedAt == nil && !ev.Date.Before(today) && ev.CheckInvite(link.Role) == nil {
+			links = append(links, *link)
+		}
+	}
+	return links, nil
+}
+
+// GetUserIDByEmail retrieves the ID of the account with an email, nil when there is none
+func (r *Invitations) GetUserIDByEmail(ctx context.Context, email string) (*int, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if u, ok := r.s.userByEmail(email); ok {
+		id := u.ID
+		return &id, nil
+	}
+	return nil, nil
+}
+
+// listInvitations returns the matching invitations with their details, newest first
+func (s *Store) listInvitations(match func(*invitation.Invitation) bool) []invitation.InvitationWithDetails {
+	invitations := []invitation.InvitationWithDetails{}
+	for _, inv := range s.invitations {
+		if !match(inv) {
+			continue
+		}
+
+		ev := s.events[inv.EventID]
+		details := invitation.InvitationWithDetails{
+			Invitation:    *inv,
+			EventTitle:    ev.Title,
+			EventDate:     ev.Date.Format("2006-01-02"),
+			EventTime:     ev.Time.Format("15:04:05"),
+			EventLocation: ev.Location,
+			EventStatus:   ev.Status,
+			InviterEmail:  s.users[inv.InviterID].Email,
+			Inviter:       s.publicProfile(inv.InviterID),
+		}
+		if inv.InviteeID != nil {
+			if _, ok := s.users[*inv.InviteeID]; ok {
+				invitee := s.publicProfile(*inv.InviteeID)
+				details.Invitee = &invitee
+			}
+		}
+		invitations = append(invitations, details)
+	}
+
+	sort.Slice(invitations, func(i, j int) bool {
+		a, b := invitations[i], invitations[j]
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.After(b.CreatedAt)
+		}
+		return a.ID > b.ID
+	})
+	return invitations
+}
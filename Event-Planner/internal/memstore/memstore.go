@@ -0,0 +1,314 @@
+// Package memstore keeps accounts, profiles, organizations, groups, events,
+// attendance, invitations, notifications, admin actions and the audit log in
+// memory. It implements the storage interfaces of the services (auth.Store,
+// account.Store, admin.Store, profile.Store, organization.Store, group.Store,
+// event.Store, invitation.Store, search.Store, notification.Store and
+// audit.Store) the way the PostgreSQL repositories do: IDs come from
+// sequences, unique, foreign key and check constraints fail with the same
+// errors, deleting an event cascades to its attendees, invitations and
+// notifications, and lists have the same order. It lets services and handlers
+// be tested without a database.
+package memstore
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"event-planner/internal/admin"
+	"event-planner/internal/audit"
+	"event-planner/internal/db"
+	"event-planner/internal/event"
+	"event-planner/internal/invitation"
+	"event-planner/internal/notification"
+	"event-planner/internal/organization"
+	"event-planner/internal/profile"
+	"event-planner/internal/user"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// PostgreSQL error codes of the violations the store reproduces
+const (
+	codeForeignKeyViolation = "23503"
+	codeUniqueViolation     = "23505"
+	codeCheckViolation      = "23514"
+)
+
+// Store holds the tables; Users, Accounts, Admin, Profiles, Organizations,
+// Groups, Events, Invitations, Search, Notifications and Audit are views
+// implementing the storage interfaces of the services
+type Store struct {
+	mu  sync.Mutex
+	seq map[string]int // last ID per table
+
+	users         map[int]*user.User
+	deletions     map[int]*deletion        // by user ID, for accounts with a pending or done deletion
+	profiles      map[int]*profile.Profile // by user ID
+	adminActions  []admin.Action           // in insertion order
+	organizations map[int]*organization.Organization
+	orgMembers    map[[2]int]*orgMember // by organization and user ID
+	events        map[int]*event.Event
+	revisions     map[int][]event.Revision // by event ID, oldest first
+	attendees     map[int]*attendee
+	groups        map[int]*userGroup
+	groupMembers  map[int]*groupMember
+	invitations   map[int]*invitation.Invitation
+	groupLinks    map[[2]int]*invitation.GroupLink // by event and group ID
+	notifications map[int]*sentNotification
+	auditLog      []audit.Entry // in insertion order
+}
+
+// deletion holds the deletion columns of users
+type deletion struct {
+	requestedAt  *time.Time
+	scheduledFor *time.Time
+	deletedAt    *time.Time
+}
+
+type orgMember struct {
+	orgID     int
+	userID    int
+	role      string
+	createdAt time.Time
+}
+
+type attendee struct {
+	id        int
+	userID    int
+	eventID   int
+	role      string
+	status    string
+	createdAt time.Time
+}
+
+type sentNotification struct {
+	recipient string // email
+	notification.Notification
+}
+
+type userGroup struct {
+	id          int
+	ownerID     int
+	name        string
+	description string
+	createdAt   time.Time
+}
+
+type groupMember struct {
+	id        int
+	groupID   int
+	email     string
+	createdAt time.Time
+}
+
+// New returns an empty store
+func New() *Store {
+	return &Store{
+		seq:           map[string]int{},
+		users:         map[int]*user.User{},
+		deletions:     map[int]*deletion{},
+		profiles:      map[int]*profile.Profile{},
+		organizations: map[int]*organization.Organization{},
+		orgMembers:    map[[2]int]*orgMember{},
+		events:        map[int]*event.Event{},
+		revisions:     map[int][]event.Revision{},
+		attendees:     map[int]*attendee{},
+		groups:        map[int]*userGroup{},
+		groupMembers:  map[int]*groupMember{},
+		invitations:   map[int]*invitation.Invitation{},
+		groupLinks:    map[[2]int]*invitation.GroupLink{},
+		notifications: map[int]*sentNotification{},
+	}
+}
+
+// Users returns the account storage
+func (s *Store) Users() *Users {
+	return &Users{s: s}
+}
+
+// Accounts returns the account deletion and export storage
+func (s *Store) Accounts() *Accounts {
+	return &Accounts{s: s}
+}
+
+// Admin returns the administration storage
+func (s *Store) Admin() *Admin {
+	return &Admin{s: s}
+}
+
+// Profiles returns the profile storage
+func (s *Store) Profiles() *Profiles {
+	return &Profiles{s: s}
+}
+
+// Organizations returns the organization storage
+func (s *Store) Organizations() *Organizations {
+	return &Organizations{s: s}
+}
+
+// Groups returns the group storage
+func (s *Store) Groups() *Groups {
+	return &Groups{s: s}
+}
+
+// Events returns the event and attendance storage
+func (s *Store) Events() *Events {
+	return &Events{s: s}
+}
+
+// Invitations returns the invitation storage
+func (s *Store) Invitations() *Invitations {
+	return &Invitations{s: s}
+}
+
+// Search returns the event search storage
+func (s *Store) Search() *Search {
+	return &Search{s: s}
+}
+
+// Notifications returns the notification storage
+func (s *Store) Notifications() *Notifications {
+	return &Notifications{s: s}
+}
+
+// Audit returns the audit log storage
+func (s *Store) Audit() *Audit {
+	return &Audit{s: s}
+}
+
+// nextID advances the sequence of a table
+func (s *Store) nextID(table string) int {
+	s.seq[table]++
+	return s.seq[table]
+}
+
+// userExists checks a foreign key to users
+func (s *Store) userExists(table, column string, userID int) error {
+	if _, ok := s.users[userID]; !ok {
+		return foreignKeyViolation(table, column, userID, "users")
+	}
+	return nil
+}
+
+// erased reports whether the account of a user was erased and only its
+// tombstone is left
+func (s *Store) erased(userID int) bool {
+	d, ok := s.deletions[userID]
+	return ok && d.deletedAt != nil
+}
+
+// eventExists checks a foreign key to events
+func (s *Store) eventExists(table, column string, eventID int) error {
+	if _, ok := s.events[eventID]; !ok {
+		return foreignKeyViolation(table, column, eventID, "events")
+	}
+	return nil
+}
+
+// displayName is the profile name of a user, or the fallback the
+// repositories use for users without one
+func (s *Store) displayName(userID int) string {
+	if p, ok := s.profiles[userID]; ok && p.DisplayName != "" {
+		return p.DisplayName
+	}
+	return fmt.Sprintf("User %d", userID)
+}
+
+// publicProfile is the profile of a user embedded in attendee lists and invitations
+func (s *Store) publicProfile(userID int) user.PublicProfile {
+	public := user.PublicProfile{UserID: userID, DisplayName: s.displayName(userID)}
+	if p, ok := s.profiles[userID]; ok {
+		public.AvatarURL = p.AvatarURL
+	}
+	return public
+}
+
+// userByEmail looks up the account with an email, which is matched exactly
+func (s *Store) userByEmail(email string) (*user.User, bool) {
+	for _, u := range s.users {
+		if u.Email == email {
+			return u, true
+		}
+	}
+	return nil, false
+}
+
+// now is a TIMESTAMP as read back from PostgreSQL
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
+
+// timestamp is t as stored in a TIMESTAMP column and read back
+func timestamp(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Microsecond)
+}
+
+// notFound is the error the repositories return for a missing row
+func notFound(err error) error {
+	return db.TranslateError(pgx.ErrNoRows, err)
+}
+
+func uniqueViolation(table, constraint, columns, values string) error {
+	return db.TranslateError(&pgconn.PgError{
+		Code:           codeUniqueViolation,
+		TableName:      table,
+		ConstraintName: constraint,
+		Detail:         fmt.Sprintf("Key (%s)=(%s) already exists.", columns, values),
+	}, nil)
+}
+
+func foreignKeyViolation(table, column string, value int, refTable string) error {
+	return db.TranslateError(&pgconn.PgError{
+		Code:           codeForeignKeyViolation,
+		TableName:      table,
+		ConstraintName: table + "_" + column + "_fkey",
+		Detail:         fmt.Sprintf("Key (%s)=(%d) is not present in table %q.", column, value, refTable),
+	}, nil)
+}
+
+// checkIn enforces a CHECK (column IN (...)) constraint
+func checkIn(table, column, value string, allowed ...string) error {
+	for _, a := range allowed {
+		if value == a {
+			return nil
+		}
+	}
+	return checkViolation(table, table+"_"+column+"_check")
+}
+
+func checkViolation(table, constraint string) error {
+	return db.TranslateError(&pgconn.PgError{
+		Code:           codeCheckViolation,
+		TableName:      table,
+		ConstraintName: constraint,
+	}, nil)
+}
+
+// pageOf applies LIMIT and OFFSET
+func pageOf[T any](items []T, limit, offset int) []T {
+	if offset >= len(items) {
+		return nil
+	}
+	items = items[offset:]
+	if limit > 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
+
+// sortEvents orders events by descending date, time when byTime is set, then ID
+func sortEvents[T any](items []T, ev func(T) *event.Event, byTime bool) {
+	sort.Slice(items, func(i, j int) bool {
+		a, b := ev(items[i]), ev(items[j])
+		if !a.Date.Equal(b.Date) {
+			return a.Date.After(b.Date)
+		}
+		if byTime && !a.Time.Equal(b.Time) {
+			return a.Time.After(b.Time)
+		}
+		return a.ID > b.ID
+	})
+}
@@ -0,0 +1,27 @@
+package memstore_test
+
+import (
+	"testing"
+
+	"event-planner/internal/memstore"
+	"event-planner/internal/storetest"
+)
+
+func TestConformance(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) storetest.Backend {
+		s := memstore.New()
+		return storetest.Backend{
+			Users:         s.Users(),
+			Events:        s.Events(),
+			Invitations:   s.Invitations(),
+			Search:        s.Search(),
+			Notifications: s.Notifications(),
+			Audit:         s.Audit(),
+			Organizations: s.Organizations(),
+			Groups:        s.Groups(),
+			Profiles:      s.Profiles(),
+			Admin:         s.Admin(),
+			Accounts:      s.Accounts(),
+		}
+	})
+}
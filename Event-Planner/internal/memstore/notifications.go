@@ -0,0 +1,71 @@
+package memstore
+
+import (
+	"context"
+	"sort"
+
+	"event-planner/internal/notification"
+)
+
+var _ notification.Store = (*Notifications)(nil)
+
+// Notifications stores the notifications of status changes of events; they
+// are sent by Events.ChangeStatus
+type Notifications struct {
+	s *Store
+}
+
+// GetNotifications retrieves the notifications sent to the email of a user,
+// newest first
+func (r *Notifications) GetNotifications(ctx context.Context, userID int, unreadOnly bool) ([]notification.Notification, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	u, ok := r.s.users[userID]
+	if !ok {
+		return nil, nil
+	}
+
+	var notifications []notification.Notification
+	for _, n := range r.s.notifications {
+		if n.recipient == u.Email && (!unreadOnly || n.ReadAt == nil) {
+			notifications = append(notifications, cloneNotification(n.Notification))
+		}
+	}
+	sort.Slice(notifications, func(i, j int) bool {
+		a, b := notifications[i], notifications[j]
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.After(b.CreatedAt)
+		}
+		return a.ID > b.ID
+	})
+	return notifications, nil
+}
+
+// MarkRead sets the time a notification sent to the email of a user was
+// read, unless it already was; notifications of other users are not found
+func (r *Notifications) MarkRead(ctx context.Context, notificationID, userID int) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	n, ok := r.s.notifications[notificationID]
+	u, exists := r.s.users[userID]
+	if !ok || !exists || n.recipient != u.Email {
+		return notification.ErrNotificationNotFound
+	}
+
+	if n.ReadAt == nil {
+		readAt := now()
+		n.ReadAt = &readAt
+	}
+	return nil
+}
+
+// cloneNotification copies the read time so callers cannot modify the stored notification
+func cloneNotification(n notification.Notification) notification.Notification {
+	if n.ReadAt != nil {
+		readAt := *n.ReadAt
+		n.ReadAt = &readAt
+	}
+	return n
+}
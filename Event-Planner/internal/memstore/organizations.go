@@ -0,0 +1,211 @@
+package memstore
+
+import (
+	"context"
+	"sort"
+
+	"event-planner/internal/organization"
+)
+
+var _ organization.Store = (*Organizations)(nil)
+
+// Organizations stores organizations and their members
+type Organizations struct {
+	s *Store
+}
+
+// CreateOrganization inserts a new organization, makes ownerID its owner and
+// sets its ID, creator and creation time
+func (r *Organizations) CreateOrganization(ctx context.Context, org *organization.Organization, ownerID int) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if err := checkIn("organizations", "default_visibility", org.DefaultVisibility, "public", "organization"); err != nil {
+		return err
+	}
+	for _, existing := range r.s.organizations {
+		if existing.Slug == org.Slug {
+			return uniqueViolation("organizations", "organizations_slug_key", "slug", org.Slug)
+		}
+	}
+	if err := r.s.userExists("organizations", "created_by", ownerID); err != nil {
+		return err
+	}
+
+	stored := *org
+	stored.ID = r.s.nextID("organizations")
+	stored.CreatedBy = ownerID
+	stored.CreatedAt = now()
+	r.s.organizations[stored.ID] = &stored
+	r.s.orgMembers[[2]int{stored.ID, ownerID}] = &orgMember{
+		orgID:     stored.ID,
+		userID:    ownerID,
+		role:      organization.RoleOwner,
+		createdAt: stored.CreatedAt,
+	}
+
+	org.ID, org.CreatedBy, org.CreatedAt = stored.ID, stored.CreatedBy, stored.CreatedAt
+	return nil
+}
+
+// UpdateOrganization saves the name and default settings of an organization
+func (r *Organizations) UpdateOrganization(ctx context.Context, org *organization.Organization) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored, ok := r.s.organizations[org.ID]
+	if !ok {
+		return organization.ErrOrganizationNotFound
+	}
+	if err := checkIn("organizations", "default_visibility", org.DefaultVisibility, "public", "organization"); err != nil {
+		return err
+	}
+
+	stored.Name = org.Name
+	stored.DefaultVisibility = org.DefaultVisibility
+	stored.Timezone = org.Timezone
+	return nil
+}
+
+// GetMembership retrieves an organization together with the role of userID in it
+func (r *Organizations) GetMembership(ctx context.Context, orgID, userID int) (*organization.Membership, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	m, ok := r.s.orgMembers[[2]int{orgID, userID}]
+	if !ok {
+		return nil, notFound(organization.ErrMemberNotFound)
+	}
+	return &organization.Membership{Organization: *r.s.organizations[orgID], Role: m.role}, nil
+}
+
+// GetUserMemberships retrieves all organizations a user belongs to, by name
+func (r *Organizations) GetUserMemberships(ctx context.Context, userID int) ([]organization.Membership, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var memberships []organization.Membership
+	for key, m := range r.s.orgMembers {
+		if key[1] == userID {
+			memberships = append(memberships, organization.Membership{Organization: *r.s.organizations[key[0]], Role: m.role})
+		}
+	}
+	sort.Slice(memberships, func(i, j int) bool {
+		if memberships[i].Name != memberships[j].Name {
+			return memberships[i].Name < memberships[j].Name
+		}
+		return memberships[i].ID < memberships[j].ID
+	})
+	return memberships, nil
+}
+
+// GetMembers retrieves all members of an organization, longest-standing first
+func (r *Organizations) GetMembers(ctx context.Context, orgID int) ([]organization.Member, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var rows []*orgMember
+	for key, m := range r.s.orgMembers {
+		if key[0] == orgID {
+			rows = append(rows, m)
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if !rows[i].createdAt.Equal(rows[j].createdAt) {
+			return rows[i].createdAt.Before(rows[j].createdAt)
+		}
+		return rows[i].userID < rows[j].userID
+	})
+
+	var members []organization.Member
+	for _, m := range rows {
+		members = append(members, organization.Member{
+			OrganizationID: m.orgID,
+			UserID:         m.userID,
+			Role:           m.role,
+			CreatedAt:      m.createdAt,
+			User:           r.s.publicProfile(m.userID),
+		})
+	}
+	return members, nil
+}
+
+// AddMember adds a user to an organization (updates the role if already a member)
+func (r *Organizations) AddMember(ctx context.Context, orgID, userID int, role string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if err := checkIn("organization_members", "role", role, organization.RoleOwner, organization.RoleAdmin, organization.RoleMember); err != nil {
+		return err
+	}
+	if _, ok := r.s.organizations[orgID]; !ok {
+		return foreignKeyViolation("organization_members", "organization_id", orgID, "organizations")
+	}
+	if err := r.s.userExists("organization_members", "user_id", userID); err != nil {
+		return err
+	}
+
+	key := [2]int{orgID, userID}
+	if m, ok := r.s.orgMembers[key]; ok {
+		m.role = role
+		return nil
+	}
+	r.s.orgMembers[key] = &orgMember{orgID: orgID, userID: userID, role: role, createdAt: now()}
+	return nil
+}
+
+// UpdateMemberRole changes the role of an existing member
+func (r *Organizations) UpdateMemberRole(ctx context.Context, orgID, userID int, role string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if err := checkIn("organization_members", "role", role, organization.RoleOwner, organization.RoleAdmin, organization.RoleMember); err != nil {
+		return err
+	}
+
+	m, ok := r.s.orgMembers[[2]int{orgID, userID}]
+	if !ok {
+		return organization.ErrMemberNotFound
+	}
+	m.role = role
+	return nil
+}
+
+// RemoveMember removes a user from an organization
+func (r *Organizations) RemoveMember(ctx context.Context, orgID, userID int) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	key := [2]int{orgID, userID}
+	if _, ok := r.s.orgMembers[key]; !ok {
+		return organization.ErrMemberNotFound
+	}
+	delete(r.s.orgMembers, key)
+	return nil
+}
+
+// CountOwners returns the number of owners of an organization
+func (r *Organizations) CountOwners(ctx context.Context, orgID int) (int, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	owners := 0
+	for key, m := range r.s.orgMembers {
+		if key[0] == orgID && m.role == organization.RoleOwner {
+			owners++
+		}
+	}
+	return owners, nil
+}
+
+// GetUserIDByEmail retrieves the ID of the account with an email, nil when there is none
+func (r *Organizations) GetUserIDByEmail(ctx context.Context, email string) (*int, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if u, ok := r.s.userByEmail(email); ok {
+		id := u.ID
+		return &id, nil
+	}
+	return nil, nil
+}
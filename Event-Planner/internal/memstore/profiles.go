@@ -0,0 +1,102 @@
+package memstore
+
+import (
+	"context"
+
+	"event-planner/internal/profile"
+)
+
+var _ profile.Store = (*Profiles)(nil)
+
+// Profiles stores user profiles
+type Profiles struct {
+	s *Store
+}
+
+// GetProfile retrieves the profile of a user; users without a stored
+// profile get the default preferences
+func (r *Profiles) GetProfile(ctx context.Context, userID int) (*profile.Profile, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	u, ok := r.s.users[userID]
+	if !ok {
+		return nil, notFound(profile.ErrUserNotFound)
+	}
+
+	found := profile.Profile{UserID: userID, Timezone: "UTC", Locale: "en"}
+	if p, ok := r.s.profiles[userID]; ok {
+		found = *p
+	}
+	found.Email = u.Email
+	return &found, nil
+}
+
+// GetPublicProfile retrieves the publicly visible part of a user's profile
+func (r *Profiles) GetPublicProfile(ctx context.Context, userID int) (*profile.PublicDetails, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.users[userID]; !ok {
+		return nil, notFound(profile.ErrUserNotFound)
+	}
+
+	details := &profile.PublicDetails{PublicProfile: r.s.publicProfile(userID)}
+	if p, ok := r.s.profiles[userID]; ok {
+		details.Bio = p.Bio
+	}
+	return details, nil
+}
+
+// UpsertProfile creates or replaces the editable fields of a profile
+func (r *Profiles) UpsertProfile(ctx context.Context, p *profile.Profile) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored, err := r.s.profile(p.UserID)
+	if err != nil {
+		return err
+	}
+
+	updatedAt := now()
+	stored.DisplayName = p.DisplayName
+	stored.Bio = p.Bio
+	stored.Timezone = p.Timezone
+	stored.Locale = p.Locale
+	stored.UpdatedAt = &updatedAt
+
+	p.UpdatedAt = &updatedAt
+	return nil
+}
+
+// SetAvatar stores the avatar location of a user (empty values clear it)
+func (r *Profiles) SetAvatar(ctx context.Context, userID int, key, url string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored, err := r.s.profile(userID)
+	if err != nil {
+		return err
+	}
+
+	updatedAt := now()
+	stored.AvatarKey = key
+	stored.AvatarURL = url
+	stored.UpdatedAt = &updatedAt
+	return nil
+}
+
+// profile returns the stored profile of a user, creating one with the
+// column defaults when there is none yet
+func (s *Store) profile(userID int) (*profile.Profile, error) {
+	if p, ok := s.profiles[userID]; ok {
+		return p, nil
+	}
+	if err := s.userExists("user_profiles", "user_id", userID); err != nil {
+		return nil, err
+	}
+
+	p := &profile.Profile{UserID: userID, Timezone: "UTC", Locale: "en"}
+	s.profiles[userID] = p
+	return p, nil
+}
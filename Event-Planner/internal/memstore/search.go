@@ -0,0 +1,93 @@
+package memstore
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"event-planner/internal/event"
+	"event-planner/internal/search"
+)
+
+var _ search.Store = (*Search)(nil)
+
+// Search searches the events users attend
+type Search struct {
+	s *Store
+}
+
+// SearchEvents searches the events a user attends with filters, by descending
+// date and time; drafts are left out
+func (r *Search) SearchEvents(ctx context.Context, f *search.EventsFilter) ([]event.EventWithAttendeeInfo, error) {
+	var keyword *regexp.Regexp
+	if f.Query != "" {
+		keyword = ilike("%" + f.Query + "%")
+	}
+
+	var from, to time.Time
+	var err error
+	if f.DateFrom != "" {
+		if from, err = time.Parse("2006-01-02", f.DateFrom); err != nil {
+			return nil, err
+		}
+	}
+	if f.DateTo != "" {
+		if to, err = time.Parse("2006-01-02", f.DateTo); err != nil {
+			return nil, err
+		}
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var events []event.EventWithAttendeeInfo
+	for _, a := range r.s.attendees {
+		ev := r.s.events[a.eventID]
+		switch {
+		case a.userID != f.UserID:
+		case ev.DeletedAt != nil, ev.Status == event.StatusDraft:
+		case keyword != nil && !keyword.MatchString(ev.Title) && !keyword.MatchString(ev.Description):
+		case f.DateFrom != "" && ev.Date.Before(from):
+		case f.DateTo != "" && ev.Date.After(to):
+		case f.Role != "" && a.role != f.Role:
+		case f.Status != "" && a.status != f.Status:
+		case f.OrganizationID != nil && !inOrganization(ev, f.OrganizationID):
+		default:
+			events = append(events, event.EventWithAttendeeInfo{Event: *ev, Role: a.role, Status: a.status})
+		}
+	}
+
+	sortEvents(events, func(e event.EventWithAttendeeInfo) *event.Event { return &e.Event }, true)
+	events = pageOf(events, f.Limit, f.Offset)
+
+	if events == nil {
+		events = []event.EventWithAttendeeInfo{}
+	}
+	return events, nil
+}
+
+// ilike compiles an ILIKE pattern: % matches any text, _ one character and
+// a backslash escapes the next character
+func ilike(pattern string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString(`(?is)^`)
+	escaped := false
+	for _, c := range pattern {
+		switch {
+		case escaped:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+			escaped = false
+		case c == '\\':
+			escaped = true
+		case c == '%':
+			b.WriteString(`.*`)
+		case c == '_':
+			b.WriteString(`.`)
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString(`$`)
+	return regexp.MustCompile(b.String())
+}
@@ -0,0 +1,87 @@
+package memstore
+
+import (
+	"context"
+	"strings"
+
+	"event-planner/internal/auth"
+	"event-planner/internal/user"
+)
+
+var _ auth.Store = (*Users)(nil)
+
+// Users stores accounts
+type Users struct {
+	s *Store
+}
+
+// CreateUser inserts a new account; a taken email is a conflict
+func (r *Users) CreateUser(ctx context.Context, email, passwordHash, role string) (*user.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if err := checkIn("users", "role", role, user.RoleAdmin, user.RoleSupport, user.RoleMember); err != nil {
+		return nil, err
+	}
+	for _, u := range r.s.users {
+		if u.Email == email {
+			return nil, uniqueViolation("users", "users_email_key", "email", email)
+		}
+	}
+
+	u := &user.User{
+		ID:           r.s.nextID("users"),
+		Email:        email,
+		PasswordHash: passwordHash,
+		Role:         role,
+		CreatedAt:    now(),
+	}
+	r.s.users[u.ID] = u
+
+	created := *u
+	created.PasswordHash = ""
+	return &created, nil
+}
+
+// GetUserByEmail retrieves an account, including its password hash, by email
+func (r *Users) GetUserByEmail(ctx context.Context, email string) (*user.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, u := range r.s.users {
+		if u.Email == email {
+			found := *u
+			return &found, nil
+		}
+	}
+	return nil, notFound(nil)
+}
+
+// GetUserByID retrieves an account, including its password hash, by ID
+func (r *Users) GetUserByID(ctx context.Context, userID int) (*user.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	u, ok := r.s.users[userID]
+	if !ok {
+		return nil, notFound(nil)
+	}
+	found := *u
+	return &found, nil
+}
+
+// PromoteToAdmin grants the admin role to the accounts with one of the
+// lower-cased emails
+func (r *Users) PromoteToAdmin(ctx context.Context, emails []string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, u := range r.s.users {
+		for _, email := range emails {
+			if strings.ToLower(u.Email) == email {
+				u.Role = user.RoleAdmin
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"event-planner/internal/db"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ObservePool exports the statistics of the connection pool
+func (m *Metrics) ObservePool(pool *pgxpool.Pool) {
+	m.registry.MustRegister(&poolCollector{pool: pool})
+}
+
+// QueryTracer returns the tracer timing the SQL statements; set it as the
+// ConnConfig.Tracer of the pool
+func (m *Metrics) QueryTracer() pgx.QueryTracer {
+	return &queryTracer{metrics: m}
+}
+
+type queryStartKey struct{}
+
+// queryStart is carried from TraceQueryStart to TraceQueryEnd
+type queryStart struct {
+	method string
+	at     time.Time
+}
+
+// queryTracer times statements and labels them with the repository method
+// that ran them (see db.QueryMethod)
+type queryTracer struct {
+	metrics *Metrics
+}
+
+func (t *queryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, _ pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, queryStartKey{}, queryStart{method: db.QueryMethod(), at: time.Now()})
+}
+
+func (t *queryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	start, ok := ctx.Value(queryStartKey{}).(queryStart)
+	if !ok {
+		return
+	}
+
+	t.metrics.queries.WithLabelValues(start.method).Observe(time.Since(start.at).Seconds())
+	if data.Err != nil {
+		t.metrics.queryErrors.WithLabelValues(start.method).Inc()
+	}
+}
+
+// poolCollector reads the statistics of the pool at every scrape
+type poolCollector struct {
+	pool *pgxpool.Pool
+}
+
+var (
+	poolAcquired = poolDesc("acquired_connections", "Connections in use.")
+	poolIdle     = poolDesc("idle_connections", "Idle connections.")
+	poolTotal    = poolDesc("total_connections", "Open connections, including those being established.")
+	poolMax      = poolDesc("max_connections", "Maximum size of the pool.")
+	poolAcquires = poolDesc("acquires_total", "Connections acquired from the pool.")
+	poolWaits    = poolDesc("acquire_waits_total", "Acquisitions that waited for a connection to be released or established.")
+	poolWaitTime = poolDesc("acquire_wait_seconds_total", "Time spent waiting for a connection.")
+	poolCanceled = poolDesc("canceled_acquires_total", "Acquisitions cancelled by their context.")
+)
+
+func poolDesc(name, help string) *prometheus.Desc {
+	return prometheus.NewDesc(prometheus.BuildFQName(namespace, "db_pool", name), help, nil, nil)
+}
+
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{poolAcquired, poolIdle, poolTotal, poolMax, poolAcquires, poolWaits, poolWaitTime, poolCanceled} {
+		ch <- desc
+	}
+}
+
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	stat := c.pool.Stat()
+
+	ch <- prometheus.MustNewConstMetric(poolAcquired, prometheus.GaugeValue, float64(stat.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(poolIdle, prometheus.GaugeValue, float64(stat.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(poolTotal, prometheus.GaugeValue, float64(stat.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(poolMax, prometheus.GaugeValue, float64(stat.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(poolAcquires, prometheus.CounterValue, float64(stat.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(poolWaits, prometheus.CounterValue, float64(stat.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(poolWaitTime, prometheus.CounterValue, stat.EmptyAcquireWaitTime().Seconds())
+	ch <- prometheus.MustNewConstMetric(poolCanceled, prometheus.CounterValue, float64(stat.CanceledAcquireCount()))
+}
@@ -0,0 +1,196 @@
+// Package metrics collects the Prometheus metrics of the API: requests by
+// route, the database pool and queries, and business activity
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace prefixes every metric name
+const namespace = "eventplanner"
+
+// Metrics holds the collectors of one server. The methods recording business
+// activity do nothing on a nil *Metrics, so services work without metrics.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	inFlight prometheus.Gauge
+
+	queries       *prometheus.HistogramVec
+	queryErrors   *prometheus.CounterVec
+	eventsCreated prometheus.Counter
+	eventJoins    prometheus.Counter
+	invitations   prometheus.Counter
+	responses     *prometheus.CounterVec
+	loginFailures *prometheus.CounterVec
+}
+
+// New creates the collectors, along with the Go runtime and process metrics
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests by method, route pattern and status code.",
+		}, []string{"method", "route", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Time to serve HTTP requests by method and route pattern.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		inFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "http_requests_in_flight",
+			Help:      "HTTP requests being served.",
+		}),
+
+		queries: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "db_query_duration_seconds",
+			Help:      "Time to run SQL statements by repository method.",
+			Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"method"}),
+		queryErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "db_query_errors_total",
+			Help:      "SQL statements that failed by repository method.",
+		}, []string{"method"}),
+
+		eventsCreated: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "events_created_total",
+			Help:      "Events created.",
+		}),
+		eventJoins: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "event_joins_total",
+			Help:      "Users who joined an event.",
+		}),
+		invitations: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "invitations_sent_total",
+			Help:      "Invitations sent, one per invitee of group invitations.",
+		}),
+		responses: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "invitation_responses_total",
+			Help:      "Invitations answered by status (accepted or declined).",
+		}, []string{"status"}),
+		loginFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "login_failures_total",
+			Help:      "Failed logins by reason (invalid_credentials or account_disabled).",
+		}, []string{"reason"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests, m.duration, m.inFlight,
+		m.queries, m.queryErrors,
+		m.eventsCreated, m.eventJoins, m.invitations, m.responses, m.loginFailures,
+	)
+
+	// Both responses and reasons are known, so they are exported before they first happen
+	for _, status := range []string{"accepted", "declined"} {
+		m.responses.WithLabelValues(status)
+	}
+	for _, reason := range []string{"invalid_credentials", "account_disabled"} {
+		m.loginFailures.WithLabelValues(reason)
+	}
+
+	return m
+}
+
+// Handler serves the metrics in the Prometheus text format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// Middleware records the rate, errors and duration of requests. It labels
+// them with the chi route pattern (/events/{id}) rather than the path, so
+// the number of series stays bounded; it must be used on the root router.
+func (m *Metrics) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		m.inFlight.Inc()
+		defer m.inFlight.Dec()
+
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r)
+
+		// The pattern is complete once the routers have matched the request
+		route := "unmatched"
+		if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+			route = rctx.RoutePattern()
+		}
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		method := requestMethod(r.Method)
+		m.requests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+		m.duration.WithLabelValues(method, route).Observe(time.Since(start).Seconds())
+	})
+}
+
+// requestMethod returns the method, or OTHER for methods outside of HTTP
+// so clients can't create series
+func requestMethod(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodOptions, http.MethodConnect, http.MethodTrace:
+		return method
+	}
+	return "OTHER"
+}
+
+// EventCreated counts an event created
+func (m *Metrics) EventCreated() {
+	if m != nil {
+		m.eventsCreated.Inc()
+	}
+}
+
+// EventJoined counts a user joining an event
+func (m *Metrics) EventJoined() {
+	if m != nil {
+		m.eventJoins.Inc()
+	}
+}
+
+// InvitationSent counts an invitation sent
+func (m *Metrics) InvitationSent() {
+	if m != nil {
+		m.invitations.Inc()
+	}
+}
+
+// InvitationAnswered counts an invitation accepted or declined
+func (m *Metrics) InvitationAnswered(status string) {
+	if m != nil {
+		m.responses.WithLabelValues(status).Inc()
+	}
+}
+
+// LoginFailed counts a login refused for the reason
+func (m *Metrics) LoginFailed(reason string) {
+	if m != nil {
+		m.loginFailures.WithLabelValues(reason).Inc()
+	}
+}
@@ -0,0 +1,496 @@
+// Package migrate applies the versioned SQL migrations embedded in the binary.
+//
+// Migrations live in migrations/ as NNNN_name.up.sql with an optional
+// NNNN_name.down.sql. Applied versions are recorded with a checksum in the
+// schema_migrations table, and an advisory lock makes concurrent runs (such as
+// several replicas starting at once) wait for each other.
+package migrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+//go:embed migrations/*.sql
+var files embed.FS
+
+// lockClass is the first half of the advisory lock key; the second half is the
+// hashed schema name, so schemas sharing a database don't block each other
+const lockClass int32 = 7_400_001
+
+const insertVersion = `INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)`
+
+var fileRegex = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration is one versioned schema change
+type Migration struct {
+	Version  int    `json:"version"`
+	Name     string `json:"name"`
+	Up       string `json:"-"`
+	Down     string `json:"-"`        // empty when the migration cannot be reverted
+	Checksum string `json:"checksum"` // SHA-256 of Up, ignoring line endings
+}
+
+func (m Migration) String() string {
+	return fmt.Sprintf("%04d_%s", m.Version, m.Name)
+}
+
+// Status is a migration and when it was applied, nil while pending
+type Status struct {
+	Migration
+	AppliedAt *time.Time `json:"applied_at"`
+}
+
+// DriftError lists the ways the database differs from the embedded migrations
+type DriftError struct {
+	Problems []string
+}
+
+func (e *DriftError) Error() string {
+	return "schema drift: " + strings.Join(e.Problems, "; ")
+}
+
+// applied is a row of schema_migrations
+type applied struct {
+	name      string
+	checksum  string
+	appliedAt time.Time
+}
+
+// Migrator applies the embedded migrations to a database
+type Migrator struct {
+	pool       *pgxpool.Pool
+	migrations []Migration
+}
+
+func New(pool *pgxpool.Pool) (*Migrator, error) {
+	migrations, err := load(files)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{pool: pool, migrations: migrations}, nil
+}
+
+// load reads and pairs the migration files, oldest first
+func load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, entry := range entries {
+		match := fileRegex.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("unexpected migration file %s", entry.Name())
+		}
+
+		body, err := fs.ReadFile(fsys, "migrations/"+entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		version, _ := strconv.Atoi(match[1])
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = mig
+		} else if mig.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has two names: %s and %s", version, mig.Name, match[2])
+		}
+
+		sql := strings.ReplaceAll(string(body), "\r\n", "\n")
+		if match[3] == "up" {
+			mig.Up = sql
+		} else {
+			mig.Down = sql
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		if mig.Up == "" {
+			return nil, fmt.Errorf("migration %s has no up file", mig)
+		}
+		sum := sha256.Sum256([]byte(mig.Up))
+		mig.Checksum = hex.EncodeToString(sum[:])
+		migrations = append(migrations, *mig)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
+
+// Migrations returns the embedded migrations, oldest first
+func (m *Migrator) Migrations() []Migration {
+	return m.migrations
+}
+
+// Up applies the pending migrations in order, each in its own transaction,
+// and returns them. Nothing is applied when the recorded history has drifted
+// from the embedded migrations.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var done []Migration
+	err := m.withLock(ctx, func(conn *pgx.Conn) error {
+		if err := m.prepare(ctx, conn); err != nil {
+			return err
+		}
+
+		history, err := readHistory(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if problems := m.historyProblems(history); len(problems) > 0 {
+			return &DriftError{Problems: problems}
+		}
+
+		for _, mig := range m.migrations {
+			if _, ok := history[mig.Version]; ok {
+				continue
+			}
+			if err := execRecorded(ctx, conn, mig.Up, insertVersion, mig.Version, mig.Name, mig.Checksum); err != nil {
+				return fmt.Errorf("migration %s failed: %w", mig, err)
+			}
+			done = append(done, mig)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Down reverts the latest steps applied migrations, newest first, and
+// returns them
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var done []Migration
+	err := m.withLock(ctx, func(conn *pgx.Conn) error {
+		if err := m.prepare(ctx, conn); err != nil {
+			return err
+		}
+
+		history, err := readHistory(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if problems := m.historyProblems(history); len(problems) > 0 {
+			return &DriftError{Problems: problems}
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+			mig := m.migrations[i]
+			if _, ok := history[mig.Version]; !ok {
+				continue
+			}
+			if mig.Down == "" {
+				return fmt.Errorf("migration %s cannot be reverted", mig)
+			}
+			if err := execRecorded(ctx, conn, mig.Down, `DELETE FROM schema_migrations WHERE version = $1`, mig.Version); err != nil {
+				return fmt.Errorf("reverting %s failed: %w", mig, err)
+			}
+			done = append(done, mig)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Status lists the embedded migrations and when each one was applied
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+
+	history, err := readHistory(ctx, conn.Conn())
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, len(m.migrations))
+	for i, mig := range m.migrations {
+		statuses[i] = Status{Migration: mig}
+		if row, ok := history[mig.Version]; ok {
+			statuses[i].AppliedAt = &row.appliedAt
+		}
+	}
+	return statuses, nil
+}
+
+// Pending returns the embedded migrations that are not applied yet, oldest
+// first. It takes no lock and doesn't look at the schema, so it is cheap
+// enough for readiness probes.
+func (m *Migrator) Pending(ctx context.Context) ([]Migration, error) {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for _, st := range statuses {
+		if st.AppliedAt == nil {
+			pending = append(pending, st.Migration)
+		}
+	}
+	return pending, nil
+}
+
+// Check compares the database with the embedded migrations: the recorded
+// history must match them, none may be pending, and the tables, columns,
+// indexes and constraints must be those the applied migrations create.
+// Differences are returned as a *DriftError.
+func (m *Migrator) Check(ctx context.Context) error {
+	return m.withLock(ctx, func(conn *pgx.Conn) error {
+		history, err := readHistory(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		problems := m.historyProblems(history)
+		for _, mig := range m.migrations {
+			if _, ok := history[mig.Version]; !ok {
+				problems = append(problems, fmt.Sprintf("%s is pending", mig))
+			}
+		}
+
+		schemaProblems, err := m.schemaProblems(ctx, conn, history)
+		if err != nil {
+			return fmt.Errorf("failed to compare schemas: %w", err)
+		}
+		problems = append(problems, schemaProblems...)
+
+		if len(problems) > 0 {
+			return &DriftError{Problems: problems}
+		}
+		return nil
+	})
+}
+
+// withLock runs fn on a single connection holding the migration lock
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *pgx.Conn) error) error {
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1, hashtext(current_schema()))`, lockClass); err != nil {
+		return fmt.Errorf("failed to take the migration lock: %w", err)
+	}
+	defer func() {
+		// A session that cannot unlock is closed, which releases the lock
+		if _, err := conn.Exec(context.Background(), `SELECT pg_advisory_unlock($1, hashtext(current_schema()))`, lockClass); err != nil {
+			conn.Conn().Close(context.Background())
+		}
+	}()
+
+	return fn(conn.Conn())
+}
+
+// prepare creates the schema_migrations table and records the first migration
+// as applied on databases created from the old schema.sql, which have its
+// tables but no migration history; Up then runs the later migrations on them
+func (m *Migrator) prepare(ctx context.Context, conn *pgx.Conn) error {
+	query := `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INT PRIMARY KEY,
+			name TEXT NOT NULL,
+			checksum TEXT NOT NULL,
+			applied_at TIMESTAMP NOT NULL DEFAULT NOW()
+		)
+	`
+	if _, err := conn.Exec(ctx, query); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	var legacy bool
+	query = `SELECT NOT EXISTS (SELECT 1 FROM schema_migrations) AND to_regclass('users') IS NOT NULL`
+	if err := conn.QueryRow(ctx, query).Scan(&legacy); err != nil {
+		return err
+	}
+	if !legacy || len(m.migrations) == 0 {
+		return nil
+	}
+
+	first := m.migrations[0]
+	_, err := conn.Exec(ctx, insertVersion, first.Version, first.Name, first.Checksum)
+	return err
+}
+
+// readHistory returns the applied migrations by version, none when the
+// schema_migrations table doesn't exist yet
+func readHistory(ctx context.Context, conn *pgx.Conn) (map[int]applied, error) {
+	var exists bool
+	if err := conn.QueryRow(ctx, `SELECT to_regclass('schema_migrations') IS NOT NULL`).Scan(&exists); err != nil {
+		return nil, err
+	}
+
+	history := map[int]applied{}
+	if !exists {
+		return history, nil
+	}
+
+	rows, err := conn.Query(ctx, `SELECT version, name, checksum, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var version int
+		var row applied
+		if err := rows.Scan(&version, &row.name, &row.checksum, &row.appliedAt); err != nil {
+			return nil, err
+		}
+		history[version] = row
+	}
+	return history, rows.Err()
+}
+
+// historyProblems reports applied migrations that were changed afterwards or
+// that this build doesn't know, e.g. after rolling back to an older release
+func (m *Migrator) historyProblems(history map[int]applied) []string {
+	known := map[int]Migration{}
+	for _, mig := range m.migrations {
+		known[mig.Version] = mig
+	}
+
+	versions := make([]int, 0, len(history))
+	for version := range history {
+		versions = append(versions, version)
+	}
+	sort.Ints(versions)
+
+	var problems []string
+	for _, version := range versions {
+		row := history[version]
+		mig, ok := known[version]
+		switch {
+		case !ok:
+			problems = append(problems, fmt.Sprintf("%04d_%s is applied but unknown to this build", version, row.name))
+		case row.checksum != mig.Checksum:
+			problems = append(problems, fmt.Sprintf("%s was modified after it was applied", mig))
+		}
+	}
+	return problems
+}
+
+// schemaProblems applies the migrations recorded in history to a scratch
+// schema inside a transaction that is rolled back, and compares the result
+// with the live schema
+func (m *Migrator) schemaProblems(ctx context.Context, conn *pgx.Conn, history map[int]applied) ([]string, error) {
+	live, err := describeSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	scratch := fmt.Sprintf("migrate_check_%d", time.Now().UnixNano())
+	if _, err := tx.Exec(ctx, "CREATE SCHEMA "+scratch); err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(ctx, "SET LOCAL search_path TO "+scratch); err != nil {
+		return nil, err
+	}
+	for _, mig := range m.migrations {
+		if _, ok := history[mig.Version]; !ok {
+			continue
+		}
+		if _, err := tx.Exec(ctx, mig.Up); err != nil {
+			return nil, fmt.Errorf("migration %s failed: %w", mig, err)
+		}
+	}
+
+	expected, err := describeSchema(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []string
+	for _, item := range expected {
+		if !contains(live, item) {
+			problems = append(problems, "missing "+item)
+		}
+	}
+	for _, item := range live {
+		if !contains(expected, item) {
+			problems = append(problems, "unexpected "+item)
+		}
+	}
+	return problems, nil
+}
+
+// describeSchema lists the columns, indexes and constraints of the current
+// schema, one sorted line each, leaving out schema_migrations
+func describeSchema(ctx context.Context, q interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}) ([]string, error) {
+	query := `
+		SELECT format('column %s.%s %s%s default %s',
+			table_name, column_name, data_type,
+			CASE WHEN is_nullable = 'NO' THEN ' not null' ELSE '' END,
+			COALESCE(column_default, 'none'))
+		FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name <> 'schema_migrations'
+		UNION ALL
+		SELECT format('index %s.%s', tablename, indexname)
+		FROM pg_indexes
+		WHERE schemaname = current_schema() AND tablename <> 'schema_migrations'
+		UNION ALL
+		SELECT format('constraint %s.%s %s', rel.relname, con.conname, pg_get_constraintdef(con.oid))
+		FROM pg_constraint con
+		JOIN pg_class rel ON rel.oid = con.conrelid
+		JOIN pg_namespace ns ON ns.oid = rel.relnamespace
+		WHERE ns.nspname = current_schema() AND rel.relname <> 'schema_migrations'
+	`
+	rows, err := q.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(items)
+	return items, nil
+}
+
+func contains(sorted []string, item string) bool {
+	i := sort.SearchStrings(sorted, item)
+	return i < len(sorted) && sorted[i] == item
+}
+
+// execRecorded runs a migration script and the statement recording it in one
+// transaction
+func execRecorded(ctx context.Context, conn *pgx.Conn, script, record string, args ...any) error {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, script); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, record, args...); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
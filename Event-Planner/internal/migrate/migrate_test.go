@@ -0,0 +1,82 @@
+package migrate_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"event-planner/internal/migrate"
+	"event-planner/internal/storetest"
+)
+
+func TestUpAndCheck(t *testing.T) {
+	pool := storetest.EmptyPostgresPool(t)
+	ctx := context.Background()
+
+	migrator, err := migrate.New(pool)
+	if err != nil {
+		t.Fatalf("migrate.New: %v", err)
+	}
+	done, err := migrator.Up(ctx)
+	if err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	if len(done) != len(migrator.Migrations()) {
+		t.Errorf("Up applied %d migrations, want %d", len(done), len(migrator.Migrations()))
+	}
+	if err := migrator.Check(ctx); err != nil {
+		t.Errorf("Check: %v", err)
+	}
+}
+
+// TestUpgradeFromSchemaSQL upgrades a database created from the schema.sql
+// that preceded the migrations, testdata/schema.sql, with data in it
+func TestUpgradeFromSchemaSQL(t *testing.T) {
+	pool := storetest.EmptyPostgresPool(t)
+	ctx := context.Background()
+
+	schema, err := os.ReadFile("testdata/schema.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pool.Exec(ctx, string(schema)); err != nil {
+		t.Fatalf("create the schema.sql tables: %v", err)
+	}
+	seed := `
+		INSERT INTO users (email, password_hash) VALUES ('ada@example.com', 'hash');
+		INSERT INTO events (title, date, time, location, organizer_id)
+		SELECT 'Launch', '2030-01-10', '09:00', 'Main Hall', id FROM users;
+		INSERT INTO invitations (event_id, inviter_id, invitee_email, role)
+		SELECT id, organizer_id, 'bob@example.com', 'attendee' FROM events;
+	`
+	if _, err := pool.Exec(ctx, seed); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+
+	migrator, err := migrate.New(pool)
+	if err != nil {
+		t.Fatalf("migrate.New: %v", err)
+	}
+	done, err := migrator.Up(ctx)
+	if err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+
+	// The schema.sql tables are the first migration, which is not run again
+	all := migrator.Migrations()
+	if len(done) != len(all)-1 || done[0].Version != all[1].Version {
+		t.Errorf("Up applied %v, want every migration after %s", done, all[0])
+	}
+	if err := migrator.Check(ctx); err != nil {
+		t.Errorf("Check: %v", err)
+	}
+
+	var role, visibility, status string
+	query := `SELECT u.role, e.visibility, e.status FROM users u JOIN events e ON e.organizer_id = u.id`
+	if err := pool.QueryRow(ctx, query).Scan(&role, &visibility, &status); err != nil {
+		t.Fatalf("read the upgraded rows: %v", err)
+	}
+	if role != "member" || visibility != "public" || status != "scheduled" {
+		t.Errorf("upgraded rows: role %q, visibility %q, status %q", role, visibility, status)
+	}
+}
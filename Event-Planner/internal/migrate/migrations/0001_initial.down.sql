@@ -0,0 +1,5 @@
+-- Drops everything created by 0001_initial.up.sql
+DROP TABLE IF EXISTS invitations;
+DROP TABLE IF EXISTS event_attendees;
+DROP TABLE IF EXISTS events;
+DROP TABLE IF EXISTS users;
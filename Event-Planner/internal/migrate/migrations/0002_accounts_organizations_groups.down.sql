@@ -0,0 +1,27 @@
+-- Drops everything created by 0002_accounts_organizations_groups.up.sql
+DROP TABLE IF EXISTS event_group_invitations;
+ALTER TABLE invitations DROP COLUMN IF EXISTS group_id;
+DROP TABLE IF EXISTS group_members;
+DROP TABLE IF EXISTS user_groups;
+
+DROP INDEX IF EXISTS idx_events_organization;
+ALTER TABLE events DROP CONSTRAINT IF EXISTS events_check;
+ALTER TABLE events DROP COLUMN IF EXISTS archived_at;
+ALTER TABLE events DROP COLUMN IF EXISTS timezone;
+ALTER TABLE events DROP COLUMN IF EXISTS visibility;
+ALTER TABLE events DROP COLUMN IF EXISTS organization_id;
+ALTER TABLE events DROP CONSTRAINT IF EXISTS events_organizer_id_fkey;
+ALTER TABLE events
+    ADD CONSTRAINT events_organizer_id_fkey FOREIGN KEY (organizer_id) REFERENCES users(id) ON DELETE CASCADE;
+
+DROP TABLE IF EXISTS organization_members;
+DROP TABLE IF EXISTS organizations;
+DROP TABLE IF EXISTS user_profiles;
+DROP TABLE IF EXISTS admin_actions;
+
+DROP INDEX IF EXISTS idx_users_deletion_scheduled;
+ALTER TABLE users DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE users DROP COLUMN IF EXISTS deletion_scheduled_for;
+ALTER TABLE users DROP COLUMN IF EXISTS deletion_requested_at;
+ALTER TABLE users DROP COLUMN IF EXISTS disabled_at;
+ALTER TABLE users DROP COLUMN IF EXISTS role;
@@ -0,0 +1,145 @@
+-- ==========================
+-- USERS: ROLES, DISABLED AND DELETED ACCOUNTS
+-- ==========================
+-- self-service deletion: erased after the grace period, the row is kept
+-- as a pseudonymous tombstone (deleted_at set) so references survive
+ALTER TABLE users
+    ADD COLUMN role TEXT NOT NULL DEFAULT 'member' CHECK (role IN ('admin', 'support', 'member')),
+    ADD COLUMN disabled_at TIMESTAMP NULL,
+    ADD COLUMN deletion_requested_at TIMESTAMP NULL,
+    ADD COLUMN deletion_scheduled_for TIMESTAMP NULL,
+    ADD COLUMN deleted_at TIMESTAMP NULL;
+
+CREATE INDEX idx_users_deletion_scheduled ON users(deletion_scheduled_for) WHERE deletion_scheduled_for IS NOT NULL;
+
+
+-- ==========================
+-- ADMIN_ACTIONS TABLE
+-- ==========================
+-- record of every action taken through the admin endpoints;
+-- target_id is not a foreign key so records survive deletions
+CREATE TABLE admin_actions (
+    id SERIAL PRIMARY KEY,
+    actor_id INT REFERENCES users(id) ON DELETE SET NULL,
+    action TEXT NOT NULL,
+    target_type TEXT NOT NULL,
+    target_id INT NOT NULL,
+    details JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_admin_actions_target ON admin_actions(target_type, target_id);
+CREATE INDEX idx_admin_actions_actor ON admin_actions(actor_id);
+
+
+-- ==========================
+-- USER_PROFILES TABLE
+-- ==========================
+-- one optional row per user; missing rows mean default preferences
+CREATE TABLE user_profiles (
+    user_id INT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    display_name TEXT NOT NULL DEFAULT '',
+    avatar_key TEXT,
+    avatar_url TEXT,
+    bio TEXT NOT NULL DEFAULT '',
+    timezone TEXT NOT NULL DEFAULT 'UTC',
+    locale TEXT NOT NULL DEFAULT 'en',
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+
+-- ==========================
+-- ORGANIZATIONS TABLES
+-- ==========================
+-- workspaces that scope events, invitations and search;
+-- default_visibility / timezone are applied to new events
+CREATE TABLE organizations (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    slug TEXT UNIQUE NOT NULL,
+    default_visibility TEXT NOT NULL DEFAULT 'organization' CHECK (default_visibility IN ('public', 'organization')),
+    timezone TEXT NOT NULL DEFAULT 'UTC',
+    created_by INT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE TABLE organization_members (
+    organization_id INT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role TEXT NOT NULL DEFAULT 'member' CHECK (role IN ('owner', 'admin', 'member')),
+    created_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (organization_id, user_id)
+);
+
+CREATE INDEX idx_organization_members_user ON organization_members(user_id);
+
+
+-- ==========================
+-- EVENTS: ORGANIZATIONS AND ARCHIVING
+-- ==========================
+-- accounts are erased via internal/account, never by deleting the users row
+ALTER TABLE events DROP CONSTRAINT events_organizer_id_fkey;
+ALTER TABLE events
+    ADD CONSTRAINT events_organizer_id_fkey FOREIGN KEY (organizer_id) REFERENCES users(id) ON DELETE RESTRICT;
+
+-- archived_at is set when the organizer was erased and nobody could take the
+-- event over; personal events have no organization to restrict them to
+ALTER TABLE events
+    ADD COLUMN organization_id INT REFERENCES organizations(id) ON DELETE CASCADE,
+    ADD COLUMN visibility TEXT NOT NULL DEFAULT 'public' CHECK (visibility IN ('public', 'organization')),
+    ADD COLUMN timezone TEXT NOT NULL DEFAULT 'UTC',
+    ADD COLUMN archived_at TIMESTAMP NULL;
+
+ALTER TABLE events
+    ADD CONSTRAINT events_check CHECK (organization_id IS NOT NULL OR visibility = 'public');
+
+CREATE INDEX idx_events_organization ON events(organization_id);
+
+
+-- ==========================
+-- USER GROUPS
+-- ==========================
+-- personal distribution lists ("design team", "board members");
+-- members are stored by email so people without an account can be listed,
+-- registered users are resolved by joining users on email
+CREATE TABLE user_groups (
+    id SERIAL PRIMARY KEY,
+    owner_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_user_groups_owner ON user_groups(owner_id);
+
+CREATE TABLE group_members (
+    id SERIAL PRIMARY KEY,
+    group_id INT NOT NULL REFERENCES user_groups(id) ON DELETE CASCADE,
+    email TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE(group_id, email)
+);
+
+CREATE INDEX idx_group_members_email ON group_members(email);
+
+
+-- ==========================
+-- GROUP INVITATIONS
+-- ==========================
+-- group_id is set when the invitation came from a group
+ALTER TABLE invitations ADD COLUMN group_id INT REFERENCES user_groups(id) ON DELETE SET NULL;
+
+-- keeps the link between a group and an event it was invited to,
+-- so members added later can be invited when invite_new_members is set
+CREATE TABLE event_group_invitations (
+    event_id INT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    group_id INT NOT NULL REFERENCES user_groups(id) ON DELETE CASCADE,
+    inviter_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role TEXT NOT NULL CHECK (role IN ('attendee', 'collaborator', 'organizer')),
+    message TEXT NOT NULL DEFAULT '',
+    invite_new_members BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (event_id, group_id)
+);
+
+CREATE INDEX idx_event_group_invitations_group ON event_group_invitations(group_id);
@@ -0,0 +1,3 @@
+-- Drops everything created by 0003_audit_log.up.sql
+DROP TABLE IF EXISTS audit_log;
+DROP FUNCTION IF EXISTS audit_log_append_only();
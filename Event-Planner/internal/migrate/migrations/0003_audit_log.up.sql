@@ -0,0 +1,35 @@
+-- ==========================
+-- AUDIT_LOG TABLE
+-- ==========================
+-- append-only record of security- and data-relevant actions: who did what to
+-- which record, the fields it changed and the request it came from.
+-- actor_id, target_id and event_id are not foreign keys so records survive
+-- deletions; the trigger below rejects updates and deletes
+CREATE TABLE audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    actor_id INT,
+    action TEXT NOT NULL,
+    target_type TEXT NOT NULL,
+    target_id INT NOT NULL,
+    event_id INT,
+    before JSONB,
+    after JSONB,
+    request_id TEXT NOT NULL DEFAULT '',
+    ip TEXT NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_audit_log_event ON audit_log(event_id, created_at DESC);
+CREATE INDEX idx_audit_log_target ON audit_log(target_type, target_id);
+CREATE INDEX idx_audit_log_actor ON audit_log(actor_id);
+
+CREATE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_append_only
+    BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
@@ -0,0 +1,2 @@
+-- Drops everything created by 0004_event_revisions.up.sql
+DROP TABLE IF EXISTS event_revisions;
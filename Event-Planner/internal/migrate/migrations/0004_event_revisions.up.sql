@@ -0,0 +1,29 @@
+-- ==========================
+-- EVENT_REVISIONS TABLE
+-- ==========================
+-- the editable fields of an event as of each edit: revision 1 is recorded
+-- when the event is created, then one more per edit that changes it
+CREATE TABLE event_revisions (
+    id SERIAL PRIMARY KEY,
+    event_id INT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    revision INT NOT NULL,
+    editor_id INT REFERENCES users(id) ON DELETE SET NULL,
+    -- fields that differ from the previous revision
+    changed_fields TEXT[] NOT NULL DEFAULT '{}',
+    -- set when the edit restored an earlier revision
+    restored_from INT,
+    title TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    date DATE NOT NULL,
+    time TIME NOT NULL,
+    location TEXT NOT NULL,
+    visibility TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE (event_id, revision)
+);
+
+-- Existing events start their history at their current state
+INSERT INTO event_revisions (event_id, revision, editor_id, changed_fields, title, description, date, time, location, visibility, created_at)
+SELECT id, 1, organizer_id, ARRAY['title', 'description', 'date', 'time', 'location', 'visibility'],
+       title, COALESCE(description, ''), date, time, location, visibility, created_at
+FROM events;
@@ -0,0 +1,2 @@
+-- Drops everything created by 0005_event_versions.up.sql
+ALTER TABLE events DROP COLUMN IF EXISTS version;
@@ -0,0 +1,6 @@
+-- ==========================
+-- EVENT VERSIONS
+-- ==========================
+-- counts the changes to an event; clients send it back in If-Match so
+-- concurrent edits can't silently overwrite each other
+ALTER TABLE events ADD COLUMN version INT NOT NULL DEFAULT 1;
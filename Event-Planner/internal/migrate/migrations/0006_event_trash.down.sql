@@ -0,0 +1,3 @@
+-- Drops everything created by 0006_event_trash.up.sql
+DROP INDEX IF EXISTS idx_events_deleted_at;
+ALTER TABLE events DROP COLUMN IF EXISTS deleted_at;
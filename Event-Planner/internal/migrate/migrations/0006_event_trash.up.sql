@@ -0,0 +1,8 @@
+-- ==========================
+-- EVENT TRASH
+-- ==========================
+-- deleted events stay in their organizer's trash, where they can be restored,
+-- until the purge worker erases them at the end of the retention period
+ALTER TABLE events ADD COLUMN deleted_at TIMESTAMP NULL;
+
+CREATE INDEX idx_events_deleted_at ON events(deleted_at) WHERE deleted_at IS NOT NULL;
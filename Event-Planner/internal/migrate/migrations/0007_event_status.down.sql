@@ -0,0 +1,5 @@
+-- Drops everything created by 0007_event_status.up.sql
+DROP TABLE IF EXISTS notifications;
+DROP INDEX IF EXISTS idx_events_status;
+ALTER TABLE events DROP COLUMN IF EXISTS status_reason;
+ALTER TABLE events DROP COLUMN IF EXISTS status;
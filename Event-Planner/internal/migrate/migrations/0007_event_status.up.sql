@@ -0,0 +1,33 @@
+-- ==========================
+-- EVENT STATUS
+-- ==========================
+-- where an event is in its lifecycle; cancelled and postponed events keep
+-- the reason their organizer gave, and stay listed so attendees see it
+ALTER TABLE events
+    ADD COLUMN status TEXT NOT NULL DEFAULT 'scheduled'
+        CHECK (status IN ('draft', 'scheduled', 'postponed', 'cancelled', 'completed')),
+    ADD COLUMN status_reason TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX idx_events_status ON events(status);
+
+
+-- ==========================
+-- NOTIFICATIONS TABLE
+-- ==========================
+-- messages to the attendees and pending invitees of an event when it is
+-- cancelled or postponed; stored by email like invitations, so people
+-- without an account find them once they register
+CREATE TABLE notifications (
+    id SERIAL PRIMARY KEY,
+    recipient_email TEXT NOT NULL,
+    event_id INT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    -- the status the event was given, and the title and reason at the time
+    event_status TEXT NOT NULL,
+    event_title TEXT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT NOW(),
+    read_at TIMESTAMP NULL
+);
+
+CREATE INDEX idx_notifications_recipient ON notifications(recipient_email);
+CREATE INDEX idx_notifications_event ON notifications(event_id);
@@ -0,0 +1,4 @@
+-- Drops everything created by 0008_event_drafts.up.sql
+DROP INDEX IF EXISTS idx_events_publish_at;
+ALTER TABLE events DROP CONSTRAINT IF EXISTS events_publish_at_check;
+ALTER TABLE events DROP COLUMN IF EXISTS publish_at;
@@ -0,0 +1,10 @@
+-- ==========================
+-- EVENT DRAFTS
+-- ==========================
+-- drafts are only visible to their organizer and collaborators until they
+-- are published, by the organizer or at publish_at by the status worker
+ALTER TABLE events
+    ADD COLUMN publish_at TIMESTAMP NULL,
+    ADD CONSTRAINT events_publish_at_check CHECK (publish_at IS NULL OR status = 'draft');
+
+CREATE INDEX idx_events_publish_at ON events(publish_at) WHERE publish_at IS NOT NULL;
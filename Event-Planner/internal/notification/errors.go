@@ -0,0 +1,8 @@
+package notification
+
+import "event-planner/internal/apperror"
+
+// Domain errors returned by the notification service
+var (
+	ErrNotificationNotFound = apperror.NotFound("notification_not_found", "notification not found")
+)
@@ -0,0 +1,77 @@
+package notification
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"event-planner/internal/apperror"
+	"event-planner/internal/auth"
+	"event-planner/internal/response"
+)
+
+// Handler handles HTTP requests for notifications
+type Handler struct {
+	service *Service
+}
+
+// NewHandler creates a new notification handler
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// GetMyNotifications handles GET /notifications
+func (h *Handler) GetMyNotifications(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, apperror.ErrUnauthorized)
+		return
+	}
+
+	unreadOnly := false
+	if v := r.URL.Query().Get("unread"); v != "" {
+		var err error
+		if unreadOnly, err = strconv.ParseBool(v); err != nil {
+			response.Error(w, apperror.Validation("unread", "unread must be true or false"))
+			return
+		}
+	}
+
+	notifications, err := h.service.GetMyNotifications(r.Context(), userID, unreadOnly)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data": notifications,
+	})
+}
+
+// MarkRead handles POST /notifications/{id}/read
+func (h *Handler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, apperror.ErrUnauthorized)
+		return
+	}
+
+	notificationID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		response.Error(w, apperror.Validation("id", "invalid notification ID"))
+		return
+	}
+
+	if err := h.service.MarkRead(r.Context(), notificationID, userID); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "notification marked as read",
+	})
+}
@@ -0,0 +1,25 @@
+// Package notification keeps the messages users get when an event they
+// attend or were invited to is cancelled or postponed
+package notification
+
+import (
+	"fmt"
+	"time"
+)
+
+// Notification tells a user that an event was given a new status
+type Notification struct {
+	ID          int        `json:"id"`
+	EventID     int        `json:"event_id"`
+	EventStatus string     `json:"event_status"` // the status the event was given: 'cancelled' or 'postponed'
+	EventTitle  string     `json:"event_title"`  // as of the change
+	Reason      string     `json:"reason"`
+	Message     string     `json:"message"` // e.g. "Launch was cancelled: the venue is closed"
+	CreatedAt   time.Time  `json:"created_at"`
+	ReadAt      *time.Time `json:"read_at,omitempty"`
+}
+
+// message describes the change for display
+func (n *Notification) message() string {
+	return fmt.Sprintf("%s was %s: %s", n.EventTitle, n.EventStatus, n.Reason)
+}
@@ -0,0 +1,76 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+
+	"event-planner/internal/db"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository handles all database operations for notifications
+type Repository struct {
+	db *pgxpool.Pool
+}
+
+// NewRepository creates a new notification repository
+func NewRepository(db *pgxpool.Pool) *Repository {
+	return &Repository{db: db}
+}
+
+// GetNotifications retrieves the notifications sent to the email of a user,
+// newest first
+func (r *Repository) GetNotifications(ctx context.Context, userID int, unreadOnly bool) ([]Notification, error) {
+	query := `
+		SELECT n.id, n.event_id, n.event_status, n.event_title, n.reason, n.created_at, n.read_at
+		FROM notifications n
+		JOIN users u ON u.email = n.recipient_email
+		WHERE u.id = $1 AND (NOT $2 OR n.read_at IS NULL)
+		ORDER BY n.created_at DESC, n.id DESC
+	`
+
+	rows, err := r.db.Query(ctx, query, userID, unreadOnly)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notifications: %w", err)
+	}
+	defer rows.Close()
+
+	var notifications []Notification
+	for rows.Next() {
+		n := Notification{}
+		err := rows.Scan(&n.ID, &n.EventID, &n.EventStatus, &n.EventTitle, &n.Reason, &n.CreatedAt, &n.ReadAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan notification: %w", err)
+		}
+		notifications = append(notifications, n)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating notifications: %w", err)
+	}
+
+	return notifications, nil
+}
+
+// MarkRead sets the time a notification sent to the email of a user was
+// read, unless it already was; notifications of other users are not found
+func (r *Repository) MarkRead(ctx context.Context, notificationID, userID int) error {
+	query := `
+		UPDATE notifications n
+		SET read_at = COALESCE(n.read_at, NOW())
+		FROM users u
+		WHERE n.id = $1 AND u.id = $2 AND u.email = n.recipient_email
+	`
+
+	result, err := r.db.Exec(ctx, query, notificationID, userID)
+	if err != nil {
+		return db.TranslateError(fmt.Errorf("failed to mark notification as read: %w", err), nil)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrNotificationNotFound
+	}
+
+	return nil
+}
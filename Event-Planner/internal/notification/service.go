@@ -0,0 +1,63 @@
+package notification
+
+import (
+	"context"
+
+	"event-planner/internal/apperror"
+	"event-planner/internal/tracing"
+)
+
+// Store is the notification storage used by Service; Repository implements it
+// on PostgreSQL. Notifications are written by the event storage, along with
+// the status change they are about.
+type Store interface {
+	GetNotifications(ctx context.Context, userID int, unreadOnly bool) ([]Notification, error)
+	MarkRead(ctx context.Context, notificationID, userID int) error
+}
+
+// Service handles business logic for notifications
+type Service struct {
+	repo Store
+}
+
+// NewService creates a new notification service
+func NewService(repo Store) *Service {
+	return &Service{repo: repo}
+}
+
+// GetMyNotifications lists the notifications of a user, newest first
+func (s *Service) GetMyNotifications(ctx context.Context, userID int, unreadOnly bool) ([]Notification, error) {
+	ctx, span := tracing.Start(ctx, "notification.Service.GetMyNotifications")
+	defer span.End()
+
+	if userID <= 0 {
+		return nil, apperror.Validation("user_id", "invalid user ID")
+	}
+
+	notifications, err := s.repo.GetNotifications(ctx, userID, unreadOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	if notifications == nil {
+		notifications = []Notification{}
+	}
+	for i := range notifications {
+		notifications[i].Message = notifications[i].message()
+	}
+
+	return notifications, nil
+}
+
+// MarkRead marks a notification of the user as read; marking it again keeps
+// the time it was first read
+func (s *Service) MarkRead(ctx context.Context, notificationID, userID int) error {
+	ctx, span := tracing.Start(ctx, "notification.Service.MarkRead")
+	defer span.End()
+
+	if notificationID <= 0 {
+		return apperror.Validation("id", "invalid notification ID")
+	}
+
+	return s.repo.MarkRead(ctx, notificationID, userID)
+}
@@ -0,0 +1,19 @@
+package openapi
+
+import (
+	"net/http"
+)
+
+// ServeSpec handles GET /openapi.json
+func (s *Spec) ServeSpec(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(s.json)
+}
+
+// ServeDocs handles GET /docs with an interactive UI for the document
+func (s *Spec) ServeDocs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(docsHTML)
+}
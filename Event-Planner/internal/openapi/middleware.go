@@ -0,0 +1,260 @@
+package openapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"event-planner/internal/apperror"
+	"event-planner/internal/auth"
+	"event-planner/internal/response"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+)
+
+// maxBodySize bounds the request bodies buffered for validation
+const maxBodySize = 4 << 20 // 4 MB
+
+// ValidationMiddleware validates the parameters and body of requests against the
+// document before they reach the handlers. It must run after
+// auth.OptionalAuthMiddleware; requests to routes missing from the document pass through.
+func (s *Spec) ValidationMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route, pathParams, err := s.router.FindRoute(r)
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		options := &openapi3filter.Options{
+			MultiError:          true,
+			SkipSettingDefaults: true,
+			AuthenticationFunc:  authenticate,
+		}
+
+		// Uploads are checked by their handler, which also limits their size
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if mediaType == "multipart/form-data" {
+			options.ExcludeRequestBody = true
+		} else if r.Body != nil {
+			r.Body = http.MaxBytesReader(w, r.Body, maxBodySize)
+		}
+
+		input := &openapi3filter.RequestValidationInput{
+			Request:    r,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    options,
+		}
+		if err := openapi3filter.ValidateRequest(r.Context(), input); err != nil {
+			response.Error(w, translateError(r.Context(), err))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// authenticate satisfies the bearerAuth requirement when OptionalAuthMiddleware identified the user
+func authenticate(ctx context.Context, input *openapi3filter.AuthenticationInput) error {
+	if _, ok := auth.GetUserID(input.RequestValidationInput.Request.Context()); !ok {
+		return auth.Unauthenticated(input.RequestValidationInput.Request.Context())
+	}
+	return nil
+}
+
+// translateError converts validation errors into a single validation_failed
+// error listing every invalid field; failed security requirements take precedence.
+func translateError(ctx context.Context, err error) error {
+	var errs openapi3.MultiError
+	if !errors.As(err, &errs) {
+		errs = openapi3.MultiError{err}
+	}
+
+	var fields []apperror.FieldError
+	seen := map[string]bool{}
+	for _, e := range errs {
+		var securityErr *openapi3filter.SecurityRequirementsError
+		if errors.As(e, &securityErr) {
+			return auth.Unauthenticated(ctx)
+		}
+
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(e, &maxBytesErr) {
+			return apperror.Validation("body", "request body is too large")
+		}
+
+		// Report one problem per field, e.g. a date failing both its pattern and format
+		for _, field := range requestErrorFields(e) {
+			if !seen[field.Field] {
+				seen[field.Field] = true
+				fields = append(fields, field)
+			}
+		}
+	}
+
+	if len(fields) == 1 {
+		return apperror.InvalidFields(fields[0].Message, fields...)
+	}
+	return apperror.InvalidFields("request does not match the API specification", fields...)
+}
+
+// requestErrorFields describes a failed parameter or body check
+func requestErrorFields(err error) []apperror.FieldError {
+	var reqErr *openapi3filter.RequestError
+	if !errors.As(err, &reqErr) {
+		return []apperror.FieldError{{Field: "request", Message: "invalid request"}}
+	}
+
+	if reqErr.Parameter != nil {
+		name := reqErr.Parameter.Name
+		if errors.Is(reqErr.Err, openapi3filter.ErrInvalidRequired) {
+			return []apperror.FieldError{fieldError(name, "is required")}
+		}
+
+		var parseErr *openapi3filter.ParseError
+		if errors.As(reqErr.Err, &parseErr) {
+			return []apperror.FieldError{fieldError(name, describe(parseErr.Reason))}
+		}
+
+		var schemaErr *openapi3.SchemaError
+		if errors.As(reqErr.Err, &schemaErr) {
+			var fields []apperror.FieldError
+			for _, field := range schemaFields(schemaErr) {
+				fields = append(fields, fieldError(name, field.Message))
+			}
+			return fields
+		}
+
+		return []apperror.FieldError{fieldError(name, "invalid value")}
+	}
+
+	if errors.Is(reqErr.Err, openapi3filter.ErrInvalidRequired) {
+		return []apperror.FieldError{{Field: "body", Message: "request body is required"}}
+	}
+
+	var schemaErrs openapi3.MultiError
+	if !errors.As(reqErr.Err, &schemaErrs) {
+		schemaErrs = openapi3.MultiError{reqErr.Err}
+	}
+
+	var fields []apperror.FieldError
+	for _, e := range schemaErrs {
+		var schemaErr *openapi3.SchemaError
+		if errors.As(e, &schemaErr) {
+			for _, field := range schemaFields(schemaErr) {
+				fields = append(fields, fieldError(field.Field, field.Message))
+			}
+		}
+	}
+
+	if len(fields) == 0 {
+		return []apperror.FieldError{{Field: "body", Message: "invalid request body"}}
+	}
+	return fields
+}
+
+var (
+	// Location prefixes of the built-in and the JSON Schema 2020-12 validator messages
+	locationPrefix = regexp.MustCompile(`^(?:error at "([^"]*)": )?(?:at '([^']*)': )?`)
+
+	missingProperty = regexp.MustCompile(`^missing propert(?:y|ies) '([^']+)'`)
+)
+
+// schemaFields flattens a schema error into the failing fields and their
+// descriptions, relative to the validated value
+func schemaFields(err *openapi3.SchemaError) []apperror.FieldError {
+	var causes openapi3.MultiError
+	if errors.As(err.Origin, &causes) {
+		var fields []apperror.FieldError
+		for _, cause := range causes {
+			var schemaErr *openapi3.SchemaError
+			if errors.As(cause, &schemaErr) {
+				fields = append(fields, schemaFields(schemaErr)...)
+			}
+		}
+		if len(fields) > 0 {
+			return fields
+		}
+	}
+
+	// The 2020-12 validator lists the failing keyword on the last line
+	reason := err.Reason
+	if i := strings.LastIndex(reason, "\n- "); i >= 0 {
+		reason = reason[i+3:]
+	}
+
+	loc := locationPrefix.FindStringSubmatch(reason)
+	reason = reason[len(loc[0]):]
+
+	path := err.JSONPointer()
+	if len(path) == 0 {
+		pointer := loc[2]
+		if pointer == "" {
+			pointer = loc[1]
+		}
+		if pointer = strings.Trim(pointer, "/"); pointer != "" {
+			path = strings.Split(pointer, "/")
+		}
+	}
+
+	if m := missingProperty.FindStringSubmatch(reason); m != nil {
+		return []apperror.FieldError{{Field: strings.Join(append(path, m[1]), "."), Message: "is required"}}
+	}
+
+	field := strings.Join(path, ".")
+	if field == "" {
+		field = "body"
+	}
+	return []apperror.FieldError{{Field: field, Message: describe(reason)}}
+}
+
+// messages rewrites validator messages into short descriptions that don't echo the value
+var messages = []struct {
+	pattern *regexp.Regexp
+	message string
+}{
+	{regexp.MustCompile(`^got (\w+), want (\w+)$`), "must be of type $2"},
+	{regexp.MustCompile(`^value must be "?(\w+)"?$`), "must be of type $1"},
+	{regexp.MustCompile(`^an invalid (\w+)`), "must be a valid $1"},
+	{regexp.MustCompile(`^minimum: got .+, want (.+)$`), "must be at least $1"},
+	{regexp.MustCompile(`^maximum: got .+, want (.+)$`), "must be at most $1"},
+	{regexp.MustCompile(`^number must be at least (.+)$`), "must be at least $1"},
+	{regexp.MustCompile(`^number must be at most (.+)$`), "must be at most $1"},
+	{regexp.MustCompile(`^minLength: got .+, want (.+)$`), "must be at least $1 characters long"},
+	{regexp.MustCompile(`^maxLength: got .+, want (.+)$`), "must be at most $1 characters long"},
+	{regexp.MustCompile(`^minimum string length is (.+)$`), "must be at least $1 characters long"},
+	{regexp.MustCompile(`^maximum string length is (.+)$`), "must be at most $1 characters long"},
+	{regexp.MustCompile(`^maxItems: got .+, want (.+)$`), "must have at most $1 items"},
+	{regexp.MustCompile(`^maximum number of items is (.+)$`), "must have at most $1 items"},
+	{regexp.MustCompile(`is not valid '?(\w[\w-]*)'?`), "must be a valid $1"},
+	{regexp.MustCompile(`^string doesn't match the format "([^"]+)"`), "must be a valid $1"},
+	{regexp.MustCompile(`does not match pattern (.+)$`), "must match the pattern $1"},
+	{regexp.MustCompile(`^string doesn't match the regular expression (.+)$`), "must match the pattern $1"},
+	{regexp.MustCompile(`^value must be one of (.+)$`), "must be one of $1"},
+	{regexp.MustCompile(`^value is not one of the allowed values (.+)$`), "must be one of $1"},
+}
+
+// describe rewrites a validator message using messages
+func describe(reason string) string {
+	reason = strings.TrimSpace(reason)
+	for _, m := range messages {
+		if loc := m.pattern.FindStringSubmatchIndex(reason); loc != nil {
+			return string(m.pattern.ExpandString(nil, m.message, reason, loc))
+		}
+	}
+	return reason
+}
+
+// fieldError builds a field error whose message also reads well on its own
+func fieldError(field, message string) apperror.FieldError {
+	if message == "is required" {
+		return apperror.FieldError{Field: field, Message: fmt.Sprintf("%s is required", field)}
+	}
+	return apperror.FieldError{Field: field, Message: fmt.Sprintf("invalid %s: %s", field, message)}
+}
@@ -0,0 +1,48 @@
+package openapi
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/legacy"
+)
+
+// The specification is written by hand next to the code; keep it in sync
+// with the routes in cmd/server and the request and response models.
+//
+//go:embed openapi.yaml
+var specYAML []byte
+
+//go:embed docs.html
+var docsHTML []byte
+
+// Spec is the loaded OpenAPI document of the API
+type Spec struct {
+	router routers.Router
+	json   []byte
+}
+
+// Load parses and validates the embedded OpenAPI document
+func Load() (*Spec, error) {
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData(specYAML)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load OpenAPI document: %w", err)
+	}
+
+	// NewRouter validates the document before indexing its operations
+	router, err := legacy.NewRouter(doc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI document: %w", err)
+	}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode OpenAPI document: %w", err)
+	}
+
+	return &Spec{router: router, json: data}, nil
+}
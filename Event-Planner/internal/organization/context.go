@@ -0,0 +1,38 @@
+package organization
+
+import "context"
+
+type contextKey string
+
+const scopeKey contextKey = "organization_scope"
+
+// Scope is the organization a request operates in, resolved from the
+// X-Organization-ID header or the token's organization claim after the
+// membership of the current user has been verified
+type Scope struct {
+	OrganizationID    int
+	Role              string
+	DefaultVisibility string
+	Timezone          string
+}
+
+// withScope adds the organization scope to context
+func withScope(ctx context.Context, scope *Scope) context.Context {
+	return context.WithValue(ctx, scopeKey, scope)
+}
+
+// CurrentScope retrieves the organization scope from context
+func CurrentScope(ctx context.Context) (*Scope, bool) {
+	scope, ok := ctx.Value(scopeKey).(*Scope)
+	return scope, ok && scope != nil
+}
+
+// CurrentID returns the ID of the organization in scope, or nil for the personal scope
+func CurrentID(ctx context.Context) *int {
+	scope, ok := CurrentScope(ctx)
+	if !ok {
+		return nil
+	}
+	id := scope.OrganizationID
+	return &id
+}
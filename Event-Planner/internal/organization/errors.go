@@ -0,0 +1,12 @@
+package organization
+
+import "event-planner/internal/apperror"
+
+// Domain errors returned by the organization service
+var (
+	ErrOrganizationNotFound = apperror.NotFound("organization_not_found", "organization not found")
+	ErrMemberNotFound       = apperror.NotFound("member_not_found", "organization member not found")
+	ErrNotMember            = apperror.Forbidden("not_organization_member", "you are not a member of this organization")
+	ErrNotManager           = apperror.Forbidden("not_organization_manager", "you are not authorized to manage this organization")
+	ErrLastOwner            = apperror.Conflict("last_owner", "an organization must keep at least one owner")
+)
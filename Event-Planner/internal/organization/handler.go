@@ -0,0 +1,356 @@
+package organization
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"event-planner/internal/apperror"
+	"event-planner/internal/auth"
+	"event-planner/internal/response"
+	"event-planner/internal/user"
+)
+
+// HeaderOrganizationID selects the organization a request operates in.
+// "0" selects the personal scope and overrides the token's organization claim.
+const HeaderOrganizationID = "X-Organization-ID"
+
+// Handler handles HTTP requests for organizations
+type Handler struct {
+	service *Service
+}
+
+// NewHandler creates a new organization handler
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// ScopeMiddleware resolves the organization a request operates in from the
+// X-Organization-ID header or the token's organization claim. It must run
+// after auth.OptionalAuthMiddleware; requests without either stay in the personal scope,
+// as do requests whose token claims an organization the user no longer belongs to.
+func (h *Handler) ScopeMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if unscoped(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		orgID, _ := auth.GetTokenOrgID(r.Context())
+		fromHeader := false
+
+		if header := r.Header.Get(HeaderOrganizationID); header != "" {
+			id, err := strconv.Atoi(header)
+			if err != nil || id < 0 {
+				response.Error(w, apperror.Validation("X-Organization-ID", "invalid X-Organization-ID header"))
+				return
+			}
+			orgID = id
+			fromHeader = true
+		}
+
+		if orgID == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		userID, ok := auth.GetUserID(r.Context())
+		if !ok {
+			response.Error(w, apperror.Unauthorized("unauthorized", "authentication required for organization scope"))
+			return
+		}
+
+		scope, err := h.service.ResolveScope(r.Context(), orgID, userID)
+		if errors.Is(err, ErrNotMember) && !fromHeader {
+			// The claim went stale when the user left the organization
+			next.ServeHTTP(w, r)
+			return
+		}
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(withScope(r.Context(), scope)))
+	})
+}
+
+// unscoped reports whether a route ignores the organization scope: logging in,
+// registering and switching organizations must work whatever the token claims
+func unscoped(path string) bool {
+	if strings.HasPrefix(path, "/auth/") {
+		return true
+	}
+	id, ok := strings.CutPrefix(path, "/organizations/")
+	if !ok {
+		return false
+	}
+	id, ok = strings.CutSuffix(id, "/switch")
+	return ok && id != "" && !strings.Contains(id, "/")
+}
+
+// CreateOrganization handles POST /organizations
+func (h *Handler) CreateOrganization(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, apperror.ErrUnauthorized)
+		return
+	}
+
+	var req CreateOrganizationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, apperror.Validation("body", "invalid request body"))
+		return
+	}
+
+	org, err := h.service.CreateOrganization(r.Context(), &req, userID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "organization created successfully",
+		"data":    org,
+	})
+}
+
+// GetMyOrganizations handles GET /organizations
+func (h *Handler) GetMyOrganizations(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, apperror.ErrUnauthorized)
+		return
+	}
+
+	memberships, err := h.service.GetMyOrganizations(r.Context(), userID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data": memberships,
+	})
+}
+
+// GetOrganization handles GET /organizations/{id}
+func (h *Handler) GetOrganization(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, apperror.ErrUnauthorized)
+		return
+	}
+
+	orgID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		response.Error(w, apperror.Validation("id", "invalid organization ID"))
+		return
+	}
+
+	membership, err := h.service.GetOrganization(r.Context(), orgID, userID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data": membership,
+	})
+}
+
+// UpdateOrganization handles PUT /organizations/{id}
+func (h *Handler) UpdateOrganization(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, apperror.ErrUnauthorized)
+		return
+	}
+
+	orgID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		response.Error(w, apperror.Validation("id", "invalid organization ID"))
+		return
+	}
+
+	var req UpdateOrganizationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, apperror.Validation("body", "invalid request body"))
+		return
+	}
+
+	org, err := h.service.UpdateOrganization(r.Context(), orgID, userID, &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "organization updated successfully",
+		"data":    org,
+	})
+}
+
+// GetMembers handles GET /organizations/{id}/members
+func (h *Handler) GetMembers(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, apperror.ErrUnauthorized)
+		return
+	}
+
+	orgID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		response.Error(w, apperror.Validation("id", "invalid organization ID"))
+		return
+	}
+
+	members, err := h.service.GetMembers(r.Context(), orgID, userID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data": members,
+	})
+}
+
+// AddMember handles POST /organizations/{id}/members
+func (h *Handler) AddMember(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, apperror.ErrUnauthorized)
+		return
+	}
+
+	orgID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		response.Error(w, apperror.Validation("id", "invalid organization ID"))
+		return
+	}
+
+	var req AddMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, apperror.Validation("body", "invalid request body"))
+		return
+	}
+
+	if err := h.service.AddMember(r.Context(), orgID, userID, &req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "member added successfully",
+	})
+}
+
+// UpdateMember handles PUT /organizations/{id}/members/{userID}
+func (h *Handler) UpdateMember(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := auth.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, apperror.ErrUnauthorized)
+		return
+	}
+
+	orgID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		response.Error(w, apperror.Validation("id", "invalid organization ID"))
+		return
+	}
+
+	userID, err := strconv.Atoi(r.PathValue("userID"))
+	if err != nil {
+		response.Error(w, apperror.Validation("userID", "invalid user ID"))
+		return
+	}
+
+	var req UpdateMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, apperror.Validation("body", "invalid request body"))
+		return
+	}
+
+	if err := h.service.UpdateMemberRole(r.Context(), orgID, actorID, userID, req.Role); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "member updated successfully",
+	})
+}
+
+// RemoveMember handles DELETE /organizations/{id}/members/{userID}
+func (h *Handler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := auth.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, apperror.ErrUnauthorized)
+		return
+	}
+
+	orgID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		response.Error(w, apperror.Validation("id", "invalid organization ID"))
+		return
+	}
+
+	userID, err := strconv.Atoi(r.PathValue("userID"))
+	if err != nil {
+		response.Error(w, apperror.Validation("userID", "invalid user ID"))
+		return
+	}
+
+	if err := h.service.RemoveMember(r.Context(), orgID, actorID, userID); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "member removed successfully",
+	})
+}
+
+// SwitchOrganization handles POST /organizations/{id}/switch (id 0 switches to the personal scope)
+func (h *Handler) SwitchOrganization(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, apperror.ErrUnauthorized)
+		return
+	}
+
+	orgID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || orgID < 0 {
+		response.Error(w, apperror.Validation("id", "invalid organization ID"))
+		return
+	}
+
+	token, err := h.service.SwitchOrganization(r.Context(), userID, orgID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(user.AuthResponse{Token: token})
+}
@@ -0,0 +1,66 @@
+package organization
+
+import (
+	"time"
+
+	"event-planner/internal/user"
+)
+
+// Organization member roles
+const (
+	RoleOwner  = "owner"
+	RoleAdmin  = "admin"
+	RoleMember = "member"
+)
+
+// Organization is a workspace that groups members and their events
+type Organization struct {
+	ID                int       `json:"id"`
+	Name              string    `json:"name"`
+	Slug              string    `json:"slug"`
+	DefaultVisibility string    `json:"default_visibility"` // 'public' or 'organization'
+	Timezone          string    `json:"timezone"`           // default timezone for new events
+	CreatedBy         int       `json:"created_by"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// Membership is an organization together with the current user's role in it
+type Membership struct {
+	Organization
+	Role string `json:"role"` // 'owner', 'admin' or 'member'
+}
+
+// Member is a user belonging to an organization
+type Member struct {
+	OrganizationID int                `json:"organization_id"`
+	UserID         int                `json:"user_id"`
+	Role           string             `json:"role"`
+	CreatedAt      time.Time          `json:"created_at"`
+	User           user.PublicProfile `json:"user"`
+}
+
+// CreateOrganizationRequest is the request payload for creating an organization
+type CreateOrganizationRequest struct {
+	Name              string `json:"name" binding:"required"`
+	Slug              string `json:"slug"`               // derived from the name when empty
+	DefaultVisibility string `json:"default_visibility"` // defaults to 'organization'
+	Timezone          string `json:"timezone"`           // defaults to 'UTC'
+}
+
+// UpdateOrganizationRequest is the request payload for updating organization settings
+type UpdateOrganizationRequest struct {
+	Name              string `json:"name"`
+	DefaultVisibility string `json:"default_visibility"`
+	Timezone          string `json:"timezone"`
+}
+
+// AddMemberRequest is the request payload for adding a member by email
+type AddMemberRequest struct {
+	Email string `json:"email" binding:"required"`
+	Role  string `json:"role"` // defaults to 'member'
+}
+
+// UpdateMemberRequest is the request payload for changing a member's role
+type UpdateMemberRequest struct {
+	Role string `json:"role" binding:"required"`
+}
@@ -0,0 +1,303 @@
+package organization
+
+import (
+	"context"
+	"fmt"
+
+	"event-planner/internal/db"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository handles all database operations for organizations
+type Repository struct {
+	db *pgxpool.Pool
+}
+
+// NewRepository creates a new organization repository
+func NewRepository(db *pgxpool.Pool) *Repository {
+	return &Repository{db: db}
+}
+
+// CreateOrganization inserts a new organization and makes ownerID its owner
+func (r *Repository) CreateOrganization(ctx context.Context, org *Organization, ownerID int) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO organizations (name, slug, default_visibility, timezone, created_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+
+	err = tx.QueryRow(ctx, query,
+		org.Name,
+		org.Slug,
+		org.DefaultVisibility,
+		org.Timezone,
+		ownerID,
+	).Scan(&org.ID, &org.CreatedAt)
+
+	if err != nil {
+		return db.TranslateError(fmt.Errorf("failed to create organization: %w", err), nil)
+	}
+	org.CreatedBy = ownerID
+
+	memberQuery := `
+		INSERT INTO organization_members (organization_id, user_id, role)
+		VALUES ($1, $2, 'owner')
+	`
+	if _, err := tx.Exec(ctx, memberQuery, org.ID, ownerID); err != nil {
+		return db.TranslateError(fmt.Errorf("failed to add organization owner: %w", err), nil)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit organization: %w", err)
+	}
+
+	return nil
+}
+
+// GetOrganizationByID retrieves a single organization by ID
+func (r *Repository) GetOrganizationByID(ctx context.Context, orgID int) (*Organization, error) {
+	query := `
+		SELECT id, name, slug, default_visibility, timezone, created_by, created_at
+		FROM organizations
+		WHERE id = $1
+	`
+
+	org := &Organization{}
+	err := r.db.QueryRow(ctx, query, orgID).Scan(
+		&org.ID,
+		&org.Name,
+		&org.Slug,
+		&org.DefaultVisibility,
+		&org.Timezone,
+		&org.CreatedBy,
+		&org.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, db.TranslateError(fmt.Errorf("failed to get organization: %w", err), ErrOrganizationNotFound)
+	}
+
+	return org, nil
+}
+
+// UpdateOrganization saves the name and default settings of an organization
+func (r *Repository) UpdateOrganization(ctx context.Context, org *Organization) error {
+	query := `
+		UPDATE organizations
+		SET name = $1, default_visibility = $2, timezone = $3
+		WHERE id = $4
+	`
+
+	result, err := r.db.Exec(ctx, query, org.Name, org.DefaultVisibility, org.Timezone, org.ID)
+	if err != nil {
+		return db.TranslateError(fmt.Errorf("failed to update organization: %w", err), nil)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrOrganizationNotFound
+	}
+
+	return nil
+}
+
+// GetMembership retrieves an organization together with the role of userID in it
+func (r *Repository) GetMembership(ctx context.Context, orgID, userID int) (*Membership, error) {
+	query := `
+		SELECT o.id, o.name, o.slug, o.default_visibility, o.timezone, o.created_by, o.created_at, m.role
+		FROM organizations o
+		JOIN organization_members m ON m.organization_id = o.id
+		WHERE o.id = $1 AND m.user_id = $2
+	`
+
+	m := &Membership{}
+	err := r.db.QueryRow(ctx, query, orgID, userID).Scan(
+		&m.ID,
+		&m.Name,
+		&m.Slug,
+		&m.DefaultVisibility,
+		&m.Timezone,
+		&m.CreatedBy,
+		&m.CreatedAt,
+		&m.Role,
+	)
+
+	if err != nil {
+		return nil, db.TranslateError(fmt.Errorf("failed to get membership: %w", err), ErrMemberNotFound)
+	}
+
+	return m, nil
+}
+
+// GetUserMemberships retrieves all organizations a user belongs to
+func (r *Repository) GetUserMemberships(ctx context.Context, userID int) ([]Membership, error) {
+	query := `
+		SELECT o.id, o.name, o.slug, o.default_visibility, o.timezone, o.created_by, o.created_at, m.role
+		FROM organizations o
+		JOIN organization_members m ON m.organization_id = o.id
+		WHERE m.user_id = $1
+		ORDER BY o.name
+	`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get memberships: %w", err)
+	}
+	defer rows.Close()
+
+	var memberships []Membership
+	for rows.Next() {
+		m := Membership{}
+		err := rows.Scan(
+			&m.ID,
+			&m.Name,
+			&m.Slug,
+			&m.DefaultVisibility,
+			&m.Timezone,
+			&m.CreatedBy,
+			&m.CreatedAt,
+			&m.Role,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan membership: %w", err)
+		}
+		memberships = append(memberships, m)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating memberships: %w", err)
+	}
+
+	return memberships, nil
+}
+
+// GetMembers retrieves all members of an organization
+func (r *Repository) GetMembers(ctx context.Context, orgID int) ([]Member, error) {
+	query := `
+		SELECT
+			m.organization_id,
+			m.user_id,
+			m.role,
+			m.created_at,
+			COALESCE(NULLIF(p.display_name, ''), 'User ' || u.id),
+			COALESCE(p.avatar_url, '')
+		FROM organization_members m
+		JOIN users u ON u.id = m.user_id
+		LEFT JOIN user_profiles p ON p.user_id = m.user_id
+		WHERE m.organization_id = $1
+		ORDER BY m.created_at
+	`
+
+	rows, err := r.db.Query(ctx, query, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get organization members: %w", err)
+	}
+	defer rows.Close()
+
+	var members []Member
+	for rows.Next() {
+		m := Member{}
+		err := rows.Scan(
+			&m.OrganizationID,
+			&m.UserID,
+			&m.Role,
+			&m.CreatedAt,
+			&m.User.DisplayName,
+			&m.User.AvatarURL,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan organization member: %w", err)
+		}
+		m.User.UserID = m.UserID
+		members = append(members, m)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating organization members: %w", err)
+	}
+
+	return members, nil
+}
+
+// AddMember adds a user to an organization (updates the role if already a member)
+func (r *Repository) AddMember(ctx context.Context, orgID, userID int, role string) error {
+	query := `
+		INSERT INTO organization_members (organization_id, user_id, role)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (organization_id, user_id) DO UPDATE SET role = EXCLUDED.role
+	`
+
+	if _, err := r.db.Exec(ctx, query, orgID, userID, role); err != nil {
+		return db.TranslateError(fmt.Errorf("failed to add organization member: %w", err), nil)
+	}
+
+	return nil
+}
+
+// UpdateMemberRole changes the role of an existing member
+func (r *Repository) UpdateMemberRole(ctx context.Context, orgID, userID int, role string) error {
+	query := `
+		UPDATE organization_members
+		SET role = $1
+		WHERE organization_id = $2 AND user_id = $3
+	`
+
+	result, err := r.db.Exec(ctx, query, role, orgID, userID)
+	if err != nil {
+		return db.TranslateError(fmt.Errorf("failed to update organization member: %w", err), nil)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrMemberNotFound
+	}
+
+	return nil
+}
+
+// RemoveMember removes a user from an organization
+func (r *Repository) RemoveMember(ctx context.Context, orgID, userID int) error {
+	query := `DELETE FROM organization_members WHERE organization_id = $1 AND user_id = $2`
+
+	result, err := r.db.Exec(ctx, query, orgID, userID)
+	if err != nil {
+		return db.TranslateError(fmt.Errorf("failed to remove organization member: %w", err), nil)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrMemberNotFound
+	}
+
+	return nil
+}
+
+// CountOwners returns the number of owners of an organization
+func (r *Repository) CountOwners(ctx context.Context, orgID int) (int, error) {
+	query := `SELECT COUNT(*) FROM organization_members WHERE organization_id = $1 AND role = 'owner'`
+
+	var count int
+	if err := r.db.QueryRow(ctx, query, orgID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count organization owners: %w", err)
+	}
+
+	return count, nil
+}
+
+// GetUserIDByEmail retrieves user ID by email (helper function)
+func (r *Repository) GetUserIDByEmail(ctx context.Context, email string) (*int, error) {
+	query := `SELECT id FROM users WHERE email = $1`
+
+	var userID int
+	err := r.db.QueryRow(ctx, query, email).Scan(&userID)
+	if err != nil {
+		// User doesn't exist, return nil (not an error)
+		return nil, nil
+	}
+
+	return &userID, nil
+}
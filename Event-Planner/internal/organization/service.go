@@ -0,0 +1,394 @@
+package organization
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"event-planner/internal/apperror"
+	"event-planner/internal/tracing"
+)
+
+var (
+	slugRegex        = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+	slugInvalidChars = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// TokenIssuer issues access tokens bound to an organization
+type TokenIssuer interface {
+	IssueToken(userID, orgID int) (string, error)
+}
+
+// Store is the organization storage used by Service; Repository implements it on PostgreSQL
+type Store interface {
+	CreateOrganization(ctx context.Context, org *Organization, ownerID int) error
+	UpdateOrganization(ctx context.Context, org *Organization) error
+	GetMembership(ctx context.Context, orgID, userID int) (*Membership, error)
+	GetUserMemberships(ctx context.Context, userID int) ([]Membership, error)
+	GetMembers(ctx context.Context, orgID int) ([]Member, error)
+	AddMember(ctx context.Context, orgID, userID int, role string) error
+	UpdateMemberRole(ctx context.Context, orgID, userID int, role string) error
+	RemoveMember(ctx context.Context, orgID, userID int) error
+	CountOwners(ctx context.Context, orgID int) (int, error)
+	GetUserIDByEmail(ctx context.Context, email string) (*int, error)
+}
+
+// Service handles business logic for organizations
+type Service struct {
+	repo   Store
+	tokens TokenIssuer
+}
+
+// NewService creates a new organization service
+func NewService(repo Store, tokens TokenIssuer) *Service {
+	return &Service{
+		repo:   repo,
+		tokens: tokens,
+	}
+}
+
+// CreateOrganization validates and creates an organization owned by userID
+func (s *Service) CreateOrganization(ctx context.Context, req *CreateOrganizationRequest, userID int) (*Organization, error) {
+	ctx, span := tracing.Start(ctx, "organization.Service.CreateOrganization")
+	defer span.End()
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return nil, apperror.Validation("name", "organization name is required")
+	}
+
+	if len(req.Name) > 100 {
+		return nil, apperror.Validation("name", "organization name must not exceed 100 characters")
+	}
+
+	if req.Slug == "" {
+		req.Slug = slugify(req.Name)
+	}
+
+	if len(req.Slug) > 63 || !slugRegex.MatchString(req.Slug) {
+		return nil, apperror.Validation("slug", "invalid slug: use lowercase letters, digits and dashes")
+	}
+
+	if req.DefaultVisibility == "" {
+		req.DefaultVisibility = "organization"
+	}
+
+	if req.Timezone == "" {
+		req.Timezone = "UTC"
+	}
+
+	if err := s.validateSettings(req.DefaultVisibility, req.Timezone); err != nil {
+		return nil, err
+	}
+
+	org := &Organization{
+		Name:              req.Name,
+		Slug:              req.Slug,
+		DefaultVisibility: req.DefaultVisibility,
+		Timezone:          req.Timezone,
+	}
+
+	if err := s.repo.CreateOrganization(ctx, org, userID); err != nil {
+		return nil, err
+	}
+
+	return org, nil
+}
+
+// GetMyOrganizations retrieves all organizations the user belongs to
+func (s *Service) GetMyOrganizations(ctx context.Context, userID int) ([]Membership, error) {
+	ctx, span := tracing.Start(ctx, "organization.Service.GetMyOrganizations")
+	defer span.End()
+
+	memberships, err := s.repo.GetUserMemberships(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if memberships == nil {
+		memberships = []Membership{}
+	}
+
+	return memberships, nil
+}
+
+// GetOrganization retrieves an organization the user is a member of
+func (s *Service) GetOrganization(ctx context.Context, orgID, userID int) (*Membership, error) {
+	ctx, span := tracing.Start(ctx, "organization.Service.GetOrganization")
+	defer span.End()
+
+	return s.requireRole(ctx, orgID, userID, RoleMember)
+}
+
+// UpdateOrganization updates the name and default settings (owners and admins only)
+func (s *Service) UpdateOrganization(ctx context.Context, orgID, userID int, req *UpdateOrganizationRequest) (*Organization, error) {
+	ctx, span := tracing.Start(ctx, "organization.Service.UpdateOrganization")
+	defer span.End()
+
+	membership, err := s.requireRole(ctx, orgID, userID, RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	org := membership.Organization
+
+	// Apply updates (only non-empty fields)
+	if name := strings.TrimSpace(req.Name); name != "" {
+		if len(name) > 100 {
+			return nil, apperror.Validation("name", "organization name must not exceed 100 characters")
+		}
+		org.Name = name
+	}
+	if req.DefaultVisibility != "" {
+		org.DefaultVisibility = req.DefaultVisibility
+	}
+	if req.Timezone != "" {
+		org.Timezone = req.Timezone
+	}
+
+	if err := s.validateSettings(org.DefaultVisibility, org.Timezone); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateOrganization(ctx, &org); err != nil {
+		return nil, err
+	}
+
+	return &org, nil
+}
+
+// GetMembers lists the members of an organization the user belongs to
+func (s *Service) GetMembers(ctx context.Context, orgID, userID int) ([]Member, error) {
+	ctx, span := tracing.Start(ctx, "organization.Service.GetMembers")
+	defer span.End()
+
+	if _, err := s.requireRole(ctx, orgID, userID, RoleMember); err != nil {
+		return nil, err
+	}
+
+	members, err := s.repo.GetMembers(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	if members == nil {
+		members = []Member{}
+	}
+
+	return members, nil
+}
+
+// AddMember adds an existing user to the organization (owners and admins only)
+func (s *Service) AddMember(ctx context.Context, orgID, actorID int, req *AddMemberRequest) error {
+	ctx, span := tracing.Start(ctx, "organization.Service.AddMember")
+	defer span.End()
+
+	if req.Role == "" {
+		req.Role = RoleMember
+	}
+
+	if !isValidRole(req.Role) {
+		return apperror.Validation("role", "invalid role: must be 'owner', 'admin', or 'member'")
+	}
+
+	actor, err := s.requireRole(ctx, orgID, actorID, RoleAdmin)
+	if err != nil {
+		return err
+	}
+
+	if req.Role == RoleOwner && actor.Role != RoleOwner {
+		return apperror.Forbidden("owner_required", "only owners can add owners")
+	}
+
+	userID, err := s.repo.GetUserIDByEmail(ctx, strings.TrimSpace(req.Email))
+	if err != nil {
+		return err
+	}
+
+	if userID == nil {
+		return apperror.NotFound("user_not_found", "no user with this email exists")
+	}
+
+	existing, err := s.repo.GetMembership(ctx, orgID, *userID)
+	if err == nil {
+		return apperror.Conflict("already_member", fmt.Sprintf("user is already a member with role '%s'", existing.Role))
+	}
+	if !errors.Is(err, apperror.ErrNotFound) {
+		return err
+	}
+
+	return s.repo.AddMember(ctx, orgID, *userID, req.Role)
+}
+
+// UpdateMemberRole changes the role of a member (owners and admins only)
+func (s *Service) UpdateMemberRole(ctx context.Context, orgID, actorID, userID int, role string) error {
+	ctx, span := tracing.Start(ctx, "organization.Service.UpdateMemberRole")
+	defer span.End()
+
+	if !isValidRole(role) {
+		return apperror.Validation("role", "invalid role: must be 'owner', 'admin', or 'member'")
+	}
+
+	actor, err := s.requireRole(ctx, orgID, actorID, RoleAdmin)
+	if err != nil {
+		return err
+	}
+
+	target, err := s.repo.GetMembership(ctx, orgID, userID)
+	if err != nil {
+		return err
+	}
+
+	// Only owners can grant or revoke ownership
+	if (role == RoleOwner || target.Role == RoleOwner) && actor.Role != RoleOwner {
+		return apperror.Forbidden("owner_required", "only owners can change the role of owners")
+	}
+
+	if target.Role == RoleOwner && role != RoleOwner {
+		if err := s.ensureAnotherOwner(ctx, orgID); err != nil {
+			return err
+		}
+	}
+
+	return s.repo.UpdateMemberRole(ctx, orgID, userID, role)
+}
+
+// RemoveMember removes a member (owners and admins), or lets a member leave
+func (s *Service) RemoveMember(ctx context.Context, orgID, actorID, userID int) error {
+	ctx, span := tracing.Start(ctx, "organization.Service.RemoveMember")
+	defer span.End()
+
+	target, err := s.repo.GetMembership(ctx, orgID, userID)
+	if err != nil {
+		return err
+	}
+
+	if actorID != userID {
+		actor, err := s.requireRole(ctx, orgID, actorID, RoleAdmin)
+		if err != nil {
+			return err
+		}
+		if target.Role == RoleOwner && actor.Role != RoleOwner {
+			return apperror.Forbidden("owner_required", "only owners can remove owners")
+		}
+	}
+
+	if target.Role == RoleOwner {
+		if err := s.ensureAnotherOwner(ctx, orgID); err != nil {
+			return err
+		}
+	}
+
+	return s.repo.RemoveMember(ctx, orgID, userID)
+}
+
+// ResolveScope verifies that the user is a member of the organization and returns the request scope
+func (s *Service) ResolveScope(ctx context.Context, orgID, userID int) (*Scope, error) {
+	ctx, span := tracing.Start(ctx, "organization.Service.ResolveScope")
+	defer span.End()
+
+	membership, err := s.repo.GetMembership(ctx, orgID, userID)
+	if errors.Is(err, apperror.ErrNotFound) {
+		return nil, ErrNotMember
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &Scope{
+		OrganizationID:    membership.ID,
+		Role:              membership.Role,
+		DefaultVisibility: membership.DefaultVisibility,
+		Timezone:          membership.Timezone,
+	}, nil
+}
+
+// SwitchOrganization issues a token bound to the organization (orgID 0 switches back to personal)
+func (s *Service) SwitchOrganization(ctx context.Context, userID, orgID int) (string, error) {
+	ctx, span := tracing.Start(ctx, "organization.Service.SwitchOrganization")
+	defer span.End()
+
+	if orgID != 0 {
+		if _, err := s.requireRole(ctx, orgID, userID, RoleMember); err != nil {
+			return "", err
+		}
+	}
+
+	return s.tokens.IssueToken(userID, orgID)
+}
+
+// requireRole returns the membership of userID if it has at least the given role
+func (s *Service) requireRole(ctx context.Context, orgID, userID int, role string) (*Membership, error) {
+	if orgID <= 0 {
+		return nil, apperror.Validation("id", "invalid organization ID")
+	}
+
+	membership, err := s.repo.GetMembership(ctx, orgID, userID)
+	if errors.Is(err, apperror.ErrNotFound) {
+		return nil, ErrNotMember
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if roleRank(membership.Role) < roleRank(role) {
+		return nil, ErrNotManager
+	}
+
+	return membership, nil
+}
+
+// ensureAnotherOwner fails when removing an owner would leave the organization without one
+func (s *Service) ensureAnotherOwner(ctx context.Context, orgID int) error {
+	owners, err := s.repo.CountOwners(ctx, orgID)
+	if err != nil {
+		return err
+	}
+
+	if owners <= 1 {
+		return ErrLastOwner
+	}
+
+	return nil
+}
+
+func (s *Service) validateSettings(visibility, timezone string) error {
+	if visibility != "public" && visibility != "organization" {
+		return apperror.Validation("default_visibility", "invalid default visibility: must be 'public' or 'organization'")
+	}
+
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return apperror.Validation("timezone", "invalid timezone: must be an IANA name such as 'Europe/Berlin'")
+	}
+
+	return nil
+}
+
+func isValidRole(role string) bool {
+	return role == RoleOwner || role == RoleAdmin || role == RoleMember
+}
+
+func roleRank(role string) int {
+	switch role {
+	case RoleOwner:
+		return 3
+	case RoleAdmin:
+		return 2
+	case RoleMember:
+		return 1
+	}
+	return 0
+}
+
+// slugify derives a URL-friendly slug from a name
+func slugify(name string) string {
+	slug := slugInvalidChars.ReplaceAllString(strings.ToLower(name), "-")
+	slug = strings.Trim(slug, "-")
+	if len(slug) > 63 {
+		slug = strings.TrimRight(slug[:63], "-")
+	}
+	return slug
+}
@@ -0,0 +1,151 @@
+package profile
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"event-planner/internal/apperror"
+	"event-planner/internal/auth"
+	"event-planner/internal/response"
+)
+
+// Handler handles HTTP requests for user profiles
+type Handler struct {
+	service *Service
+}
+
+// NewHandler creates a new profile handler
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// GetPublicProfile handles GET /users/{id}/profile
+func (h *Handler) GetPublicProfile(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	userID, err := strconv.Atoi(idStr)
+	if err != nil {
+		response.Error(w, apperror.Validation("id", "invalid user ID"))
+		return
+	}
+
+	profile, err := h.service.GetPublicProfile(r.Context(), userID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data": profile,
+	})
+}
+
+// GetMyProfile handles GET /users/me/profile
+func (h *Handler) GetMyProfile(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, apperror.ErrUnauthorized)
+		return
+	}
+
+	profile, err := h.service.GetMyProfile(r.Context(), userID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data": profile,
+	})
+}
+
+// UpdateMyProfile handles PUT /users/me/profile
+func (h *Handler) UpdateMyProfile(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, apperror.ErrUnauthorized)
+		return
+	}
+
+	var req UpdateProfileRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, apperror.Validation("body", "invalid request body"))
+		return
+	}
+
+	profile, err := h.service.UpdateProfile(r.Context(), userID, &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "profile updated successfully",
+		"data":    profile,
+	})
+}
+
+// UploadAvatar handles PUT /users/me/avatar (multipart form field "avatar")
+func (h *Handler) UploadAvatar(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, apperror.ErrUnauthorized)
+		return
+	}
+
+	// Leave some room for the multipart envelope around the file
+	r.Body = http.MaxBytesReader(w, r.Body, MaxAvatarSize+64<<10)
+
+	file, _, err := r.FormFile("avatar")
+	if err != nil {
+		response.Error(w, apperror.Validation("avatar", "avatar file is required and must not exceed 2 MB"))
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, MaxAvatarSize+1))
+	if err != nil {
+		response.Error(w, apperror.Validation("avatar", "failed to read avatar file"))
+		return
+	}
+
+	profile, err := h.service.UploadAvatar(r.Context(), userID, data)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "avatar uploaded successfully",
+		"data":    profile,
+	})
+}
+
+// DeleteAvatar handles DELETE /users/me/avatar
+func (h *Handler) DeleteAvatar(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserID(r.Context())
+	if !ok {
+		response.Error(w, apperror.ErrUnauthorized)
+		return
+	}
+
+	if err := h.service.DeleteAvatar(r.Context(), userID); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "avatar removed successfully",
+	})
+}
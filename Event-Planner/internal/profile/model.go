@@ -0,0 +1,35 @@
+package profile
+
+import (
+	"time"
+
+	"event-planner/internal/user"
+)
+
+// Profile holds the personal details and preferences of a user
+type Profile struct {
+	UserID      int        `json:"user_id"`
+	Email       string     `json:"email"`
+	DisplayName string     `json:"display_name"`
+	AvatarURL   string     `json:"avatar_url,omitempty"`
+	Bio         string     `json:"bio"`
+	Timezone    string     `json:"timezone"` // IANA name, e.g. 'Europe/Berlin'
+	Locale      string     `json:"locale"`   // BCP 47 tag, e.g. 'en-US'
+	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
+	AvatarKey   string     `json:"-"` // where the avatar is kept in storage
+}
+
+// PublicDetails is the profile as shown to other users
+type PublicDetails struct {
+	user.PublicProfile
+	Bio string `json:"bio"`
+}
+
+// UpdateProfileRequest is the request payload for updating a profile.
+// The profile is replaced as a whole; empty timezone/locale fall back to defaults.
+type UpdateProfileRequest struct {
+	DisplayName string `json:"display_name"`
+	Bio         string `json:"bio"`
+	Timezone    string `json:"timezone"`
+	Locale      string `json:"locale"`
+}
@@ -0,0 +1,138 @@
+package profile
+
+import (
+	"context"
+	"fmt"
+
+	"event-planner/internal/apperror"
+	"event-planner/internal/db"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ErrUserNotFound is returned when the profile owner does not exist
+var ErrUserNotFound = apperror.NotFound("user_not_found", "user not found")
+
+// Repository handles all database operations for user profiles
+type Repository struct {
+	db *pgxpool.Pool
+}
+
+// NewRepository creates a new profile repository
+func NewRepository(db *pgxpool.Pool) *Repository {
+	return &Repository{db: db}
+}
+
+// GetProfile retrieves the profile of a user; users without a stored
+// profile get the default preferences
+func (r *Repository) GetProfile(ctx context.Context, userID int) (*Profile, error) {
+	query := `
+		SELECT
+			u.id,
+			u.email,
+			COALESCE(p.display_name, ''),
+			COALESCE(p.avatar_url, ''),
+			COALESCE(p.avatar_key, ''),
+			COALESCE(p.bio, ''),
+			COALESCE(p.timezone, 'UTC'),
+			COALESCE(p.locale, 'en'),
+			p.updated_at
+		FROM users u
+		LEFT JOIN user_profiles p ON p.user_id = u.id
+		WHERE u.id = $1
+	`
+
+	profile := &Profile{}
+	err := r.db.QueryRow(ctx, query, userID).Scan(
+		&profile.UserID,
+		&profile.Email,
+		&profile.DisplayName,
+		&profile.AvatarURL,
+		&profile.AvatarKey,
+		&profile.Bio,
+		&profile.Timezone,
+		&profile.Locale,
+		&profile.UpdatedAt,
+	)
+
+	if err != nil {
+		return nil, db.TranslateError(fmt.Errorf("failed to get profile: %w", err), ErrUserNotFound)
+	}
+
+	return profile, nil
+}
+
+// GetPublicProfile retrieves the publicly visible part of a user's profile
+func (r *Repository) GetPublicProfile(ctx context.Context, userID int) (*PublicDetails, error) {
+	query := `
+		SELECT
+			u.id,
+			COALESCE(NULLIF(p.display_name, ''), 'User ' || u.id),
+			COALESCE(p.avatar_url, ''),
+			COALESCE(p.bio, '')
+		FROM users u
+		LEFT JOIN user_profiles p ON p.user_id = u.id
+		WHERE u.id = $1
+	`
+
+	details := &PublicDetails{}
+	err := r.db.QueryRow(ctx, query, userID).Scan(
+		&details.UserID,
+		&details.DisplayName,
+		&details.AvatarURL,
+		&details.Bio,
+	)
+
+	if err != nil {
+		return nil, db.TranslateError(fmt.Errorf("failed to get profile: %w", err), ErrUserNotFound)
+	}
+
+	return details, nil
+}
+
+// UpsertProfile creates or replaces the editable fields of a profile
+func (r *Repository) UpsertProfile(ctx context.Context, profile *Profile) error {
+	query := `
+		INSERT INTO user_profiles (user_id, display_name, bio, timezone, locale, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())
+		ON CONFLICT (user_id) DO UPDATE
+		SET display_name = EXCLUDED.display_name,
+			bio = EXCLUDED.bio,
+			timezone = EXCLUDED.timezone,
+			locale = EXCLUDED.locale,
+			updated_at = EXCLUDED.updated_at
+		RETURNING updated_at
+	`
+
+	err := r.db.QueryRow(ctx, query,
+		profile.UserID,
+		profile.DisplayName,
+		profile.Bio,
+		profile.Timezone,
+		profile.Locale,
+	).Scan(&profile.UpdatedAt)
+
+	if err != nil {
+		return db.TranslateError(fmt.Errorf("failed to save profile: %w", err), nil)
+	}
+
+	return nil
+}
+
+// SetAvatar stores the avatar location of a user (empty values clear it)
+func (r *Repository) SetAvatar(ctx context.Context, userID int, key, url string) error {
+	query := `
+		INSERT INTO user_profiles (user_id, avatar_key, avatar_url, updated_at)
+		VALUES ($1, NULLIF($2, ''), NULLIF($3, ''), NOW())
+		ON CONFLICT (user_id) DO UPDATE
+		SET avatar_key = EXCLUDED.avatar_key,
+			avatar_url = EXCLUDED.avatar_url,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	if _, err := r.db.Exec(ctx, query, userID, key, url); err != nil {
+		return db.TranslateError(fmt.Errorf("failed to update avatar: %w", err), nil)
+	}
+
+	return nil
+}
@@ -0,0 +1,212 @@
+package profile
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"time"
+
+	"event-planner/internal/apperror"
+	"event-planner/internal/logging"
+	"event-planner/internal/storage"
+	"event-planner/internal/tracing"
+)
+
+// MaxAvatarSize is the largest accepted avatar upload in bytes
+const MaxAvatarSize = 2 << 20 // 2 MB
+
+// allowed avatar content types and the extension they are stored with
+var avatarTypes = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+var localeRegex = regexp.MustCompile(`^[a-zA-Z]{2,3}(-[a-zA-Z0-9]{2,8})*$`)
+
+// Store is the profile storage used by Service; Repository implements it on PostgreSQL
+type Store interface {
+	GetProfile(ctx context.Context, userID int) (*Profile, error)
+	GetPublicProfile(ctx context.Context, userID int) (*PublicDetails, error)
+	UpsertProfile(ctx context.Context, profile *Profile) error
+	SetAvatar(ctx context.Context, userID int, key, url string) error
+}
+
+// Service handles business logic for user profiles
+type Service struct {
+	repo    Store
+	avatars storage.Storage
+}
+
+// NewService creates a new profile service
+func NewService(repo Store, avatars storage.Storage) *Service {
+	return &Service{
+		repo:    repo,
+		avatars: avatars,
+	}
+}
+
+// GetMyProfile retrieves the full profile of the current user
+func (s *Service) GetMyProfile(ctx context.Context, userID int) (*Profile, error) {
+	ctx, span := tracing.Start(ctx, "profile.Service.GetMyProfile")
+	defer span.End()
+
+	if userID <= 0 {
+		return nil, apperror.Validation("user_id", "invalid user ID")
+	}
+
+	return s.repo.GetProfile(ctx, userID)
+}
+
+// GetPublicProfile retrieves the public profile of any user
+func (s *Service) GetPublicProfile(ctx context.Context, userID int) (*PublicDetails, error) {
+	ctx, span := tracing.Start(ctx, "profile.Service.GetPublicProfile")
+	defer span.End()
+
+	if userID <= 0 {
+		return nil, apperror.Validation("user_id", "invalid user ID")
+	}
+
+	return s.repo.GetPublicProfile(ctx, userID)
+}
+
+// UpdateProfile validates and replaces the editable fields of a profile
+func (s *Service) UpdateProfile(ctx context.Context, userID int, req *UpdateProfileRequest) (*Profile, error) {
+	ctx, span := tracing.Start(ctx, "profile.Service.UpdateProfile")
+	defer span.End()
+
+	if userID <= 0 {
+		return nil, apperror.Validation("user_id", "invalid user ID")
+	}
+
+	if req.Timezone == "" {
+		req.Timezone = "UTC"
+	}
+	if req.Locale == "" {
+		req.Locale = "en"
+	}
+
+	if err := s.validateUpdateRequest(req); err != nil {
+		return nil, err
+	}
+
+	profile, err := s.repo.GetProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	profile.DisplayName = req.DisplayName
+	profile.Bio = req.Bio
+	profile.Timezone = req.Timezone
+	profile.Locale = req.Locale
+
+	if err := s.repo.UpsertProfile(ctx, profile); err != nil {
+		return nil, err
+	}
+
+	return profile, nil
+}
+
+// UploadAvatar validates and stores a new avatar image, replacing the old one
+func (s *Service) UploadAvatar(ctx context.Context, userID int, data []byte) (*Profile, error) {
+	ctx, span := tracing.Start(ctx, "profile.Service.UploadAvatar")
+	defer span.End()
+
+	if userID <= 0 {
+		return nil, apperror.Validation("user_id", "invalid user ID")
+	}
+
+	if len(data) == 0 {
+		return nil, apperror.Validation("avatar", "avatar file is empty")
+	}
+
+	if len(data) > MaxAvatarSize {
+		return nil, apperror.Validation("avatar", "avatar must not exceed 2 MB")
+	}
+
+	ext, ok := avatarTypes[http.DetectContentType(data)]
+	if !ok {
+		return nil, apperror.Validation("avatar", "avatar must be a PNG, JPEG, GIF or WebP image")
+	}
+
+	profile, err := s.repo.GetProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// A fresh key per upload keeps cached copies of the old avatar from being served
+	key := fmt.Sprintf("%d/%d%s", userID, time.Now().UnixNano(), ext)
+	if err := s.avatars.Save(ctx, key, bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+
+	url := s.avatars.URL(key)
+	if err := s.repo.SetAvatar(ctx, userID, key, url); err != nil {
+		_ = s.avatars.Delete(ctx, key)
+		return nil, err
+	}
+
+	s.deleteAvatarFile(ctx, profile.AvatarKey)
+
+	profile.AvatarKey = key
+	profile.AvatarURL = url
+
+	return profile, nil
+}
+
+// DeleteAvatar removes the avatar of a user
+func (s *Service) DeleteAvatar(ctx context.Context, userID int) error {
+	ctx, span := tracing.Start(ctx, "profile.Service.DeleteAvatar")
+	defer span.End()
+
+	if userID <= 0 {
+		return apperror.Validation("user_id", "invalid user ID")
+	}
+
+	profile, err := s.repo.GetProfile(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.SetAvatar(ctx, userID, "", ""); err != nil {
+		return err
+	}
+
+	s.deleteAvatarFile(ctx, profile.AvatarKey)
+
+	return nil
+}
+
+// deleteAvatarFile removes a replaced avatar; failures only leave an orphaned file
+func (s *Service) deleteAvatarFile(ctx context.Context, key string) {
+	if key == "" {
+		return
+	}
+	if err := s.avatars.Delete(ctx, key); err != nil {
+		slog.WarnContext(ctx, "failed to delete old avatar", "key", key, logging.Err(err))
+	}
+}
+
+func (s *Service) validateUpdateRequest(req *UpdateProfileRequest) error {
+	if len(req.DisplayName) > 100 {
+		return apperror.Validation("display_name", "display name must not exceed 100 characters")
+	}
+
+	if len(req.Bio) > 500 {
+		return apperror.Validation("bio", "bio must not exceed 500 characters")
+	}
+
+	if _, err := time.LoadLocation(req.Timezone); err != nil {
+		return apperror.Validation("timezone", "invalid timezone: must be an IANA name such as 'Europe/Berlin'")
+	}
+
+	if !localeRegex.MatchString(req.Locale) {
+		return apperror.Validation("locale", "invalid locale: must be a language tag such as 'en-US'")
+	}
+
+	return nil
+}
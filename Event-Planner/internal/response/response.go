@@ -0,0 +1,76 @@
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"event-planner/internal/apperror"
+	"event-planner/internal/logging"
+)
+
+// ErrorBody is the JSON envelope of every error response
+type ErrorBody struct {
+	Error   string                `json:"error"`             // human-readable message
+	Code    string                `json:"code"`              // machine-readable code
+	Details []apperror.FieldError `json:"details,omitempty"` // field-level validation details
+}
+
+// kinds maps error kinds to their HTTP status and default code
+var kinds = []struct {
+	kind   error
+	status int
+	code   string
+}{
+	{apperror.ErrValidation, http.StatusBadRequest, "validation_failed"},
+	{apperror.ErrUnauthorized, http.StatusUnauthorized, "unauthorized"},
+	{apperror.ErrForbidden, http.StatusForbidden, "forbidden"},
+	{apperror.ErrNotFound, http.StatusNotFound, "not_found"},
+	{apperror.ErrConflict, http.StatusConflict, "conflict"},
+	{apperror.ErrUnprocessable, http.StatusUnprocessableEntity, "unprocessable"},
+	{apperror.ErrPreconditionFailed, http.StatusPreconditionFailed, "precondition_failed"},
+	{apperror.ErrPreconditionRequired, http.StatusPreconditionRequired, "precondition_required"},
+}
+
+// JSON writes payload as a JSON response with the given status
+func JSON(w http.ResponseWriter, status int, payload interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(payload)
+}
+
+// Error writes err as a JSON error envelope. Domain errors are mapped to their
+// status code; anything else is logged with the request and reported as a 500
+// without details.
+func Error(w http.ResponseWriter, err error) {
+	status, body := Describe(err)
+	if status == http.StatusInternalServerError {
+		logging.RecordError(w, err)
+	}
+	JSON(w, status, body)
+}
+
+// Describe returns the status code and envelope err is reported with
+func Describe(err error) (int, ErrorBody) {
+	var appErr *apperror.Error
+	if errors.As(err, &appErr) {
+		for _, k := range kinds {
+			if errors.Is(appErr.Kind, k.kind) {
+				code := appErr.Code
+				if code == "" {
+					code = k.code
+				}
+				return k.status, ErrorBody{Error: appErr.Message, Code: code, Details: appErr.Fields}
+			}
+		}
+	}
+
+	// Bare kinds, e.g. apperror.ErrUnauthorized
+	for _, k := range kinds {
+		if errors.Is(err, k.kind) {
+			return k.status, ErrorBody{Error: err.Error(), Code: k.code}
+		}
+	}
+
+	return http.StatusInternalServerError, ErrorBody{Error: "internal server error", Code: "internal_error"}
+}
@@ -2,10 +2,13 @@ package search
 
 import (
 	"encoding/json"
-	"fmt"
 	"net/http"
 
+	"event-planner/internal/apperror"
 	"event-planner/internal/auth"
+	"event-planner/internal/event"
+	"event-planner/internal/organization"
+	"event-planner/internal/response"
 )
 
 // Handler handles HTTP requests for search
@@ -22,12 +25,18 @@ func (h *Handler) SearchEvents(w http.ResponseWriter, r *http.Request) {
 	// Get current user ID from context
 	userID, ok := auth.GetUserID(r.Context())
 	if !ok {
-		http.Error(w, `{"error": "unauthorized"}`, http.StatusUnauthorized)
+		response.Error(w, apperror.ErrUnauthorized)
 		return
 	}
 
 	q := r.URL.Query()
 
+	page, err := event.ParsePage(q)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
 	filter := &EventsFilter{
 		Query:    q.Get("q"),
 		DateFrom: q.Get("date_from"),
@@ -35,11 +44,15 @@ func (h *Handler) SearchEvents(w http.ResponseWriter, r *http.Request) {
 		Role:     q.Get("role"),
 		Status:   q.Get("status"),
 		UserID:   userID,
+		Limit:    page.Limit,
+		Offset:   page.Offset,
+
+		OrganizationID: organization.CurrentID(r.Context()),
 	}
 
 	events, err := h.service.SearchEvents(r.Context(), filter)
 	if err != nil {
-		http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusBadRequest)
+		response.Error(w, err)
 		return
 	}
 
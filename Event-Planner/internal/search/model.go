@@ -8,4 +8,8 @@ type EventsFilter struct {
 	Role     string // 'organizer', 'attendee', 'collaborator' (optional)
 	Status   string // 'going', 'maybe', 'not_going' (optional)
 	UserID   int    // current user ID (required)
+	Limit    int    // page size, every match when zero (optional)
+	Offset   int    // (optional)
+
+	OrganizationID *int // organization in scope (optional)
 }
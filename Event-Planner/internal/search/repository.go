@@ -18,23 +18,16 @@ func NewRepository(db *pgxpool.Pool) *Repository {
 	return &Repository{db: db}
 }
 
-// SearchEvents searches events for a given user with filters
+// SearchEvents searches events for a given user with filters; drafts are left out
 func (r *Repository) SearchEvents(ctx context.Context, f *EventsFilter) ([]event.EventWithAttendeeInfo, error) {
 	query := `
 		SELECT 
-			e.id,
-			e.title,
-			e.description,
-			e.date,
-			e.time,
-			e.location,
-			e.organizer_id,
-			e.created_at,
+			` + event.EventColumns + `,
 			ea.role,
 			ea.status
 		FROM events e
 		JOIN event_attendees ea ON e.id = ea.event_id
-		WHERE ea.user_id = $1
+		WHERE ea.user_id = $1 AND e.deleted_at IS NULL AND e.status <> 'draft'
 	`
 
 	args := []interface{}{f.UserID}
@@ -76,7 +69,16 @@ func (r *Repository) SearchEvents(ctx context.Context, f *EventsFilter) ([]event
 		argIdx++
 	}
 
-	query += " ORDER BY e.date DESC, e.time DESC"
+	// Organization scope
+	if f.OrganizationID != nil {
+		query += fmt.Sprintf(" AND e.organization_id = $%d", argIdx)
+		args = append(args, *f.OrganizationID)
+		argIdx++
+	}
+
+	query += " ORDER BY e.date DESC, e.time DESC, e.id DESC"
+	query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argIdx, argIdx+1)
+	args = append(args, event.LimitArg(f.Limit), f.Offset)
 
 	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
@@ -87,18 +89,7 @@ func (r *Repository) SearchEvents(ctx context.Context, f *EventsFilter) ([]event
 	var eventsWithInfo []event.EventWithAttendeeInfo
 	for rows.Next() {
 		var e event.EventWithAttendeeInfo
-		if err := rows.Scan(
-			&e.ID,
-			&e.Title,
-			&e.Description,
-			&e.Date,
-			&e.Time,
-			&e.Location,
-			&e.OrganizerID,
-			&e.CreatedAt,
-			&e.Role,
-			&e.Status,
-		); err != nil {
+		if err := event.ScanEvent(rows, &e.Event, &e.Role, &e.Status); err != nil {
 			return nil, fmt.Errorf("failed to scan event: %w", err)
 		}
 		eventsWithInfo = append(eventsWithInfo, e)
@@ -2,27 +2,39 @@ package search
 
 import (
 	"context"
-	"fmt"
 	"time"
 
+	"event-planner/internal/apperror"
 	"event-planner/internal/event"
+	"event-planner/internal/tracing"
 )
 
+// Store is the search storage used by Service; Repository implements it on PostgreSQL
+type Store interface {
+	SearchEvents(ctx context.Context, f *EventsFilter) ([]event.EventWithAttendeeInfo, error)
+}
+
 // Service handles business logic for search
 type Service struct {
-	repo *Repository
+	repo Store
 }
 
-func NewService(repo *Repository) *Service {
+func NewService(repo Store) *Service {
 	return &Service{repo: repo}
 }
 
 // SearchEvents searches events for the current user with filters
 func (s *Service) SearchEvents(ctx context.Context, f *EventsFilter) ([]event.EventWithAttendeeInfo, error) {
+	ctx, span := tracing.Start(ctx, "search.Service.SearchEvents")
+	defer span.End()
+
 	if f.UserID <= 0 {
-		return nil, fmt.Errorf("invalid user ID")
+		return nil, apperror.Validation("user_id", "invalid user ID")
 	}
 
+	// Every invalid filter is reported, not just the first one
+	var invalid []apperror.FieldError
+
 	// Validate role if provided
 	if f.Role != "" {
 		validRoles := map[string]bool{
@@ -31,7 +43,7 @@ func (s *Service) SearchEvents(ctx context.Context, f *EventsFilter) ([]event.Ev
 			"collaborator": true,
 		}
 		if !validRoles[f.Role] {
-			return nil, fmt.Errorf("invalid role: must be 'organizer', 'attendee', or 'collaborator'")
+			invalid = append(invalid, apperror.FieldError{Field: "role", Message: "invalid role: must be 'organizer', 'attendee', or 'collaborator'"})
 		}
 	}
 
@@ -43,22 +55,26 @@ func (s *Service) SearchEvents(ctx context.Context, f *EventsFilter) ([]event.Ev
 			"not_going": true,
 		}
 		if !validStatuses[f.Status] {
-			return nil, fmt.Errorf("invalid status: must be 'going', 'maybe', or 'not_going'")
+			invalid = append(invalid, apperror.FieldError{Field: "status", Message: "invalid status: must be 'going', 'maybe', or 'not_going'"})
 		}
 	}
 
 	// Validate dates if provided
 	if f.DateFrom != "" {
 		if _, err := time.Parse("2006-01-02", f.DateFrom); err != nil {
-			return nil, fmt.Errorf("invalid date_from format, use YYYY-MM-DD")
+			invalid = append(invalid, apperror.FieldError{Field: "date_from", Message: "invalid date_from format, use YYYY-MM-DD"})
 		}
 	}
 	if f.DateTo != "" {
 		if _, err := time.Parse("2006-01-02", f.DateTo); err != nil {
-			return nil, fmt.Errorf("invalid date_to format, use YYYY-MM-DD")
+			invalid = append(invalid, apperror.FieldError{Field: "date_to", Message: "invalid date_to format, use YYYY-MM-DD"})
 		}
 	}
 
+	if len(invalid) > 0 {
+		return nil, apperror.InvalidFields(invalid[0].Message, invalid...)
+	}
+
 	events, err := s.repo.SearchEvents(ctx, f)
 	if err != nil {
 		return nil, err
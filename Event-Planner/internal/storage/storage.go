@@ -0,0 +1,22 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrNotFound is returned when a stored object does not exist
+var ErrNotFound = errors.New("object not found")
+
+// Storage persists binary objects (avatars, exports, ...) under a key
+type Storage interface {
+	// Save writes the content of r under key, replacing any existing object
+	Save(ctx context.Context, key string, r io.Reader) error
+	// Open returns a reader for the object stored under key
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the object stored under key; missing objects are ignored
+	Delete(ctx context.Context, key string) error
+	// URL returns the public URL the object can be downloaded from
+	URL(key string) string
+}
@@ -0,0 +1,105 @@
+package storetest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"event-planner/internal/account"
+	"event-planner/internal/admin"
+	"event-planner/internal/audit"
+	"event-planner/internal/auth"
+	"event-planner/internal/event"
+	"event-planner/internal/group"
+	"event-planner/internal/invitation"
+	"event-planner/internal/migrate"
+	"event-planner/internal/notification"
+	"event-planner/internal/organization"
+	"event-planner/internal/profile"
+	"event-planner/internal/search"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresPool connects to a fresh, migrated schema of the database at
+// TEST_DATABASE_URL and drops it when the test ends; the test is skipped when
+// the variable is not set
+func PostgresPool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+
+	pool := EmptyPostgresPool(t)
+	migrator, err := migrate.New(pool)
+	if err != nil {
+		t.Fatalf("migrate.New: %v", err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatalf("apply migrations: %v", err)
+	}
+
+	return pool
+}
+
+// EmptyPostgresPool is PostgresPool without the migrations applied
+func EmptyPostgresPool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	ctx := context.Background()
+
+	conn, err := pgx.Connect(ctx, dsn)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer conn.Close(ctx)
+
+	schema := fmt.Sprintf("test_%d", time.Now().UnixNano())
+	if _, err := conn.Exec(ctx, "CREATE SCHEMA "+schema); err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+	t.Cleanup(func() {
+		conn, err := pgx.Connect(context.Background(), dsn)
+		if err != nil {
+			t.Errorf("connect: %v", err)
+			return
+		}
+		defer conn.Close(context.Background())
+		conn.Exec(context.Background(), "DROP SCHEMA "+schema+" CASCADE")
+	})
+
+	cfg, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		t.Fatalf("parse database URL: %v", err)
+	}
+	cfg.ConnConfig.RuntimeParams["search_path"] = schema
+
+	pool, err := pgxpool.NewWithConfig(ctx, cfg)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	return pool
+}
+
+// PostgresBackend returns the PostgreSQL repositories on a pool from PostgresPool
+func PostgresBackend(pool *pgxpool.Pool) Backend {
+	return Backend{
+		Users:         auth.NewRepository(pool),
+		Events:        event.NewRepository(pool),
+		Invitations:   invitation.NewRepository(pool),
+		Search:        search.NewRepository(pool),
+		Notifications: notification.NewRepository(pool),
+		Audit:         audit.NewRepository(pool),
+		Organizations: organization.NewRepository(pool),
+		Groups:        group.NewRepository(pool),
+		Profiles:      profile.NewRepository(pool),
+		Admin:         admin.NewRepository(pool),
+		Accounts:      account.NewRepository(pool),
+	}
+}
@@ -0,0 +1,13 @@
+package storetest_test
+
+import (
+	"testing"
+
+	"event-planner/internal/storetest"
+)
+
+func TestPostgres(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) storetest.Backend {
+		return storetest.PostgresBackend(storetest.PostgresPool(t))
+	})
+}
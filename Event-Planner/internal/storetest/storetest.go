@@ -0,0 +1,1869 @@
+// Package storetest is the conformance suite of the storage interfaces used by
+// the services. The PostgreSQL repositories and the in-memory store both run
+// it, so tests written against either backend see the same behaviour.
+package storetest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"event-planner/internal/account"
+	"event-planner/internal/admin"
+	"event-planner/internal/apperror"
+	"event-planner/internal/audit"
+	"event-planner/internal/auth"
+	"event-planner/internal/event"
+	"event-planner/internal/group"
+	"event-planner/internal/invitation"
+	"event-planner/internal/notification"
+	"event-planner/internal/organization"
+	"event-planner/internal/profile"
+	"event-planner/internal/search"
+	"event-planner/internal/user"
+)
+
+// Backend is a storage implementation under test
+type Backend struct {
+	Users         auth.Store
+	Events        event.Store
+	Invitations   invitation.Store
+	Search        search.Store
+	Notifications notification.Store
+	Audit         audit.Store
+	Organizations organization.Store
+	Groups        group.Store
+	Profiles      profile.Store
+	Admin         admin.Store
+	Accounts      account.Store
+}
+
+// Run runs the suite; newBackend must return an empty backend on every call
+func Run(t *testing.T, newBackend func(t *testing.T) Backend) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, f *fixture)
+	}{
+		{"Users", testUsers},
+		{"Events", testEvents},
+		{"Revisions", testRevisions},
+		{"Versions", testVersions},
+		{"Patches", testPatches},
+		{"Trash", testTrash},
+		{"Statuses", testStatuses},
+		{"Drafts", testDrafts},
+		{"EventListings", testEventListings},
+		{"Attendance", testAttendance},
+		{"Invitations", testInvitations},
+		{"GroupInvitations", testGroupInvitations},
+		{"Search", testSearch},
+		{"Audit", testAudit},
+		{"Organizations", testOrganizations},
+		{"Groups", testGroups},
+		{"Profiles", testProfiles},
+		{"Admin", testAdmin},
+		{"Accounts", testAccounts},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.test(t, &fixture{Backend: newBackend(t), ctx: context.Background()})
+		})
+	}
+}
+
+// fixture wraps a backend with helpers that fail the test on errors
+type fixture struct {
+	Backend
+	ctx context.Context
+}
+
+func (f *fixture) user(t *testing.T, email string) int {
+	t.Helper()
+
+	u, err := f.Users.CreateUser(f.ctx, email, "hash", user.RoleMember)
+	if err != nil {
+		t.Fatalf("CreateUser(%s): %v", email, err)
+	}
+	return u.ID
+}
+
+func (f *fixture) organization(t *testing.T, createdBy int) int {
+	t.Helper()
+
+	org := &organization.Organization{
+		Name:              "Acme",
+		Slug:              fmt.Sprintf("acme-%d", time.Now().UnixNano()),
+		DefaultVisibility: event.VisibilityOrganization,
+		Timezone:          "UTC",
+	}
+	if err := f.Organizations.CreateOrganization(f.ctx, org, createdBy); err != nil {
+		t.Fatalf("CreateOrganization: %v", err)
+	}
+	return org.ID
+}
+
+func (f *fixture) group(t *testing.T, ownerID int, name string, emails ...string) int {
+	t.Helper()
+
+	g := &group.Group{OwnerID: ownerID, Name: name}
+	if err := f.Groups.CreateGroup(f.ctx, g); err != nil {
+		t.Fatalf("CreateGroup(%s): %v", name, err)
+	}
+	for _, email := range emails {
+		if _, err := f.Groups.AddMember(f.ctx, g.ID, email); err != nil {
+			t.Fatalf("AddMember(%s): %v", email, err)
+		}
+	}
+	return g.ID
+}
+
+// event creates an event on date (YYYY-MM-DD) at time (HH:MM:SS), personal
+// and public unless orgID is given
+func (f *fixture) event(t *testing.T, organizerID int, title, date, clock string, orgID *int) *event.Event {
+	t.Helper()
+
+	ev := &event.Event{
+		Title:          title,
+		Description:    title + " description",
+		Date:           mustParse(t, "2006-01-02", date),
+		Time:           mustParse(t, "15:04:05", clock),
+		Location:       "Main Hall",
+		OrganizerID:    organizerID,
+		OrganizationID: orgID,
+		Visibility:     event.VisibilityPublic,
+		Timezone:       "UTC",
+	}
+	if orgID != nil {
+		ev.Visibility = event.VisibilityOrganization
+	}
+
+	if err := f.Events.CreateEvent(f.ctx, ev); err != nil {
+		t.Fatalf("CreateEvent(%s): %v", title, err)
+	}
+	return ev
+}
+
+func (f *fixture) invite(t *testing.T, eventID, inviterID int, email string) *invitation.Invitation {
+	t.Helper()
+
+	inviteeID, err := f.Invitations.GetUserIDByEmail(f.ctx, email)
+	if err != nil {
+		t.Fatalf("GetUserIDByEmail: %v", err)
+	}
+
+	inv := &invitation.Invitation{
+		EventID:      eventID,
+		InviterID:    inviterID,
+		InviteeEmail: email,
+		InviteeID:    inviteeID,
+		Role:         "attendee",
+		Message:      "join us",
+	}
+	if err := f.Invitations.SendInvitation(f.ctx, inv); err != nil {
+		t.Fatalf("SendInvitation(%s): %v", email, err)
+	}
+	return inv
+}
+
+func testUsers(t *testing.T, f *fixture) {
+	u, err := f.Users.CreateUser(f.ctx, "Ada@example.com", "hash", user.RoleMember)
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	if u.ID == 0 || u.Email != "Ada@example.com" || u.Role != user.RoleMember || u.CreatedAt.IsZero() {
+		t.Errorf("CreateUser = %+v", u)
+	}
+
+	second := f.user(t, "bob@example.com")
+	if second <= u.ID {
+		t.Errorf("IDs are not increasing: %d after %d", second, u.ID)
+	}
+
+	_, err = f.Users.CreateUser(f.ctx, "Ada@example.com", "other", user.RoleMember)
+	wantError(t, "duplicate email", err, apperror.ErrConflict, "email_taken", "")
+
+	_, err = f.Users.CreateUser(f.ctx, "carol@example.com", "hash", "superuser")
+	wantError(t, "invalid role", err, apperror.ErrUnprocessable, "constraint_violation", "role")
+
+	found, err := f.Users.GetUserByEmail(f.ctx, "Ada@example.com")
+	if err != nil {
+		t.Fatalf("GetUserByEmail: %v", err)
+	}
+	if found.ID != u.ID || found.PasswordHash != "hash" || found.DisabledAt != nil {
+		t.Errorf("GetUserByEmail = %+v", found)
+	}
+
+	// Emails are matched exactly
+	_, err = f.Users.GetUserByEmail(f.ctx, "ada@example.com")
+	wantError(t, "GetUserByEmail with other case", err, apperror.ErrNotFound, "not_found", "")
+
+	byID, err := f.Users.GetUserByID(f.ctx, u.ID)
+	if err != nil {
+		t.Fatalf("GetUserByID: %v", err)
+	}
+	if byID.Email != u.Email || byID.PasswordHash != "hash" {
+		t.Errorf("GetUserByID = %+v", byID)
+	}
+
+	_, err = f.Users.GetUserByID(f.ctx, 999999)
+	wantError(t, "GetUserByID of a missing user", err, apperror.ErrNotFound, "not_found", "")
+
+	// Emails are compared lower-cased when promoting
+	if err := f.Users.PromoteToAdmin(f.ctx, []string{"ada@example.com"}); err != nil {
+		t.Fatalf("PromoteToAdmin: %v", err)
+	}
+	promoted, _ := f.Users.GetUserByID(f.ctx, u.ID)
+	other, _ := f.Users.GetUserByID(f.ctx, second)
+	if promoted.Role != user.RoleAdmin || other.Role != user.RoleMember {
+		t.Errorf("after PromoteToAdmin: roles %s and %s", promoted.Role, other.Role)
+	}
+}
+
+func testEvents(t *testing.T, f *fixture) {
+	ada := f.user(t, "ada@example.com")
+
+	ev := f.event(t, ada, "Launch", "2030-05-01", "18:30:00", nil)
+	if ev.ID == 0 || ev.CreatedAt.IsZero() {
+		t.Fatalf("CreateEvent did not set the ID and creation time: %+v", ev)
+	}
+
+	got, err := f.Events.GetEventByID(f.ctx, ev.ID)
+	if err != nil {
+		t.Fatalf("GetEventByID: %v", err)
+	}
+	if got.Title != "Launch" || got.OrganizerID != ada || got.Visibility != event.VisibilityPublic ||
+		got.Date.Format("2006-01-02") != "2030-05-01" || got.Time.Format("15:04:05") != "18:30:00" ||
+		got.OrganizationID != nil || got.ArchivedAt != nil || !got.CreatedAt.Equal(ev.CreatedAt) {
+		t.Errorf("GetEventByID = %+v", got)
+	}
+
+	_, err = f.Events.GetEventByID(f.ctx, 999999)
+	wantError(t, "GetEventByID of a missing event", err, apperror.ErrNotFound, "event_not_found", "")
+
+	// Constraints
+	bad := &event.Event{Title: "x", Date: got.Date, Time: got.Time, Location: "x", OrganizerID: 999999, Visibility: event.VisibilityPublic, Timezone: "UTC"}
+	wantError(t, "unknown organizer", f.Events.CreateEvent(f.ctx, bad), apperror.ErrUnprocessable, "invalid_reference", "organizer_id")
+
+	bad.OrganizerID, bad.Visibility = ada, event.VisibilityOrganization
+	wantError(t, "personal organization event", f.Events.CreateEvent(f.ctx, bad), apperror.ErrUnprocessable, "invalid_visibility", "")
+
+	// Updates only change the given fields
+	updated, err := f.Events.UpdateEvent(f.ctx, ev.ID, &event.UpdateEventRequest{Title: "Launch party", Time: "19:00:00"}, ada, event.AnyVersion)
+	if err != nil {
+		t.Fatalf("UpdateEvent: %v", err)
+	}
+	if updated.Title != "Launch party" || updated.Time.Format("15:04:05") != "19:00:00" ||
+		updated.Date.Format("2006-01-02") != "2030-05-01" || updated.Location != "Main Hall" {
+		t.Errorf("UpdateEvent = %+v", updated)
+	}
+	if got, _ := f.Events.GetEventByID(f.ctx, ev.ID); got.Title != "Launch party" {
+		t.Errorf("update not stored: %+v", got)
+	}
+
+	_, err = f.Events.UpdateEvent(f.ctx, ev.ID, &event.UpdateEventRequest{Visibility: event.VisibilityOrganization}, ada, event.AnyVersion)
+	wantError(t, "organization visibility on a personal event", err, apperror.ErrUnprocessable, "invalid_visibility", "")
+
+	_, err = f.Events.UpdateEvent(f.ctx, 999999, &event.UpdateEventRequest{Title: "x"}, ada, event.AnyVersion)
+	wantError(t, "UpdateEvent of a missing event", err, apperror.ErrNotFound, "event_not_found", "")
+
+	// Deleting cascades to attendees and invitations
+	bob := f.user(t, "bob@example.com")
+	if err := f.Events.JoinEvent(f.ctx, bob, ev.ID); err != nil {
+		t.Fatalf("JoinEvent: %v", err)
+	}
+	f.invite(t, ev.ID, ada, "carol@example.com")
+
+	if err := f.Events.DeleteEvent(f.ctx, ev.ID, event.AnyVersion); err != nil {
+		t.Fatalf("DeleteEvent: %v", err)
+	}
+	_, err = f.Events.GetEventByID(f.ctx, ev.ID)
+	wantError(t, "GetEventByID after delete", err, apperror.ErrNotFound, "event_not_found", "")
+
+	attending, err := f.Events.GetEventsByAttendeeID(f.ctx, bob, nil)
+	if err != nil || len(attending) != 0 {
+		t.Errorf("attendance survived the event: %v, %v", attending, err)
+	}
+	invitations, err := f.Invitations.GetInvitationsByEmail(f.ctx, "carol@example.com", nil)
+	if err != nil || len(invitations) != 0 {
+		t.Errorf("invitation survived the event: %v, %v", invitations, err)
+	}
+
+	wantError(t, "deleting twice", f.Events.DeleteEvent(f.ctx, ev.ID, event.AnyVersion), apperror.ErrNotFound, "event_not_found", "")
+}
+
+func testRevisions(t *testing.T, f *fixture) {
+	ada := f.user(t, "ada@example.com")
+	bob := f.user(t, "bob@example.com")
+
+	ev := f.event(t, ada, "Launch", "2030-05-01", "18:30:00", nil)
+
+	// Every edit that changes a revision field adds a revision; no-op edits do not
+	if _, err := f.Events.UpdateEvent(f.ctx, ev.ID, &event.UpdateEventRequest{Time: "19:00:00", Location: "Roof"}, bob, event.AnyVersion); err != nil {
+		t.Fatalf("UpdateEvent: %v", err)
+	}
+	if _, err := f.Events.UpdateEvent(f.ctx, ev.ID, &event.UpdateEventRequest{Title: "Launch"}, ada, event.AnyVersion); err != nil {
+		t.Fatalf("no-op UpdateEvent: %v", err)
+	}
+
+	revisions, err := f.Events.GetRevisions(f.ctx, ev.ID)
+	if err != nil {
+		t.Fatalf("GetRevisions: %v", err)
+	}
+	if len(revisions) != 2 {
+		t.Fatalf("GetRevisions = %+v, want 2 revisions", revisions)
+	}
+	latest, first := revisions[0], revisions[1]
+	if first.Revision != 1 || first.EditorID == nil || *first.EditorID != ada ||
+		!reflect.DeepEqual(first.Changed, event.RevisionFields) || first.Time.Format("15:04:05") != "18:30:00" {
+		t.Errorf("first revision = %+v", first)
+	}
+	if latest.Revision != 2 || latest.EditorID == nil || *latest.EditorID != bob || latest.RestoredFrom != nil ||
+		!reflect.DeepEqual(latest.Changed, []string{"time", "location"}) ||
+		latest.Time.Format("15:04:05") != "19:00:00" || latest.Location != "Roof" || latest.CreatedAt.IsZero() {
+		t.Errorf("latest revision = %+v", latest)
+	}
+
+	got, err := f.Events.GetRevision(f.ctx, ev.ID, 1)
+	if err != nil {
+		t.Fatalf("GetRevision: %v", err)
+	}
+	if got.Revision != 1 || got.Location != "Main Hall" || got.Date.Format("2006-01-02") != "2030-05-01" {
+		t.Errorf("GetRevision = %+v", got)
+	}
+	_, err = f.Events.GetRevision(f.ctx, ev.ID, 9)
+	wantError(t, "GetRevision of a missing revision", err, apperror.ErrNotFound, "revision_not_found", "")
+
+	// Restoring applies the old fields and records where they came from
+	restored, err := f.Events.RestoreRevision(f.ctx, ev.ID, 1, ada)
+	if err != nil {
+		t.Fatalf("RestoreRevision: %v", err)
+	}
+	if restored.Time.Format("15:04:05") != "18:30:00" || restored.Location != "Main Hall" || restored.Title != "Launch" {
+		t.Errorf("RestoreRevision = %+v", restored)
+	}
+	if stored, _ := f.Events.GetEventByID(f.ctx, ev.ID); stored.Location != "Main Hall" {
+		t.Errorf("restore not stored: %+v", stored)
+	}
+	third, err := f.Events.GetRevision(f.ctx, ev.ID, 3)
+	if err != nil {
+		t.Fatalf("GetRevision of the restore: %v", err)
+	}
+	if third.RestoredFrom == nil || *third.RestoredFrom != 1 || !reflect.DeepEqual(third.Changed, []string{"time", "location"}) {
+		t.Errorf("restore revision = %+v", third)
+	}
+
+	_, err = f.Events.RestoreRevision(f.ctx, ev.ID, 9, ada)
+	wantError(t, "RestoreRevision of a missing revision", err, apperror.ErrNotFound, "revision_not_found", "")
+	_, err = f.Events.RestoreRevision(f.ctx, 999999, 1, ada)
+	wantError(t, "RestoreRevision of a missing event", err, apperror.ErrNotFound, "event_not_found", "")
+
+	// Revisions go with the event
+	if err := f.Events.DeleteEvent(f.ctx, ev.ID, event.AnyVersion); err != nil {
+		t.Fatalf("DeleteEvent: %v", err)
+	}
+	revisions, err = f.Events.GetRevisions(f.ctx, ev.ID)
+	if err != nil || len(revisions) != 0 {
+		t.Errorf("revisions survived the event: %v, %v", revisions, err)
+	}
+}
+
+func testVersions(t *testing.T, f *fixture) {
+	ada := f.user(t, "ada@example.com")
+
+	ev := f.event(t, ada, "Launch", "2030-05-01", "18:30:00", nil)
+	if ev.Version != 1 {
+		t.Errorf("CreateEvent version = %d, want 1", ev.Version)
+	}
+
+	// Every change increments the version, edits that change nothing keep it
+	updated, err := f.Events.UpdateEvent(f.ctx, ev.ID, &event.UpdateEventRequest{Location: "Roof"}, ada, 1)
+	if err != nil {
+		t.Fatalf("UpdateEvent at the current version: %v", err)
+	}
+	if updated.Version != 2 {
+		t.Errorf("version after an update = %d, want 2", updated.Version)
+	}
+	if same, err := f.Events.UpdateEvent(f.ctx, ev.ID, &event.UpdateEventRequest{Location: "Roof"}, ada, 2); err != nil || same.Version != 2 {
+		t.Errorf("no-op UpdateEvent = %+v, %v; want version 2", same, err)
+	}
+	if restored, err := f.Events.RestoreRevision(f.ctx, ev.ID, 1, ada); err != nil || restored.Version != 3 {
+		t.Errorf("RestoreRevision = %+v, %v; want version 3", restored, err)
+	}
+	if got, _ := f.Events.GetEventByID(f.ctx, ev.ID); got.Version != 3 {
+		t.Errorf("stored version = %d, want 3", got.Version)
+	}
+
+	// Stale versions are rejected without changing anything
+	_, err = f.Events.UpdateEvent(f.ctx, ev.ID, &event.UpdateEventRequest{Title: "Overwritten"}, ada, 2)
+	wantError(t, "UpdateEvent at a stale version", err, apperror.ErrPreconditionFailed, "version_mismatch", "")
+	wantError(t, "DeleteEvent at a stale version", f.Events.DeleteEvent(f.ctx, ev.ID, 2), apperror.ErrPreconditionFailed, "version_mismatch", "")
+	if got, _ := f.Events.GetEventByID(f.ctx, ev.ID); got.Title != "Launch" || got.Version != 3 {
+		t.Errorf("stale requests changed the event: %+v", got)
+	}
+
+	_, err = f.Events.UpdateEvent(f.ctx, 999999, &event.UpdateEventRequest{Title: "x"}, ada, 1)
+	wantError(t, "UpdateEvent of a missing event", err, apperror.ErrNotFound, "event_not_found", "")
+	wantError(t, "DeleteEvent of a missing event", f.Events.DeleteEvent(f.ctx, 999999, 1), apperror.ErrNotFound, "event_not_found", "")
+
+	if err := f.Events.DeleteEvent(f.ctx, ev.ID, 3); err != nil {
+		t.Fatalf("DeleteEvent at the current version: %v", err)
+	}
+}
+
+func testPatches(t *testing.T, f *fixture) {
+	ada := f.user(t, "ada@example.com")
+
+	ev := f.event(t, ada, "Launch", "2030-05-01", "18:30:00", nil)
+	patch := func(body string) *event.EventPatch {
+		t.Helper()
+
+		p, err := event.DecodeEventPatch(strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("DecodeEventPatch(%s): %v", body, err)
+		}
+		return p
+	}
+
+	// Absent fields are kept, null clears the description
+	patched, err := f.Events.PatchEvent(f.ctx, ev.ID, patch(`{"description": null, "time": "20:00:00"}`), ada, 1)
+	if err != nil {
+		t.Fatalf("PatchEvent: %v", err)
+	}
+	if patched.Description != "" || patched.Time.Format("15:04:05") != "20:00:00" || patched.Title != "Launch" ||
+		patched.Date.Format("2006-01-02") != "2030-05-01" || patched.Location != "Main Hall" || patched.Version != 2 {
+		t.Errorf("PatchEvent = %+v", patched)
+	}
+	if got, _ := f.Events.GetEventByID(f.ctx, ev.ID); got.Description != "" || got.Time.Format("15:04:05") != "20:00:00" {
+		t.Errorf("patch not stored: %+v", got)
+	}
+	if rev, err := f.Events.GetRevision(f.ctx, ev.ID, 2); err != nil || !reflect.DeepEqual(rev.Changed, []string{"description", "time"}) {
+		t.Errorf("patch revision = %+v, %v", rev, err)
+	}
+
+	// An empty patch changes nothing
+	if same, err := f.Events.PatchEvent(f.ctx, ev.ID, patch(`{}`), ada, 2); err != nil || same.Version != 2 {
+		t.Errorf("empty PatchEvent = %+v, %v", same, err)
+	}
+
+	_, err = f.Events.PatchEvent(f.ctx, ev.ID, patch(`{"location": null}`), ada, 2)
+	wantError(t, "removing the location", err, apperror.ErrValidation, "validation_failed", "location")
+	_, err = f.Events.PatchEvent(f.ctx, ev.ID, patch(`{"visibility": "organization"}`), ada, 2)
+	wantError(t, "organization visibility on a personal event", err, apperror.ErrUnprocessable, "invalid_visibility", "")
+	_, err = f.Events.PatchEvent(f.ctx, ev.ID, patch(`{"title": "Stale"}`), ada, 1)
+	wantError(t, "PatchEvent at a stale version", err, apperror.ErrPreconditionFailed, "version_mismatch", "")
+	_, err = f.Events.PatchEvent(f.ctx, 999999, patch(`{"title": "x"}`), ada, event.AnyVersion)
+	wantError(t, "PatchEvent of a missing event", err, apperror.ErrNotFound, "event_not_found", "")
+}
+
+func testTrash(t *testing.T, f *fixture) {
+	ada := f.user(t, "ada@example.com")
+	bob := f.user(t, "bob@example.com")
+
+	kept := f.event(t, ada, "kept", "2030-05-01", "18:30:00", nil)
+	first := f.event(t, ada, "first", "2030-05-02", "18:30:00", nil)
+	second := f.event(t, ada, "second", "2030-05-03", "18:30:00", nil)
+	for _, ev := range []*event.Event{kept, first, second} {
+		if err := f.Events.JoinEvent(f.ctx, bob, ev.ID); err != nil {
+			t.Fatalf("JoinEvent: %v", err)
+		}
+	}
+	f.invite(t, first.ID, ada, "bob@example.com")
+
+	wantError(t, "TrashEvent at a stale version", f.Events.TrashEvent(f.ctx, first.ID, 2), apperror.ErrPreconditionFailed, "version_mismatch", "")
+	if err := f.Events.TrashEvent(f.ctx, first.ID, 1); err != nil {
+		t.Fatalf("TrashEvent: %v", err)
+	}
+	if err := f.Events.TrashEvent(f.ctx, second.ID, event.AnyVersion); err != nil {
+		t.Fatalf("TrashEvent: %v", err)
+	}
+	wantError(t, "TrashEvent of a trashed event", f.Events.TrashEvent(f.ctx, first.ID, event.AnyVersion), apperror.ErrNotFound, "event_not_found", "")
+
+	// Trashed events are gone from everything but the trash
+	_, err := f.Events.GetEventByID(f.ctx, first.ID)
+	wantError(t, "GetEventByID of a trashed event", err, apperror.ErrNotFound, "event_not_found", "")
+	_, err = f.Events.UpdateEvent(f.ctx, first.ID, &event.UpdateEventRequest{Title: "x"}, ada, event.AnyVersion)
+	wantError(t, "UpdateEvent of a trashed event", err, apperror.ErrNotFound, "event_not_found", "")
+	_, _, err = f.Invitations.GetEventVisibility(f.ctx, first.ID)
+	wantError(t, "GetEventVisibility of a trashed event", err, apperror.ErrNotFound, "event_not_found", "")
+
+	all, err := f.Events.GetAllEvents(f.ctx, nil, event.Page{})
+	if err != nil {
+		t.Fatalf("GetAllEvents: %v", err)
+	}
+	wantIDs(t, "GetAllEvents", ids(all), kept.ID)
+	organized, err := f.Events.GetMyOrganizedEvents(f.ctx, ada, nil)
+	if err != nil {
+		t.Fatalf("GetMyOrganizedEvents: %v", err)
+	}
+	wantIDs(t, "GetMyOrganizedEvents", ids(organized), kept.ID)
+	attending, err := f.Events.GetEventsByAttendeeID(f.ctx, bob, nil)
+	if err != nil || len(attending) != 1 || attending[0].ID != kept.ID {
+		t.Errorf("GetEventsByAttendeeID = %+v, %v; want only %d", attending, err, kept.ID)
+	}
+	found, err := f.Search.SearchEvents(f.ctx, &search.EventsFilter{UserID: bob})
+	if err != nil || len(found) != 1 || found[0].ID != kept.ID {
+		t.Errorf("SearchEvents = %+v, %v; want only %d", found, err, kept.ID)
+	}
+	invitations, err := f.Invitations.GetInvitationsByEmail(f.ctx, "bob@example.com", nil)
+	if err != nil || len(invitations) != 0 {
+		t.Errorf("GetInvitationsByEmail = %+v, %v; want none", invitations, err)
+	}
+
+	// The trash lists the most recently deleted first
+	trash, err := f.Events.GetTrashedEvents(f.ctx, ada)
+	if err != nil {
+		t.Fatalf("GetTrashedEvents: %v", err)
+	}
+	wantIDs(t, "GetTrashedEvents", ids(trash), second.ID, first.ID)
+	if trash[0].DeletedAt == nil {
+		t.Errorf("trashed event without deleted_at: %+v", trash[0])
+	}
+	if others, err := f.Events.GetTrashedEvents(f.ctx, bob); err != nil || len(others) != 0 {
+		t.Errorf("GetTrashedEvents of another organizer = %+v, %v", others, err)
+	}
+
+	// Restoring brings the event back with its attendees and invitations
+	_, err = f.Events.RestoreEvent(f.ctx, first.ID, bob)
+	wantError(t, "RestoreEvent by another user", err, apperror.ErrNotFound, "trashed_event_not_found", "")
+	_, err = f.Events.RestoreEvent(f.ctx, kept.ID, ada)
+	wantError(t, "RestoreEvent of an event not in the trash", err, apperror.ErrNotFound, "trashed_event_not_found", "")
+	restored, err := f.Events.RestoreEvent(f.ctx, first.ID, ada)
+	if err != nil {
+		t.Fatalf("RestoreEvent: %v", err)
+	}
+	if restored.DeletedAt != nil || restored.Title != "first" || restored.Version != 1 {
+		t.Errorf("RestoreEvent = %+v", restored)
+	}
+	if attendees, err := f.Events.GetEventAttendees(f.ctx, first.ID); err != nil || len(attendees) != 1 {
+		t.Errorf("attendees after restoring = %+v, %v; want bob", attendees, err)
+	}
+	if invitations, err := f.Invitations.GetInvitationsByEmail(f.ctx, "bob@example.com", nil); err != nil || len(invitations) != 1 {
+		t.Errorf("invitations after restoring = %+v, %v; want 1", invitations, err)
+	}
+
+	// Purging erases what was deleted before the cutoff
+	if purged, err := f.Events.PurgeTrashedEvents(f.ctx, time.Now().UTC().Add(-time.Hour)); err != nil || len(purged) != 0 {
+		t.Errorf("PurgeTrashedEvents before the deletion = %+v, %v; want none", purged, err)
+	}
+	purged, err := f.Events.PurgeTrashedEvents(f.ctx, time.Now().UTC().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("PurgeTrashedEvents: %v", err)
+	}
+	wantIDs(t, "PurgeTrashedEvents", ids(purged), second.ID)
+	if trash, err := f.Events.GetTrashedEvents(f.ctx, ada); err != nil || len(trash) != 0 {
+		t.Errorf("trash after purging = %+v, %v", trash, err)
+	}
+	if attendees, err := f.Events.GetEventAttendees(f.ctx, second.ID); err != nil || len(attendees) != 0 {
+		t.Errorf("attendees of a purged event = %+v, %v", attendees, err)
+	}
+	_, err = f.Events.RestoreEvent(f.ctx, second.ID, ada)
+	wantError(t, "RestoreEvent of a purged event", err, apperror.ErrNotFound, "trashed_event_not_found", "")
+}
+
+func testStatuses(t *testing.T, f *fixture) {
+	ada := f.user(t, "ada@example.com")
+	bob := f.user(t, "bob@example.com")
+	dave := f.user(t, "dave@example.com")
+
+	ev := f.event(t, ada, "Launch", "2030-06-01", "18:00:00", nil)
+	if ev.Status != event.StatusScheduled {
+		t.Errorf("new event has status %q, want scheduled", ev.Status)
+	}
+	if err := f.Events.AddOrganizerAsAttendee(f.ctx, ada, ev.ID); err != nil {
+		t.Fatalf("AddOrganizerAsAttendee: %v", err)
+	}
+	if err := f.Events.JoinEvent(f.ctx, bob, ev.ID); err != nil {
+		t.Fatalf("JoinEvent: %v", err)
+	}
+	f.invite(t, ev.ID, ada, "carol@example.com")
+	declined := f.invite(t, ev.ID, ada, "dave@example.com")
+	if err := f.Invitations.UpdateInvitationStatus(f.ctx, declined.ID, "declined"); err != nil {
+		t.Fatalf("UpdateInvitationStatus: %v", err)
+	}
+
+	_, err := f.Events.ChangeStatus(f.ctx, ev.ID, event.StatusPostponed, "storm", ada, 2)
+	wantError(t, "ChangeStatus at a stale version", err, apperror.ErrPreconditionFailed, "version_mismatch", "")
+	postponed, err := f.Events.ChangeStatus(f.ctx, ev.ID, event.StatusPostponed, "storm", ada, 1)
+	if err != nil {
+		t.Fatalf("ChangeStatus: %v", err)
+	}
+	if postponed.Status != event.StatusPostponed || postponed.StatusReason != "storm" || postponed.Version != 2 {
+		t.Errorf("postponed event = %+v", postponed)
+	}
+	_, err = f.Events.ChangeStatus(f.ctx, ev.ID, event.StatusPostponed, "again", ada, event.AnyVersion)
+	wantError(t, "postponing a postponed event", err, apperror.ErrConflict, "invalid_status_transition", "")
+
+	// Attendees but the organizer and pending invitees are notified, by email
+	notifications, err := f.Notifications.GetNotifications(f.ctx, bob, false)
+	if err != nil || len(notifications) != 1 {
+		t.Fatalf("notifications of bob = %+v, %v; want 1", notifications, err)
+	}
+	if n := notifications[0]; n.EventID != ev.ID || n.EventStatus != event.StatusPostponed || n.EventTitle != "Launch" || n.Reason != "storm" || n.ReadAt != nil {
+		t.Errorf("notification = %+v", n)
+	}
+	for _, userID := range []int{ada, dave} {
+		if others, err := f.Notifications.GetNotifications(f.ctx, userID, false); err != nil || len(others) != 0 {
+			t.Errorf("notifications of user %d = %+v, %v; want none", userID, others, err)
+		}
+	}
+
+	// Moving a postponed event schedules it again; other edits keep it postponed
+	patched, err := f.Events.PatchEvent(f.ctx, ev.ID, &event.EventPatch{Title: event.PatchString{Set: true, Value: "Launch party"}}, ada, event.AnyVersion)
+	if err != nil || patched.Status != event.StatusPostponed {
+		t.Errorf("PatchEvent of the title = %+v, %v; want it postponed", patched, err)
+	}
+	moved, err := f.Events.UpdateEvent(f.ctx, ev.ID, &event.UpdateEventRequest{Date: "2030-07-01"}, ada, event.AnyVersion)
+	if err != nil || moved.Status != event.StatusScheduled || moved.StatusReason != "" {
+		t.Errorf("UpdateEvent of the date = %+v, %v; want it scheduled", moved, err)
+	}
+
+	cancelled, err := f.Events.ChangeStatus(f.ctx, ev.ID, event.StatusCancelled, "no venue", ada, moved.Version)
+	if err != nil || cancelled.Status != event.StatusCancelled || cancelled.Version != moved.Version+1 {
+		t.Fatalf("ChangeStatus = %+v, %v", cancelled, err)
+	}
+	_, err = f.Events.ChangeStatus(f.ctx, ev.ID, event.StatusCancelled, "again", ada, event.AnyVersion)
+	wantError(t, "cancelling a cancelled event", err, apperror.ErrConflict, "invalid_status_transition", "")
+
+	// Cancelled events stay listed, marked as such
+	if got, err := f.Events.GetEventByID(f.ctx, ev.ID); err != nil || got.Status != event.StatusCancelled || got.StatusReason != "no venue" {
+		t.Errorf("GetEventByID = %+v, %v", got, err)
+	}
+	attending, err := f.Events.GetEventsByAttendeeID(f.ctx, bob, nil)
+	if err != nil || len(attending) != 1 || attending[0].Event.Status != event.StatusCancelled {
+		t.Errorf("GetEventsByAttendeeID = %+v, %v; want the cancelled event", attending, err)
+	}
+	invitations, err := f.Invitations.GetInvitationsByEmail(f.ctx, "carol@example.com", nil)
+	if err != nil || len(invitations) != 1 || invitations[0].EventStatus != event.StatusCancelled {
+		t.Errorf("GetInvitationsByEmail = %+v, %v; want the cancelled event", invitations, err)
+	}
+	if status, err := f.Invitations.GetEventStatus(f.ctx, ev.ID); err != nil || status != event.StatusCancelled {
+		t.Errorf("GetEventStatus = %q, %v", status, err)
+	}
+	_, err = f.Invitations.GetEventStatus(f.ctx, 999999)
+	wantError(t, "GetEventStatus of a missing event", err, apperror.ErrNotFound, "event_not_found", "")
+
+	// Invitees without an account find their notifications once they register
+	carol := f.user(t, "carol@example.com")
+	notifications, err = f.Notifications.GetNotifications(f.ctx, carol, false)
+	if err != nil || len(notifications) != 2 || notifications[0].EventStatus != event.StatusCancelled || notifications[1].EventStatus != event.StatusPostponed {
+		t.Fatalf("notifications of carol = %+v, %v; want cancelled then postponed", notifications, err)
+	}
+	wantError(t, "MarkRead by another user", f.Notifications.MarkRead(f.ctx, notifications[0].ID, bob), apperror.ErrNotFound, "notification_not_found", "")
+	if err := f.Notifications.MarkRead(f.ctx, notifications[0].ID, carol); err != nil {
+		t.Fatalf("MarkRead: %v", err)
+	}
+	if err := f.Notifications.MarkRead(f.ctx, notifications[0].ID, carol); err != nil {
+		t.Errorf("MarkRead again: %v", err)
+	}
+	unread, err := f.Notifications.GetNotifications(f.ctx, carol, true)
+	if err != nil || len(unread) != 1 || unread[0].ID != notifications[1].ID {
+		t.Errorf("unread notifications = %+v, %v; want the postponement", unread, err)
+	}
+
+	// Scheduled events that have started are completed
+	past := f.event(t, ada, "past", "2020-01-01", "10:00:00", nil)
+	pastCancelled := f.event(t, ada, "past cancelled", "2020-01-02", "10:00:00", nil)
+	if _, err := f.Events.ChangeStatus(f.ctx, pastCancelled.ID, event.StatusCancelled, "rain", ada, event.AnyVersion); err != nil {
+		t.Fatalf("ChangeStatus: %v", err)
+	}
+	completed, err := f.Events.CompleteEvents(f.ctx, time.Now())
+	if err != nil {
+		t.Fatalf("CompleteEvents: %v", err)
+	}
+	wantIDs(t, "CompleteEvents", ids(completed), past.ID)
+	if completed[0].Status != event.StatusCompleted || completed[0].Version != 2 {
+		t.Errorf("completed event = %+v", completed[0])
+	}
+	if again, err := f.Events.CompleteEvents(f.ctx, time.Now()); err != nil || len(again) != 0 {
+		t.Errorf("CompleteEvents again = %+v, %v; want none", again, err)
+	}
+
+	// Deleting the event deletes its notifications
+	if err := f.Events.DeleteEvent(f.ctx, ev.ID, event.AnyVersion); err != nil {
+		t.Fatalf("DeleteEvent: %v", err)
+	}
+	if notifications, err := f.Notifications.GetNotifications(f.ctx, bob, false); err != nil || len(notifications) != 0 {
+		t.Errorf("notifications of a deleted event = %+v, %v", notifications, err)
+	}
+}
+
+func testDrafts(t *testing.T, f *fixture) {
+	ada := f.user(t, "ada@example.com")
+	bob := f.user(t, "bob@example.com")
+	carol := f.user(t, "carol@example.com")
+
+	published := f.event(t, ada, "Published", "2030-05-01", "18:00:00", nil)
+	publishAt := time.Date(2030, 5, 15, 9, 0, 0, 0, time.UTC)
+	draft := &event.Event{
+		Title:       "Draft",
+		Date:        mustParse(t, "2006-01-02", "2030-06-01"),
+		Time:        mustParse(t, "15:04:05", "18:00:00"),
+		Location:    "Main Hall",
+		OrganizerID: ada,
+		Visibility:  event.VisibilityPublic,
+		Timezone:    "UTC",
+		Status:      event.StatusDraft,
+		PublishAt:   &publishAt,
+	}
+	if err := f.Events.CreateEvent(f.ctx, draft); err != nil {
+		t.Fatalf("CreateEvent: %v", err)
+	}
+	later := f.event(t, ada, "Later", "2030-07-01", "18:00:00", nil)
+	for _, ev := range []*event.Event{published, draft} {
+		if err := f.Events.AddOrganizerAsAttendee(f.ctx, ada, ev.ID); err != nil {
+			t.Fatalf("AddOrganizerAsAttendee: %v", err)
+		}
+	}
+	if err := f.Events.AddAttendee(f.ctx, draft.ID, bob, "collaborator"); err != nil {
+		t.Fatalf("AddAttendee: %v", err)
+	}
+
+	got, err := f.Events.GetEventByID(f.ctx, draft.ID)
+	if err != nil || got.Status != event.StatusDraft || got.PublishAt == nil || !got.PublishAt.Equal(publishAt) {
+		t.Fatalf("GetEventByID = %+v, %v; want the draft to be published at %v", got, err, publishAt)
+	}
+
+	// Drafts are left out of listings and search, but not of the organizer's own events
+	all, err := f.Events.GetAllEvents(f.ctx, nil, event.Page{})
+	if err != nil {
+		t.Fatalf("GetAllEvents: %v", err)
+	}
+	wantIDs(t, "GetAllEvents", ids(all), later.ID, published.ID)
+	byOrganizer, err := f.Events.GetEventsByOrganizerID(f.ctx, ada, nil)
+	if err != nil {
+		t.Fatalf("GetEventsByOrganizerID: %v", err)
+	}
+	wantIDs(t, "GetEventsByOrganizerID", ids(byOrganizer), later.ID, published.ID)
+	found, err := f.Search.SearchEvents(f.ctx, &search.EventsFilter{UserID: bob})
+	if err != nil || len(found) != 0 {
+		t.Errorf("SearchEvents of a collaborator = %+v, %v; want no drafts", found, err)
+	}
+	organized, err := f.Events.GetMyOrganizedEvents(f.ctx, ada, nil)
+	if err != nil {
+		t.Fatalf("GetMyOrganizedEvents: %v", err)
+	}
+	wantIDs(t, "GetMyOrganizedEvents", ids(organized), later.ID, draft.ID, published.ID)
+
+	// Only the organizer and collaborators can invite to drafts
+	for _, tt := range []struct {
+		userID int
+		want   bool
+	}{{ada, true}, {bob, true}, {carol, false}} {
+		if got, err := f.Invitations.IsEventCollaborator(f.ctx, draft.ID, tt.userID); err != nil || got != tt.want {
+			t.Errorf("IsEventCollaborator(%d) = %v, %v; want %v", tt.userID, got, err, tt.want)
+		}
+		if got, err := f.Invitations.IsEventOrganizer(f.ctx, draft.ID, tt.userID); err != nil || got != (tt.userID == ada) {
+			t.Errorf("IsEventOrganizer(%d) = %v, %v", tt.userID, got, err)
+		}
+	}
+
+	_, err = f.Events.PublishEvent(f.ctx, published.ID, nil, event.AnyVersion)
+	wantError(t, "PublishEvent of a published event", err, apperror.ErrConflict, "event_published", "")
+	_, err = f.Events.PublishEvent(f.ctx, draft.ID, nil, 2)
+	wantError(t, "PublishEvent at a stale version", err, apperror.ErrPreconditionFailed, "version_mismatch", "")
+
+	// Rescheduling keeps it a draft
+	sooner := publishAt.Add(-24 * time.Hour)
+	rescheduled, err := f.Events.PublishEvent(f.ctx, draft.ID, &sooner, 1)
+	if err != nil || rescheduled.Status != event.StatusDraft || !rescheduled.PublishAt.Equal(sooner) || rescheduled.Version != 2 {
+		t.Fatalf("PublishEvent later = %+v, %v", rescheduled, err)
+	}
+
+	// The worker publishes drafts once their time has come
+	due, err := f.Events.PublishDueEvents(f.ctx, sooner.Add(-time.Minute))
+	if err != nil || len(due) != 0 {
+		t.Errorf("PublishDueEvents before the time = %+v, %v; want none", due, err)
+	}
+	due, err = f.Events.PublishDueEvents(f.ctx, sooner)
+	if err != nil {
+		t.Fatalf("PublishDueEvents: %v", err)
+	}
+	wantIDs(t, "PublishDueEvents", ids(due), draft.ID)
+	if due[0].Status != event.StatusScheduled || due[0].PublishAt != nil || due[0].Version != 3 {
+		t.Errorf("published draft = %+v", due[0])
+	}
+	all, _ = f.Events.GetAllEvents(f.ctx, nil, event.Page{})
+	wantIDs(t, "GetAllEvents after publishing", ids(all), later.ID, draft.ID, published.ID)
+
+	// A draft published by its organizer
+	another := &event.Event{
+		Title:       "Another draft",
+		Date:        mustParse(t, "2006-01-02", "2030-08-01"),
+		Time:        mustParse(t, "15:04:05", "18:00:00"),
+		Location:    "Main Hall",
+		OrganizerID: ada,
+		Visibility:  event.VisibilityPublic,
+		Timezone:    "UTC",
+		Status:      event.StatusDraft,
+	}
+	if err := f.Events.CreateEvent(f.ctx, another); err != nil {
+		t.Fatalf("CreateEvent: %v", err)
+	}
+	now, err := f.Events.PublishEvent(f.ctx, another.ID, nil, event.AnyVersion)
+	if err != nil || now.Status != event.StatusScheduled || now.PublishAt != nil {
+		t.Errorf("PublishEvent = %+v, %v", now, err)
+	}
+
+	// Only drafts have a publication time
+	bad := *published
+	bad.ID, bad.PublishAt = 0, &publishAt
+	err = f.Events.CreateEvent(f.ctx, &bad)
+	wantError(t, "CreateEvent of a scheduled event with publish_at", err, apperror.ErrUnprocessable, "constraint_violation", "publish_at")
+}
+
+func testEventListings(t *testing.T, f *fixture) {
+	ada := f.user(t, "ada@example.com")
+	bob := f.user(t, "bob@example.com")
+	org := f.organization(t, ada)
+
+	// Two events on the same date are ordered by descending ID
+	first := f.event(t, ada, "first", "2030-01-10", "10:00:00", nil)
+	second := f.event(t, bob, "second", "2030-01-10", "09:00:00", nil)
+	later := f.event(t, ada, "later", "2030-03-01", "10:00:00", nil)
+	internal := f.event(t, ada, "internal", "2030-02-01", "10:00:00", &org)
+
+	announced := f.event(t, ada, "announced", "2030-02-15", "10:00:00", &org)
+	if _, err := f.Events.UpdateEvent(f.ctx, announced.ID, &event.UpdateEventRequest{Visibility: event.VisibilityPublic}, ada, event.AnyVersion); err != nil {
+		t.Fatalf("UpdateEvent: %v", err)
+	}
+
+	all, err := f.Events.GetAllEvents(f.ctx, nil, event.Page{})
+	if err != nil {
+		t.Fatalf("GetAllEvents: %v", err)
+	}
+	wantIDs(t, "GetAllEvents", ids(all), later.ID, announced.ID, second.ID, first.ID)
+
+	paged, err := f.Events.GetAllEvents(f.ctx, nil, event.Page{Limit: 2, Offset: 1})
+	if err != nil {
+		t.Fatalf("GetAllEvents page: %v", err)
+	}
+	wantIDs(t, "GetAllEvents page", ids(paged), announced.ID, second.ID)
+
+	beyond, err := f.Events.GetAllEvents(f.ctx, nil, event.Page{Limit: 2, Offset: 10})
+	if err != nil || len(beyond) != 0 {
+		t.Errorf("GetAllEvents past the end = %v, %v", beyond, err)
+	}
+
+	inOrg, err := f.Events.GetAllEvents(f.ctx, &org, event.Page{})
+	if err != nil {
+		t.Fatalf("GetAllEvents in organization: %v", err)
+	}
+	wantIDs(t, "GetAllEvents in organization", ids(inOrg), announced.ID, internal.ID)
+
+	byOrganizer, err := f.Events.GetEventsByOrganizerID(f.ctx, ada, nil)
+	if err != nil {
+		t.Fatalf("GetEventsByOrganizerID: %v", err)
+	}
+	wantIDs(t, "GetEventsByOrganizerID", ids(byOrganizer), later.ID, announced.ID, first.ID)
+
+	organized, err := f.Events.GetMyOrganizedEvents(f.ctx, ada, nil)
+	if err != nil {
+		t.Fatalf("GetMyOrganizedEvents: %v", err)
+	}
+	wantIDs(t, "GetMyOrganizedEvents", ids(organized), later.ID, announced.ID, internal.ID, first.ID)
+
+	organizedInOrg, err := f.Events.GetMyOrganizedEvents(f.ctx, ada, &org)
+	if err != nil {
+		t.Fatalf("GetMyOrganizedEvents in organization: %v", err)
+	}
+	wantIDs(t, "GetMyOrganizedEvents in organization", ids(organizedInOrg), announced.ID, internal.ID)
+}
+
+func testAttendance(t *testing.T, f *fixture) {
+	ada := f.user(t, "ada@example.com")
+	bob := f.user(t, "bob@example.com")
+	carol := f.user(t, "carol@example.com")
+
+	morning := f.event(t, ada, "morning", "2030-01-10", "09:00:00", nil)
+	evening := f.event(t, ada, "evening", "2030-01-10", "19:00:00", nil)
+	if err := f.Events.AddOrganizerAsAttendee(f.ctx, ada, morning.ID); err != nil {
+		t.Fatalf("AddOrganizerAsAttendee: %v", err)
+	}
+
+	if err := f.Events.JoinEvent(f.ctx, bob, morning.ID); err != nil {
+		t.Fatalf("JoinEvent: %v", err)
+	}
+	if err := f.Events.JoinEvent(f.ctx, bob, evening.ID); err != nil {
+		t.Fatalf("JoinEvent: %v", err)
+	}
+	wantError(t, "joining twice", f.Events.JoinEvent(f.ctx, bob, morning.ID), apperror.ErrConflict, "already_attending", "")
+	wantError(t, "joining a missing event", f.Events.JoinEvent(f.ctx, bob, 999999), apperror.ErrUnprocessable, "invalid_reference", "event_id")
+	wantError(t, "joining as a missing user", f.Events.JoinEvent(f.ctx, 999999, morning.ID), apperror.ErrUnprocessable, "invalid_reference", "user_id")
+
+	// Adding an attendee again changes the role and keeps the status
+	if err := f.Events.UpdateAttendanceStatus(f.ctx, bob, morning.ID, "maybe"); err != nil {
+		t.Fatalf("UpdateAttendanceStatus: %v", err)
+	}
+	if err := f.Events.AddAttendee(f.ctx, morning.ID, bob, "collaborator"); err != nil {
+		t.Fatalf("AddAttendee: %v", err)
+	}
+	if err := f.Events.AddAttendee(f.ctx, morning.ID, carol, "attendee"); err != nil {
+		t.Fatalf("AddAttendee: %v", err)
+	}
+	wantError(t, "invalid attendee role", f.Events.AddAttendee(f.ctx, morning.ID, carol, "host"), apperror.ErrUnprocessable, "constraint_violation", "role")
+	wantError(t, "invalid status", f.Events.UpdateAttendanceStatus(f.ctx, bob, morning.ID, "late"), apperror.ErrUnprocessable, "constraint_violation", "status")
+	wantError(t, "status of a non-attendee", f.Events.UpdateAttendanceStatus(f.ctx, carol, evening.ID, "going"), apperror.ErrNotFound, "attendance_not_found", "")
+
+	// Attendees are listed newest first with their public profile
+	attendees, err := f.Events.GetEventAttendees(f.ctx, morning.ID)
+	if err != nil {
+		t.Fatalf("GetEventAttendees: %v", err)
+	}
+	if len(attendees) != 3 {
+		t.Fatalf("GetEventAttendees returned %d attendees, want 3", len(attendees))
+	}
+	want := []struct {
+		userID       int
+		role, status string
+		name         string
+	}{
+		{carol, "attendee", "going", fmt.Sprintf("User %d", carol)},
+		{bob, "collaborator", "maybe", fmt.Sprintf("User %d", bob)},
+		{ada, "organizer", "going", fmt.Sprintf("User %d", ada)},
+	}
+	for i, w := range want {
+		a := attendees[i]
+		if a.UserID != w.userID || a.EventID != morning.ID || a.Role != w.role || a.Status != w.status ||
+			a.User.UserID != w.userID || a.User.DisplayName != w.name || a.CreatedAt.IsZero() {
+			t.Errorf("attendee %d = %+v, want %+v", i, a, w)
+		}
+	}
+
+	// Events a user attends, by descending date and time
+	attending, err := f.Events.GetEventsByAttendeeID(f.ctx, bob, nil)
+	if err != nil {
+		t.Fatalf("GetEventsByAttendeeID: %v", err)
+	}
+	if len(attending) != 2 || attending[0].ID != evening.ID || attending[1].ID != morning.ID ||
+		attending[1].Role != "collaborator" || attending[1].Status != "maybe" {
+		t.Errorf("GetEventsByAttendeeID = %+v", attending)
+	}
+}
+
+func testInvitations(t *testing.T, f *fixture) {
+	ada := f.user(t, "ada@example.com")
+	bob := f.user(t, "bob@example.com")
+	org := f.organization(t, ada)
+
+	personal := f.event(t, ada, "personal", "2030-01-10", "09:00:00", nil)
+	internal := f.event(t, ada, "internal", "2030-02-10", "09:00:00", &org)
+
+	first := f.invite(t, personal.ID, ada, "bob@example.com")
+	second := f.invite(t, internal.ID, ada, "bob@example.com")
+	guest := f.invite(t, personal.ID, ada, "Guest@example.com")
+
+	if first.ID == 0 || first.CreatedAt.IsZero() || first.InviteeID == nil || *first.InviteeID != bob {
+		t.Errorf("SendInvitation = %+v", first)
+	}
+	if guest.InviteeID != nil {
+		t.Errorf("invitee without an account has ID %d", *guest.InviteeID)
+	}
+
+	wantError(t, "invitation to a missing event",
+		f.Invitations.SendInvitation(f.ctx, &invitation.Invitation{EventID: 999999, InviterID: ada, InviteeEmail: "x@example.com", Role: "attendee"}),
+		apperror.ErrUnprocessable, "invalid_reference", "event_id")
+	wantError(t, "invalid invitation role",
+		f.Invitations.SendInvitation(f.ctx, &invitation.Invitation{EventID: personal.ID, InviterID: ada, InviteeEmail: "x@example.com", Role: "host"}),
+		apperror.ErrUnprocessable, "constraint_violation", "role")
+
+	got, err := f.Invitations.GetInvitationByID(f.ctx, first.ID)
+	if err != nil {
+		t.Fatalf("GetInvitationByID: %v", err)
+	}
+	if got.Status != "pending" || got.EventID != personal.ID || got.InviterID != ada || got.Message != "join us" || got.RespondedAt != nil {
+		t.Errorf("GetInvitationByID = %+v", got)
+	}
+
+	_, err = f.Invitations.GetInvitationByID(f.ctx, 999999)
+	wantError(t, "GetInvitationByID of a missing invitation", err, apperror.ErrNotFound, "invitation_not_found", "")
+
+	// Newest first, with the event and people
+	mine, err := f.Invitations.GetInvitationsByEmail(f.ctx, "bob@example.com", nil)
+	if err != nil {
+		t.Fatalf("GetInvitationsByEmail: %v", err)
+	}
+	wantIDs(t, "GetInvitationsByEmail", invitationIDs(mine), second.ID, first.ID)
+	if d := mine[1]; d.EventTitle != "personal" || d.EventDate != "2030-01-10" || d.EventTime != "09:00:00" ||
+		d.EventLocation != "Main Hall" || d.InviterEmail != "ada@example.com" ||
+		d.Inviter.UserID != ada || d.Inviter.DisplayName != fmt.Sprintf("User %d", ada) ||
+		d.Invitee == nil || d.Invitee.UserID != bob || d.Invitee.DisplayName != fmt.Sprintf("User %d", bob) {
+		t.Errorf("invitation details = %+v", d)
+	}
+
+	inOrg, err := f.Invitations.GetInvitationsByEmail(f.ctx, "bob@example.com", &org)
+	if err != nil {
+		t.Fatalf("GetInvitationsByEmail in organization: %v", err)
+	}
+	wantIDs(t, "GetInvitationsByEmail in organization", invitationIDs(inOrg), second.ID)
+
+	none, err := f.Invitations.GetInvitationsByEmail(f.ctx, "nobody@example.com", nil)
+	if err != nil || none == nil || len(none) != 0 {
+		t.Errorf("GetInvitationsByEmail without invitations = %#v, %v", none, err)
+	}
+
+	forEvent, err := f.Invitations.GetInvitationsByEventID(f.ctx, personal.ID)
+	if err != nil {
+		t.Fatalf("GetInvitationsByEventID: %v", err)
+	}
+	wantIDs(t, "GetInvitationsByEventID", invitationIDs(forEvent), guest.ID, first.ID)
+	if forEvent[0].Invitee != nil {
+		t.Errorf("invitee profile without an account: %+v", forEvent[0].Invitee)
+	}
+
+	// Responding
+	if err := f.Invitations.UpdateInvitationStatus(f.ctx, first.ID, "accepted"); err != nil {
+		t.Fatalf("UpdateInvitationStatus: %v", err)
+	}
+	if got, _ := f.Invitations.GetInvitationByID(f.ctx, first.ID); got.Status != "accepted" || got.RespondedAt == nil {
+		t.Errorf("after UpdateInvitationStatus: %+v", got)
+	}
+	wantError(t, "invalid invitation status", f.Invitations.UpdateInvitationStatus(f.ctx, second.ID, "maybe"),
+		apperror.ErrUnprocessable, "constraint_violation", "status")
+
+	// Helpers used by the service
+	orgID, visibility, err := f.Invitations.GetEventVisibility(f.ctx, internal.ID)
+	if err != nil || orgID == nil || *orgID != org || visibility != event.VisibilityOrganization {
+		t.Errorf("GetEventVisibility = %v, %q, %v", orgID, visibility, err)
+	}
+	_, _, err = f.Invitations.GetEventVisibility(f.ctx, 999999)
+	wantError(t, "GetEventVisibility of a missing event", err, apperror.ErrNotFound, "event_not_found", "")
+
+	invited, err := f.Invitations.GetInvitedEmails(f.ctx, personal.ID)
+	if err != nil || len(invited) != 2 || !invited["bob@example.com"] || !invited["guest@example.com"] {
+		t.Errorf("GetInvitedEmails = %v, %v", invited, err)
+	}
+
+	if id, err := f.Invitations.GetUserIDByEmail(f.ctx, "nobody@example.com"); id != nil || err != nil {
+		t.Errorf("GetUserIDByEmail of a missing user = %v, %v", id, err)
+	}
+}
+
+func testGroupInvitations(t *testing.T, f *fixture) {
+	ada := f.user(t, "ada@example.com")
+	bob := f.user(t, "bob@example.com")
+
+	groupID := f.group(t, ada, "Board", "zed@example.com", "amy@example.com")
+
+	// Members in the order they were added
+	emails, err := f.Invitations.GetGroupEmails(f.ctx, groupID, ada)
+	if err != nil || len(emails) != 2 || emails[0] != "zed@example.com" || emails[1] != "amy@example.com" {
+		t.Errorf("GetGroupEmails = %v, %v", emails, err)
+	}
+	_, err = f.Invitations.GetGroupEmails(f.ctx, groupID, bob)
+	wantError(t, "GetGroupEmails of another owner", err, apperror.ErrNotFound, "group_not_found", "")
+
+	upcoming := f.event(t, ada, "upcoming", time.Now().AddDate(0, 1, 0).Format("2006-01-02"), "10:00:00", nil)
+	past := f.event(t, ada, "past", "2000-01-01", "10:00:00", nil)
+
+	link := &invitation.GroupLink{EventID: upcoming.ID, GroupID: groupID, InviterID: ada, Role: "attendee", InviteNewMembers: true}
+	if err := f.Invitations.SaveGroupLink(f.ctx, link); err != nil {
+		t.Fatalf("SaveGroupLink: %v", err)
+	}
+	created := link.CreatedAt
+
+	// Saving again updates the link but keeps its creation time
+	link.Role, link.Message = "collaborator", "welcome"
+	if err := f.Invitations.SaveGroupLink(f.ctx, link); err != nil {
+		t.Fatalf("SaveGroupLink again: %v", err)
+	}
+	if !link.CreatedAt.Equal(created) {
+		t.Errorf("SaveGroupLink changed the creation time from %v to %v", created, link.CreatedAt)
+	}
+
+	pastLink := &invitation.GroupLink{EventID: past.ID, GroupID: groupID, InviterID: ada, Role: "attendee", InviteNewMembers: true}
+	if err := f.Invitations.SaveGroupLink(f.ctx, pastLink); err != nil {
+		t.Fatalf("SaveGroupLink: %v", err)
+	}
+
+	links, err := f.Invitations.GetLateJoinerLinks(f.ctx, groupID)
+	if err != nil {
+		t.Fatalf("GetLateJoinerLinks: %v", err)
+	}
+	if len(links) != 1 || links[0].EventID != upcoming.ID || links[0].Role != "collaborator" || links[0].Message != "welcome" {
+		t.Errorf("GetLateJoinerLinks = %+v", links)
+	}
+
+	wantError(t, "link to a missing group",
+		f.Invitations.SaveGroupLink(f.ctx, &invitation.GroupLink{EventID: upcoming.ID, GroupID: 999999, InviterID: ada, Role: "attendee"}),
+		apperror.ErrUnprocessable, "invalid_reference", "group_id")
+
+	// Invitations remember the group they came from
+	inv := &invitation.Invitation{EventID: upcoming.ID, InviterID: ada, InviteeEmail: "zed@example.com", GroupID: &groupID, Role: "attendee"}
+	if err := f.Invitations.SendInvitation(f.ctx, inv); err != nil {
+		t.Fatalf("SendInvitation: %v", err)
+	}
+	if got, _ := f.Invitations.GetInvitationByID(f.ctx, inv.ID); got.GroupID == nil || *got.GroupID != groupID {
+		t.Errorf("invitation group = %v", got.GroupID)
+	}
+}
+
+func testSearch(t *testing.T, f *fixture) {
+	ada := f.user(t, "ada@example.com")
+	bob := f.user(t, "bob@example.com")
+	org := f.organization(t, ada)
+
+	standup := f.event(t, ada, "Daily Standup", "2030-01-10", "09:00:00", nil)
+	retro := f.event(t, ada, "Sprint retro", "2030-01-10", "16:00:00", nil)
+	offsite := f.event(t, ada, "Offsite 100%", "2030-03-01", "09:00:00", &org)
+	other := f.event(t, bob, "Bob's standup", "2030-02-01", "09:00:00", nil)
+	for _, ev := range []*event.Event{standup, retro, offsite} {
+		if err := f.Events.AddOrganizerAsAttendee(f.ctx, ada, ev.ID); err != nil {
+			t.Fatalf("AddOrganizerAsAttendee: %v", err)
+		}
+	}
+	if err := f.Events.AddOrganizerAsAttendee(f.ctx, bob, other.ID); err != nil {
+		t.Fatalf("AddOrganizerAsAttendee: %v", err)
+	}
+	if err := f.Events.JoinEvent(f.ctx, ada, other.ID); err != nil {
+		t.Fatalf("JoinEvent: %v", err)
+	}
+	if err := f.Events.UpdateAttendanceStatus(f.ctx, ada, other.ID, "maybe"); err != nil {
+		t.Fatalf("UpdateAttendanceStatus: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		filter search.EventsFilter
+		want   []int
+	}{
+		{"all", search.EventsFilter{}, []int{offsite.ID, other.ID, retro.ID, standup.ID}},
+		{"keyword ignores case", search.EventsFilter{Query: "STANDUP"}, []int{other.ID, standup.ID}},
+		{"keyword in description", search.EventsFilter{Query: "retro desc"}, []int{retro.ID}},
+		{"wildcards", search.EventsFilter{Query: "s_rint"}, []int{retro.ID}},
+		{"escaped wildcard", search.EventsFilter{Query: `100\%`}, []int{offsite.ID}},
+		{"date range", search.EventsFilter{DateFrom: "2030-01-11", DateTo: "2030-02-01"}, []int{other.ID}},
+		{"role", search.EventsFilter{Role: "attendee"}, []int{other.ID}},
+		{"status", search.EventsFilter{Status: "going"}, []int{offsite.ID, retro.ID, standup.ID}},
+		{"organization", search.EventsFilter{OrganizationID: &org}, []int{offsite.ID}},
+		{"page", search.EventsFilter{Limit: 2, Offset: 1}, []int{other.ID, retro.ID}},
+		{"no match", search.EventsFilter{Query: "nothing"}, []int{}},
+	}
+	for _, tt := range tests {
+		tt.filter.UserID = ada
+		events, err := f.Search.SearchEvents(f.ctx, &tt.filter)
+		if err != nil {
+			t.Errorf("%s: SearchEvents: %v", tt.name, err)
+			continue
+		}
+		if events == nil {
+			t.Errorf("%s: SearchEvents returned nil", tt.name)
+		}
+
+		got := make([]int, len(events))
+		for i, e := range events {
+			got[i] = e.ID
+		}
+		wantIDs(t, tt.name, got, tt.want...)
+	}
+
+	events, _ := f.Search.SearchEvents(f.ctx, &search.EventsFilter{UserID: ada, Query: "bob"})
+	if len(events) != 1 || events[0].Role != "attendee" || events[0].Status != "maybe" {
+		t.Errorf("search result attendance = %+v", events)
+	}
+}
+
+func testAudit(t *testing.T, f *fixture) {
+	ada := f.user(t, "ada@example.com")
+	bob := f.user(t, "bob@example.com")
+	party := f.event(t, ada, "Party", "2030-01-10", "20:00:00", nil)
+	standup := f.event(t, bob, "Standup", "2030-01-11", "09:00:00", nil)
+
+	appendEntry := func(e audit.Entry) *audit.Entry {
+		t.Helper()
+		if err := f.Audit.Append(f.ctx, &e); err != nil {
+			t.Fatalf("Append(%s): %v", e.Action, err)
+		}
+		if e.ID == 0 || e.CreatedAt.IsZero() {
+			t.Fatalf("Append(%s) did not set the ID and creation time: %+v", e.Action, e)
+		}
+		return &e
+	}
+
+	created := appendEntry(audit.Entry{
+		ActorID: &ada, Action: audit.ActionEventCreate, TargetType: audit.TargetEvent, TargetID: party.ID,
+		EventID: &party.ID, After: json.RawMessage(`{"title":"Party"}`),
+	})
+	other := appendEntry(audit.Entry{
+		ActorID: &bob, Action: audit.ActionEventCreate, TargetType: audit.TargetEvent, TargetID: standup.ID,
+		EventID: &standup.ID, After: json.RawMessage(`{"title":"Standup"}`),
+	})
+	failed := appendEntry(audit.Entry{
+		Action: audit.ActionUserLoginFailed, TargetType: audit.TargetUser,
+		After: json.RawMessage(`{"reason":"invalid_credentials"}`),
+	})
+	status := appendEntry(audit.Entry{
+		ActorID: &bob, Action: audit.ActionAttendeeStatus, TargetType: audit.TargetAttendee, TargetID: bob,
+		EventID: &party.ID, Before: json.RawMessage(`{"status":"going"}`), After: json.RawMessage(`{"status":"maybe"}`),
+		RequestID: "req-1", IP: "192.0.2.1", UserAgent: "curl/8.0",
+	})
+
+	tests := []struct {
+		name   string
+		filter audit.Filter
+		want   []int64
+	}{
+		{"all", audit.Filter{}, []int64{status.ID, failed.ID, other.ID, created.ID}},
+		{"actor", audit.Filter{ActorID: bob}, []int64{status.ID, other.ID}},
+		{"action", audit.Filter{Action: audit.ActionEventCreate}, []int64{other.ID, created.ID}},
+		{"target", audit.Filter{TargetType: audit.TargetAttendee, TargetID: bob}, []int64{status.ID}},
+		{"event", audit.Filter{EventID: party.ID}, []int64{status.ID, created.ID}},
+		{"organizer", audit.Filter{OrganizerID: bob}, []int64{other.ID}},
+		{"organizer and event", audit.Filter{OrganizerID: bob, EventID: party.ID}, nil},
+		{"page", audit.Filter{Limit: 2, Offset: 1}, []int64{failed.ID, other.ID}},
+	}
+	for _, tt := range tests {
+		if tt.filter.Limit == 0 {
+			tt.filter.Limit = 50
+		}
+		entries, err := f.Audit.List(f.ctx, &tt.filter)
+		if err != nil {
+			t.Errorf("%s: List: %v", tt.name, err)
+			continue
+		}
+
+		got := make([]int, len(entries))
+		for i, e := range entries {
+			got[i] = int(e.ID)
+		}
+		want := make([]int, len(tt.want))
+		for i, id := range tt.want {
+			want[i] = int(id)
+		}
+		wantIDs(t, tt.name, got, want...)
+	}
+
+	entries, err := f.Audit.List(f.ctx, &audit.Filter{Limit: 2})
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("List = %+v, %v", entries, err)
+	}
+	got := entries[0]
+	if got.ActorID == nil || *got.ActorID != bob || got.EventID == nil || *got.EventID != party.ID ||
+		got.RequestID != "req-1" || got.IP != "192.0.2.1" || got.UserAgent != "curl/8.0" ||
+		!sameJSON(got.Before, status.Before) || !sameJSON(got.After, status.After) {
+		t.Errorf("entry = %+v, want %+v", got, status)
+	}
+	if got := entries[1]; got.ActorID != nil || got.EventID != nil || got.Before != nil || !sameJSON(got.After, failed.After) {
+		t.Errorf("anonymous entry = %+v", got)
+	}
+}
+
+func testOrganizations(t *testing.T, f *fixture) {
+	ada := f.user(t, "ada@example.com")
+	bob := f.user(t, "bob@example.com")
+	carol := f.user(t, "carol@example.com")
+
+	org := &organization.Organization{Name: "Zeta", Slug: "zeta", DefaultVisibility: "public", Timezone: "Europe/Berlin"}
+	if err := f.Organizations.CreateOrganization(f.ctx, org, ada); err != nil {
+		t.Fatalf("CreateOrganization: %v", err)
+	}
+	if org.ID == 0 || org.CreatedBy != ada || org.CreatedAt.IsZero() {
+		t.Errorf("CreateOrganization = %+v", org)
+	}
+
+	taken := &organization.Organization{Name: "Other", Slug: "zeta", DefaultVisibility: "public", Timezone: "UTC"}
+	err := f.Organizations.CreateOrganization(f.ctx, taken, bob)
+	wantError(t, "duplicate slug", err, apperror.ErrConflict, "organization_slug_taken", "")
+
+	invalid := &organization.Organization{Name: "Other", Slug: "other", DefaultVisibility: "secret", Timezone: "UTC"}
+	err = f.Organizations.CreateOrganization(f.ctx, invalid, bob)
+	wantError(t, "invalid visibility", err, apperror.ErrUnprocessable, "constraint_violation", "default_visibility")
+
+	acme := f.organization(t, bob)
+
+	// The creator is the owner
+	m, err := f.Organizations.GetMembership(f.ctx, org.ID, ada)
+	if err != nil || m.Role != organization.RoleOwner || m.Name != "Zeta" || m.Timezone != "Europe/Berlin" {
+		t.Fatalf("GetMembership = %+v, %v", m, err)
+	}
+	_, err = f.Organizations.GetMembership(f.ctx, org.ID, bob)
+	wantError(t, "GetMembership of a non-member", err, apperror.ErrNotFound, "member_not_found", "")
+
+	org.Name, org.DefaultVisibility, org.Timezone = "Zeta Inc", "organization", "UTC"
+	if err := f.Organizations.UpdateOrganization(f.ctx, org); err != nil {
+		t.Fatalf("UpdateOrganization: %v", err)
+	}
+	if m, _ := f.Organizations.GetMembership(f.ctx, org.ID, ada); m.Name != "Zeta Inc" || m.DefaultVisibility != "organization" || m.Slug != "zeta" {
+		t.Errorf("after UpdateOrganization: %+v", m)
+	}
+	err = f.Organizations.UpdateOrganization(f.ctx, &organization.Organization{ID: 999999, DefaultVisibility: "public"})
+	wantError(t, "UpdateOrganization of a missing organization", err, apperror.ErrNotFound, "organization_not_found", "")
+
+	// Adding an existing member changes the role
+	if err := f.Organizations.AddMember(f.ctx, org.ID, bob, organization.RoleMember); err != nil {
+		t.Fatalf("AddMember: %v", err)
+	}
+	if err := f.Organizations.AddMember(f.ctx, org.ID, bob, organization.RoleAdmin); err != nil {
+		t.Fatalf("AddMember again: %v", err)
+	}
+	if err := f.Organizations.AddMember(f.ctx, org.ID, carol, organization.RoleMember); err != nil {
+		t.Fatalf("AddMember: %v", err)
+	}
+	err = f.Organizations.AddMember(f.ctx, org.ID, carol, "guest")
+	wantError(t, "AddMember with an invalid role", err, apperror.ErrUnprocessable, "constraint_violation", "role")
+	err = f.Organizations.AddMember(f.ctx, org.ID, 999999, organization.RoleMember)
+	wantError(t, "AddMember of a missing user", err, apperror.ErrUnprocessable, "invalid_reference", "")
+
+	members, err := f.Organizations.GetMembers(f.ctx, org.ID)
+	if err != nil || len(members) != 3 {
+		t.Fatalf("GetMembers = %+v, %v", members, err)
+	}
+	for i, want := range []struct {
+		userID int
+		role   string
+	}{{ada, organization.RoleOwner}, {bob, organization.RoleAdmin}, {carol, organization.RoleMember}} {
+		got := members[i]
+		if got.UserID != want.userID || got.Role != want.role || got.OrganizationID != org.ID || got.User.UserID != want.userID {
+			t.Errorf("member %d = %+v, want user %d as %s", i, got, want.userID, want.role)
+		}
+	}
+
+	// Memberships by organization name
+	memberships, err := f.Organizations.GetUserMemberships(f.ctx, bob)
+	if err != nil || len(memberships) != 2 || memberships[0].ID != acme || memberships[1].ID != org.ID ||
+		memberships[0].Role != organization.RoleOwner || memberships[1].Role != organization.RoleAdmin {
+		t.Errorf("GetUserMemberships = %+v, %v", memberships, err)
+	}
+
+	if err := f.Organizations.UpdateMemberRole(f.ctx, org.ID, bob, organization.RoleOwner); err != nil {
+		t.Fatalf("UpdateMemberRole: %v", err)
+	}
+	if owners, err := f.Organizations.CountOwners(f.ctx, org.ID); err != nil || owners != 2 {
+		t.Errorf("CountOwners = %d, %v", owners, err)
+	}
+	err = f.Organizations.UpdateMemberRole(f.ctx, acme, carol, organization.RoleAdmin)
+	wantError(t, "UpdateMemberRole of a non-member", err, apperror.ErrNotFound, "member_not_found", "")
+
+	if err := f.Organizations.RemoveMember(f.ctx, org.ID, carol); err != nil {
+		t.Fatalf("RemoveMember: %v", err)
+	}
+	err = f.Organizations.RemoveMember(f.ctx, org.ID, carol)
+	wantError(t, "RemoveMember twice", err, apperror.ErrNotFound, "member_not_found", "")
+	if memberships, err := f.Organizations.GetUserMemberships(f.ctx, carol); err != nil || len(memberships) != 0 {
+		t.Errorf("GetUserMemberships after RemoveMember = %+v, %v", memberships, err)
+	}
+
+	// Emails are matched exactly
+	if id, err := f.Organizations.GetUserIDByEmail(f.ctx, "bob@example.com"); err != nil || id == nil || *id != bob {
+		t.Errorf("GetUserIDByEmail = %v, %v", id, err)
+	}
+	if id, err := f.Organizations.GetUserIDByEmail(f.ctx, "Bob@example.com"); err != nil || id != nil {
+		t.Errorf("GetUserIDByEmail with other case = %v, %v", id, err)
+	}
+}
+
+func testGroups(t *testing.T, f *fixture) {
+	ada := f.user(t, "ada@example.com")
+	bob := f.user(t, "bob@example.com")
+
+	board := &group.Group{OwnerID: ada, Name: "Board", Description: "directors"}
+	if err := f.Groups.CreateGroup(f.ctx, board); err != nil {
+		t.Fatalf("CreateGroup: %v", err)
+	}
+	if board.ID == 0 || board.CreatedAt.IsZero() {
+		t.Errorf("CreateGroup = %+v", board)
+	}
+	err := f.Groups.CreateGroup(f.ctx, &group.Group{OwnerID: 999999, Name: "Orphans"})
+	wantError(t, "CreateGroup of a missing owner", err, apperror.ErrUnprocessable, "invalid_reference", "")
+
+	alumni := f.group(t, ada, "Alumni")
+	f.group(t, bob, "Band")
+
+	// Members in the order they were added; duplicates are skipped
+	for _, email := range []string{"zed@example.com", "bob@example.com", "zed@example.com"} {
+		if _, err := f.Groups.AddMember(f.ctx, board.ID, email); err != nil {
+			t.Fatalf("AddMember(%s): %v", email, err)
+		}
+	}
+	if added, err := f.Groups.AddMember(f.ctx, board.ID, "bob@example.com"); err != nil || added {
+		t.Errorf("AddMember of a member = %v, %v", added, err)
+	}
+	_, err = f.Groups.AddMember(f.ctx, 999999, "zed@example.com")
+	wantError(t, "AddMember to a missing group", err, apperror.ErrUnprocessable, "invalid_reference", "")
+
+	found, err := f.Groups.GetGroupByID(f.ctx, board.ID)
+	if err != nil || found.Name != "Board" || found.Description != "directors" || found.OwnerID != ada || found.MemberCount != 2 {
+		t.Errorf("GetGroupByID = %+v, %v", found, err)
+	}
+	_, err = f.Groups.GetGroupByID(f.ctx, 999999)
+	wantError(t, "GetGroupByID of a missing group", err, apperror.ErrNotFound, "group_not_found", "")
+
+	// Owned groups by name
+	groups, err := f.Groups.GetGroupsByOwnerID(f.ctx, ada)
+	if err != nil || len(groups) != 2 || groups[0].ID != alumni || groups[1].ID != board.ID || groups[1].MemberCount != 2 {
+		t.Errorf("GetGroupsByOwnerID = %+v, %v", groups, err)
+	}
+
+	// Members with an account are linked to it
+	members, err := f.Groups.GetMembers(f.ctx, board.ID)
+	if err != nil || len(members) != 2 {
+		t.Fatalf("GetMembers = %+v, %v", members, err)
+	}
+	if m := members[0]; m.Email != "zed@example.com" || m.UserID != nil || m.User != nil || m.GroupID != board.ID {
+		t.Errorf("email-only member = %+v", m)
+	}
+	if m := members[1]; m.Email != "bob@example.com" || m.UserID == nil || *m.UserID != bob || m.User == nil || m.User.UserID != bob {
+		t.Errorf("member with an account = %+v", m)
+	}
+
+	board.Name, board.Description = "Directors", ""
+	if err := f.Groups.UpdateGroup(f.ctx, board); err != nil {
+		t.Fatalf("UpdateGroup: %v", err)
+	}
+	if found, _ := f.Groups.GetGroupByID(f.ctx, board.ID); found.Name != "Directors" || found.Description != "" {
+		t.Errorf("after UpdateGroup: %+v", found)
+	}
+
+	if err := f.Groups.RemoveMember(f.ctx, alumni, members[0].ID); !errors.Is(err, group.ErrMemberNotFound) {
+		t.Errorf("RemoveMember from another group: %v", err)
+	}
+	if err := f.Groups.RemoveMember(f.ctx, board.ID, members[0].ID); err != nil {
+		t.Fatalf("RemoveMember: %v", err)
+	}
+	if found, _ := f.Groups.GetGroupByID(f.ctx, board.ID); found.MemberCount != 1 {
+		t.Errorf("MemberCount after RemoveMember = %d", found.MemberCount)
+	}
+
+	if email, err := f.Groups.GetUserEmail(f.ctx, bob); err != nil || email != "bob@example.com" {
+		t.Errorf("GetUserEmail = %q, %v", email, err)
+	}
+	_, err = f.Groups.GetUserEmail(f.ctx, 999999)
+	wantError(t, "GetUserEmail of a missing user", err, apperror.ErrNotFound, "user_not_found", "")
+
+	// Deleting a group removes its members and the invitations to it
+	ev := f.event(t, ada, "Meeting", "2030-05-01", "10:00:00", nil)
+	if err := f.Invitations.SaveGroupLink(f.ctx, &invitation.GroupLink{EventID: ev.ID, GroupID: board.ID, InviterID: ada, Role: "attendee"}); err != nil {
+		t.Fatalf("SaveGroupLink: %v", err)
+	}
+	if err := f.Groups.DeleteGroup(f.ctx, board.ID); err != nil {
+		t.Fatalf("DeleteGroup: %v", err)
+	}
+	if links, err := f.Invitations.GetLateJoinerLinks(f.ctx, board.ID); err != nil || len(links) != 0 {
+		t.Errorf("GetLateJoinerLinks after DeleteGroup = %+v, %v", links, err)
+	}
+	wantError(t, "DeleteGroup twice", f.Groups.DeleteGroup(f.ctx, board.ID), apperror.ErrNotFound, "group_not_found", "")
+	if members, err := f.Groups.GetMembers(f.ctx, board.ID); err != nil || len(members) != 0 {
+		t.Errorf("GetMembers after DeleteGroup = %+v, %v", members, err)
+	}
+}
+
+func testProfiles(t *testing.T, f *fixture) {
+	ada := f.user(t, "ada@example.com")
+
+	// Users without a stored profile get the defaults
+	p, err := f.Profiles.GetProfile(f.ctx, ada)
+	if err != nil || p.UserID != ada || p.Email != "ada@example.com" || p.DisplayName != "" ||
+		p.Timezone != "UTC" || p.Locale != "en" || p.UpdatedAt != nil {
+		t.Fatalf("GetProfile = %+v, %v", p, err)
+	}
+	_, err = f.Profiles.GetProfile(f.ctx, 999999)
+	wantError(t, "GetProfile of a missing user", err, apperror.ErrNotFound, "user_not_found", "")
+
+	if err := f.Profiles.SetAvatar(f.ctx, ada, "avatars/1.png", "/avatars/1.png"); err != nil {
+		t.Fatalf("SetAvatar: %v", err)
+	}
+
+	// Saving the profile keeps the avatar
+	p = &profile.Profile{UserID: ada, DisplayName: "Ada L.", Bio: "Engines", Timezone: "Europe/London", Locale: "en-GB"}
+	if err := f.Profiles.UpsertProfile(f.ctx, p); err != nil {
+		t.Fatalf("UpsertProfile: %v", err)
+	}
+	if p.UpdatedAt == nil {
+		t.Error("UpsertProfile did not set UpdatedAt")
+	}
+	err = f.Profiles.UpsertProfile(f.ctx, &profile.Profile{UserID: 999999, Timezone: "UTC", Locale: "en"})
+	wantError(t, "UpsertProfile of a missing user", err, apperror.ErrUnprocessable, "invalid_reference", "")
+
+	p, err = f.Profiles.GetProfile(f.ctx, ada)
+	if err != nil || p.DisplayName != "Ada L." || p.Bio != "Engines" || p.Timezone != "Europe/London" || p.Locale != "en-GB" ||
+		p.AvatarKey != "avatars/1.png" || p.AvatarURL != "/avatars/1.png" || p.UpdatedAt == nil {
+		t.Errorf("GetProfile after UpsertProfile = %+v, %v", p, err)
+	}
+
+	details, err := f.Profiles.GetPublicProfile(f.ctx, ada)
+	if err != nil || details.UserID != ada || details.DisplayName != "Ada L." || details.AvatarURL != "/avatars/1.png" || details.Bio != "Engines" {
+		t.Errorf("GetPublicProfile = %+v, %v", details, err)
+	}
+	_, err = f.Profiles.GetPublicProfile(f.ctx, 999999)
+	wantError(t, "GetPublicProfile of a missing user", err, apperror.ErrNotFound, "user_not_found", "")
+
+	// Empty values clear the avatar
+	if err := f.Profiles.SetAvatar(f.ctx, ada, "", ""); err != nil {
+		t.Fatalf("SetAvatar: %v", err)
+	}
+	if p, _ := f.Profiles.GetProfile(f.ctx, ada); p.AvatarKey != "" || p.AvatarURL != "" || p.DisplayName != "Ada L." {
+		t.Errorf("GetProfile after clearing the avatar = %+v", p)
+	}
+}
+
+func testAdmin(t *testing.T, f *fixture) {
+	ada := f.user(t, "ada@example.com")
+	bob := f.user(t, "bob@example.com")
+	carol := f.user(t, "carol@example.com")
+
+	if err := f.Admin.SetRole(f.ctx, bob, user.RoleSupport); err != nil {
+		t.Fatalf("SetRole: %v", err)
+	}
+	wantError(t, "SetRole with an invalid role", f.Admin.SetRole(f.ctx, bob, "root"), apperror.ErrUnprocessable, "constraint_violation", "role")
+	wantError(t, "SetRole of a missing user", f.Admin.SetRole(f.ctx, 999999, user.RoleMember), apperror.ErrNotFound, "user_not_found", "")
+
+	if err := f.Admin.SetDisabled(f.ctx, carol, true); err != nil {
+		t.Fatalf("SetDisabled: %v", err)
+	}
+	disabled, _ := f.Admin.GetUserByID(f.ctx, carol)
+	if disabled.DisabledAt == nil {
+		t.Fatal("SetDisabled did not disable the account")
+	}
+
+	// Disabling again keeps the original time
+	if err := f.Admin.SetDisabled(f.ctx, carol, true); err != nil {
+		t.Fatalf("SetDisabled again: %v", err)
+	}
+	if again, _ := f.Admin.GetUserByID(f.ctx, carol); again.DisabledAt == nil || !again.DisabledAt.Equal(*disabled.DisabledAt) {
+		t.Errorf("DisabledAt after disabling again = %v, want %v", again.DisabledAt, disabled.DisabledAt)
+	}
+	wantError(t, "SetDisabled of a missing user", f.Admin.SetDisabled(f.ctx, 999999, true), apperror.ErrNotFound, "user_not_found", "")
+
+	found, err := f.Admin.GetUserByID(f.ctx, bob)
+	if err != nil || found.Email != "bob@example.com" || found.Role != user.RoleSupport || found.PasswordHash != "" {
+		t.Errorf("GetUserByID = %+v, %v", found, err)
+	}
+	_, err = f.Admin.GetUserByID(f.ctx, 999999)
+	wantError(t, "GetUserByID of a missing user", err, apperror.ErrNotFound, "user_not_found", "")
+
+	yes, no := true, false
+	users := []struct {
+		name   string
+		filter admin.UsersFilter
+		want   []int
+	}{
+		{"all", admin.UsersFilter{}, []int{carol, bob, ada}},
+		{"query", admin.UsersFilter{Query: "OB@"}, []int{bob}},
+		{"role", admin.UsersFilter{Role: user.RoleSupport}, []int{bob}},
+		{"disabled", admin.UsersFilter{Disabled: &yes}, []int{carol}},
+		{"active", admin.UsersFilter{Disabled: &no}, []int{bob, ada}},
+		{"page", admin.UsersFilter{Limit: 1, Offset: 1}, []int{bob}},
+	}
+	for _, tt := range users {
+		if tt.filter.Limit == 0 {
+			tt.filter.Limit = 50
+		}
+		list, err := f.Admin.ListUsers(f.ctx, &tt.filter)
+		if err != nil {
+			t.Errorf("%s: ListUsers: %v", tt.name, err)
+			continue
+		}
+		got := make([]int, len(list))
+		for i, u := range list {
+			got[i] = u.ID
+		}
+		wantIDs(t, tt.name, got, tt.want...)
+	}
+
+	if err := f.Admin.SetDisabled(f.ctx, carol, false); err != nil {
+		t.Fatalf("SetDisabled(false): %v", err)
+	}
+	if enabled, _ := f.Admin.GetUserByID(f.ctx, carol); enabled.DisabledAt != nil {
+		t.Errorf("DisabledAt after enabling = %v", enabled.DisabledAt)
+	}
+
+	disable := &admin.Action{ActorID: ada, Action: "user.disable", TargetType: "user", TargetID: carol, Details: json.RawMessage(`{"reason": "spam"}`)}
+	if err := f.Admin.RecordAction(f.ctx, disable); err != nil {
+		t.Fatalf("RecordAction: %v", err)
+	}
+	if disable.ID == 0 || disable.CreatedAt.IsZero() {
+		t.Errorf("RecordAction = %+v", disable)
+	}
+	role := &admin.Action{ActorID: ada, Action: "user.role", TargetType: "user", TargetID: bob}
+	if err := f.Admin.RecordAction(f.ctx, role); err != nil {
+		t.Fatalf("RecordAction: %v", err)
+	}
+	moderate := &admin.Action{ActorID: bob, Action: "event.delete", TargetType: "event", TargetID: carol}
+	if err := f.Admin.RecordAction(f.ctx, moderate); err != nil {
+		t.Fatalf("RecordAction: %v", err)
+	}
+	err = f.Admin.RecordAction(f.ctx, &admin.Action{ActorID: 999999, Action: "user.role", TargetType: "user", TargetID: bob})
+	wantError(t, "RecordAction of a missing actor", err, apperror.ErrUnprocessable, "invalid_reference", "")
+
+	actions := []struct {
+		name   string
+		filter admin.ActionsFilter
+		want   []int
+	}{
+		{"all", admin.ActionsFilter{}, []int{moderate.ID, role.ID, disable.ID}},
+		{"actor", admin.ActionsFilter{ActorID: ada}, []int{role.ID, disable.ID}},
+		{"target type", admin.ActionsFilter{TargetType: "user"}, []int{role.ID, disable.ID}},
+		{"target", admin.ActionsFilter{TargetType: "user", TargetID: carol}, []int{disable.ID}},
+		{"page", admin.ActionsFilter{Limit: 1, Offset: 2}, []int{disable.ID}},
+	}
+	for _, tt := range actions {
+		if tt.filter.Limit == 0 {
+			tt.filter.Limit = 50
+		}
+		list, err := f.Admin.ListActions(f.ctx, &tt.filter)
+		if err != nil {
+			t.Errorf("%s: ListActions: %v", tt.name, err)
+			continue
+		}
+		got := make([]int, len(list))
+		for i, a := range list {
+			got[i] = a.ID
+		}
+		wantIDs(t, tt.name, got, tt.want...)
+	}
+
+	// Actions without details get an empty object
+	list, _ := f.Admin.ListActions(f.ctx, &admin.ActionsFilter{Limit: 50})
+	if len(list) != 3 || !sameJSON(list[1].Details, json.RawMessage(`{}`)) || !sameJSON(list[2].Details, disable.Details) {
+		t.Errorf("ListActions details = %+v", list)
+	}
+}
+
+func testAccounts(t *testing.T, f *fixture) {
+	ada := f.user(t, "ada@example.com")
+	bob := f.user(t, "bob@example.com")
+	carol := f.user(t, "carol@example.com")
+
+	found, err := f.Accounts.GetUser(f.ctx, ada)
+	if err != nil || found.Email != "ada@example.com" || found.PasswordHash != "" {
+		t.Errorf("GetUser = %+v, %v", found, err)
+	}
+	_, err = f.Accounts.GetUser(f.ctx, 999999)
+	wantError(t, "GetUser of a missing user", err, apperror.ErrNotFound, "user_not_found", "")
+
+	// Scheduling and cancelling a deletion
+	status, err := f.Accounts.GetDeletionStatus(f.ctx, ada)
+	if err != nil || status.Pending || status.RequestedAt != nil || status.ScheduledFor != nil {
+		t.Errorf("GetDeletionStatus = %+v, %v", status, err)
+	}
+	scheduledFor := time.Now().Add(time.Hour).UTC().Truncate(time.Microsecond)
+	if err := f.Accounts.ScheduleDeletion(f.ctx, ada, scheduledFor); err != nil {
+		t.Fatalf("ScheduleDeletion: %v", err)
+	}
+	status, err = f.Accounts.GetDeletionStatus(f.ctx, ada)
+	if err != nil || !status.Pending || status.RequestedAt == nil || status.ScheduledFor == nil || !status.ScheduledFor.Equal(scheduledFor) {
+		t.Errorf("GetDeletionStatus after ScheduleDeletion = %+v, %v", status, err)
+	}
+	wantError(t, "ScheduleDeletion of a missing user", f.Accounts.ScheduleDeletion(f.ctx, 999999, scheduledFor),
+		apperror.ErrNotFound, "user_not_found", "")
+
+	if err := f.Accounts.ScheduleDeletion(f.ctx, bob, scheduledFor.Add(-time.Minute)); err != nil {
+		t.Fatalf("ScheduleDeletion: %v", err)
+	}
+	due, err := f.Accounts.GetDueDeletions(f.ctx, scheduledFor)
+	if err != nil {
+		t.Fatalf("GetDueDeletions: %v", err)
+	}
+	wantIDs(t, "GetDueDeletions", due, bob, ada)
+	if due, err := f.Accounts.GetDueDeletions(f.ctx, time.Now()); err != nil || len(due) != 0 {
+		t.Errorf("GetDueDeletions before the grace period ended = %v, %v", due, err)
+	}
+
+	if cancelled, err := f.Accounts.CancelDeletion(f.ctx, bob); err != nil || !cancelled {
+		t.Errorf("CancelDeletion = %v, %v", cancelled, err)
+	}
+	if cancelled, err := f.Accounts.CancelDeletion(f.ctx, bob); err != nil || cancelled {
+		t.Errorf("CancelDeletion without a pending deletion = %v, %v", cancelled, err)
+	}
+
+	// Data to export and erase
+	org := f.organization(t, ada)
+	if err := f.Organizations.AddMember(f.ctx, org, carol, organization.RoleMember); err != nil {
+		t.Fatalf("AddMember: %v", err)
+	}
+	if err := f.Organizations.AddMember(f.ctx, org, bob, organization.RoleAdmin); err != nil {
+		t.Fatalf("AddMember: %v", err)
+	}
+
+	shared := f.event(t, ada, "Shared", "2030-05-01", "10:00:00", nil)
+	solo := f.event(t, ada, "Solo", "2030-04-01", "10:00:00", nil)
+	party := f.event(t, bob, "Party", "2030-06-01", "20:00:00", nil)
+	for _, ev := range []*event.Event{shared, solo} {
+		if err := f.Events.AddOrganizerAsAttendee(f.ctx, ada, ev.ID); err != nil {
+			t.Fatalf("AddOrganizerAsAttendee: %v", err)
+		}
+	}
+	if err := f.Events.AddAttendee(f.ctx, shared.ID, bob, "collaborator"); err != nil {
+		t.Fatalf("AddAttendee: %v", err)
+	}
+	if err := f.Events.JoinEvent(f.ctx, ada, party.ID); err != nil {
+		t.Fatalf("JoinEvent: %v", err)
+	}
+
+	received := f.invite(t, party.ID, bob, "ada@example.com")
+	sent := f.invite(t, solo.ID, ada, "carol@example.com")
+
+	friends := f.group(t, ada, "Friends", "bob@example.com", "carol@example.com")
+	team := f.group(t, bob, "Team", "ada@example.com", "carol@example.com")
+
+	if err := f.Profiles.UpsertProfile(f.ctx, &profile.Profile{UserID: ada, DisplayName: "Ada", Timezone: "UTC", Locale: "en"}); err != nil {
+		t.Fatalf("UpsertProfile: %v", err)
+	}
+
+	organized, err := f.Accounts.GetOrganizedEvents(f.ctx, ada)
+	if err != nil {
+		t.Fatalf("GetOrganizedEvents: %v", err)
+	}
+	wantIDs(t, "GetOrganizedEvents", ids(organized), solo.ID, shared.ID)
+
+	rsvps, err := f.Accounts.GetRSVPs(f.ctx, ada)
+	if err != nil || len(rsvps) != 3 {
+		t.Fatalf("GetRSVPs = %+v, %v", rsvps, err)
+	}
+	if rsvps[0].ID != solo.ID || rsvps[1].ID != shared.ID || rsvps[2].ID != party.ID ||
+		rsvps[0].Role != "organizer" || rsvps[2].Role != "attendee" || rsvps[2].Status != "going" {
+		t.Errorf("GetRSVPs = %+v", rsvps)
+	}
+
+	in, out, err := f.Accounts.GetInvitations(f.ctx, ada, "ADA@example.com")
+	if err != nil || len(in) != 1 || in[0].ID != received.ID || len(out) != 1 || out[0].ID != sent.ID || out[0].Message != "join us" {
+		t.Errorf("GetInvitations = %+v, %+v, %v", in, out, err)
+	}
+
+	groups, err := f.Accounts.GetGroups(f.ctx, ada)
+	if err != nil || len(groups) != 1 || groups[0].ID != friends || strings.Join(groups[0].Members, ",") != "bob@example.com,carol@example.com" {
+		t.Errorf("GetGroups = %+v, %v", groups, err)
+	}
+	if groups, err := f.Accounts.GetGroups(f.ctx, carol); err != nil || groups == nil || len(groups) != 0 {
+		t.Errorf("GetGroups without groups = %#v, %v", groups, err)
+	}
+
+	// Erasure hands shared events over and archives the others
+	result, err := f.Accounts.EraseUser(f.ctx, ada)
+	if err != nil {
+		t.Fatalf("EraseUser: %v", err)
+	}
+	wantIDs(t, "TransferredEvents", result.TransferredEvents, shared.ID)
+	wantIDs(t, "ArchivedEvents", result.ArchivedEvents, solo.ID)
+
+	transferred, _ := f.Events.GetEventByID(f.ctx, shared.ID)
+	if transferred.OrganizerID != bob || transferred.Version != shared.Version+1 || transferred.ArchivedAt != nil {
+		t.Errorf("transferred event = %+v", transferred)
+	}
+	attendees, _ := f.Events.GetEventAttendees(f.ctx, shared.ID)
+	if len(attendees) != 1 || attendees[0].UserID != bob || attendees[0].Role != "organizer" {
+		t.Errorf("attendees of the transferred event = %+v", attendees)
+	}
+	archived, _ := f.Events.GetEventByID(f.ctx, solo.ID)
+	if archived.OrganizerID != ada || archived.ArchivedAt == nil || archived.Version != solo.Version+1 {
+		t.Errorf("archived event = %+v", archived)
+	}
+
+	// The admin takes over the organization
+	if m, err := f.Organizations.GetMembership(f.ctx, org, bob); err != nil || m.Role != organization.RoleOwner {
+		t.Errorf("membership of the new owner = %+v, %v", m, err)
+	}
+	if m, err := f.Organizations.GetMembership(f.ctx, org, carol); err != nil || m.Role != organization.RoleMember {
+		t.Errorf("membership of another member = %+v, %v", m, err)
+	}
+	_, err = f.Organizations.GetMembership(f.ctx, org, ada)
+	wantError(t, "membership of the erased user", err, apperror.ErrNotFound, "member_not_found", "")
+
+	pseudo := fmt.Sprintf("deleted-user-%d@deleted.invalid", ada)
+	_, out, _ = f.Accounts.GetInvitations(f.ctx, bob, "bob@example.com")
+	if len(out) != 1 || out[0].InviteeEmail != pseudo {
+		t.Errorf("invitations to the erased user = %+v", out)
+	}
+
+	_, err = f.Groups.GetGroupByID(f.ctx, friends)
+	wantError(t, "group of the erased user", err, apperror.ErrNotFound, "group_not_found", "")
+	if members, _ := f.Groups.GetMembers(f.ctx, team); len(members) != 1 || members[0].Email != "carol@example.com" {
+		t.Errorf("members after erasure = %+v", members)
+	}
+
+	if p, err := f.Profiles.GetProfile(f.ctx, ada); err != nil || p.DisplayName != "" || p.Email != pseudo {
+		t.Errorf("profile of the erased user = %+v, %v", p, err)
+	}
+
+	// Only a disabled tombstone is left
+	tombstone, err := f.Admin.GetUserByID(f.ctx, ada)
+	if err != nil || tombstone.Email != pseudo || tombstone.DisabledAt == nil {
+		t.Errorf("tombstone = %+v, %v", tombstone, err)
+	}
+	wantError(t, "SetDisabled of the tombstone", f.Admin.SetDisabled(f.ctx, ada, false), apperror.ErrNotFound, "user_not_found", "")
+	_, err = f.Accounts.GetUser(f.ctx, ada)
+	wantError(t, "GetUser of the erased user", err, apperror.ErrNotFound, "user_not_found", "")
+	_, err = f.Accounts.GetDeletionStatus(f.ctx, ada)
+	wantError(t, "GetDeletionStatus of the erased user", err, apperror.ErrNotFound, "user_not_found", "")
+	_, err = f.Accounts.EraseUser(f.ctx, ada)
+	wantError(t, "EraseUser twice", err, apperror.ErrNotFound, "user_not_found", "")
+	if due, err := f.Accounts.GetDueDeletions(f.ctx, scheduledFor); err != nil || len(due) != 0 {
+		t.Errorf("GetDueDeletions after erasure = %v, %v", due, err)
+	}
+}
+
+// sameJSON reports whether two documents have the same value, whatever their
+// formatting and key order
+func sameJSON(a, b json.RawMessage) bool {
+	var va, vb interface{}
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+// wantError checks the kind, code and field of a domain error
+func wantError(t *testing.T, what string, err, kind error, code, field string) {
+	t.Helper()
+
+	if !errors.Is(err, kind) {
+		t.Errorf("%s: got error %v, want %v", what, err, kind)
+		return
+	}
+
+	var appErr *apperror.Error
+	if !errors.As(err, &appErr) || appErr.Code != code {
+		t.Errorf("%s: got error %#v, want code %q", what, err, code)
+		return
+	}
+
+	if field != "" && (len(appErr.Fields) != 1 || appErr.Fields[0].Field != field) {
+		t.Errorf("%s: got fields %+v, want %q", what, appErr.Fields, field)
+	}
+}
+
+func wantIDs(t *testing.T, what string, got []int, want ...int) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Errorf("%s: got IDs %v, want %v", what, got, want)
+		return
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("%s: got IDs %v, want %v", what, got, want)
+			return
+		}
+	}
+}
+
+func ids(events []event.Event) []int {
+	out := make([]int, len(events))
+	for i, e := range events {
+		out[i] = e.ID
+	}
+	return out
+}
+
+func invitationIDs(invitations []invitation.InvitationWithDetails) []int {
+	out := make([]int, len(invitations))
+	for i, inv := range invitations {
+		out[i] = inv.ID
+	}
+	return out
+}
+
+func mustParse(t *testing.T, layout, value string) time.Time {
+	t.Helper()
+
+	parsed, err := time.Parse(layout, value)
+	if err != nil {
+		t.Fatalf("parse %q: %v", value, err)
+	}
+	return parsed
+}
@@ -2,11 +2,25 @@ package user
 
 import "time"
 
+// System roles
+const (
+	RoleAdmin   = "admin"
+	RoleSupport = "support"
+	RoleMember  = "member"
+)
+
 type User struct {
-	ID           int       `json:"id"`
-	Email        string    `json:"email"`
-	PasswordHash string    `json:"-"`
-	CreatedAt    time.Time `json:"created_at"`
+	ID           int        `json:"id"`
+	Email        string     `json:"email"`
+	PasswordHash string     `json:"-"`
+	Role         string     `json:"role"` // 'admin', 'support' or 'member'
+	DisabledAt   *time.Time `json:"disabled_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// IsValidRole reports whether role is a known system role
+func IsValidRole(role string) bool {
+	return role == RoleAdmin || role == RoleSupport || role == RoleMember
 }
 
 type LoginRequest struct {
@@ -22,3 +36,11 @@ type RegisterRequest struct {
 type AuthResponse struct {
 	Token string `json:"token"`
 }
+
+// PublicProfile is the compact, publicly visible view of a user that is
+// embedded in attendee lists and invitations
+type PublicProfile struct {
+	UserID      int    `json:"user_id"`
+	DisplayName string `json:"display_name"`
+	AvatarURL   string `json:"avatar_url,omitempty"`
+}